@@ -19,6 +19,7 @@ var (
 			cli.NewGetCommand(applicationShowCmd, applicationShowRun, nil),
 			cli.NewCommand(applicationCreateCmd, applicationCreateRun, nil),
 			cli.NewCommand(applicationDeleteCmd, applicationDeleteRun, nil),
+			cli.NewCommand(applicationMigrateCmd, applicationMigrateRun, nil),
 			applicationKey,
 		})
 )
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var applicationMigrateCmd = cli.Command{
+	Name:  "migrate",
+	Short: "Migrate the pipelines and triggers of a CDS application to workflows",
+	Long: `Convert the pipelines of an application and their triggers to workflows, one workflow by root pipeline.
+
+Triggers become workflow triggers with the same conditions, repository webhooks and pollers become workflow
+hooks and schedulers become scheduler hooks. Everything which cannot be translated is listed in the report.
+
+	$ cdsctl application migrate MYPROJ myapp --dry-run
+	$ cdsctl application migrate MYPROJ myapp --disable-legacy`,
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "application-name"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:    "dry-run",
+			Usage:   "Only show the workflows which would be created",
+			Default: "false",
+			Kind:    reflect.Bool,
+		},
+		{
+			Name:    "disable-legacy",
+			Usage:   "Disable the migrated triggers, pollers and schedulers: triggers become manual, repository hooks are kept until their webhook is replaced",
+			Default: "false",
+			Kind:    reflect.Bool,
+		},
+	},
+}
+
+func applicationMigrateRun(v cli.Values) error {
+	report, err := client.ApplicationMigrate(v["project-key"], v["application-name"], v.GetBool("dry-run"), v.GetBool("disable-legacy"))
+	if err != nil {
+		return err
+	}
+
+	if report.DryRun {
+		fmt.Println("Workflows which would be created:")
+	} else {
+		fmt.Println("Workflows created:")
+	}
+	for _, w := range report.Workflows {
+		fmt.Printf("  %s\n", w.Name)
+		if w.Root != nil {
+			applicationMigratePrintNode(*w.Root, "    ", "")
+		}
+	}
+
+	if len(report.Untranslated) > 0 {
+		fmt.Println("Not translated:")
+		for _, u := range report.Untranslated {
+			fmt.Printf("  - %s\n", u)
+		}
+	}
+
+	if report.LegacyDisabled {
+		fmt.Println("Legacy triggers, pollers and schedulers are disabled, repository hooks are kept until their webhook is replaced")
+	}
+	return nil
+}
+
+func applicationMigratePrintNode(n sdk.WorkflowNode, indent, prefix string) {
+	s := n.Pipeline.Name
+	if n.Context != nil && n.Context.Application != nil {
+		s = n.Context.Application.Name + "/" + s
+	}
+	if n.Context != nil && n.Context.Environment != nil {
+		s += " on " + n.Context.Environment.Name
+	}
+	var hooks []string
+	for _, h := range n.Hooks {
+		hooks = append(hooks, h.WorkflowHookModel.Name)
+	}
+	if len(hooks) > 0 {
+		s += " [" + strings.Join(hooks, ", ") + "]"
+	}
+	fmt.Println(indent + prefix + s)
+
+	for _, t := range n.Triggers {
+		var conditions []string
+		for _, c := range t.Conditions {
+			conditions = append(conditions, fmt.Sprintf("%s %s %s", c.Variable, sdk.WorkflowConditionsOperators[c.Operator], c.Value))
+		}
+		p := "-> "
+		if t.Manual {
+			p += "(manual) "
+		}
+		if len(conditions) > 0 {
+			p += "if " + strings.Join(conditions, " and ") + ": "
+		}
+		applicationMigratePrintNode(t.WorkflowDestNode, indent+"  ", p)
+	}
+}
@@ -18,7 +18,9 @@ type config struct {
 	Host                  string
 	user                  string
 	token                 string
+	accessToken           string
 	InsecureSkipVerifyTLS bool
+	AccessToken           bool
 }
 
 func loadConfig(configFile string) (*cdsclient.Config, error) {
@@ -28,9 +30,10 @@ func loadConfig(configFile string) (*cdsclient.Config, error) {
 	c.Host = os.Getenv("CDS_API")
 	c.user = os.Getenv("CDS_USER")
 	c.token = os.Getenv("CDS_TOKEN")
+	c.accessToken = os.Getenv("CDS_ACCESS_TOKEN")
 	c.InsecureSkipVerifyTLS, _ = strconv.ParseBool(os.Getenv("CDS_INSECURE"))
 
-	if c.Host != "" && c.user != "" {
+	if c.Host != "" && (c.user != "" || c.accessToken != "") {
 		if verbose {
 			fmt.Println("Configuration loaded from environment variables")
 		}
@@ -81,21 +84,32 @@ func loadConfig(configFile string) (*cdsclient.Config, error) {
 	}
 
 	conf := &cdsclient.Config{
-		Host:    c.Host,
-		User:    c.user,
-		Token:   c.token,
-		Verbose: verbose,
+		Host:        c.Host,
+		User:        c.user,
+		Token:       c.token,
+		AccessToken: c.accessToken,
+		Verbose:     verbose,
 	}
+	useAccessToken = c.AccessToken
 
 	return conf, nil
 }
 
 func loadClient(c *cdsclient.Config) (cdsclient.Interface, error) {
+	//An access token set in the environment doesn't need the keychain
+	if c.AccessToken != "" {
+		return cdsclient.New(*c), nil
+	}
+
 	user, secret, err := keychain.GetSecret(c.Host)
 	if err != nil {
 		return nil, err
 	}
 	c.User = user
-	c.Token = secret
+	if useAccessToken {
+		c.AccessToken = secret
+	} else {
+		c.Token = secret
+	}
 	return cdsclient.New(*c), nil
 }
@@ -18,6 +18,8 @@ var (
 	environment = cli.NewCommand(environmentCmd, nil,
 		[]*cobra.Command{
 			cli.NewListCommand(environmentListCmd, environmentListRun, nil),
+			cli.NewListCommand(environmentHistoryCmd, environmentHistoryRun, nil),
+			cli.NewCommand(environmentPromoteCmd, environmentPromoteRun, nil),
 			environmentKey,
 		})
 )
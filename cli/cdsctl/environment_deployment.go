@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var environmentHistoryCmd = cli.Command{
+	Name:  "history",
+	Short: "List the deployments on a CDS environment, the current ones are flagged",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "environment"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "application",
+			Usage: "Only list the deployments of this application",
+			Kind:  reflect.String,
+		},
+	},
+}
+
+func environmentHistoryRun(v cli.Values) (cli.ListResult, error) {
+	ds, err := client.EnvironmentDeployments(v["project-key"], v["environment"], v.GetString("application"))
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(ds), nil
+}
+
+var environmentPromoteCmd = cli.Command{
+	Name:  "promote",
+	Short: "Deploy on an environment what is deployed on another one, with the same artifacts",
+	Long: `Re-run, in the workflow run of the deployment, the node deploying the application on the target environment.
+By default the current deployment of the application is promoted.`,
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "environment"},
+		{Name: "to"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "application",
+			Usage: "Application to promote, mandatory if several applications are deployed on the environment",
+			Kind:  reflect.String,
+		},
+		{
+			Name:  "deployment",
+			Usage: "ID of the deployment to promote, instead of the current one",
+			Kind:  reflect.String,
+		},
+	},
+}
+
+func environmentPromoteRun(v cli.Values) error {
+	promotion := sdk.EnvironmentPromotion{
+		To:              v["to"],
+		ApplicationName: v.GetString("application"),
+	}
+	if s := v.GetString("deployment"); s != "" {
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return fmt.Errorf("deployment has to be an integer")
+		}
+		promotion.DeploymentID = id
+	}
+
+	wr, err := client.EnvironmentPromote(v["project-key"], v["environment"], promotion)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Workflow %s #%d.%d has been started\n", wr.Workflow.Name, wr.Number, wr.LastSubNumber)
+	return nil
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	freezeCmd = cli.Command{
+		Name:  "freeze",
+		Short: "Manage CDS deployment freeze windows",
+	}
+
+	freeze = cli.NewCommand(freezeCmd, nil,
+		[]*cobra.Command{
+			cli.NewListCommand(freezeListCmd, freezeListRun, nil),
+			cli.NewCommand(freezeCreateCmd, freezeCreateRun, nil),
+			cli.NewCommand(freezeDeleteCmd, freezeDeleteRun, nil),
+		})
+)
+
+var freezeProjectFlag = cli.Flag{
+	Name:  "project",
+	Usage: "Key of the project; global freeze windows are managed without it",
+	Kind:  reflect.String,
+}
+
+var freezeListCmd = cli.Command{
+	Name:  "list",
+	Short: "List the active and upcoming freeze windows, global ones and those of a project",
+	Flags: []cli.Flag{freezeProjectFlag},
+}
+
+func freezeListRun(v cli.Values) (cli.ListResult, error) {
+	ws, err := client.FreezeWindowList(v.GetString("project"))
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(ws), nil
+}
+
+var freezeCreateCmd = cli.Command{
+	Name:  "create",
+	Short: "Create a freeze window",
+	Long: `Create a freeze window during which deployments are held until its end, or refused. Global freeze windows can only be
+created by administrators. The end of a recurring freeze window is the end of its recurrence.
+
+	$ cdsctl freeze create release-week 2018-06-01T00:00:00Z 2018-06-08T00:00:00Z --project MYPROJ --environment prod
+	$ cdsctl freeze create weekend 2018-01-01T00:00:00Z 2019-01-01T00:00:00Z --recurrence "0 18 * * 5" --duration 3840 --timezone Europe/Paris --action refuse`,
+	Args: []cli.Arg{
+		{Name: "name"},
+		{Name: "start"},
+		{Name: "end"},
+	},
+	Flags: []cli.Flag{
+		freezeProjectFlag,
+		{
+			Name:  "environment",
+			Usage: "Only freeze the deployments on this environment of the project",
+			Kind:  reflect.String,
+		}, {
+			Name:  "recurrence",
+			Usage: "Cron expression of the openings of the freeze window",
+			Kind:  reflect.String,
+		}, {
+			Name:  "duration",
+			Usage: "Duration in minutes of each opening of a recurring freeze window",
+			Kind:  reflect.String,
+		}, {
+			Name:  "timezone",
+			Usage: "Timezone of the recurrence",
+			Kind:  reflect.String,
+		}, {
+			Name:    "action",
+			Usage:   "hold or refuse the deployments",
+			Default: sdk.FreezeActionHold,
+			Kind:    reflect.String,
+		}, {
+			Name:  "description",
+			Usage: "Description of the freeze window",
+			Kind:  reflect.String,
+		},
+	},
+}
+
+func freezeCreateRun(v cli.Values) error {
+	w := &sdk.FreezeWindow{
+		Name:            v["name"],
+		Description:     v.GetString("description"),
+		EnvironmentName: v.GetString("environment"),
+		Recurrence:      v.GetString("recurrence"),
+		Timezone:        v.GetString("timezone"),
+		Action:          v.GetString("action"),
+	}
+	var err error
+	if w.Start, err = time.Parse(time.RFC3339, v["start"]); err != nil {
+		return fmt.Errorf("start parameter have to be a RFC3339 date")
+	}
+	if w.End, err = time.Parse(time.RFC3339, v["end"]); err != nil {
+		return fmt.Errorf("end parameter have to be a RFC3339 date")
+	}
+	if s := v.GetString("duration"); s != "" {
+		if w.Duration, err = strconv.ParseInt(s, 10, 64); err != nil {
+			return fmt.Errorf("duration parameter have to be an integer")
+		}
+	}
+
+	if err := client.FreezeWindowCreate(v.GetString("project"), w); err != nil {
+		return err
+	}
+	fmt.Printf("Freeze window %s created: next from %s to %s\n", w.Name, w.NextStart, w.NextEnd)
+	return nil
+}
+
+var freezeDeleteCmd = cli.Command{
+	Name:  "delete",
+	Short: "Delete a freeze window",
+	Args: []cli.Arg{
+		{Name: "id"},
+	},
+	Flags: []cli.Flag{freezeProjectFlag},
+}
+
+func freezeDeleteRun(v cli.Values) error {
+	id, err := strconv.ParseInt(v["id"], 10, 64)
+	if err != nil {
+		return fmt.Errorf("id parameter have to be an integer")
+	}
+	return client.FreezeWindowDelete(v.GetString("project"), id)
+}
@@ -18,6 +18,7 @@ var (
 			cli.NewGetCommand(groupShowCmd, groupShowRun, nil),
 			groupToken,
 			groupUser,
+			groupQueue,
 		})
 )
 
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	groupQueueCmd = cli.Command{
+		Name:  "queue",
+		Short: "Manage CDS groups share of the job queue (admin only)",
+	}
+
+	groupQueue = cli.NewCommand(groupQueueCmd, nil,
+		[]*cobra.Command{
+			cli.NewListCommand(groupQueueListCmd, groupQueueListRun, nil),
+			cli.NewCommand(groupQueueSetCmd, groupQueueSetRun, nil),
+		})
+)
+
+var groupQueueListCmd = cli.Command{
+	Name:  "list",
+	Short: "List waiting and building jobs of groups against their quota",
+}
+
+func groupQueueListRun(v cli.Values) (cli.ListResult, error) {
+	usage, err := client.GroupQueueList()
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(usage), nil
+}
+
+var groupQueueSetCmd = cli.Command{
+	Name:  "set",
+	Short: "Set the weight of a group in the job queue and its quota of building jobs, 0 for no quota",
+	Args: []cli.Arg{
+		{Name: "groupname"},
+		{Name: "weight"},
+		{Name: "max-building"},
+	},
+}
+
+func groupQueueSetRun(v cli.Values) error {
+	weight, err := strconv.Atoi(v["weight"])
+	if err != nil {
+		return err
+	}
+	maxBuilding, err := strconv.Atoi(v["max-building"])
+	if err != nil {
+		return err
+	}
+	return client.GroupQueueSettingsSet(v["groupname"], sdk.GroupQueueSettings{Weight: weight, MaxBuilding: maxBuilding})
+}
@@ -42,6 +42,10 @@ var loginCmd = cli.Command{
 			ShortHand: "p",
 			Usage:     "CDS Password",
 			Kind:      reflect.String,
+		}, {
+			Name:  "token",
+			Usage: "CDS personal access token, used instead of the password",
+			Kind:  reflect.String,
 		}, {
 			Name:  "env",
 			Usage: "Display the commands to set up the environment for the cds client",
@@ -54,10 +58,11 @@ func loginRun(v cli.Values) error {
 	url := v.GetString("host")
 	username := v.GetString("username")
 	password := v.GetString("password")
+	accessToken := v.GetString("token")
 	env := v.GetBool("env")
 
 	if env &&
-		(url == "" || username == "" || password == "") {
+		(url == "" || username == "" || (password == "" && accessToken == "")) {
 		return fmt.Errorf("Please set flags to use --env option")
 	}
 
@@ -73,6 +78,10 @@ func loginRun(v cli.Values) error {
 		fmt.Println("Username:", username)
 	}
 
+	if accessToken != "" {
+		return doLoginWithAccessToken(url, username, accessToken, env)
+	}
+
 	//Take the password from flags or ask for on command line
 	if password == "" {
 		//Ask for the password
@@ -118,6 +127,41 @@ func doLogin(url, username, password string, env bool) error {
 		return nil
 	}
 
+	return writeLoginConfig(url, username, token, false)
+}
+
+func doLoginWithAccessToken(url, username, accessToken string, env bool) error {
+	conf := cdsclient.Config{
+		Host:        url,
+		User:        username,
+		AccessToken: accessToken,
+		Verbose:     os.Getenv("CDS_VERBOSE") == "true",
+	}
+
+	//Check the token by loading the user it belongs to
+	client = cdsclient.New(conf)
+	if _, err := client.UserGet(username); err != nil {
+		return fmt.Errorf("login failed: %v", err)
+	}
+
+	if env && runtime.GOOS == "windows" {
+		fmt.Println("env option is not supported on windows yet")
+		os.Exit(1)
+	}
+
+	if env {
+		fmt.Printf("export CDS_API=%s\n", url)
+		fmt.Printf("export CDS_USER=%s\n", username)
+		fmt.Printf("export CDS_ACCESS_TOKEN=%s\n", accessToken)
+		fmt.Println("# Run this command to configure your shell:")
+		fmt.Println(`# eval "$(cds login -H HOST -u USERNAME --token TOKEN --env)`)
+		return nil
+	}
+
+	return writeLoginConfig(url, username, accessToken, true)
+}
+
+func writeLoginConfig(url, username, token string, isAccessToken bool) error {
 	if configFile == "" {
 		u, err := user.Current()
 		if err != nil {
@@ -135,8 +179,9 @@ func doLogin(url, username, password string, env bool) error {
 	}
 
 	tomlConf := config{
-		Host: url,
+		Host:                  url,
 		InsecureSkipVerifyTLS: insecureSkipVerifyTLS,
+		AccessToken:           isAccessToken,
 	}
 	var buf = new(bytes.Buffer)
 	e := toml.NewEncoder(buf)
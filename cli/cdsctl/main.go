@@ -15,6 +15,7 @@ var (
 	verbose               bool
 	noWarnings            bool
 	insecureSkipVerifyTLS bool
+	useAccessToken        bool
 	client                cdsclient.Interface
 )
 
@@ -32,6 +33,7 @@ func main() {
 			signup,
 			application,
 			environment,
+			freeze,
 			pipeline,
 			group,
 			project,
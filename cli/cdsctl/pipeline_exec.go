@@ -0,0 +1,391 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/hcl"
+	"gopkg.in/yaml.v2"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+var pipelineExecCmd = cli.Command{
+	Name:  "exec",
+	Short: "Execute a CDS pipeline on this machine",
+	Long: `Execute the jobs of a pipeline in the current directory with the CDS worker, without run on CDS API.
+
+The pipeline is fetched from CDS API, or read in a file with --file. Project, application and environment
+variables are given to the jobs, but not their secrets, as CDS API only returns their placeholder: they can be
+given with --param, like any parameter, and are masked in the logs:
+
+	$ cdsctl pipeline exec MYPROJ build --param cds.pip.version=1.0 --param "cds.proj.password=my secret"
+
+Artifacts are copied in a local directory, and the CDS worker is searched in the PATH, it can be downloaded
+from CDS API on /download/worker/x86_64.`,
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "pipeline-name"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "file",
+			Usage: "Path of a pipeline file to execute instead of the pipeline on CDS API",
+			Kind:  reflect.String,
+		},
+		{
+			Name:  "application",
+			Usage: "Name of the application whose variables are used",
+			Kind:  reflect.String,
+		},
+		{
+			Name:  "environment",
+			Usage: "Name of the environment whose variables are used",
+			Kind:  reflect.String,
+		},
+		{
+			Name:  "param",
+			Usage: "Parameter or variable override, like cds.pip.version=1.0, can be repeated",
+			Kind:  reflect.Slice,
+		},
+		{
+			Name:    "artifacts-dir",
+			Usage:   "Directory where artifacts are copied",
+			Default: "cds-artifacts",
+			Kind:    reflect.String,
+		},
+		{
+			Name:    "worker",
+			Usage:   "Path of the CDS worker",
+			Default: "worker",
+			Kind:    reflect.String,
+		},
+	},
+}
+
+func pipelineExecRun(v cli.Values) error {
+	workerPath, err := exec.LookPath(v["worker"])
+	if err != nil {
+		return fmt.Errorf("CDS worker not found, you can download it from %s/download/worker/x86_64", client.APIURL())
+	}
+
+	artifactsDir, err := filepath.Abs(v["artifacts-dir"])
+	if err != nil {
+		return err
+	}
+
+	pip, err := pipelineExecLoad(v)
+	if err != nil {
+		return err
+	}
+
+	params, err := pipelineExecParameters(v, pip)
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(pip.Stages, func(i, j int) bool {
+		return pip.Stages[i].BuildOrder < pip.Stages[j].BuildOrder
+	})
+
+	var reports []pipelineExecReport
+	var failed bool
+	for _, s := range pip.Stages {
+		if !s.Enabled {
+			fmt.Printf("Stage %s is disabled\n", s.Name)
+			continue
+		}
+		if failed {
+			fmt.Printf("Stage %s is not run as a previous stage failed\n", s.Name)
+			continue
+		}
+		ok, err := sdk.WorkflowCheckConditions(s.Conditions(), params)
+		if err != nil {
+			return fmt.Errorf("invalid prerequisites on stage %s: %v", s.Name, err)
+		}
+		if !ok {
+			fmt.Printf("Stage %s is skipped, its prerequisites are not met\n", s.Name)
+			continue
+		}
+
+		for _, j := range s.Jobs {
+			if !j.Enabled {
+				fmt.Printf("Job %s is disabled\n", j.Action.Name)
+				continue
+			}
+
+			job := sdk.LocalJob{
+				Name:               j.Action.Name,
+				Action:             j.Action,
+				Parameters:         append([]sdk.Parameter{}, params...),
+				ArtifactsDirectory: artifactsDir,
+			}
+			sdk.AddParameter(&job.Parameters, "cds.stage", sdk.StringParameter, s.Name)
+			sdk.AddParameter(&job.Parameters, "cds.job", sdk.StringParameter, j.Action.Name)
+
+			fmt.Printf("Starting job %s/%s\n", s.Name, j.Action.Name)
+			report, err := pipelineExecJob(workerPath, job)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("End of job %s/%s [%s]\n", s.Name, j.Action.Name, report.Status)
+
+			if report.Status != sdk.StatusSuccess.String() {
+				failed = true
+			}
+			// Variables exported by a job are given to the next jobs
+			for _, e := range report.Variables {
+				sdk.AddParameter(&params, e.Name, e.Type, e.Value)
+			}
+			reports = append(reports, pipelineExecReport{stage: s.Name, report: *report})
+		}
+	}
+
+	pipelineExecPrintReports(reports, artifactsDir)
+	if failed {
+		return fmt.Errorf("pipeline %s failed", pip.Name)
+	}
+	return nil
+}
+
+type pipelineExecReport struct {
+	stage  string
+	report sdk.LocalJobReport
+}
+
+// pipelineExecLoad returns the pipeline from the file given by --file, or from CDS API. The actions used by the
+// steps of a pipeline file are fetched from CDS API
+func pipelineExecLoad(v cli.Values) (*sdk.Pipeline, error) {
+	if v["file"] == "" {
+		return client.PipelineGet(v["project-key"], v["pipeline-name"])
+	}
+
+	btes, format, err := exportentities.ReadFile(v["file"])
+	if err != nil {
+		return nil, err
+	}
+
+	payload := &exportentities.Pipeline{}
+	switch format {
+	case exportentities.FormatJSON, exportentities.FormatHCL:
+		err = hcl.Unmarshal(btes, payload)
+	default:
+		err = yaml.Unmarshal(btes, payload)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %v", v["file"], err)
+	}
+
+	pip, err := payload.Pipeline()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range pip.Stages {
+		for j := range pip.Stages[i].Jobs {
+			steps := pip.Stages[i].Jobs[j].Action.Actions
+			for k := range steps {
+				if (steps[k].Type != "" && steps[k].Type != sdk.DefaultAction) || len(steps[k].Actions) > 0 {
+					continue
+				}
+				a, err := client.ActionGet(steps[k].Name)
+				if err != nil {
+					return nil, fmt.Errorf("cannot get action %s: %v", steps[k].Name, err)
+				}
+				steps[k].Type = a.Type
+				steps[k].Actions = a.Actions
+				for _, p := range a.Parameters {
+					if sdk.ParameterFind(steps[k].Parameters, p.Name) == nil {
+						steps[k].Parameters = append(steps[k].Parameters, p)
+					}
+				}
+			}
+		}
+	}
+	return pip, nil
+}
+
+// pipelineExecParameters returns the parameters of the jobs, with the overrides given by --param. The secrets
+// of the project, application and environment are not returned by CDS API, they must be given with --param
+func pipelineExecParameters(v cli.Values, pip *sdk.Pipeline) ([]sdk.Parameter, error) {
+	params := []sdk.Parameter{}
+	for k, val := range map[string]string{
+		"cds.project":     v["project-key"],
+		"cds.pipeline":    pip.Name,
+		"cds.version":     "0",
+		"cds.run.number":  "0",
+		"cds.buildNumber": "0",
+	} {
+		sdk.AddParameter(&params, k, sdk.StringParameter, val)
+	}
+	secrets := []sdk.Parameter{}
+
+	proj, err := client.ProjectGet(v["project-key"], func(r *http.Request) {
+		q := r.URL.Query()
+		q.Set("withVariables", "true")
+		r.URL.RawQuery = q.Encode()
+	})
+	if err != nil {
+		return nil, err
+	}
+	pipelineExecVariables(&params, &secrets, "cds.proj", proj.Variable)
+
+	if v["application"] != "" {
+		app, err := client.ApplicationGet(v["project-key"], v["application"])
+		if err != nil {
+			return nil, err
+		}
+		sdk.AddParameter(&params, "cds.application", sdk.StringParameter, app.Name)
+		pipelineExecVariables(&params, &secrets, "cds.app", app.Variable)
+	}
+
+	if v["environment"] != "" {
+		env, err := client.EnvironmentGet(v["project-key"], v["environment"])
+		if err != nil {
+			return nil, err
+		}
+		sdk.AddParameter(&params, "cds.environment", sdk.StringParameter, env.Name)
+		pipelineExecVariables(&params, &secrets, "cds.env", env.Variable)
+	}
+
+	for _, p := range pip.Parameter {
+		p.Name = "cds.pip." + p.Name
+		if sdk.NeedPlaceholder(p.Type) && p.Value == sdk.PasswordPlaceholder {
+			secrets = append(secrets, p)
+			continue
+		}
+		sdk.AddParameter(&params, p.Name, p.Type, p.Value)
+	}
+
+	missing, err := pipelineExecOverride(&params, secrets, v.GetStringSlice("param"))
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range missing {
+		fmt.Printf("Warning: %s is a secret, CDS API only returns its placeholder %s: give its value with --param %s=...\n", name, sdk.PasswordPlaceholder, name)
+	}
+
+	return params, nil
+}
+
+// pipelineExecVariables adds the variables to the parameters, except the secrets whose values are not known
+func pipelineExecVariables(params, secrets *[]sdk.Parameter, prefix string, vars []sdk.Variable) {
+	for _, va := range vars {
+		p := sdk.Parameter{Name: prefix + "." + va.Name, Type: va.Type, Value: va.Value}
+		if sdk.NeedPlaceholder(va.Type) {
+			*secrets = append(*secrets, p)
+			continue
+		}
+		if va.Type == sdk.SecretReferenceVariable {
+			continue
+		}
+		sdk.AddParameter(params, p.Name, p.Type, p.Value)
+	}
+}
+
+// pipelineExecOverride sets the overrides, like name=value, in the parameters. Overrides keep the type of the
+// parameter or secret they replace, so that the worker masks secrets in the logs. It returns the secrets which
+// are not overridden
+func pipelineExecOverride(params *[]sdk.Parameter, secrets []sdk.Parameter, overrides []string) ([]string, error) {
+	for _, o := range overrides {
+		t := strings.SplitN(o, "=", 2)
+		if len(t) != 2 {
+			return nil, fmt.Errorf("invalid parameter %s, it must be like name=value", o)
+		}
+		p := sdk.ParameterFind(*params, t[0])
+		if p == nil {
+			p = sdk.ParameterFind(secrets, t[0])
+		}
+		if p == nil {
+			sdk.AddParameter(params, t[0], sdk.StringParameter, t[1])
+			continue
+		}
+		p.Value = t[1]
+		pipelineExecSetParameter(params, *p)
+	}
+
+	missing := []string{}
+	for _, s := range secrets {
+		if sdk.ParameterFind(*params, s.Name) == nil {
+			missing = append(missing, s.Name)
+		}
+	}
+	return missing, nil
+}
+
+func pipelineExecSetParameter(params *[]sdk.Parameter, p sdk.Parameter) {
+	for i := range *params {
+		if (*params)[i].Name == p.Name {
+			(*params)[i] = p
+			return
+		}
+	}
+	*params = append(*params, p)
+}
+
+// pipelineExecJob runs a job with "worker exec" and returns its report
+func pipelineExecJob(workerPath string, job sdk.LocalJob) (*sdk.LocalJobReport, error) {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return nil, err
+	}
+
+	reportFile, err := ioutil.TempFile("", "cds-report-")
+	if err != nil {
+		return nil, err
+	}
+	reportFile.Close()
+	defer os.Remove(reportFile.Name())
+
+	cmd := exec.Command(workerPath, "exec", "--report", reportFile.Name())
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("cannot run job %s: %v", job.Name, err)
+	}
+
+	btes, err := ioutil.ReadFile(reportFile.Name())
+	if err != nil {
+		return nil, err
+	}
+	report := &sdk.LocalJobReport{}
+	if err := json.Unmarshal(btes, report); err != nil {
+		return nil, fmt.Errorf("invalid report of job %s: %v", job.Name, err)
+	}
+	return report, nil
+}
+
+func pipelineExecPrintReports(reports []pipelineExecReport, artifactsDir string) {
+	fmt.Println()
+	fmt.Println("Report:")
+	var artifacts []string
+	for _, r := range reports {
+		fmt.Printf("  %s/%s [%s] %s\n", r.stage, r.report.Name, r.report.Status, sdk.Round(r.report.Duration, time.Second))
+		if r.report.Reason != "" && r.report.Status != sdk.StatusSuccess.String() {
+			fmt.Printf("    %s\n", strings.TrimSpace(r.report.Reason))
+		}
+		for _, s := range r.report.Steps {
+			fmt.Printf("    - %s [%s] %s\n", s.Name, s.Status, sdk.Round(s.Duration, time.Second))
+		}
+		if t := r.report.Tests; t != nil {
+			fmt.Printf("    Tests: %d, OK: %d, KO: %d, skipped: %d\n", t.Total, t.TotalOK, t.TotalKO, t.TotalSkipped)
+		}
+		artifacts = append(artifacts, r.report.Artifacts...)
+	}
+	if len(artifacts) > 0 {
+		fmt.Printf("Artifacts in %s: %s\n", artifactsDir, strings.Join(artifacts, ", "))
+	}
+}
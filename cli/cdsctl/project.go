@@ -20,6 +20,11 @@ var (
 			cli.NewListCommand(projectListCmd, projectListRun, nil),
 			cli.NewGetCommand(projectShowCmd, projectShowRun, nil),
 			projectKey,
+			projectAudit,
+			projectSecretBackend,
+			cli.NewCommand(projectExportCmd, projectExportRun, nil),
+			cli.NewCommand(projectImportCmd, projectImportRun, nil),
+			cli.NewListCommand(projectMetricsCmd, projectMetricsRun, nil),
 		})
 )
 
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	projectAuditCmd = cli.Command{
+		Name:  "audit",
+		Short: "Browse the audit trail of a CDS project",
+	}
+
+	projectAudit = cli.NewCommand(projectAuditCmd, nil,
+		[]*cobra.Command{
+			cli.NewListCommand(projectAuditListCmd, projectAuditListRun, nil),
+			cli.NewCommand(projectAuditExportCmd, projectAuditExportRun, nil),
+		})
+)
+
+var projectAuditFlags = []cli.Flag{
+	{
+		Name:  "type",
+		Usage: "Filter on entity type: project, application, environment, pipeline, workflow, variable or key",
+		Kind:  reflect.String,
+	}, {
+		Name:  "name",
+		Usage: "Filter on entity name",
+		Kind:  reflect.String,
+	}, {
+		Name:  "username",
+		Usage: "Filter on the author of the changes",
+		Kind:  reflect.String,
+	}, {
+		Name:  "since",
+		Usage: "Only changes after this date (RFC3339)",
+		Kind:  reflect.String,
+	}, {
+		Name:  "until",
+		Usage: "Only changes before this date (RFC3339)",
+		Kind:  reflect.String,
+	}, {
+		Name:  "limit",
+		Usage: "Maximum number of changes",
+		Kind:  reflect.String,
+	},
+}
+
+var projectAuditListCmd = cli.Command{
+	Name:  "list",
+	Short: "List changes of a project",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+	},
+	Flags: projectAuditFlags,
+}
+
+func projectAuditListRun(v cli.Values) (cli.ListResult, error) {
+	f, err := projectAuditFilter(v)
+	if err != nil {
+		return nil, err
+	}
+	audits, err := client.ProjectAuditList(v["project-key"], f)
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(audits), nil
+}
+
+var projectAuditExportCmd = cli.Command{
+	Name:  "export",
+	Short: "Export changes of a project as CSV",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+	},
+	Flags: projectAuditFlags,
+}
+
+func projectAuditExportRun(v cli.Values) error {
+	f, err := projectAuditFilter(v)
+	if err != nil {
+		return err
+	}
+	return client.ProjectAuditExport(v["project-key"], f, os.Stdout)
+}
+
+func projectAuditFilter(v cli.Values) (sdk.AuditFilter, error) {
+	f := sdk.AuditFilter{
+		EntityType: v.GetString("type"),
+		EntityName: v.GetString("name"),
+		Username:   v.GetString("username"),
+	}
+
+	var err error
+	if s := v.GetString("since"); s != "" {
+		if f.Since, err = time.Parse(time.RFC3339, s); err != nil {
+			return f, fmt.Errorf("since parameter have to be a RFC3339 date")
+		}
+	}
+	if s := v.GetString("until"); s != "" {
+		if f.Until, err = time.Parse(time.RFC3339, s); err != nil {
+			return f, fmt.Errorf("until parameter have to be a RFC3339 date")
+		}
+	}
+	if s := v.GetString("limit"); s != "" {
+		if f.Limit, err = strconv.Atoi(s); err != nil {
+			return f, fmt.Errorf("limit parameter have to be an integer")
+		}
+	}
+	return f, nil
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+
+	"github.com/howeyc/gopass"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+var projectExportCmd = cli.Command{
+	Name:  "export",
+	Short: "Export a CDS project in an archive",
+	Long: `Export a project with its variables, keys, permissions, environments, applications, pipelines and workflows
+in a tar.gz archive. Secrets are encrypted with a passphrase, which is asked if it is not given.
+
+Legacy notifications of applications are not exported. Worker models are only referenced by their name.
+
+	$ cdsctl project export MYPROJ --file myproj.tar.gz`,
+	Args: []cli.Arg{
+		{Name: "project-key"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "file",
+			Usage: "Archive file, default is <project-key>.tar.gz",
+			Kind:  reflect.String,
+		},
+		{
+			Name:  "passphrase",
+			Usage: "Passphrase which encrypts the secrets",
+			Kind:  reflect.String,
+		},
+	},
+}
+
+func projectExportRun(v cli.Values) error {
+	passphrase, err := projectPassphrase(v)
+	if err != nil {
+		return err
+	}
+
+	p, err := client.ProjectExport(v["project-key"], passphrase)
+	if err != nil {
+		return err
+	}
+
+	file := v.GetString("file")
+	if file == "" {
+		file = v["project-key"] + ".tar.gz"
+	}
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := p.WriteArchive(f); err != nil {
+		return err
+	}
+
+	fmt.Printf("Project %s exported in %s: %d environments, %d applications, %d pipelines, %d workflows\n",
+		p.Key, file, len(p.Environments), len(p.Applications), len(p.Pipelines), len(p.Workflows))
+	return nil
+}
+
+var projectImportCmd = cli.Command{
+	Name:  "import",
+	Short: "Import a CDS project from an archive",
+	Long: `Import a project exported with "cdsctl project export". The project is created if it does not exist.
+
+Missing entities are created, existing ones are left untouched and reported if they differ from the archive, so an
+import can be run again. Missing groups are created with you as administrator. Worker models which do not exist are
+reported.
+
+	$ cdsctl project import myproj.tar.gz`,
+	Args: []cli.Arg{
+		{Name: "archive"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "passphrase",
+			Usage: "Passphrase used on export",
+			Kind:  reflect.String,
+		},
+	},
+}
+
+func projectImportRun(v cli.Values) error {
+	f, err := os.Open(v["archive"])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	p, err := exportentities.ReadProjectArchive(f)
+	if err != nil {
+		return err
+	}
+
+	passphrase, err := projectPassphrase(v)
+	if err != nil {
+		return err
+	}
+
+	report, err := client.ProjectImport(p, passphrase)
+	if err != nil {
+		return err
+	}
+
+	if len(report.Created) == 0 {
+		fmt.Println("Nothing created")
+	} else {
+		fmt.Println("Created:")
+		for _, c := range report.Created {
+			fmt.Printf("  - %s\n", c)
+		}
+	}
+	if len(report.Conflicts) > 0 {
+		fmt.Println("Conflicts:")
+		for _, c := range report.Conflicts {
+			fmt.Printf("  - %s\n", c)
+		}
+	}
+	return nil
+}
+
+// projectPassphrase returns the passphrase flag, or asks for it
+func projectPassphrase(v cli.Values) (string, error) {
+	if p := v.GetString("passphrase"); p != "" {
+		return p, nil
+	}
+	fmt.Printf("Passphrase: ")
+	b, err := gopass.GetPasswd()
+	if err != nil {
+		return "", err
+	}
+	if len(b) == 0 {
+		return "", fmt.Errorf("passphrase is mandatory")
+	}
+	return string(b), nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var projectMetricsCmd = cli.Command{
+	Name:  "metrics",
+	Short: "Show the delivery metrics of a CDS project and its workflows",
+	Long: `Show the deployment frequency (successful deployments by day), the lead time (from a commit to its deployment),
+the mean time to recovery and the change failure rate of a project and its workflows, on the last 90 days by default.
+
+Deployments are the runs of workflow nodes with a deployment pipeline and an environment.
+
+	$ cdsctl project metrics MYPROJ --from 2018-01-01T00:00:00Z --to 2018-04-01T00:00:00Z --environment prod`,
+	Args: []cli.Arg{
+		{Name: "project-key"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "from",
+			Usage: "Start of the period (RFC3339)",
+			Kind:  reflect.String,
+		}, {
+			Name:  "to",
+			Usage: "End of the period (RFC3339)",
+			Kind:  reflect.String,
+		}, {
+			Name:  "environment",
+			Usage: "Only deployments on this environment",
+			Kind:  reflect.String,
+		},
+	},
+}
+
+func projectMetricsRun(v cli.Values) (cli.ListResult, error) {
+	var from, to time.Time
+	var err error
+	if s := v.GetString("from"); s != "" {
+		if from, err = time.Parse(time.RFC3339, s); err != nil {
+			return nil, fmt.Errorf("from parameter have to be a RFC3339 date")
+		}
+	}
+	if s := v.GetString("to"); s != "" {
+		if to, err = time.Parse(time.RFC3339, s); err != nil {
+			return nil, fmt.Errorf("to parameter have to be a RFC3339 date")
+		}
+	}
+
+	m, err := client.ProjectDeliveryMetrics(v["project-key"], from, to, v.GetString("environment"))
+	if err != nil {
+		return nil, err
+	}
+
+	type metrics struct {
+		Workflow            string `cli:"workflow,key"`
+		Deployments         int64  `cli:"deployments"`
+		FailedDeployments   int64  `cli:"failed_deployments"`
+		DeploymentFrequency string `cli:"deployment_frequency"`
+		LeadTime            string `cli:"lead_time"`
+		MTTR                string `cli:"mttr"`
+		ChangeFailureRate   string `cli:"change_failure_rate"`
+	}
+	row := func(name string, m sdk.DeliveryMetrics) metrics {
+		return metrics{
+			Workflow:            name,
+			Deployments:         m.Deployments,
+			FailedDeployments:   m.FailedDeployments,
+			DeploymentFrequency: fmt.Sprintf("%.2f/day", m.DeploymentFrequency),
+			LeadTime:            (time.Duration(m.LeadTime) * time.Second).String(),
+			MTTR:                (time.Duration(m.MTTR) * time.Second).String(),
+			ChangeFailureRate:   fmt.Sprintf("%.1f%%", m.ChangeFailureRate*100),
+		}
+	}
+
+	res := []metrics{row("*", *m)}
+	for _, w := range m.Workflows {
+		res = append(res, row(w.WorkflowName, w))
+	}
+	return cli.AsListResult(res), nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"reflect"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	projectSecretBackendCmd = cli.Command{
+		Name:  "secret-backend",
+		Short: "Manage CDS project secret backends",
+	}
+
+	projectSecretBackend = cli.NewCommand(projectSecretBackendCmd, nil,
+		[]*cobra.Command{
+			cli.NewListCommand(projectSecretBackendListCmd, projectSecretBackendListRun, nil),
+			cli.NewCommand(projectSecretBackendAddCmd, projectSecretBackendAddRun, nil),
+			cli.NewCommand(projectSecretBackendDeleteCmd, projectSecretBackendDeleteRun, nil),
+		})
+)
+
+var projectSecretBackendListCmd = cli.Command{
+	Name:  "list",
+	Short: "List secret backends of a project",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+	},
+}
+
+func projectSecretBackendListRun(v cli.Values) (cli.ListResult, error) {
+	bs, err := client.ProjectSecretBackendList(v["project-key"])
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(bs), nil
+}
+
+var projectSecretBackendAddCmd = cli.Command{
+	Name:  "add",
+	Short: "Add a secret backend on a project. type can be vault-kv1, vault-kv2 or file. Variables of type secret_reference use it with name:path#field",
+	Long: `Add a secret backend on a project. Only administrators can add secret backends. The address of a vault backend must be
+allowed by the API configuration, and the address of a file backend is the path of a file in the secret files directory of the API.`,
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "name"},
+		{Name: "type"},
+		{Name: "address"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "token",
+			Usage: "Vault token",
+			Kind:  reflect.String,
+		},
+	},
+}
+
+func projectSecretBackendAddRun(v cli.Values) error {
+	b := &sdk.ProjectSecretBackend{
+		Name:    v["name"],
+		Type:    v["type"],
+		Address: v["address"],
+		Token:   v.GetString("token"),
+	}
+	return client.ProjectSecretBackendCreate(v["project-key"], b)
+}
+
+var projectSecretBackendDeleteCmd = cli.Command{
+	Name:  "delete",
+	Short: "Delete a secret backend of a project",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "name"},
+	},
+}
+
+func projectSecretBackendDeleteRun(v cli.Values) error {
+	return client.ProjectSecretBackendDelete(v["project-key"], v["name"])
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	userTokenCmd = cli.Command{
+		Name:  "token",
+		Short: "Manage CDS user personal access tokens",
+	}
+
+	userToken = cli.NewCommand(userTokenCmd, nil,
+		[]*cobra.Command{
+			cli.NewListCommand(userTokenListCmd, userTokenListRun, nil),
+			cli.NewGetCommand(userTokenCreateCmd, userTokenCreateRun, nil),
+			cli.NewCommand(userTokenDeleteCmd, userTokenDeleteRun, nil),
+		})
+)
+
+var userTokenListCmd = cli.Command{
+	Name:  "list",
+	Short: "List your personal access tokens",
+}
+
+func userTokenListRun(v cli.Values) (cli.ListResult, error) {
+	tokens, err := client.UserAccessTokenList(cfg.User)
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(tokens), nil
+}
+
+var userTokenCreateCmd = cli.Command{
+	Name:  "create",
+	Short: "Create a new personal access token",
+	Long:  fmt.Sprintf("Scopes is a comma separated list of: %s", strings.Join(sdk.AccessTokenScopes, ", ")),
+	Args: []cli.Arg{
+		{Name: "name"},
+		{Name: "scopes"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:    "expiration",
+			Usage:   "Number of days before the token expires",
+			Default: "30",
+			Kind:    reflect.String,
+			IsValid: func(s string) bool {
+				n, err := strconv.Atoi(s)
+				return err == nil && n > 0
+			},
+		},
+	},
+}
+
+func userTokenCreateRun(v cli.Values) (interface{}, error) {
+	days, err := strconv.Atoi(v.GetString("expiration"))
+	if err != nil {
+		return nil, err
+	}
+
+	t := &sdk.AccessToken{
+		Name:     v["name"],
+		Scopes:   strings.Split(v["scopes"], ","),
+		ExpireAt: time.Now().Add(time.Duration(days) * 24 * time.Hour),
+	}
+
+	token, err := client.UserAccessTokenCreate(cfg.User, t)
+	if err != nil {
+		return nil, err
+	}
+	return *token, nil
+}
+
+var userTokenDeleteCmd = cli.Command{
+	Name:  "delete",
+	Short: "Revoke a personal access token",
+	Args: []cli.Arg{
+		{Name: "id"},
+	},
+}
+
+func userTokenDeleteRun(v cli.Values) error {
+	id, err := strconv.ParseInt(v["id"], 10, 64)
+	if err != nil {
+		return err
+	}
+	return client.UserAccessTokenDelete(cfg.User, id)
+}
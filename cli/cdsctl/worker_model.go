@@ -1,9 +1,16 @@
 package main
 
 import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
 	"github.com/spf13/cobra"
 
 	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk/exportentities"
 )
 
 var (
@@ -15,6 +22,10 @@ var (
 	workerModel = cli.NewCommand(workerModelCmd, nil,
 		[]*cobra.Command{
 			cli.NewListCommand(workerModelListCmd, workerModelListRun, nil),
+			cli.NewCommand(workerModelExportCmd, workerModelExportRun, nil),
+			cli.NewCommand(workerModelImportCmd, workerModelImportRun, nil),
+			cli.NewListCommand(workerModelVersionsCmd, workerModelVersionsRun, nil),
+			cli.NewCommand(workerModelRollbackCmd, workerModelRollbackRun, nil),
 		})
 )
 
@@ -30,3 +41,134 @@ func workerModelListRun(v cli.Values) (cli.ListResult, error) {
 	}
 	return cli.AsListResult(workerModels), nil
 }
+
+var workerModelExportCmd = cli.Command{
+	Name:  "export",
+	Short: "Export CDS worker model",
+	Args: []cli.Arg{
+		{Name: "name"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "format",
+			Usage: "yml or json",
+			IsValid: func(s string) bool {
+				if s != "json" && s != "yml" {
+					return false
+				}
+				return true
+			},
+			Kind:    reflect.String,
+			Default: "yml",
+		},
+	},
+}
+
+func workerModelExportRun(v cli.Values) error {
+	btes, err := client.WorkerModelExport(v["name"], v["format"])
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(btes))
+	return nil
+}
+
+var workerModelImportCmd = cli.Command{
+	Name:  "import",
+	Short: "Import CDS worker model",
+	Long:  "PATH: Path or URL of worker model to import",
+	Args: []cli.Arg{
+		{Name: "path"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "force",
+			Usage: "Use force flag to update your worker model, as a new version",
+			IsValid: func(s string) bool {
+				if s != "true" && s != "false" {
+					return false
+				}
+				return true
+			},
+			Default: "false",
+			Kind:    reflect.Bool,
+		},
+	},
+}
+
+func workerModelImportRun(v cli.Values) error {
+	var btes []byte
+	var format = "yaml"
+
+	if strings.HasSuffix(v["path"], ".json") {
+		format = "json"
+	}
+
+	isURL, _ := regexp.MatchString(`http[s]?:\/\/(.*)`, v["path"])
+	if isURL {
+		var err error
+		btes, _, err = exportentities.ReadURL(v["path"], format)
+		if err != nil {
+			return err
+		}
+	} else {
+		var err error
+		btes, _, err = exportentities.ReadFile(v["path"])
+		if err != nil {
+			return err
+		}
+	}
+
+	model, err := client.WorkerModelImport(btes, format, v.GetBool("force"))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Worker model %s imported, version %d\n", model.Name, model.Version)
+	return nil
+}
+
+var workerModelVersionsCmd = cli.Command{
+	Name:  "versions",
+	Short: "List the versions of a CDS worker model",
+	Args: []cli.Arg{
+		{Name: "name"},
+	},
+}
+
+func workerModelVersionsRun(v cli.Values) (cli.ListResult, error) {
+	model, err := client.WorkerModel(v["name"])
+	if err != nil {
+		return nil, err
+	}
+	versions, err := client.WorkerModelVersions(model.ID)
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(versions), nil
+}
+
+var workerModelRollbackCmd = cli.Command{
+	Name:  "rollback",
+	Short: "Restore a version of a CDS worker model, as a new version",
+	Args: []cli.Arg{
+		{Name: "name"},
+		{Name: "version"},
+	},
+}
+
+func workerModelRollbackRun(v cli.Values) error {
+	version, err := strconv.ParseInt(v["version"], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid version %s", v["version"])
+	}
+	model, err := client.WorkerModel(v["name"])
+	if err != nil {
+		return err
+	}
+	model, err = client.WorkerModelRollback(model.ID, version)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Worker model %s restored from version %d, version %d\n", model.Name, version, model.Version)
+	return nil
+}
@@ -25,8 +25,14 @@ var (
 		[]*cobra.Command{
 			cli.NewListCommand(workflowListCmd, workflowListRun, nil),
 			cli.NewGetCommand(workflowShowCmd, workflowShowRun, nil),
+			cli.NewListCommand(workflowHistoryCmd, workflowHistoryRun, nil),
 			cli.NewCommand(workflowRunManualCmd, workflowRunManualRun, nil),
+			cli.NewCommand(workflowRestoreCmd, workflowRestoreRun, nil),
+			cli.NewCommand(workflowRollbackCmd, workflowRollbackRun, nil),
 			workflowArtifact,
+			workflowGroup,
+			workflowNotification,
+			workflowTest,
 		})
 )
 
@@ -46,6 +52,53 @@ func workflowListRun(v cli.Values) (cli.ListResult, error) {
 	return cli.AsListResult(w), nil
 }
 
+var workflowRestoreCmd = cli.Command{
+	Name:  "restore",
+	Short: "Restore the version of a workflow recorded by an entry of the project audit trail",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "workflow-name"},
+		{Name: "audit-id"},
+	},
+}
+
+func workflowRestoreRun(v cli.Values) error {
+	id, err := strconv.ParseInt(v["audit-id"], 10, 64)
+	if err != nil {
+		return fmt.Errorf("audit-id parameter have to be an integer")
+	}
+	if _, err := client.WorkflowRestore(v["project-key"], v["workflow-name"], id); err != nil {
+		return err
+	}
+	fmt.Printf("Workflow %s restored\n", v["workflow-name"])
+	return nil
+}
+
+var workflowRollbackCmd = cli.Command{
+	Name:  "rollback",
+	Short: "Deploy again the previous successful deployment of a workflow node on an environment",
+	Long: `Run again the node in the previous workflow run which deployed it successfully on the environment, with the payload,
+parameters and artifacts of this run.`,
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "workflow-name"},
+		{Name: "node-name"},
+		{Name: "environment"},
+	},
+}
+
+func workflowRollbackRun(v cli.Values) error {
+	wr, err := client.WorkflowRollback(v["project-key"], v["workflow-name"], sdk.WorkflowRollback{
+		NodeName:    v["node-name"],
+		Environment: v["environment"],
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Workflow %s #%d.%d has been started to rollback %s on %s\n", v["workflow-name"], wr.Number, wr.LastSubNumber, v["node-name"], v["environment"])
+	return nil
+}
+
 var workflowShowCmd = cli.Command{
 	Name:  "show",
 	Short: "Show a CDS workflow",
@@ -88,11 +141,17 @@ var workflowRunManualCmd = cli.Command{
 			Usage: "Node Name to relaunch; Flag run-number is mandatory",
 			Kind:  reflect.String,
 		},
+		{
+			Name:    "ignore-freeze",
+			Usage:   "Run the deployments refused by freeze windows; project owners only",
+			Default: "false",
+			Kind:    reflect.Bool,
+		},
 	},
 }
 
 func workflowRunManualRun(v cli.Values) error {
-	manual := sdk.WorkflowNodeRunManual{}
+	manual := sdk.WorkflowNodeRunManual{IgnoreFreeze: v.GetBool("ignore-freeze")}
 	if v["payload"] != "" {
 		manual.Payload = v["payload"]
 	}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+)
+
+var (
+	workflowGroupCmd = cli.Command{
+		Name:  "group",
+		Short: "Manage groups permissions on a CDS workflow",
+	}
+
+	workflowGroup = cli.NewCommand(workflowGroupCmd, nil,
+		[]*cobra.Command{
+			cli.NewListCommand(workflowGroupListCmd, workflowGroupListRun, nil),
+			cli.NewCommand(workflowGroupAddCmd, workflowGroupAddRun, nil),
+			cli.NewCommand(workflowGroupUpdateCmd, workflowGroupUpdateRun, nil),
+			cli.NewCommand(workflowGroupDeleteCmd, workflowGroupDeleteRun, nil),
+		})
+)
+
+var workflowGroupListCmd = cli.Command{
+	Name:  "list",
+	Short: "List groups permissions on a workflow",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "workflow-name"},
+	},
+}
+
+func workflowGroupListRun(v cli.Values) (cli.ListResult, error) {
+	w, err := client.WorkflowGet(v["project-key"], v["workflow-name"])
+	if err != nil {
+		return nil, err
+	}
+
+	type groupPermission struct {
+		Group      string `cli:"group"`
+		Permission int    `cli:"permission"`
+	}
+	res := make([]groupPermission, len(w.Groups))
+	for i, gp := range w.Groups {
+		res[i] = groupPermission{Group: gp.Group.Name, Permission: gp.Permission}
+	}
+	return cli.AsListResult(res), nil
+}
+
+var workflowGroupAddCmd = cli.Command{
+	Name:  "add",
+	Short: "Give permissions on a workflow to a group (4: read, 5: read and execute, 7: read, write and execute)",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "workflow-name"},
+		{Name: "group-name"},
+		{Name: "permission"},
+	},
+}
+
+func workflowGroupAddRun(v cli.Values) error {
+	perm, err := strconv.Atoi(v["permission"])
+	if err != nil {
+		return fmt.Errorf("permission parameter have to be an integer")
+	}
+	return client.WorkflowGroupAdd(v["project-key"], v["workflow-name"], v["group-name"], perm)
+}
+
+var workflowGroupUpdateCmd = cli.Command{
+	Name:  "update",
+	Short: "Update permissions of a group on a workflow (4: read, 5: read and execute, 7: read, write and execute)",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "workflow-name"},
+		{Name: "group-name"},
+		{Name: "permission"},
+	},
+}
+
+func workflowGroupUpdateRun(v cli.Values) error {
+	perm, err := strconv.Atoi(v["permission"])
+	if err != nil {
+		return fmt.Errorf("permission parameter have to be an integer")
+	}
+	return client.WorkflowGroupUpdate(v["project-key"], v["workflow-name"], v["group-name"], perm)
+}
+
+var workflowGroupDeleteCmd = cli.Command{
+	Name:  "delete",
+	Short: "Remove permissions of a group on a workflow",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "workflow-name"},
+		{Name: "group-name"},
+	},
+}
+
+func workflowGroupDeleteRun(v cli.Values) error {
+	return client.WorkflowGroupDelete(v["project-key"], v["workflow-name"], v["group-name"])
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var workflowHistoryCmd = cli.Command{
+	Name:  "history",
+	Short: "Search the runs of a CDS workflow",
+	Long: `Search the runs of a workflow, the most recent first. Examples:
+
+	cdsctl workflow history MYPROJ my-workflow --status Fail --tag git.branch=master
+	cdsctl workflow history MYPROJ my-workflow --tag environment=prod --from 2017-10-01T00:00:00Z --node deploy`,
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "workflow-name"},
+	},
+	Flags: []cli.Flag{
+		{Name: "status", Usage: "Comma separated statuses of the runs", Kind: reflect.String},
+		{Name: "tag", Usage: "Comma separated tags of the runs, as key=value", Kind: reflect.String},
+		{Name: "from", Usage: "Runs started from this date, in RFC3339", Kind: reflect.String},
+		{Name: "to", Usage: "Runs started before this date, in RFC3339", Kind: reflect.String},
+		{Name: "triggered-by", Usage: "Runs triggered by this user", Kind: reflect.String},
+		{Name: "hook", Usage: "Runs triggered by this hook model", Kind: reflect.String},
+		{Name: "node", Usage: "Runs which reached this node", Kind: reflect.String},
+		{Name: "sort", Usage: "Sort the runs by start or number", Default: sdk.WorkflowRunSortStart, Kind: reflect.String},
+		{Name: "asc", Usage: "Oldest runs first", Kind: reflect.Bool},
+		{Name: "limit", Usage: "Maximum number of runs", Default: "20", Kind: reflect.String},
+	},
+}
+
+func workflowHistoryRun(v cli.Values) (cli.ListResult, error) {
+	s := sdk.WorkflowRunSearch{
+		TriggeredBy: v.GetString("triggered-by"),
+		Hook:        v.GetString("hook"),
+		Node:        v.GetString("node"),
+		Sort:        v.GetString("sort"),
+		Ascending:   v.GetBool("asc"),
+	}
+	if status := v.GetString("status"); status != "" {
+		s.Status = strings.Split(status, ",")
+	}
+	if tags := v.GetString("tag"); tags != "" {
+		s.Tags = map[string]string{}
+		for _, tag := range strings.Split(tags, ",") {
+			kv := strings.SplitN(tag, "=", 2)
+			if len(kv) != 2 {
+				return nil, fmt.Errorf("invalid tag %s, expected key=value", tag)
+			}
+			s.Tags[kv[0]] = kv[1]
+		}
+	}
+	for flag, t := range map[string]*time.Time{"from": &s.From, "to": &s.To} {
+		if d := v.GetString(flag); d != "" {
+			var err error
+			if *t, err = time.Parse(time.RFC3339, d); err != nil {
+				return nil, fmt.Errorf("%s has to be a date in RFC3339", flag)
+			}
+		}
+	}
+	limit, err := strconv.Atoi(v.GetString("limit"))
+	if err != nil || limit <= 0 {
+		return nil, fmt.Errorf("limit has to be a positive integer")
+	}
+
+	type run struct {
+		Number int64  `cli:"number,key"`
+		Status string `cli:"status"`
+		Start  string `cli:"start"`
+		Tags   string `cli:"tags"`
+	}
+	var runs []run
+	for len(runs) < limit {
+		s.Limit = limit - len(runs)
+		res, err := client.WorkflowRunSearch(v["project-key"], v["workflow-name"], s)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range res.Runs {
+			tags := make([]string, len(r.Tags))
+			for i, t := range r.Tags {
+				tags[i] = t.Tag + "=" + t.Value
+			}
+			runs = append(runs, run{
+				Number: r.Number,
+				Status: r.Status,
+				Start:  r.Start.Format(time.RFC3339),
+				Tags:   strings.Join(tags, " "),
+			})
+		}
+		if res.NextCursor == "" {
+			break
+		}
+		s.Cursor = res.NextCursor
+	}
+	return cli.AsListResult(runs), nil
+}
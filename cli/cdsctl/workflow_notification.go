@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	workflowNotificationCmd = cli.Command{
+		Name:  "notification",
+		Short: "Manage CDS workflow notifications",
+	}
+
+	workflowNotification = cli.NewCommand(workflowNotificationCmd, nil,
+		[]*cobra.Command{
+			cli.NewListCommand(workflowNotificationListCmd, workflowNotificationListRun, nil),
+			cli.NewCommand(workflowNotificationAddCmd, workflowNotificationAddRun, nil),
+			cli.NewCommand(workflowNotificationDeleteCmd, workflowNotificationDeleteRun, nil),
+		})
+)
+
+var workflowNotificationListCmd = cli.Command{
+	Name:  "list",
+	Short: "List notifications of a workflow",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "workflow-name"},
+	},
+}
+
+func workflowNotificationListRun(v cli.Values) (cli.ListResult, error) {
+	notifs, err := client.WorkflowNotificationList(v["project-key"], v["workflow-name"])
+	if err != nil {
+		return nil, err
+	}
+
+	type notification struct {
+		ID         int64  `cli:"id,key"`
+		Type       string `cli:"type"`
+		Nodes      string `cli:"nodes"`
+		OnSuccess  string `cli:"on_success"`
+		OnFailure  string `cli:"on_failure"`
+		OnStart    bool   `cli:"on_start"`
+		Recipients string `cli:"recipients"`
+	}
+	res := make([]notification, len(notifs))
+	for i, n := range notifs {
+		res[i] = notification{
+			ID:         n.ID,
+			Type:       string(n.Type),
+			Nodes:      strings.Join(n.NodeNames, ","),
+			OnSuccess:  string(n.Settings.OnSuccess),
+			OnFailure:  string(n.Settings.OnFailure),
+			OnStart:    n.Settings.OnStart,
+			Recipients: strings.Join(n.Settings.Recipients, ","),
+		}
+	}
+	return cli.AsListResult(res), nil
+}
+
+var workflowNotificationAddCmd = cli.Command{
+	Name:  "add",
+	Short: "Add a notification on a workflow (type: email, jabber or webhook)",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "workflow-name"},
+		{Name: "type"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "nodes",
+			Usage: "Comma separated list of node names, all nodes if empty",
+			Kind:  reflect.String,
+		}, {
+			Name:  "recipients",
+			Usage: "Comma separated list of recipients, webhook URLs for webhook notifications",
+			Kind:  reflect.String,
+		}, {
+			Name:    "on-success",
+			Usage:   "always, never or change",
+			Default: string(sdk.UserNotificationChange),
+			Kind:    reflect.String,
+		}, {
+			Name:    "on-failure",
+			Usage:   "always, never or change",
+			Default: string(sdk.UserNotificationAlways),
+			Kind:    reflect.String,
+		}, {
+			Name:  "on-start",
+			Usage: "Notify when a node starts",
+			Kind:  reflect.Bool,
+		}, {
+			Name:  "send-to-groups",
+			Usage: "Notify all users allowed to read the workflow",
+			Kind:  reflect.Bool,
+		}, {
+			Name:  "send-to-author",
+			Usage: "Notify the author of the run",
+			Kind:  reflect.Bool,
+		}, {
+			Name:  "subject",
+			Usage: "Subject template, ie. {{.cds.workflow}} {{.cds.status}}",
+			Kind:  reflect.String,
+		}, {
+			Name:  "body",
+			Usage: "Body template",
+			Kind:  reflect.String,
+		},
+	},
+}
+
+func workflowNotificationAddRun(v cli.Values) error {
+	n := sdk.WorkflowNotification{
+		Type: sdk.UserNotificationSettingsType(v["type"]),
+		Settings: sdk.JabberEmailUserNotificationSettings{
+			OnSuccess:    sdk.UserNotificationEventType(v.GetString("on-success")),
+			OnFailure:    sdk.UserNotificationEventType(v.GetString("on-failure")),
+			OnStart:      v.GetBool("on-start"),
+			SendToGroups: v.GetBool("send-to-groups"),
+			SendToAuthor: v.GetBool("send-to-author"),
+			Template: sdk.UserNotificationTemplate{
+				Subject: v.GetString("subject"),
+				Body:    v.GetString("body"),
+			},
+		},
+	}
+	if nodes := v.GetString("nodes"); nodes != "" {
+		n.NodeNames = strings.Split(nodes, ",")
+	}
+	if recipients := v.GetString("recipients"); recipients != "" {
+		n.Settings.Recipients = strings.Split(recipients, ",")
+	}
+
+	if err := client.WorkflowNotificationAdd(v["project-key"], v["workflow-name"], &n); err != nil {
+		return err
+	}
+	fmt.Printf("Notification %d added\n", n.ID)
+	return nil
+}
+
+var workflowNotificationDeleteCmd = cli.Command{
+	Name:  "delete",
+	Short: "Delete a notification of a workflow",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "workflow-name"},
+		{Name: "id"},
+	},
+}
+
+func workflowNotificationDeleteRun(v cli.Values) error {
+	id, err := strconv.ParseInt(v["id"], 10, 64)
+	if err != nil {
+		return fmt.Errorf("id parameter have to be an integer")
+	}
+	return client.WorkflowNotificationDelete(v["project-key"], v["workflow-name"], id)
+}
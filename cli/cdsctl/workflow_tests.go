@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	workflowTestCmd = cli.Command{
+		Name:  "test",
+		Short: "Analyze CDS workflow test results",
+	}
+
+	workflowTest = cli.NewCommand(workflowTestCmd, nil,
+		[]*cobra.Command{
+			cli.NewListCommand(workflowTestTrendsCmd, workflowTestTrendsRun, nil),
+			cli.NewListCommand(workflowTestFlakyCmd, workflowTestFlakyRun, nil),
+			cli.NewListCommand(workflowTestQuarantineListCmd, workflowTestQuarantineListRun, nil),
+			cli.NewCommand(workflowTestQuarantineAddCmd, workflowTestQuarantineAddRun, nil),
+			cli.NewCommand(workflowTestQuarantineDeleteCmd, workflowTestQuarantineDeleteRun, nil),
+		})
+)
+
+var workflowTestDaysFlag = cli.Flag{
+	Name:    "days",
+	Usage:   "Number of days of history",
+	Default: "30",
+	Kind:    reflect.String,
+}
+
+func workflowTestDays(v cli.Values) (int, error) {
+	days, err := strconv.Atoi(v.GetString("days"))
+	if err != nil || days <= 0 {
+		return 0, fmt.Errorf("days have to be a positive integer")
+	}
+	return days, nil
+}
+
+var workflowTestTrendsCmd = cli.Command{
+	Name:  "trends",
+	Short: "Show the failure rate and the average duration of the test suites of a workflow",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "workflow-name"},
+	},
+	Flags: []cli.Flag{workflowTestDaysFlag},
+}
+
+func workflowTestTrendsRun(v cli.Values) (cli.ListResult, error) {
+	days, err := workflowTestDays(v)
+	if err != nil {
+		return nil, err
+	}
+	trends, err := client.WorkflowTestsTrends(v["project-key"], v["workflow-name"], days)
+	if err != nil {
+		return nil, err
+	}
+
+	type trend struct {
+		Suite       string `cli:"suite,key"`
+		Total       int64  `cli:"total"`
+		Failures    int64  `cli:"failures"`
+		FailureRate string `cli:"failure_rate"`
+		Duration    string `cli:"average_duration"`
+	}
+	res := make([]trend, len(trends))
+	for i, t := range trends {
+		res[i] = trend{
+			Suite:       t.Suite,
+			Total:       t.Total,
+			Failures:    t.Failures,
+			FailureRate: fmt.Sprintf("%.1f%%", t.FailureRate*100),
+			Duration:    fmt.Sprintf("%.3fs", t.AverageDuration),
+		}
+	}
+	return cli.AsListResult(res), nil
+}
+
+var workflowTestFlakyCmd = cli.Command{
+	Name:  "flaky",
+	Short: "List the tests of a workflow which both passed and failed on a same commit",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "workflow-name"},
+	},
+	Flags: []cli.Flag{workflowTestDaysFlag},
+}
+
+func workflowTestFlakyRun(v cli.Values) (cli.ListResult, error) {
+	days, err := workflowTestDays(v)
+	if err != nil {
+		return nil, err
+	}
+	flaky, err := client.WorkflowTestsFlaky(v["project-key"], v["workflow-name"], days)
+	if err != nil {
+		return nil, err
+	}
+
+	type flakyTest struct {
+		Suite       string `cli:"suite,key"`
+		Name        string `cli:"name"`
+		Commits     int64  `cli:"commits"`
+		Successes   int64  `cli:"successes"`
+		Failures    int64  `cli:"failures"`
+		LastSeen    string `cli:"last_seen"`
+		Quarantined bool   `cli:"quarantined"`
+	}
+	res := make([]flakyTest, len(flaky))
+	for i, f := range flaky {
+		res[i] = flakyTest{
+			Suite:       f.Suite,
+			Name:        f.Name,
+			Commits:     f.Commits,
+			Successes:   f.Successes,
+			Failures:    f.Failures,
+			LastSeen:    f.LastSeen.Format(time.RFC3339),
+			Quarantined: f.Quarantined,
+		}
+	}
+	return cli.AsListResult(res), nil
+}
+
+var workflowTestQuarantineListCmd = cli.Command{
+	Name:  "quarantine",
+	Short: "List the quarantined tests of a workflow",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "workflow-name"},
+	},
+}
+
+func workflowTestQuarantineListRun(v cli.Values) (cli.ListResult, error) {
+	qs, err := client.WorkflowTestsQuarantineList(v["project-key"], v["workflow-name"])
+	if err != nil {
+		return nil, err
+	}
+
+	type quarantine struct {
+		ID     int64  `cli:"id,key"`
+		Suite  string `cli:"suite"`
+		Name   string `cli:"name"`
+		Author string `cli:"author"`
+	}
+	res := make([]quarantine, len(qs))
+	for i, q := range qs {
+		res[i] = quarantine{ID: q.ID, Suite: q.Suite, Name: q.Name, Author: q.Author}
+	}
+	return cli.AsListResult(res), nil
+}
+
+var workflowTestQuarantineAddCmd = cli.Command{
+	Name:  "quarantine-add",
+	Short: "Quarantine a test: its failures do not fail the JUnit action any more",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "workflow-name"},
+		{Name: "suite"},
+		{Name: "name"},
+	},
+}
+
+func workflowTestQuarantineAddRun(v cli.Values) error {
+	q := sdk.WorkflowTestQuarantine{Suite: v["suite"], Name: v["name"]}
+	if err := client.WorkflowTestsQuarantineAdd(v["project-key"], v["workflow-name"], &q); err != nil {
+		return err
+	}
+	fmt.Printf("Test %s/%s quarantined (id %d)\n", q.Suite, q.Name, q.ID)
+	return nil
+}
+
+var workflowTestQuarantineDeleteCmd = cli.Command{
+	Name:  "quarantine-delete",
+	Short: "Remove a test from the quarantine",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "workflow-name"},
+		{Name: "id"},
+	},
+}
+
+func workflowTestQuarantineDeleteRun(v cli.Values) error {
+	id, err := strconv.ParseInt(v["id"], 10, 64)
+	if err != nil {
+		return fmt.Errorf("id parameter have to be an integer")
+	}
+	return client.WorkflowTestsQuarantineDelete(v["project-key"], v["workflow-name"], id)
+}
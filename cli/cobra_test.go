@@ -22,3 +22,13 @@ func TestListItem(t *testing.T) {
 	myResult := listItem(keyProject, nil, false, nil, false, map[string]string{})
 	assert.Equal(t, len(myResult), 3)
 }
+
+func TestValuesGetStringSlice(t *testing.T) {
+	v := Values{
+		"param": "a=1" + sliceSeparator + "b=hello world",
+		"empty": "",
+	}
+	assert.Equal(t, []string{"a=1", "b=hello world"}, v.GetStringSlice("param"))
+	assert.Nil(t, v.GetStringSlice("empty"))
+	assert.Nil(t, v.GetStringSlice("unknown"))
+}
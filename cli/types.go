@@ -20,11 +20,22 @@ type Flag struct {
 // Values represents commands flags and args values accessible with their name
 type Values map[string]string
 
+// sliceSeparator joins the values of the repeatable flags, of kind reflect.Slice
+const sliceSeparator = "\x1f"
+
 // GetString returns a string
 func (v *Values) GetString(s string) string {
 	return (*v)[s]
 }
 
+// GetStringSlice returns the values of a repeatable flag
+func (v *Values) GetStringSlice(s string) []string {
+	if (*v)[s] == "" {
+		return nil
+	}
+	return strings.Split((*v)[s], sliceSeparator)
+}
+
 // GetBool returns a string
 func (v *Values) GetBool(s string) bool {
 	return strings.ToLower((*v)[s]) == "true" || strings.ToLower((*v)[s]) == "yes" || strings.ToLower((*v)[s]) == "y" || strings.ToLower((*v)[s]) == "1"
@@ -1,6 +1,7 @@
 package action
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -10,11 +11,21 @@ import (
 	"github.com/ovh/cds/sdk/log"
 )
 
-func insertEdge(db gorp.SqlExecutor, parentID, childID int64, execOrder int, optional, alwaysExecuted, enabled bool) (int64, error) {
-	query := `INSERT INTO action_edge (parent_id, child_id, exec_order, optional, always_executed, enabled) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+func insertEdge(db gorp.SqlExecutor, parentID, childID int64, execOrder int, optional, alwaysExecuted, enabled bool, timeout int64, conditions []sdk.WorkflowTriggerCondition, image string) (int64, error) {
+	query := `INSERT INTO action_edge (parent_id, child_id, exec_order, optional, always_executed, enabled, timeout, conditions, image) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`
+
+	// Steps without conditions have null conditions
+	var conditionsJSON interface{}
+	if len(conditions) > 0 {
+		btes, err := json.Marshal(conditions)
+		if err != nil {
+			return 0, err
+		}
+		conditionsJSON = string(btes)
+	}
 
 	var id int64
-	err := db.QueryRow(query, parentID, childID, execOrder, optional, alwaysExecuted, enabled).Scan(&id)
+	err := db.QueryRow(query, parentID, childID, execOrder, optional, alwaysExecuted, enabled, timeout, conditionsJSON, image).Scan(&id)
 	if err != nil {
 		return 0, err
 	}
@@ -27,7 +38,7 @@ func insertActionChild(db gorp.SqlExecutor, actionID int64, child sdk.Action, ex
 		return fmt.Errorf("insertActionChild: child action has no id")
 	}
 
-	id, err := insertEdge(db, actionID, child.ID, execOrder, child.Optional, child.AlwaysExecuted, child.Enabled)
+	id, err := insertEdge(db, actionID, child.ID, execOrder, child.Optional, child.AlwaysExecuted, child.Enabled, child.Timeout, child.Conditions, child.Image)
 	if err != nil {
 		return err
 	}
@@ -67,7 +78,7 @@ func loadActionChildren(db gorp.SqlExecutor, actionID int64) ([]sdk.Action, erro
 	var children []sdk.Action
 	var edgeIDs []int64
 	var childrenIDs []int64
-	query := `SELECT id, child_id, exec_order, optional, always_executed, enabled FROM action_edge WHERE parent_id = $1 ORDER BY exec_order ASC`
+	query := `SELECT id, child_id, exec_order, optional, always_executed, enabled, timeout, conditions, image FROM action_edge WHERE parent_id = $1 ORDER BY exec_order ASC`
 
 	rows, err := db.Query(query, actionID)
 	if err != nil {
@@ -81,17 +92,32 @@ func loadActionChildren(db gorp.SqlExecutor, actionID int64) ([]sdk.Action, erro
 	var mapOptional = make(map[int64]bool)
 	var mapAlwaysExecuted = make(map[int64]bool)
 	var mapEnabled = make(map[int64]bool)
+	var timeout int64
+	var mapTimeout = make(map[int64]int64)
+	var conditions []byte
+	var mapConditions = make(map[int64][]sdk.WorkflowTriggerCondition)
+	var image string
+	var mapImage = make(map[int64]string)
 
 	for rows.Next() {
-		err = rows.Scan(&edgeID, &childID, &execOrder, &optional, &alwaysExecuted, &enabled)
+		err = rows.Scan(&edgeID, &childID, &execOrder, &optional, &alwaysExecuted, &enabled, &timeout, &conditions, &image)
 		if err != nil {
 			return nil, err
 		}
+		if len(conditions) > 0 {
+			var c []sdk.WorkflowTriggerCondition
+			if err := json.Unmarshal(conditions, &c); err != nil {
+				return nil, fmt.Errorf("cannot unmarshal conditions of step> %s", err)
+			}
+			mapConditions[edgeID] = c
+		}
 		edgeIDs = append(edgeIDs, edgeID)
 		childrenIDs = append(childrenIDs, childID)
 		mapOptional[edgeID] = optional
 		mapAlwaysExecuted[edgeID] = alwaysExecuted
 		mapEnabled[edgeID] = enabled
+		mapTimeout[edgeID] = timeout
+		mapImage[edgeID] = image
 	}
 	rows.Close()
 
@@ -118,6 +144,10 @@ func loadActionChildren(db gorp.SqlExecutor, actionID int64) ([]sdk.Action, erro
 		children[i].AlwaysExecuted = mapAlwaysExecuted[edgeIDs[i]]
 		// Get enable flag
 		children[i].Enabled = mapEnabled[edgeIDs[i]]
+		// Get step timeout, conditions and image
+		children[i].Timeout = mapTimeout[edgeIDs[i]]
+		children[i].Conditions = mapConditions[edgeIDs[i]]
+		children[i].Image = mapImage[edgeIDs[i]]
 	}
 
 	return children, nil
@@ -94,3 +94,27 @@ func UpdateAllRequirements(db gorp.SqlExecutor, oldValue, newValue, reqType stri
 
 	return actions, nil
 }
+
+// UpdateAllModelRequirements renames a worker model in all model requirements, including the ones pinning
+// a version of the model, ie. "oldName@3" becomes "newName@3". It returns action ID
+func UpdateAllModelRequirements(db gorp.SqlExecutor, oldName, newName string) ([]int64, error) {
+	query := `UPDATE action_requirement SET value = $1 || substr(value, length($2) + 1)
+	WHERE type = $3 AND (value = $2 OR (left(value, length($2) + 1) = $2 || $4 AND substr(value, length($2) + 2) ~ '^[0-9]+$'))
+	RETURNING action_id`
+	rows, err := db.Query(query, newName, oldName, sdk.ModelRequirement, sdk.ModelVersionSeparator)
+	if err != nil {
+		return nil, sdk.WrapError(err, "UpdateAllModelRequirements> cannot update model requirements (newName=%s, oldName=%s)", newName, oldName)
+	}
+	defer rows.Close()
+
+	var actionID int64
+	var actions = []int64{}
+	for rows.Next() {
+		if err := rows.Scan(&actionID); err != nil {
+			return nil, sdk.WrapError(err, "UpdateAllModelRequirements> unable to scan action ID")
+		}
+		actions = append(actions, actionID)
+	}
+
+	return actions, nil
+}
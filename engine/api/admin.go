@@ -3,7 +3,12 @@ package api
 import (
 	"context"
 	"net/http"
+	"sort"
 
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/sdk"
 )
 
@@ -37,3 +42,44 @@ func (api *API) deleteAdminMaintenanceHandler() Handler {
 		return nil
 	}
 }
+
+func (api *API) getAdminQueueGroupsHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		usage, err := workflow.LoadGroupQueueUsage(api.mustDB())
+		if err != nil {
+			return sdk.WrapError(err, "getAdminQueueGroupsHandler> Unable to load groups queue usage")
+		}
+
+		res := make([]sdk.GroupQueueUsage, 0, len(usage))
+		for _, u := range usage {
+			res = append(res, u)
+		}
+		sort.Slice(res, func(i, j int) bool { return res[i].GroupName < res[j].GroupName })
+		return WriteJSON(w, r, res, http.StatusOK)
+	}
+}
+
+func (api *API) putAdminQueueGroupHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		groupName := mux.Vars(r)["groupName"]
+
+		var s sdk.GroupQueueSettings
+		if err := UnmarshalBody(r, &s); err != nil {
+			return sdk.WrapError(err, "putAdminQueueGroupHandler> Cannot unmarshal request")
+		}
+		if s.Weight <= 0 || s.MaxBuilding < 0 {
+			return sdk.WrapError(sdk.ErrWrongRequest, "putAdminQueueGroupHandler> Invalid weight %d or max building %d", s.Weight, s.MaxBuilding)
+		}
+
+		g, err := group.LoadGroup(api.mustDB(), groupName)
+		if err != nil {
+			return sdk.WrapError(err, "putAdminQueueGroupHandler> Cannot load group %s", groupName)
+		}
+		s.GroupID = g.ID
+
+		if err := group.UpsertQueueSettings(api.mustDB(), s); err != nil {
+			return sdk.WrapError(err, "putAdminQueueGroupHandler> Cannot save queue settings of group %s", groupName)
+		}
+		return WriteJSON(w, r, s, http.StatusOK)
+	}
+}
@@ -35,6 +35,7 @@ import (
 	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/tracing"
 )
 
 // Configuration is the configuraton structure for CDS API
@@ -52,7 +53,11 @@ type Configuration struct {
 		Port int `toml:"port" default:"8082"`
 	} `toml:"grpc"`
 	Secrets struct {
-		Key string `toml:"key"`
+		Key      string `toml:"key"`
+		Backends struct {
+			VaultAddresses []string `toml:"vaultAddresses" comment:"Addresses of the vault servers which project secret backends can use"`
+			FileDirectory  string   `toml:"fileDirectory" comment:"Directory of the secret files which project secret backends can read. File backends are disabled if empty"`
+		} `toml:"backends" comment:"Project secret backends are registered by administrators"`
 	} `toml:"secrets"`
 	Database struct {
 		User     string `toml:"user" default:"cds"`
@@ -98,6 +103,9 @@ type Configuration struct {
 		Password string `toml:"password"`
 		From     string `toml:"from" default:"no-reply@cds.local"`
 	} `toml:"smtp" comment:"#####################n# CDS SMTP Settings \n####################"`
+	Notification struct {
+		WebhookAllowedNetworks []string `toml:"webhookAllowedNetworks" comment:"Internal networks, in CIDR notation, which webhook notifications can be sent to. Loopback, private and link-local addresses are refused otherwise"`
+	} `toml:"notification"`
 	Artifact struct {
 		Mode  string `toml:"mode" default:"local" comment:"swift or local"`
 		Local struct {
@@ -145,6 +153,7 @@ type Configuration struct {
 	Vault struct {
 		ConfigurationKey string `toml:"configurationKey"`
 	} `toml:"vault"`
+	Tracing tracing.Configuration `toml:"tracing" comment:"###########################\n CDS Distributed Tracing Settings \n##########################"`
 }
 
 // DefaultValues is the struc for API Default configuration default values
@@ -315,6 +324,18 @@ func getService(c context.Context) *sdk.Service {
 	return u
 }
 
+func getAccessToken(c context.Context) *sdk.AccessToken {
+	i := c.Value(auth.ContextAccessToken)
+	if i == nil {
+		return nil
+	}
+	t, ok := i.(*sdk.AccessToken)
+	if !ok {
+		return nil
+	}
+	return t
+}
+
 func (a *API) mustDB() *gorp.DbMap {
 	db := a.DBConnectionFactory.GetDBMap()
 	if db == nil {
@@ -331,6 +352,12 @@ func (a *API) Serve(ctx context.Context) error {
 
 	//Initialize secret driver
 	secret.Init(a.Config.Secrets.Key)
+	secret.InitBackends(a.Config.Secrets.Backends.VaultAddresses, a.Config.Secrets.Backends.FileDirectory)
+
+	//Initialize tracing
+	if err := tracing.Init(a.Config.Tracing, "cds-api"); err != nil {
+		return fmt.Errorf("cannot initialize tracing: %v", err)
+	}
 
 	//Initialize mail package
 	mail.Init(a.Config.SMTP.User,
@@ -413,8 +440,9 @@ func (a *API) Serve(ctx context.Context) error {
 	}
 
 	a.Router = &Router{
-		Mux:        mux.NewRouter(),
-		Background: ctx,
+		Mux:             mux.NewRouter(),
+		Background:      ctx,
+		RequestDuration: metrics.HTTPRequestDuration,
 	}
 	a.InitRouter()
 
@@ -446,6 +474,9 @@ func (a *API) Serve(ctx context.Context) error {
 
 	//Intialize notification package
 	notification.Init(a.Config.URL.API, a.Config.URL.UI)
+	if err := notification.InitWebhooks(a.Config.Notification.WebhookAllowedNetworks); err != nil {
+		return fmt.Errorf("unable to initialize notifications: %v", err)
+	}
 
 	// Initialize the auth driver
 	var authMode string
@@ -503,6 +534,8 @@ func (a *API) Serve(ctx context.Context) error {
 	go stats.StartRoutine(ctx, a.DBConnectionFactory.GetDBMap)
 	go action.RequirementsCacheLoader(ctx, 5*time.Second, a.DBConnectionFactory.GetDBMap, a.Cache)
 	go hookRecoverer(ctx, a.DBConnectionFactory.GetDBMap, a.Cache)
+	go jobRunWatchdog(ctx, a.DBConnectionFactory.GetDBMap, a.Cache)
+	go freezeHoldRoutine(ctx, a.DBConnectionFactory.GetDBMap, a.Cache)
 	go services.KillDeadServices(ctx, services.NewRepository(a.mustDB, a.Cache))
 
 	if !a.Config.VCS.Polling.Disabled {
@@ -533,6 +566,7 @@ func (a *API) Serve(ctx context.Context) error {
 			a.DBConnectionFactory.Close()
 			event.Publish(sdk.EventEngine{Message: "shutdown"})
 			event.Close()
+			tracing.Shutdown()
 		}
 	}()
 
@@ -30,9 +30,14 @@ func (api *API) InitRouter() {
 	r.Handle("/action/{actionName}/using", r.GET(api.getPipelinesUsingActionHandler, NeedAdmin(true)))
 	r.Handle("/action/{actionID}/audit", r.GET(api.getActionAuditHandler, NeedAdmin(true)))
 
+	// Audit
+	r.Handle("/audit", r.GET(api.getAuditHandler, NeedAdmin(true)))
+
 	// Admin
 	r.Handle("/admin/warning", r.DELETE(api.adminTruncateWarningsHandler, NeedAdmin(true)))
 	r.Handle("/admin/maintenance", r.POST(api.postAdminMaintenanceHandler, NeedAdmin(true)), r.GET(api.getAdminMaintenanceHandler, NeedAdmin(true)), r.DELETE(api.deleteAdminMaintenanceHandler, NeedAdmin(true)))
+	r.Handle("/admin/queue/groups", r.GET(api.getAdminQueueGroupsHandler, NeedAdmin(true)))
+	r.Handle("/admin/queue/groups/{groupName}", r.PUT(api.putAdminQueueGroupHandler, NeedAdmin(true)))
 
 	// Action plugin
 	r.Handle("/plugin", r.POST(api.addPluginHandler, NeedAdmin(true)), r.PUT(api.updatePluginHandler, NeedAdmin(true)))
@@ -57,6 +62,10 @@ func (api *API) InitRouter() {
 	r.ServeAbsoluteFile("/download/cds-worker-darwin-amd64", path.Join(api.Config.Directories.Download, "cds-worker-darwin-amd64"), "cds-worker-darwin-amd64")
 	r.ServeAbsoluteFile("/download/cds-engine-linux-amd64", path.Join(api.Config.Directories.Download, "cds-engine-linux-amd64"), "cds-engine-linux-amd64")
 
+	// Freeze windows
+	r.Handle("/freeze", r.GET(api.getFreezeWindowsHandler), r.POST(api.postFreezeWindowHandler, NeedAdmin(true)))
+	r.Handle("/freeze/{id}", r.DELETE(api.deleteFreezeWindowHandler, NeedAdmin(true)))
+
 	// Group
 	r.Handle("/group", r.GET(api.getGroupsHandler), r.POST(api.addGroupHandler))
 	r.Handle("/group/public", r.GET(api.getPublicGroupsHandler))
@@ -86,9 +95,18 @@ func (api *API) InitRouter() {
 
 	// Project
 	r.Handle("/project", r.GET(api.getProjectsHandler), r.POST(api.addProjectHandler))
+	r.Handle("/project/import", r.POST(api.postProjectImportHandler))
 	r.Handle("/project/{permProjectKey}", r.GET(api.getProjectHandler), r.PUT(api.updateProjectHandler), r.DELETE(api.deleteProjectHandler))
 	r.Handle("/project/{permProjectKey}/group", r.POST(api.addGroupInProjectHandler), r.PUT(api.updateGroupsInProjectHandler, DEPRECATED))
 	r.Handle("/project/{permProjectKey}/group/{group}", r.PUT(api.updateGroupRoleOnProjectHandler), r.DELETE(api.deleteGroupFromProjectHandler))
+	r.Handle("/project/{permProjectKey}/freeze", r.GET(api.getProjectFreezeWindowsHandler), r.POST(api.postProjectFreezeWindowHandler))
+	r.Handle("/project/{permProjectKey}/freeze/{id}", r.DELETE(api.deleteProjectFreezeWindowHandler))
+	r.Handle("/project/{permProjectKey}/export", r.POST(api.postProjectExportHandler))
+	r.Handle("/project/{permProjectKey}/metrics/delivery", r.GET(api.getProjectDeliveryMetricsHandler))
+	r.Handle("/project/{permProjectKey}/audit", r.GET(api.getProjectAuditHandler))
+	r.Handle("/project/{permProjectKey}/audit/{auditID}", r.GET(api.getProjectAuditEntryHandler))
+	r.Handle("/project/{permProjectKey}/secretbackend", r.GET(api.getSecretBackendsInProjectHandler), r.POST(api.addSecretBackendInProjectHandler, NeedAdmin(true)))
+	r.Handle("/project/{permProjectKey}/secretbackend/{name}", r.PUT(api.updateSecretBackendInProjectHandler, NeedAdmin(true)), r.DELETE(api.deleteSecretBackendInProjectHandler, NeedAdmin(true)))
 	r.Handle("/project/{permProjectKey}/variable", r.GET(api.getVariablesInProjectHandler), r.PUT(api.updateVariablesInProjectHandler, DEPRECATED))
 	r.Handle("/project/{key}/variable/audit", r.GET(api.getVariablesAuditInProjectnHandler))
 	r.Handle("/project/{key}/variable/audit/{auditID}", r.PUT(api.restoreProjectVariableAuditHandler, DEPRECATED))
@@ -111,6 +129,7 @@ func (api *API) InitRouter() {
 	r.Handle("/project/{key}/application/{permApplicationName}/group/{group}", r.PUT(api.updateGroupRoleOnApplicationHandler), r.DELETE(api.deleteGroupFromApplicationHandler))
 	r.Handle("/project/{key}/application/{permApplicationName}/history/branch", r.GET(api.getPipelineBuildBranchHistoryHandler))
 	r.Handle("/project/{key}/application/{permApplicationName}/history/env/deploy", r.GET(api.getApplicationDeployHistoryHandler))
+	r.Handle("/project/{key}/application/{permApplicationName}/migrate", r.POST(api.postApplicationMigrateHandler))
 	r.Handle("/project/{key}/application/{permApplicationName}/notifications", r.POST(api.addNotificationsHandler))
 	r.Handle("/project/{key}/application/{permApplicationName}/pipeline", r.GET(api.getPipelinesInApplicationHandler), r.PUT(api.updatePipelinesToApplicationHandler))
 	r.Handle("/project/{key}/application/{permApplicationName}/pipeline/attach", r.POST(api.attachPipelinesToApplicationHandler))
@@ -153,6 +172,7 @@ func (api *API) InitRouter() {
 	r.Handle("/project/{key}/pipeline/{permPipelineKey}/parameter/{name}", r.POST(api.addParameterInPipelineHandler), r.PUT(api.updateParameterInPipelineHandler), r.DELETE(api.deleteParameterFromPipelineHandler))
 	r.Handle("/project/{key}/pipeline/{permPipelineKey}", r.GET(api.getPipelineHandler), r.PUT(api.updatePipelineHandler), r.DELETE(api.deletePipelineHandler))
 	r.Handle("/project/{key}/pipeline/{permPipelineKey}/audits", r.GET(api.getPipelineAuditHandler))
+	r.Handle("/project/{key}/pipeline/{permPipelineKey}/audits/{auditID}/restore", r.POST(api.postPipelineRestoreHandler))
 	r.Handle("/project/{key}/pipeline/{permPipelineKey}/stage", r.POST(api.addStageHandler))
 	r.Handle("/project/{key}/pipeline/{permPipelineKey}/stage/move", r.POST(api.moveStageHandler))
 	r.Handle("/project/{key}/pipeline/{permPipelineKey}/stage/{stageID}", r.GET(api.getStageHandler), r.PUT(api.updateStageHandler), r.DELETE(api.deleteStageHandler))
@@ -164,16 +184,23 @@ func (api *API) InitRouter() {
 
 	r.Handle("/project/{permProjectKey}/workflows", r.POST(api.postWorkflowHandler), r.GET(api.getWorkflowsHandler))
 	r.Handle("/project/{permProjectKey}/workflows/{workflowName}", r.GET(api.getWorkflowHandler), r.PUT(api.putWorkflowHandler), r.DELETE(api.deleteWorkflowHandler))
+	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/groups", r.POST(api.postWorkflowGroupHandler))
+	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/groups/{groupName}", r.PUT(api.putWorkflowGroupHandler), r.DELETE(api.deleteWorkflowGroupHandler))
+	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/notifications", r.GET(api.getWorkflowNotificationsHandler), r.POST(api.postWorkflowNotificationHandler))
+	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/notifications/{notificationID}", r.PUT(api.putWorkflowNotificationHandler), r.DELETE(api.deleteWorkflowNotificationHandler))
+	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/audit/{auditID}/restore", r.POST(api.postWorkflowRestoreHandler))
 	// Workflows run
 	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs", r.GET(api.getWorkflowRunsHandler), r.POSTEXECUTE(api.postWorkflowRunHandler))
 	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs/latest", r.GET(api.getLatestWorkflowRunHandler))
 	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs/tags", r.GET(api.getWorkflowRunTagsHandler))
+	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs/search", r.GET(api.searchWorkflowRunsHandler))
+	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/rollback", r.POSTEXECUTE(api.postWorkflowRollbackHandler))
 	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs/{number}", r.GET(api.getWorkflowRunHandler))
-	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs/{number}/stop", r.POST(api.stopWorkflowRunHandler))
+	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs/{number}/stop", r.POSTEXECUTE(api.stopWorkflowRunHandler))
 	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs/{number}/resync", r.POST(api.resyncWorkflowRunPipelinesHandler))
 	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs/{number}/artifacts", r.GET(api.getWorkflowRunArtifactsHandler))
 	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs/{number}/nodes/{nodeRunID}", r.GET(api.getWorkflowNodeRunHandler))
-	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs/{number}/nodes/{nodeRunID}/stop", r.POST(api.stopWorkflowNodeRunHandler))
+	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs/{number}/nodes/{nodeRunID}/stop", r.POSTEXECUTE(api.stopWorkflowNodeRunHandler))
 	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs/{number}/nodes/{nodeID}/history", r.GET(api.getWorkflowNodeRunHistoryHandler))
 	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs/{number}/nodes/{nodeRunID}/job/{runJobId}/step/{stepOrder}", r.GET(api.getWorkflowNodeRunJobStepHandler))
 	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs/{number}/nodes/{nodeRunID}/artifacts", r.GET(api.getWorkflowNodeRunArtifactsHandler))
@@ -181,6 +208,12 @@ func (api *API) InitRouter() {
 	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/node/{nodeID}/triggers/condition", r.GET(api.getWorkflowTriggerConditionHandler))
 	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/join/{joinID}/triggers/condition", r.GET(api.getWorkflowTriggerJoinConditionHandler))
 	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs/{number}/nodes/{nodeRunID}/release", r.POST(api.releaseApplicationWorkflowHandler))
+	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/tests/trends", r.GET(api.getWorkflowTestsTrendsHandler))
+	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/tests/flaky", r.GET(api.getWorkflowTestsFlakyHandler))
+	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/tests/history", r.GET(api.getWorkflowTestHistoryHandler))
+	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/tests/quarantine", r.GET(api.getWorkflowTestsQuarantineHandler), r.POST(api.postWorkflowTestsQuarantineHandler))
+	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/tests/quarantine/{quarantineID}", r.DELETE(api.deleteWorkflowTestsQuarantineHandler))
+	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/metrics/delivery", r.GET(api.getWorkflowDeliveryMetricsHandler))
 
 	// DEPRECATED
 	r.Handle("/project/{key}/pipeline/{permPipelineKey}/action/{jobID}", r.PUT(api.updatePipelineActionHandler, DEPRECATED), r.DELETE(api.deleteJobHandler))
@@ -202,6 +235,8 @@ func (api *API) InitRouter() {
 	r.Handle("/project/{key}/environment/{permEnvironmentName}/keys", r.GET(api.getKeysInEnvironmentHandler), r.POST(api.addKeyInEnvironmentHandler))
 	r.Handle("/project/{key}/environment/{permEnvironmentName}/keys/{name}", r.DELETE(api.deleteKeyInEnvironmentHandler))
 	r.Handle("/project/{key}/environment/{permEnvironmentName}/clone/{cloneName}", r.POST(api.cloneEnvironmentHandler))
+	r.Handle("/project/{key}/environment/{permEnvironmentName}/deployment", r.GET(api.getEnvironmentDeploymentsHandler))
+	r.Handle("/project/{key}/environment/{permEnvironmentName}/deployment/promote", r.POST(api.postEnvironmentPromoteHandler))
 	r.Handle("/project/{key}/environment/{permEnvironmentName}/audit", r.GET(api.getEnvironmentsAuditHandler, DEPRECATED))
 	r.Handle("/project/{key}/environment/{permEnvironmentName}/audit/{auditID}", r.PUT(api.restoreEnvironmentAuditHandler, DEPRECATED))
 	r.Handle("/project/{key}/environment/{permEnvironmentName}/group", r.POST(api.addGroupInEnvironmentHandler))
@@ -247,6 +282,7 @@ func (api *API) InitRouter() {
 	r.Handle("/queue/workflows/{permID}/result", r.POSTEXECUTE(api.postWorkflowJobResultHandler, NeedWorker()))
 	r.Handle("/queue/workflows/{permID}/log", r.POSTEXECUTE(api.postWorkflowJobLogsHandler, NeedWorker()))
 	r.Handle("/queue/workflows/{permID}/test", r.POSTEXECUTE(api.postWorkflowJobTestsResultsHandler, NeedWorker()))
+	r.Handle("/queue/workflows/{id}/test/quarantine", r.GET(api.getWorkflowJobTestsQuarantineHandler, NeedWorker()))
 	r.Handle("/queue/workflows/{permID}/variable", r.POSTEXECUTE(api.postWorkflowJobVariableHandler, NeedWorker()))
 	r.Handle("/queue/workflows/{permID}/step", r.POSTEXECUTE(api.postWorkflowJobStepStatusHandler, NeedWorker()))
 	r.Handle("/queue/workflows/{permID}/artifact/{tag}", r.POSTEXECUTE(api.postWorkflowJobArtifactHandler, NeedWorker()))
@@ -256,6 +292,7 @@ func (api *API) InitRouter() {
 	r.Handle("/pipeline/type", r.GET(api.getPipelineTypeHandler))
 	r.Handle("/notification/type", r.GET(api.getUserNotificationTypeHandler))
 	r.Handle("/notification/state", r.GET(api.getUserNotificationStateValueHandler))
+	r.Handle("/notification/workflow/type", r.GET(api.getWorkflowNotificationTypesHandler))
 
 	// RepositoriesManager
 	r.Handle("/repositories_manager", r.GET(api.getRepositoriesManagerHandler))
@@ -297,6 +334,8 @@ func (api *API) InitRouter() {
 	r.Handle("/user/import", r.POST(api.importUsersHandler, NeedAdmin(true)))
 	r.Handle("/user/{username}", r.GET(api.getUserHandler, NeedUsernameOrAdmin(true)), r.PUT(api.updateUserHandler, NeedUsernameOrAdmin(true)), r.DELETE(api.deleteUserHandler, NeedUsernameOrAdmin(true)))
 	r.Handle("/user/{username}/groups", r.GET(api.getUserGroupsHandler, NeedUsernameOrAdmin(true)))
+	r.Handle("/user/{username}/token", r.GET(api.getUserAccessTokensHandler, NeedUsernameOrAdmin(true)), r.POST(api.postUserAccessTokenHandler, NeedUsernameOrAdmin(true)))
+	r.Handle("/user/{username}/token/{tokenID}", r.DELETE(api.deleteUserAccessTokenHandler, NeedUsernameOrAdmin(true)))
 	r.Handle("/user/{username}/confirm/{token}", r.GET(api.confirmUserHandler, Auth(false)))
 	r.Handle("/user/{username}/reset", r.POST(api.resetUserHandler, Auth(false)))
 	r.Handle("/auth/mode", r.GET(api.authModeHandler, Auth(false)))
@@ -315,11 +354,16 @@ func (api *API) InitRouter() {
 	r.Handle("/worker/model/enabled", r.GET(api.getWorkerModelsEnabledHandler))
 	r.Handle("/worker/model/type", r.GET(api.getWorkerModelTypesHandler))
 	r.Handle("/worker/model/communication", r.GET(api.getWorkerModelCommunicationsHandler))
+	r.Handle("/worker/model/import", r.POST(api.importWorkerModelHandler))
 	r.Handle("/worker/model/{permModelID}", r.PUT(api.updateWorkerModelHandler), r.DELETE(api.deleteWorkerModelHandler))
+	r.Handle("/worker/model/{permModelID}/version", r.GET(api.getWorkerModelVersionsHandler))
+	r.Handle("/worker/model/{permModelID}/version/{version}", r.GET(api.getWorkerModelVersionHandler))
+	r.Handle("/worker/model/{permModelID}/version/{version}/rollback", r.POST(api.postWorkerModelRollbackHandler))
 	r.Handle("/worker/model/capability/type", r.GET(api.getWorkerModelCapaTypesHandler))
 
 	// Workflows
 	r.Handle("/workflow/hook", r.GET(api.getWorkflowHooksHandler, NeedService()))
+	r.Handle("/workflow/hook/freeze/{key}/{workflow}", r.GET(api.getWorkflowHookFreezeWindowsHandler, NeedService()))
 	r.Handle("/workflow/hook/model", r.GET(api.getWorkflowHookModelsHandler))
 	r.Handle("/workflow/hook/model/{model}", r.GET(api.getWorkflowHookModelHandler), r.POST(api.postWorkflowHookModelHandler, NeedAdmin(true)), r.PUT(api.putWorkflowHookModelHandler, NeedAdmin(true)))
 
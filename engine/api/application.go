@@ -11,6 +11,7 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/environment"
 	"github.com/ovh/cds/engine/api/group"
@@ -437,6 +438,9 @@ func (api *API) addApplicationHandler() Handler {
 			return sdk.WrapError(err, "addApplicationHandler> Cannot add groups on application")
 		}
 
+		if err := audit.Record(tx, getUser(ctx), key, sdk.AuditApplication, app.Name, audit.Added, nil, &app); err != nil {
+			return sdk.WrapError(err, "addApplicationHandler> Cannot record audit")
+		}
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "addApplicationHandler> Cannot commit transaction")
 		}
@@ -488,6 +492,9 @@ func (api *API) deleteApplicationHandler() Handler {
 			return sdk.WrapError(err, "deleteApplicationHandler> Cannot update project last modified date")
 		}
 
+		if err := audit.Record(tx, getUser(ctx), projectKey, sdk.AuditApplication, app.Name, audit.Deleted, app, nil); err != nil {
+			return sdk.WrapError(err, "deleteApplicationHandler> Cannot record audit")
+		}
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "deleteApplicationHandler> Cannot commit transaction")
 		}
@@ -671,6 +678,8 @@ func (api *API) updateApplicationHandler() Handler {
 			return sdk.WrapError(sdk.ErrInvalidApplicationPattern, "updateApplicationHandler> Application name %s do not respect pattern %s", appPost.Name, sdk.NamePattern)
 		}
 
+		before := *app
+
 		//Update name and Metadata
 		app.Name = appPost.Name
 		app.Metadata = appPost.Metadata
@@ -684,6 +693,10 @@ func (api *API) updateApplicationHandler() Handler {
 			return sdk.WrapError(err, "updateApplicationHandler> Cannot delete application %s", applicationName)
 		}
 
+		if err := audit.Record(tx, getUser(ctx), projectKey, sdk.AuditApplication, applicationName, audit.Updated, &before, app); err != nil {
+			return sdk.WrapError(err, "updateApplicationHandler> Cannot record audit")
+		}
+
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "updateApplicationHandler> Cannot commit transaction")
 		}
@@ -8,6 +8,7 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/keys"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
@@ -59,6 +60,9 @@ func (api *API) deleteKeyInApplicationHandler() Handler {
 				if err := application.UpdateLastModified(tx, api.Cache, app, getUser(ctx)); err != nil {
 					return sdk.WrapError(err, "deleteKeyInApplicationHandler> Cannot update application last modified date")
 				}
+				if err := audit.Record(tx, getUser(ctx), key, sdk.AuditKey, appName+"/"+keyName, audit.Deleted, k, nil); err != nil {
+					return sdk.WrapError(err, "deleteKeyInApplicationHandler> Cannot record audit")
+				}
 			}
 		}
 
@@ -127,6 +131,10 @@ func (api *API) addKeyInApplicationHandler() Handler {
 			return sdk.WrapError(err, "addKeyInApplicationHandler> Cannot update project last modified date")
 		}
 
+		if err := audit.Record(tx, getUser(ctx), key, sdk.AuditKey, appName+"/"+newKey.Name, audit.Added, nil, &newKey); err != nil {
+			return sdk.WrapError(err, "addKeyInApplicationHandler> Cannot record audit")
+		}
+
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "addKeyInApplicationHandler> Cannot commit transaction")
 		}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/audit"
+	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/api/workflowv0"
+	"github.com/ovh/cds/sdk"
+)
+
+// postApplicationMigrateHandler converts the pipelines and triggers of an application to workflows. With
+// dryRun, the workflows are only returned. With disableLegacy, the migrated triggers, pollers and schedulers
+// are disabled, repository hooks are kept until their webhook is replaced
+func (api *API) postApplicationMigrateHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		appName := vars["permApplicationName"]
+		dryRun := FormBool(r, "dryRun")
+		disableLegacy := FormBool(r, "disableLegacy")
+
+		if permission.ProjectPermission(key, getUser(ctx)) < permission.PermissionReadWriteExecute {
+			return sdk.WrapError(sdk.ErrForbidden, "postApplicationMigrateHandler> Cannot create workflows in project %s", key)
+		}
+
+		p, errP := project.Load(api.mustDB(), api.Cache, key, getUser(ctx), project.LoadOptions.WithApplications, project.LoadOptions.WithPipelines, project.LoadOptions.WithEnvironments)
+		if errP != nil {
+			return sdk.WrapError(errP, "postApplicationMigrateHandler> Cannot load project %s", key)
+		}
+
+		app, errA := application.LoadByName(api.mustDB(), api.Cache, key, appName, getUser(ctx), application.LoadOptions.WithPipelines)
+		if errA != nil {
+			return sdk.WrapError(errA, "postApplicationMigrateHandler> Cannot load application %s", appName)
+		}
+
+		trees, errT := workflowv0.LoadCDTree(api.mustDB(), api.Cache, key, appName, getUser(ctx), "", "", 0)
+		if errT != nil {
+			return sdk.WrapError(errT, "postApplicationMigrateHandler> Cannot load CD tree of application %s", appName)
+		}
+
+		workflows, errW := workflow.LoadAll(api.mustDB(), key)
+		if errW != nil {
+			return sdk.WrapError(errW, "postApplicationMigrateHandler> Cannot load workflows")
+		}
+
+		report, legacy := workflowv0.Migrate(p, app, trees, workflows)
+		report.DryRun = dryRun
+		if dryRun {
+			return WriteJSON(w, r, report, http.StatusOK)
+		}
+
+		tx, errTx := api.mustDB().Begin()
+		if errTx != nil {
+			return sdk.WrapError(errTx, "postApplicationMigrateHandler> Cannot start transaction")
+		}
+		defer tx.Rollback()
+
+		for i := range report.Workflows {
+			wf := &report.Workflows[i]
+			if err := workflow.Insert(tx, api.Cache, wf, p, getUser(ctx)); err != nil {
+				return sdk.WrapError(err, "postApplicationMigrateHandler> Cannot insert workflow %s", wf.Name)
+			}
+			if err := audit.Record(tx, getUser(ctx), key, sdk.AuditWorkflow, wf.Name, audit.Added, nil, wf); err != nil {
+				return sdk.WrapError(err, "postApplicationMigrateHandler> Cannot record audit")
+			}
+		}
+
+		if disableLegacy {
+			if err := workflowv0.DisableLegacy(tx, legacy); err != nil {
+				return sdk.WrapError(err, "postApplicationMigrateHandler> Cannot disable legacy triggers")
+			}
+			report.LegacyDisabled = true
+		}
+
+		if err := project.UpdateLastModified(tx, api.Cache, getUser(ctx), p); err != nil {
+			return sdk.WrapError(err, "postApplicationMigrateHandler> Cannot update project last modified date")
+		}
+
+		for i := range report.Workflows {
+			wf := &report.Workflows[i]
+			if err := api.pushWorkflowHooks(wf, wf.Name); err != nil {
+				return sdk.WrapError(err, "postApplicationMigrateHandler> Unable to push hooks of workflow %s", wf.Name)
+			}
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "postApplicationMigrateHandler> Cannot commit transaction")
+		}
+
+		for i := range report.Workflows {
+			wf := &report.Workflows[i]
+			//We filter project and workflow configurtaion key, because they are always set on insertHooks
+			wf.FilterHooksConfig("project", "workflow")
+		}
+
+		return WriteJSON(w, r, report, http.StatusOK)
+	}
+}
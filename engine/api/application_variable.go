@@ -8,6 +8,7 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/api/sanity"
 	"github.com/ovh/cds/engine/api/secret"
@@ -197,6 +198,10 @@ func (api *API) deleteVariableFromApplicationHandler() Handler {
 			return sdk.WrapError(err, "deleteVariableFromApplicationHandler> Cannot delete %s", varName)
 		}
 
+		if err := audit.Record(tx, getUser(ctx), key, sdk.AuditVariable, appName+"/"+varName, audit.Deleted, varToDelete, nil); err != nil {
+			return sdk.WrapError(err, "deleteVariableFromApplicationHandler> Cannot record audit")
+		}
+
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "deleteVariableFromApplicationHandler> Cannot commit transaction")
 		}
@@ -343,10 +348,18 @@ func (api *API) updateVariableInApplicationHandler() Handler {
 		}
 		defer tx.Rollback()
 
+		oldVar, errV := application.LoadVariable(tx, app.ID, varName)
+		if errV != nil {
+			return sdk.WrapError(errV, "updateVariableInApplicationHandler> Cannot load variable %s", varName)
+		}
 		if err := application.UpdateVariable(tx, api.Cache, app, &newVar, getUser(ctx)); err != nil {
 			return sdk.WrapError(err, "updateVariableInApplicationHandler> Cannot update variable %s for application %s", varName, appName)
 		}
 
+		if err := audit.Record(tx, getUser(ctx), key, sdk.AuditVariable, appName+"/"+varName, audit.Updated, oldVar, &newVar); err != nil {
+			return sdk.WrapError(err, "updateVariableInApplicationHandler> Cannot record audit")
+		}
+
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "updateVariableInApplicationHandler> Cannot commit transaction")
 		}
@@ -413,6 +426,10 @@ func (api *API) addVariableInApplicationHandler() Handler {
 			return sdk.WrapError(err, "addVariableInApplicationHandler> Cannot add variable %s in application %s", varName, appName)
 		}
 
+		if err := audit.Record(tx, getUser(ctx), key, sdk.AuditVariable, appName+"/"+varName, audit.Added, nil, &newVar); err != nil {
+			return sdk.WrapError(err, "addVariableInApplicationHandler> Cannot record audit")
+		}
+
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "addVariableInApplicationHandler> Cannot commit transaction")
 		}
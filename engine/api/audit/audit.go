@@ -0,0 +1,177 @@
+package audit
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/secret"
+	"github.com/ovh/cds/sdk"
+)
+
+// auditColumns are the columns mapped on sdk.Audit, the JSON values are loaded by PostGet
+const auditColumns = "id, created, username, project_key, entity_type, entity_name, action"
+
+// restorableTypes are the types of the entities which can be restored from the audit trail. An encrypted
+// snapshot of their version after the change, with their secrets, is recorded with the audit entry
+var restorableTypes = map[string]bool{
+	sdk.AuditWorkflow: true,
+}
+
+// Record inserts an entry in the audit trail. before and after are the versions of the entity
+// before and after the change, nil for additions and deletions. Secrets are masked, see LoadSnapshot
+func Record(db gorp.SqlExecutor, u *sdk.User, projectKey, entityType, entityName, action string, before, after interface{}) error {
+	a := sdk.Audit{
+		Created:    time.Now(),
+		ProjectKey: projectKey,
+		EntityType: entityType,
+		EntityName: entityName,
+		Action:     action,
+	}
+	if u != nil {
+		a.Username = u.Username
+	}
+
+	var err error
+	if a.Before, err = marshal(before); err != nil {
+		return sdk.WrapError(err, "audit.Record> Unable to marshal %s %s", entityType, entityName)
+	}
+	if a.After, err = marshal(after); err != nil {
+		return sdk.WrapError(err, "audit.Record> Unable to marshal %s %s", entityType, entityName)
+	}
+
+	dba := dbAudit(a)
+	if err := db.Insert(&dba); err != nil {
+		return sdk.WrapError(err, "audit.Record> Unable to insert audit on %s %s", entityType, entityName)
+	}
+
+	if !restorableTypes[entityType] || len(a.After) == 0 {
+		return nil
+	}
+	btes, err := json.Marshal(after)
+	if err != nil {
+		return sdk.WrapError(err, "audit.Record> Unable to marshal %s %s", entityType, entityName)
+	}
+	snapshot, err := secret.Encrypt(btes)
+	if err != nil {
+		return sdk.WrapError(err, "audit.Record> Unable to encrypt snapshot of %s %s", entityType, entityName)
+	}
+	if _, err := db.Exec("update audit set snapshot = $2 where id = $1", dba.ID, snapshot); err != nil {
+		return sdk.WrapError(err, "audit.Record> Unable to insert snapshot of %s %s", entityType, entityName)
+	}
+	return nil
+}
+
+// LoadSnapshot loads the version of an entity after the change recorded by an audit entry, secrets
+// included. Only restorable entities have a snapshot
+func LoadSnapshot(db gorp.SqlExecutor, projectKey string, id int64) (json.RawMessage, error) {
+	var snapshot []byte
+	if err := db.QueryRow("select snapshot from audit where project_key = $1 and id = $2", projectKey, id).Scan(&snapshot); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.ErrAuditNotFound
+		}
+		return nil, sdk.WrapError(err, "audit.LoadSnapshot> Unable to load audit %d", id)
+	}
+	if len(snapshot) == 0 {
+		return nil, sdk.WrapError(sdk.ErrWrongRequest, "audit.LoadSnapshot> Audit %d has no snapshot", id)
+	}
+	btes, err := secret.Decrypt(snapshot)
+	if err != nil {
+		return nil, sdk.WrapError(err, "audit.LoadSnapshot> Unable to decrypt snapshot of audit %d", id)
+	}
+	return json.RawMessage(btes), nil
+}
+
+// LoadAll loads audit entries matching the filter, most recent first
+func LoadAll(db gorp.SqlExecutor, f sdk.AuditFilter) ([]sdk.Audit, error) {
+	clauses := []string{"1 = 1"}
+	args := []interface{}{}
+	var addClause = func(clause string, arg interface{}) {
+		args = append(args, arg)
+		clauses = append(clauses, fmt.Sprintf(clause, len(args)))
+	}
+
+	if f.ProjectKey != "" {
+		addClause("project_key = $%d", f.ProjectKey)
+	}
+	if f.EntityType != "" {
+		addClause("entity_type = $%d", f.EntityType)
+	}
+	if f.EntityName != "" {
+		addClause("entity_name = $%d", f.EntityName)
+	}
+	if f.Username != "" {
+		addClause("username = $%d", f.Username)
+	}
+	if !f.Since.IsZero() {
+		addClause("created >= $%d", f.Since)
+	}
+	if !f.Until.IsZero() {
+		addClause("created <= $%d", f.Until)
+	}
+	if f.Limit <= 0 || f.Limit > 1000 {
+		f.Limit = 100
+	}
+
+	query := fmt.Sprintf("select "+auditColumns+" from audit where %s order by created desc, id desc offset %d limit %d", strings.Join(clauses, " and "), f.Offset, f.Limit)
+	dbas := []dbAudit{}
+	if _, err := db.Select(&dbas, query, args...); err != nil {
+		return nil, sdk.WrapError(err, "audit.LoadAll> Unable to load audit")
+	}
+
+	audits := make([]sdk.Audit, len(dbas))
+	for i := range dbas {
+		audits[i] = sdk.Audit(dbas[i])
+	}
+	return audits, nil
+}
+
+// LoadByID loads an audit entry of a project
+func LoadByID(db gorp.SqlExecutor, projectKey string, id int64) (*sdk.Audit, error) {
+	dba := dbAudit{}
+	if err := db.SelectOne(&dba, "select "+auditColumns+" from audit where project_key = $1 and id = $2", projectKey, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.ErrAuditNotFound
+		}
+		return nil, sdk.WrapError(err, "audit.LoadByID> Unable to load audit %d", id)
+	}
+	a := sdk.Audit(dba)
+	return &a, nil
+}
+
+// PostInsert is a db hook
+func (a *dbAudit) PostInsert(db gorp.SqlExecutor) error {
+	if _, err := db.Exec("update audit set before = $2, after = $3 where id = $1", a.ID, nullJSON(a.Before), nullJSON(a.After)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// PostGet is a db hook
+func (a *dbAudit) PostGet(db gorp.SqlExecutor) error {
+	var before, after sql.NullString
+	if err := db.QueryRow("select before, after from audit where id = $1", a.ID).Scan(&before, &after); err != nil {
+		return err
+	}
+	if before.Valid {
+		a.Before = json.RawMessage(before.String)
+	}
+	if after.Valid {
+		a.After = json.RawMessage(after.String)
+	}
+
+	var err error
+	a.Diff, err = Diff(a.Before, a.After)
+	return err
+}
+
+func nullJSON(m json.RawMessage) sql.NullString {
+	if len(m) == 0 {
+		return sql.NullString{}
+	}
+	return sql.NullString{Valid: true, String: string(m)}
+}
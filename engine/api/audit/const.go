@@ -2,7 +2,8 @@ package audit
 
 // Audit constants describing event
 const (
-	Added   = "added"
-	Deleted = "deleted"
-	Updated = "updated"
+	Added    = "added"
+	Deleted  = "deleted"
+	Updated  = "updated"
+	Restored = "restored"
 )
@@ -0,0 +1,116 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// marshal returns the JSON value of v with secrets masked, nil if v is nil
+func marshal(v interface{}) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var i interface{}
+	if err := json.Unmarshal(b, &i); err != nil {
+		return nil, err
+	}
+	maskSecrets(i)
+	return json.Marshal(i)
+}
+
+// maskSecrets replaces values of password and key variables, and private keys, by a placeholder
+func maskSecrets(i interface{}) {
+	switch v := i.(type) {
+	case map[string]interface{}:
+		if t, ok := v["type"].(string); ok && sdk.NeedPlaceholder(t) {
+			if _, ok := v["value"]; ok {
+				v["value"] = sdk.PasswordPlaceholder
+			}
+		}
+		if p, ok := v["private"].(string); ok && p != "" {
+			v["private"] = sdk.PasswordPlaceholder
+		}
+		for _, child := range v {
+			maskSecrets(child)
+		}
+	case []interface{}:
+		for _, child := range v {
+			maskSecrets(child)
+		}
+	}
+}
+
+// Diff returns the values changed between two JSON documents. There is no diff if one of them is empty
+func Diff(before, after json.RawMessage) ([]sdk.AuditDiff, error) {
+	if len(before) == 0 || len(after) == 0 {
+		return nil, nil
+	}
+
+	var b, a interface{}
+	if err := json.Unmarshal(before, &b); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(after, &a); err != nil {
+		return nil, err
+	}
+
+	diffs := []sdk.AuditDiff{}
+	diff("", b, a, &diffs)
+	return diffs, nil
+}
+
+func diff(path string, before, after interface{}, diffs *[]sdk.AuditDiff) {
+	switch b := before.(type) {
+	case map[string]interface{}:
+		if a, ok := after.(map[string]interface{}); ok {
+			keys := []string{}
+			for k := range b {
+				keys = append(keys, k)
+			}
+			for k := range a {
+				if _, ok := b[k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				p := k
+				if path != "" {
+					p = path + "." + k
+				}
+				diff(p, b[k], a[k], diffs)
+			}
+			return
+		}
+	case []interface{}:
+		if a, ok := after.([]interface{}); ok {
+			for i := 0; i < len(a) || i < len(b); i++ {
+				var bi, ai interface{}
+				if i < len(b) {
+					bi = b[i]
+				}
+				if i < len(a) {
+					ai = a[i]
+				}
+				diff(fmt.Sprintf("%s[%d]", path, i), bi, ai, diffs)
+			}
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(before, after) {
+		*diffs = append(*diffs, sdk.AuditDiff{Path: path, Before: before, After: after})
+	}
+}
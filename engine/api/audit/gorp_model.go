@@ -0,0 +1,12 @@
+package audit
+
+import (
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+type dbAudit sdk.Audit
+
+func init() {
+	gorpmapping.Register(gorpmapping.New(dbAudit{}, "audit", true, "id"))
+}
@@ -0,0 +1,338 @@
+package api
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/audit"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+// getAuditHandler returns the audit trail of all entities. Only for admins
+func (api *API) getAuditHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		f, err := auditFilterFromRequest(r)
+		if err != nil {
+			return sdk.WrapError(err, "getAuditHandler> Invalid filter")
+		}
+		f.ProjectKey = r.FormValue("project")
+
+		audits, err := audit.LoadAll(api.mustDB(), f)
+		if err != nil {
+			return sdk.WrapError(err, "getAuditHandler> Cannot load audit")
+		}
+		return writeAudits(w, r, audits)
+	}
+}
+
+// getProjectAuditHandler returns the audit trail of a project
+func (api *API) getProjectAuditHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["permProjectKey"]
+
+		f, err := auditFilterFromRequest(r)
+		if err != nil {
+			return sdk.WrapError(err, "getProjectAuditHandler> Invalid filter")
+		}
+		f.ProjectKey = key
+
+		audits, err := audit.LoadAll(api.mustDB(), f)
+		if err != nil {
+			return sdk.WrapError(err, "getProjectAuditHandler> Cannot load audit of project %s", key)
+		}
+		return writeAudits(w, r, audits)
+	}
+}
+
+// getProjectAuditEntryHandler returns an entry of the audit trail of a project, with before and after values
+func (api *API) getProjectAuditEntryHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["permProjectKey"]
+
+		id, err := requestVarInt(r, "auditID")
+		if err != nil {
+			return err
+		}
+
+		a, err := audit.LoadByID(api.mustDB(), key, id)
+		if err != nil {
+			return sdk.WrapError(err, "getProjectAuditEntryHandler> Cannot load audit %d", id)
+		}
+		return WriteJSON(w, r, a, http.StatusOK)
+	}
+}
+
+// postWorkflowRestoreHandler restores the version of a workflow recorded by an audit entry
+func (api *API) postWorkflowRestoreHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["permProjectKey"]
+		name := vars["workflowName"]
+
+		id, err := requestVarInt(r, "auditID")
+		if err != nil {
+			return err
+		}
+
+		a, err := audit.LoadByID(api.mustDB(), key, id)
+		if err != nil {
+			return sdk.WrapError(err, "postWorkflowRestoreHandler> Cannot load audit %d", id)
+		}
+		if a.EntityType != sdk.AuditWorkflow || a.EntityName != name || len(a.After) == 0 {
+			return sdk.WrapError(sdk.ErrWrongRequest, "postWorkflowRestoreHandler> Audit %d is not a version of workflow %s", id, name)
+		}
+
+		p, err := project.Load(api.mustDB(), api.Cache, key, getUser(ctx), project.LoadOptions.WithApplications, project.LoadOptions.WithPipelines, project.LoadOptions.WithEnvironments)
+		if err != nil {
+			return sdk.WrapError(err, "postWorkflowRestoreHandler> Cannot load project %s", key)
+		}
+
+		oldW, err := workflow.Load(api.mustDB(), api.Cache, key, name, getUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "postWorkflowRestoreHandler> Cannot load workflow %s", name)
+		}
+
+		// The audit entry has masked secrets, the workflow is restored from its encrypted snapshot
+		snapshot, err := audit.LoadSnapshot(api.mustDB(), key, id)
+		if err != nil {
+			return sdk.WrapError(err, "postWorkflowRestoreHandler> Cannot load snapshot of audit %d", id)
+		}
+		var wf sdk.Workflow
+		if err := json.Unmarshal(snapshot, &wf); err != nil {
+			return sdk.WrapError(err, "postWorkflowRestoreHandler> Cannot read workflow from audit %d", id)
+		}
+		if wf.Root == nil {
+			return sdk.WrapError(sdk.ErrWrongRequest, "postWorkflowRestoreHandler> Audit %d has no root node", id)
+		}
+		resetWorkflowIDs(&wf)
+		wf.ID = oldW.ID
+		wf.ProjectID = p.ID
+		wf.ProjectKey = key
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WrapError(err, "postWorkflowRestoreHandler> Cannot start transaction")
+		}
+		defer tx.Rollback()
+
+		if err := workflow.Update(tx, api.Cache, &wf, oldW, p, getUser(ctx)); err != nil {
+			return sdk.WrapError(err, "postWorkflowRestoreHandler> Cannot restore workflow")
+		}
+
+		if err := project.UpdateLastModified(tx, api.Cache, getUser(ctx), p); err != nil {
+			return sdk.WrapError(err, "postWorkflowRestoreHandler> Cannot update project last modified date")
+		}
+
+		if err := audit.Record(tx, getUser(ctx), key, sdk.AuditWorkflow, wf.Name, audit.Restored, oldW, &wf); err != nil {
+			return sdk.WrapError(err, "postWorkflowRestoreHandler> Cannot record audit")
+		}
+
+		if err := api.pushWorkflowHooks(&wf, name); err != nil {
+			return sdk.WrapError(err, "postWorkflowRestoreHandler> Unable to push hooks")
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "postWorkflowRestoreHandler> Cannot commit transaction")
+		}
+
+		wf1, err := workflow.LoadByID(api.mustDB(), api.Cache, wf.ID, getUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "postWorkflowRestoreHandler> Cannot load workflow")
+		}
+		wf1.FilterHooksConfig("project", "workflow")
+
+		return WriteJSON(w, r, wf1, http.StatusOK)
+	}
+}
+
+// resetWorkflowIDs clears the ids of the nodes, hooks, triggers and joins of a recorded workflow, which
+// are inserted again when it is restored. Joins reference their sources by the refs of the nodes
+func resetWorkflowIDs(w *sdk.Workflow) {
+	w.RootID = 0
+	resetWorkflowNodeIDs(w.Root)
+	for i := range w.Joins {
+		j := &w.Joins[i]
+		if len(j.SourceNodeRefs) == 0 {
+			for _, id := range j.SourceNodeIDs {
+				j.SourceNodeRefs = append(j.SourceNodeRefs, strconv.FormatInt(id, 10))
+			}
+		}
+		j.ID = 0
+		j.SourceNodeIDs = nil
+		for k := range j.Triggers {
+			t := &j.Triggers[k]
+			t.ID = 0
+			t.WorkflowNodeJoinID = 0
+			t.WorkflowDestNodeID = 0
+			resetWorkflowNodeIDs(&t.WorkflowDestNode)
+		}
+	}
+}
+
+func resetWorkflowNodeIDs(n *sdk.WorkflowNode) {
+	if n == nil {
+		return
+	}
+	if n.Ref == "" {
+		n.Ref = strconv.FormatInt(n.ID, 10)
+	}
+	n.ID = 0
+	n.WorkflowID = 0
+	if n.Context != nil {
+		n.Context.ID = 0
+		n.Context.WorkflowNodeID = 0
+	}
+	for i := range n.Hooks {
+		n.Hooks[i].ID = 0
+		n.Hooks[i].WorkflowNodeID = 0
+	}
+	for i := range n.Triggers {
+		t := &n.Triggers[i]
+		t.ID = 0
+		t.WorkflowNodeID = 0
+		t.WorkflowDestNodeID = 0
+		resetWorkflowNodeIDs(&t.WorkflowDestNode)
+	}
+}
+
+// postPipelineRestoreHandler restores the version of a pipeline recorded by a pipeline audit
+func (api *API) postPipelineRestoreHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permPipelineKey"]
+
+		id, err := requestVarInt(r, "auditID")
+		if err != nil {
+			return err
+		}
+
+		p, err := project.Load(api.mustDB(), api.Cache, key, getUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "postPipelineRestoreHandler> Cannot load project %s", key)
+		}
+
+		oldPip, err := pipeline.LoadPipeline(api.mustDB(), key, name, true)
+		if err != nil {
+			return sdk.WrapError(err, "postPipelineRestoreHandler> Cannot load pipeline %s", name)
+		}
+
+		pa, err := pipeline.LoadAuditByID(api.mustDB(), oldPip.ID, id)
+		if err != nil {
+			return sdk.WrapError(err, "postPipelineRestoreHandler> Cannot load audit %d", id)
+		}
+		if pa.Pipeline == nil {
+			return sdk.WrapError(sdk.ErrWrongRequest, "postPipelineRestoreHandler> Audit %d has no pipeline", id)
+		}
+		pip := pa.Pipeline
+		pip.Name = oldPip.Name
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WrapError(err, "postPipelineRestoreHandler> Cannot start transaction")
+		}
+		defer tx.Rollback()
+
+		if err := pipeline.ImportUpdate(tx, p, pip, nil, getUser(ctx)); err != nil {
+			return sdk.WrapError(err, "postPipelineRestoreHandler> Cannot restore pipeline")
+		}
+
+		if err := pipeline.UpdatePipelineLastModified(tx, p, pip, getUser(ctx)); err != nil {
+			return sdk.WrapError(err, "postPipelineRestoreHandler> Cannot update pipeline last modified date")
+		}
+
+		if err := pipeline.CreateAudit(tx, pip, pipeline.AuditRestorePipeline, getUser(ctx)); err != nil {
+			return sdk.WrapError(err, "postPipelineRestoreHandler> Cannot create pipeline audit")
+		}
+
+		if err := audit.Record(tx, getUser(ctx), key, sdk.AuditPipeline, pip.Name, audit.Restored, oldPip, pip); err != nil {
+			return sdk.WrapError(err, "postPipelineRestoreHandler> Cannot record audit")
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "postPipelineRestoreHandler> Cannot commit transaction")
+		}
+
+		pip1, err := pipeline.LoadPipeline(api.mustDB(), key, name, true)
+		if err != nil {
+			return sdk.WrapError(err, "postPipelineRestoreHandler> Cannot load pipeline %s", name)
+		}
+		return WriteJSON(w, r, pip1, http.StatusOK)
+	}
+}
+
+// auditFilterFromRequest reads the filter of the audit trail in query parameters: type, name,
+// username, since and until (RFC3339), offset and limit
+func auditFilterFromRequest(r *http.Request) (sdk.AuditFilter, error) {
+	f := sdk.AuditFilter{
+		EntityType: r.FormValue("type"),
+		EntityName: r.FormValue("name"),
+		Username:   r.FormValue("username"),
+	}
+
+	var err error
+	if s := r.FormValue("since"); s != "" {
+		if f.Since, err = time.Parse(time.RFC3339, s); err != nil {
+			return f, sdk.WrapError(sdk.ErrWrongRequest, "auditFilterFromRequest> Invalid since %s", s)
+		}
+	}
+	if s := r.FormValue("until"); s != "" {
+		if f.Until, err = time.Parse(time.RFC3339, s); err != nil {
+			return f, sdk.WrapError(sdk.ErrWrongRequest, "auditFilterFromRequest> Invalid until %s", s)
+		}
+	}
+	if s := r.FormValue("offset"); s != "" {
+		if f.Offset, err = strconv.Atoi(s); err != nil || f.Offset < 0 {
+			return f, sdk.WrapError(sdk.ErrWrongRequest, "auditFilterFromRequest> Invalid offset %s", s)
+		}
+	}
+	if s := r.FormValue("limit"); s != "" {
+		if f.Limit, err = strconv.Atoi(s); err != nil {
+			return f, sdk.WrapError(sdk.ErrWrongRequest, "auditFilterFromRequest> Invalid limit %s", s)
+		}
+	}
+	return f, nil
+}
+
+// writeAudits writes audit entries as JSON, or as CSV if the format query parameter is csv
+func writeAudits(w http.ResponseWriter, r *http.Request, audits []sdk.Audit) error {
+	if r.FormValue("format") != "csv" {
+		return WriteJSON(w, r, audits, http.StatusOK)
+	}
+
+	w.Header().Add("Content-Type", "text/csv")
+	w.Header().Add("Content-Disposition", "attachment; filename=audit.csv")
+	w.WriteHeader(http.StatusOK)
+
+	c := csv.NewWriter(w)
+	c.Write([]string{"id", "created", "username", "project", "type", "name", "action", "diff"})
+	for _, a := range audits {
+		diff, err := json.Marshal(a.Diff)
+		if err != nil {
+			return sdk.WrapError(err, "writeAudits> Cannot marshal diff of audit %d", a.ID)
+		}
+		c.Write([]string{
+			strconv.FormatInt(a.ID, 10),
+			a.Created.Format(time.RFC3339),
+			a.Username,
+			a.ProjectKey,
+			a.EntityType,
+			a.EntityName,
+			a.Action,
+			string(diff),
+		})
+	}
+	c.Flush()
+	return c.Error()
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/audit"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_postWorkflowRestoreHandlerWithSecret(t *testing.T) {
+	api, db, router := newTestAPI(t)
+
+	u, pass := assets.InsertAdminUser(api.mustDB())
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, api.Cache, key, key, u)
+	pip := sdk.Pipeline{
+		Name:      "pipeline1",
+		ProjectID: proj.ID,
+		Type:      sdk.BuildPipeline,
+	}
+	test.NoError(t, pipeline.InsertPipeline(api.mustDB(), proj, &pip, nil))
+	test.NoError(t, pipeline.InsertParameterInPipeline(api.mustDB(), pip.ID, &sdk.Parameter{Name: "password", Type: sdk.SecretVariable}))
+
+	newWorkflow := func(secret string) *sdk.Workflow {
+		return &sdk.Workflow{
+			Name: "Name",
+			Root: &sdk.WorkflowNode{
+				PipelineID: pip.ID,
+				Context: &sdk.WorkflowNodeContext{
+					DefaultPipelineParameters: []sdk.Parameter{{Name: "password", Type: sdk.SecretVariable, Value: secret}},
+				},
+			},
+		}
+	}
+
+	// Create the workflow, then change its secret
+	vars := map[string]string{"permProjectKey": proj.Key}
+	uri := router.GetRoute("POST", api.postWorkflowHandler, vars)
+	w := httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "POST", uri, newWorkflow("first secret")))
+	assert.Equal(t, 201, w.Code)
+	var created sdk.Workflow
+	test.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+
+	vars = map[string]string{"permProjectKey": proj.Key, "workflowName": "Name"}
+	uri = router.GetRoute("PUT", api.putWorkflowHandler, vars)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "PUT", uri, newWorkflow("second secret")))
+	assert.Equal(t, 200, w.Code)
+
+	audits, err := audit.LoadAll(db, sdk.AuditFilter{ProjectKey: proj.Key, EntityType: sdk.AuditWorkflow, EntityName: "Name"})
+	test.NoError(t, err)
+	if !assert.Len(t, audits, 2) {
+		return
+	}
+	added := audits[1]
+	assert.Equal(t, audit.Added, added.Action)
+	// The audit trail never shows secrets
+	assert.False(t, strings.Contains(string(added.After), "first secret"))
+
+	// Restore the first version
+	vars["auditID"] = fmt.Sprintf("%d", added.ID)
+	uri = router.GetRoute("POST", api.postWorkflowRestoreHandler, vars)
+	test.NotEmpty(t, uri)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "POST", uri, nil))
+	assert.Equal(t, 200, w.Code)
+
+	wf, err := workflow.Load(db, api.Cache, proj.Key, "Name", u)
+	test.NoError(t, err)
+	assert.NotEqual(t, created.Root.ID, wf.Root.ID)
+	if assert.Len(t, wf.Root.Context.DefaultPipelineParameters, 1) {
+		assert.Equal(t, "first secret", wf.Root.Context.DefaultPipelineParameters[0].Value)
+	}
+}
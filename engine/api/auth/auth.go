@@ -13,6 +13,7 @@ import (
 	"github.com/ovh/cds/engine/api/hatchery"
 	"github.com/ovh/cds/engine/api/services"
 	"github.com/ovh/cds/engine/api/sessionstore"
+	"github.com/ovh/cds/engine/api/user"
 	"github.com/ovh/cds/engine/api/worker"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/cdsclient"
@@ -26,6 +27,7 @@ const (
 	ContextHatchery
 	ContextWorker
 	ContextService
+	ContextAccessToken
 )
 
 //Driver is an interface to all auth method (local, ldap and beyond...)
@@ -182,3 +184,27 @@ func CheckHatcheryAuth(ctx context.Context, db *gorp.DbMap, headers http.Header)
 	ctx = context.WithValue(ctx, ContextHatchery, h)
 	return ctx, nil
 }
+
+// CheckAccessTokenAuth checks user authentication with a personal access token
+func CheckAccessTokenAuth(ctx context.Context, db gorp.SqlExecutor, headers http.Header) (context.Context, error) {
+	t, err := user.LoadAccessToken(db, headers.Get(sdk.AccessTokenHeader))
+	if err != nil {
+		return ctx, fmt.Errorf("invalid access token: %s", err)
+	}
+	if t.IsExpired() {
+		return ctx, fmt.Errorf("access token %s has expired", t.Name)
+	}
+
+	u, err := user.LoadUserWithoutAuthByID(db, t.UserID)
+	if err != nil {
+		return ctx, fmt.Errorf("cannot load user %d of access token %s: %s", t.UserID, t.Name, err)
+	}
+
+	if err := user.UpdateAccessTokenLastUsed(db, t); err != nil {
+		log.Warning("CheckAccessTokenAuth> %s", err)
+	}
+
+	ctx = context.WithValue(ctx, ContextUser, u)
+	ctx = context.WithValue(ctx, ContextAccessToken, t)
+	return ctx, nil
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/ovh/cds/engine/api/permission"
 	"github.com/ovh/cds/engine/api/pipeline"
 	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/services"
 	"github.com/ovh/cds/engine/api/stats"
 	"github.com/ovh/cds/engine/api/worker"
 	"github.com/ovh/cds/sdk"
@@ -393,6 +394,12 @@ func (api *API) takePipelineBuildJobHandler() Handler {
 			return sdk.WrapError(errSecret, "takePipelineBuildJobHandler> Cannot load action build secrets")
 		}
 
+		proxyParams, errProxy := services.NewRepository(api.mustDB, api.Cache).ProxyParameters()
+		if errProxy != nil {
+			return sdk.WrapError(errProxy, "takePipelineBuildJobHandler> Cannot load proxy parameters")
+		}
+		pbji.PipelineBuildJob.Parameters = append(pbji.PipelineBuildJob.Parameters, proxyParams...)
+
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "takePipelineBuildJobHandler> Cannot commit transaction")
 		}
@@ -435,6 +442,10 @@ func (api *API) addSpawnInfosPipelineBuildJobHandler() Handler {
 			return sdk.WrapError(err, "addSpawnInfosPipelineBuildJobHandler> Cannot save job %d", pbJobID)
 		}
 
+		if err := worker.InsertSpawnInfos(tx, s); err != nil {
+			return sdk.WrapError(err, "addSpawnInfosPipelineBuildJobHandler> Cannot save spawn durations")
+		}
+
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "addSpawnInfosPipelineBuildJobHandler> Cannot commit tx")
 		}
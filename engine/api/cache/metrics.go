@@ -0,0 +1,20 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// OperationDuration is the latency of the redis commands, by command. It is registered by the services using the cache
+var OperationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "cache_operation_duration_seconds", Help: "metrics cache_operation_duration_seconds: latency of the redis commands", Buckets: prometheus.ExponentialBuckets(0.0005, 2, 14)}, []string{"command"})
+
+func observeOperation(process func(cmd redis.Cmder) error) func(cmd redis.Cmder) error {
+	return func(cmd redis.Cmder) error {
+		start := time.Now()
+		err := process(cmd)
+		OperationDuration.WithLabelValues(cmd.Name()).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
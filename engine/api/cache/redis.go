@@ -43,6 +43,7 @@ func NewRedisStore(host, password string, ttl int) (*RedisStore, error) {
 			DB:       0,        // use default DB
 		})
 	}
+	client.WrapProcess(observeOperation)
 	pong, err := client.Ping().Result()
 	if err != nil {
 		return nil, err
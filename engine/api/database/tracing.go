@@ -0,0 +1,173 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk/tracing"
+)
+
+// tracedExecutor is a gorp.SqlExecutor recording a span for each database call, child of the span of its context
+type tracedExecutor struct {
+	db  gorp.SqlExecutor
+	ctx context.Context
+}
+
+// WithTracing returns a gorp.SqlExecutor whose database calls are traced as children of the span of the context.
+// It returns the executor itself when tracing is disabled
+func WithTracing(ctx context.Context, db gorp.SqlExecutor) gorp.SqlExecutor {
+	if !tracing.Enabled() {
+		return db
+	}
+	if t, ok := db.(*tracedExecutor); ok {
+		db = t.db
+	}
+	return &tracedExecutor{db: db, ctx: ctx}
+}
+
+// TracingContext returns the tracing context of an executor returned by WithTracing
+func TracingContext(db gorp.SqlExecutor) (context.Context, bool) {
+	t, ok := db.(*tracedExecutor)
+	if !ok {
+		return nil, false
+	}
+	return t.ctx, true
+}
+
+func (t *tracedExecutor) start(operation, query string) *tracing.Span {
+	_, span := tracing.Start(t.ctx, "db."+operation)
+	if query != "" {
+		span.SetTag("db.statement", strings.Join(strings.Fields(query), " "))
+	}
+	return span
+}
+
+func (t *tracedExecutor) startWithTypes(operation string, list []interface{}) *tracing.Span {
+	span := t.start(operation, "")
+	if len(list) > 0 {
+		span.SetTag("db.type", fmt.Sprintf("%T", list[0]))
+	}
+	return span
+}
+
+func finish(span *tracing.Span, err error) {
+	span.SetError(err)
+	span.Finish()
+}
+
+func (t *tracedExecutor) Get(i interface{}, keys ...interface{}) (interface{}, error) {
+	span := t.startWithTypes("Get", []interface{}{i})
+	res, err := t.db.Get(i, keys...)
+	finish(span, err)
+	return res, err
+}
+
+func (t *tracedExecutor) Insert(list ...interface{}) error {
+	span := t.startWithTypes("Insert", list)
+	err := t.db.Insert(list...)
+	finish(span, err)
+	return err
+}
+
+func (t *tracedExecutor) Update(list ...interface{}) (int64, error) {
+	span := t.startWithTypes("Update", list)
+	n, err := t.db.Update(list...)
+	finish(span, err)
+	return n, err
+}
+
+func (t *tracedExecutor) Delete(list ...interface{}) (int64, error) {
+	span := t.startWithTypes("Delete", list)
+	n, err := t.db.Delete(list...)
+	finish(span, err)
+	return n, err
+}
+
+func (t *tracedExecutor) Exec(query string, args ...interface{}) (sql.Result, error) {
+	span := t.start("Exec", query)
+	res, err := t.db.Exec(query, args...)
+	finish(span, err)
+	return res, err
+}
+
+func (t *tracedExecutor) Select(i interface{}, query string, args ...interface{}) ([]interface{}, error) {
+	span := t.start("Select", query)
+	res, err := t.db.Select(i, query, args...)
+	finish(span, err)
+	return res, err
+}
+
+func (t *tracedExecutor) SelectInt(query string, args ...interface{}) (int64, error) {
+	span := t.start("SelectInt", query)
+	res, err := t.db.SelectInt(query, args...)
+	finish(span, err)
+	return res, err
+}
+
+func (t *tracedExecutor) SelectNullInt(query string, args ...interface{}) (sql.NullInt64, error) {
+	span := t.start("SelectNullInt", query)
+	res, err := t.db.SelectNullInt(query, args...)
+	finish(span, err)
+	return res, err
+}
+
+func (t *tracedExecutor) SelectFloat(query string, args ...interface{}) (float64, error) {
+	span := t.start("SelectFloat", query)
+	res, err := t.db.SelectFloat(query, args...)
+	finish(span, err)
+	return res, err
+}
+
+func (t *tracedExecutor) SelectNullFloat(query string, args ...interface{}) (sql.NullFloat64, error) {
+	span := t.start("SelectNullFloat", query)
+	res, err := t.db.SelectNullFloat(query, args...)
+	finish(span, err)
+	return res, err
+}
+
+func (t *tracedExecutor) SelectStr(query string, args ...interface{}) (string, error) {
+	span := t.start("SelectStr", query)
+	res, err := t.db.SelectStr(query, args...)
+	finish(span, err)
+	return res, err
+}
+
+func (t *tracedExecutor) SelectNullStr(query string, args ...interface{}) (sql.NullString, error) {
+	span := t.start("SelectNullStr", query)
+	res, err := t.db.SelectNullStr(query, args...)
+	finish(span, err)
+	return res, err
+}
+
+func (t *tracedExecutor) SelectOne(holder interface{}, query string, args ...interface{}) error {
+	span := t.start("SelectOne", query)
+	err := t.db.SelectOne(holder, query, args...)
+	finish(span, err)
+	return err
+}
+
+func (t *tracedExecutor) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	span := t.start("Query", query)
+	rows, err := t.db.Query(query, args...)
+	finish(span, err)
+	return rows, err
+}
+
+func (t *tracedExecutor) QueryRow(query string, args ...interface{}) *sql.Row {
+	span := t.start("QueryRow", query)
+	row := t.db.QueryRow(query, args...)
+	span.Finish()
+	return row
+}
+
+// Unwrap returns the executor wrapped by WithTracing, or the executor itself
+func Unwrap(db gorp.SqlExecutor) gorp.SqlExecutor {
+	if t, ok := db.(*tracedExecutor); ok {
+		return t.db
+	}
+	return db
+}
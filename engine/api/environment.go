@@ -7,6 +7,7 @@ import (
 
 	"github.com/gorilla/mux"
 
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/environment"
 	"github.com/ovh/cds/engine/api/group"
 	"github.com/ovh/cds/engine/api/permission"
@@ -305,6 +306,9 @@ func (api *API) addEnvironmentHandler() Handler {
 			return sdk.WrapError(err, "addEnvironmentHandler> Cannot update last modified date")
 		}
 
+		if err := audit.Record(tx, getUser(ctx), key, sdk.AuditEnvironment, env.Name, audit.Added, nil, &env); err != nil {
+			return sdk.WrapError(err, "addEnvironmentHandler> Cannot record audit")
+		}
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "addEnvironmentHandler> Cannot commit transaction")
 		}
@@ -353,6 +357,9 @@ func (api *API) deleteEnvironmentHandler() Handler {
 			return sdk.WrapError(err, "deleteEnvironmentHandler> Cannot update last modified date")
 		}
 
+		if err := audit.Record(tx, getUser(ctx), projectKey, sdk.AuditEnvironment, environmentName, audit.Deleted, env, nil); err != nil {
+			return sdk.WrapError(err, "deleteEnvironmentHandler> Cannot record audit")
+		}
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "deleteEnvironmentHandler> Cannot commit transaction")
 		}
@@ -389,6 +396,7 @@ func (api *API) updateEnvironmentHandler() Handler {
 			return err
 		}
 
+		before := *env
 		env.Name = envPost.Name
 
 		tx, errBegin := api.mustDB().Begin()
@@ -443,6 +451,14 @@ func (api *API) updateEnvironmentHandler() Handler {
 			return sdk.WrapError(err, "updateEnvironmentHandler> Cannot update last modified date")
 		}
 
+		after := *env
+		if len(envPost.Variable) > 0 {
+			after.Variable = envPost.Variable
+		}
+		if err := audit.Record(tx, getUser(ctx), projectKey, sdk.AuditEnvironment, environmentName, audit.Updated, &before, &after); err != nil {
+			return sdk.WrapError(err, "updateEnvironmentHandler> Cannot record audit")
+		}
+
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "updateEnvironmentHandler> Cannot commit transaction")
 		}
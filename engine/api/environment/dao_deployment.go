@@ -0,0 +1,96 @@
+package environment
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// InsertDeployment records a deployment on an environment
+func InsertDeployment(db gorp.SqlExecutor, d *sdk.EnvironmentDeployment) error {
+	d.Created = time.Now()
+	dbD := dbEnvironmentDeployment(*d)
+	if err := db.Insert(&dbD); err != nil {
+		return sdk.WrapError(err, "InsertDeployment> Unable to insert deployment on environment %d", d.EnvironmentID)
+	}
+	*d = sdk.EnvironmentDeployment(dbD)
+	return nil
+}
+
+// LoadDeployments loads the last deployments on an environment, the most recent first. The current deployment
+// of each application is the last successful one
+func LoadDeployments(db gorp.SqlExecutor, envID int64, applicationName string, limit int) ([]sdk.EnvironmentDeployment, error) {
+	var res []dbEnvironmentDeployment
+	query := `SELECT * FROM environment_deployment
+		WHERE environment_id = $1 AND ($2 = '' OR application_name = $2)
+		ORDER BY created DESC LIMIT $3`
+	if _, err := db.Select(&res, query, envID, applicationName, limit); err != nil {
+		return nil, sdk.WrapError(err, "LoadDeployments> Unable to load deployments on environment %d", envID)
+	}
+
+	current, err := LoadCurrentDeployments(db, envID)
+	if err != nil {
+		return nil, err
+	}
+	currentIDs := make(map[int64]bool, len(current))
+	for _, d := range current {
+		currentIDs[d.ID] = true
+	}
+
+	ds := make([]sdk.EnvironmentDeployment, len(res))
+	for i := range res {
+		ds[i] = sdk.EnvironmentDeployment(res[i])
+		ds[i].Current = currentIDs[ds[i].ID]
+	}
+	return ds, nil
+}
+
+// LoadCurrentDeployments loads the last successful deployment of each application on an environment
+func LoadCurrentDeployments(db gorp.SqlExecutor, envID int64) ([]sdk.EnvironmentDeployment, error) {
+	var res []dbEnvironmentDeployment
+	query := `SELECT DISTINCT ON (application_name) * FROM environment_deployment
+		WHERE environment_id = $1 AND status = $2
+		ORDER BY application_name, created DESC`
+	if _, err := db.Select(&res, query, envID, sdk.StatusSuccess.String()); err != nil {
+		return nil, sdk.WrapError(err, "LoadCurrentDeployments> Unable to load current deployments on environment %d", envID)
+	}
+
+	ds := make([]sdk.EnvironmentDeployment, len(res))
+	for i := range res {
+		ds[i] = sdk.EnvironmentDeployment(res[i])
+		ds[i].Current = true
+	}
+	return ds, nil
+}
+
+// LoadDeployment loads a deployment on an environment
+func LoadDeployment(db gorp.SqlExecutor, envID, id int64) (*sdk.EnvironmentDeployment, error) {
+	var dbD dbEnvironmentDeployment
+	if err := db.SelectOne(&dbD, "SELECT * FROM environment_deployment WHERE environment_id = $1 AND id = $2", envID, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.WrapError(sdk.ErrEnvironmentDeploymentNotFound, "LoadDeployment> Deployment %d not found", id)
+		}
+		return nil, sdk.WrapError(err, "LoadDeployment> Unable to load deployment %d", id)
+	}
+	d := sdk.EnvironmentDeployment(dbD)
+	return &d, nil
+}
+
+// LoadNodeDeployments loads the last deployments of a workflow node on an environment, the most recent first
+func LoadNodeDeployments(db gorp.SqlExecutor, envID, workflowID int64, nodeName string, limit int) ([]sdk.EnvironmentDeployment, error) {
+	var res []dbEnvironmentDeployment
+	query := `SELECT * FROM environment_deployment
+		WHERE environment_id = $1 AND workflow_id = $2 AND node_name = $3
+		ORDER BY created DESC LIMIT $4`
+	if _, err := db.Select(&res, query, envID, workflowID, nodeName, limit); err != nil {
+		return nil, sdk.WrapError(err, "LoadNodeDeployments> Unable to load deployments of node %s on environment %d", nodeName, envID)
+	}
+	ds := make([]sdk.EnvironmentDeployment, len(res))
+	for i := range res {
+		ds[i] = sdk.EnvironmentDeployment(res[i])
+	}
+	return ds, nil
+}
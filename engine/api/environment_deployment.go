@@ -0,0 +1,163 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/go-gorp/gorp"
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/notification"
+	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+func (api *API) getEnvironmentDeploymentsHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		projectKey := vars["key"]
+		environmentName := vars["permEnvironmentName"]
+
+		limit := 50
+		if limitS := r.FormValue("limit"); limitS != "" {
+			var errAtoi error
+			limit, errAtoi = strconv.Atoi(limitS)
+			if errAtoi != nil || limit <= 0 {
+				return sdk.ErrWrongRequest
+			}
+		}
+
+		env, errEnv := environment.LoadEnvironmentByName(api.mustDB(), projectKey, environmentName)
+		if errEnv != nil {
+			return sdk.WrapError(errEnv, "getEnvironmentDeploymentsHandler> Cannot load environment %s", environmentName)
+		}
+
+		ds, err := environment.LoadDeployments(api.mustDB(), env.ID, r.FormValue("application"), limit)
+		if err != nil {
+			return sdk.WrapError(err, "getEnvironmentDeploymentsHandler> Cannot load deployments on environment %s", environmentName)
+		}
+
+		return WriteJSON(w, r, ds, http.StatusOK)
+	}
+}
+
+func (api *API) postEnvironmentPromoteHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		projectKey := vars["key"]
+		environmentName := vars["permEnvironmentName"]
+
+		var promotion sdk.EnvironmentPromotion
+		if err := UnmarshalBody(r, &promotion); err != nil {
+			return err
+		}
+		if promotion.To == "" || promotion.To == environmentName {
+			return sdk.ErrWrongRequest
+		}
+
+		p, errP := project.Load(api.mustDB(), api.Cache, projectKey, getUser(ctx), project.LoadOptions.WithVariables)
+		if errP != nil {
+			return sdk.WrapError(errP, "postEnvironmentPromoteHandler> Cannot load project %s", projectKey)
+		}
+
+		tx, errTx := api.mustDB().Begin()
+		if errTx != nil {
+			return sdk.WrapError(errTx, "postEnvironmentPromoteHandler> Cannot start transaction")
+		}
+		defer tx.Rollback()
+		defer notification.Discard(tx)
+
+		source, errEnv := environment.LoadEnvironmentByName(tx, projectKey, environmentName)
+		if errEnv != nil {
+			return sdk.WrapError(errEnv, "postEnvironmentPromoteHandler> Cannot load environment %s", environmentName)
+		}
+		target, errEnv := environment.LoadEnvironmentByName(tx, projectKey, promotion.To)
+		if errEnv != nil {
+			return sdk.WrapError(errEnv, "postEnvironmentPromoteHandler> Cannot load environment %s", promotion.To)
+		}
+		if !permission.AccessToEnvironment(target.ID, getUser(ctx), permission.PermissionReadExecute) {
+			return sdk.WrapError(sdk.ErrForbidden, "postEnvironmentPromoteHandler> Not allowed to deploy on environment %s", target.Name)
+		}
+
+		d, errD := loadPromotedDeployment(tx, source.ID, promotion)
+		if errD != nil {
+			return sdk.WrapError(errD, "postEnvironmentPromoteHandler> Cannot load deployment to promote from environment %s", environmentName)
+		}
+
+		wf, errW := workflow.Load(tx, api.Cache, projectKey, d.WorkflowName, getUser(ctx))
+		if errW != nil {
+			return sdk.WrapError(errW, "postEnvironmentPromoteHandler> Cannot load workflow %s", d.WorkflowName)
+		}
+		run, errR := workflow.LoadRun(tx, projectKey, d.WorkflowName, d.WorkflowRunNumber)
+		if errR != nil {
+			return sdk.WrapError(errR, "postEnvironmentPromoteHandler> Cannot load run %d of workflow %s", d.WorkflowRunNumber, d.WorkflowName)
+		}
+
+		// Promote to the node of the same run deploying the application on the target environment, so that the
+		// artifacts of the run are deployed
+		var pipelineName string
+		for _, nodeRuns := range run.WorkflowNodeRuns {
+			for _, nr := range nodeRuns {
+				if nr.ID != d.WorkflowNodeRunID {
+					continue
+				}
+				if n := run.Workflow.GetNode(nr.WorkflowNodeID); n != nil {
+					pipelineName = n.Pipeline.Name
+				}
+			}
+		}
+		n := workflow.FindDeploymentNode(&run.Workflow, d.ApplicationName, target.ID, pipelineName)
+		if n == nil {
+			return sdk.WrapError(sdk.ErrEnvironmentPromotionNodeNotFound, "postEnvironmentPromoteHandler> No node deploys %s on environment %s in workflow %s", d.ApplicationName, target.Name, d.WorkflowName)
+		}
+
+		manual := &sdk.WorkflowNodeRunManual{
+			User:               *getUser(ctx),
+			Payload:            n.Context.DefaultPayload,
+			PipelineParameters: n.Context.DefaultPipelineParameters,
+		}
+		wr, errRun := workflow.ManualRunFromNode(tx, api.Cache, p, wf, run.Number, manual, n.ID)
+		if errRun != nil {
+			return sdk.WrapError(errRun, "postEnvironmentPromoteHandler> Unable to run node %s", n.Name)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "postEnvironmentPromoteHandler> Cannot commit transaction")
+		}
+		notification.Flush(tx)
+
+		wr.Translate(r.Header.Get("Accept-Language"))
+		return WriteJSON(w, r, wr, http.StatusOK)
+	}
+}
+
+// loadPromotedDeployment loads the deployment of a promotion: the given one, or the current deployment of the application
+func loadPromotedDeployment(db gorp.SqlExecutor, envID int64, promotion sdk.EnvironmentPromotion) (*sdk.EnvironmentDeployment, error) {
+	if promotion.DeploymentID != 0 {
+		return environment.LoadDeployment(db, envID, promotion.DeploymentID)
+	}
+
+	current, err := environment.LoadCurrentDeployments(db, envID)
+	if err != nil {
+		return nil, err
+	}
+	var d *sdk.EnvironmentDeployment
+	for i := range current {
+		if promotion.ApplicationName != "" && current[i].ApplicationName != promotion.ApplicationName {
+			continue
+		}
+		if d != nil {
+			// Several applications are deployed, the application to promote must be given
+			return nil, sdk.ErrWrongRequest
+		}
+		d = &current[i]
+	}
+	if d == nil {
+		return nil, sdk.ErrEnvironmentDeploymentNotFound
+	}
+	return d, nil
+}
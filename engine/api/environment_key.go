@@ -7,6 +7,7 @@ import (
 
 	"github.com/gorilla/mux"
 
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/environment"
 	"github.com/ovh/cds/engine/api/keys"
 	"github.com/ovh/cds/engine/api/project"
@@ -62,6 +63,9 @@ func (api *API) deleteKeyInEnvironmentHandler() Handler {
 				if err := project.UpdateLastModified(tx, api.Cache, getUser(ctx), p); err != nil {
 					return sdk.WrapError(err, "deleteKeyInEnvironmentHandler> Cannot update application last modified date")
 				}
+				if err := audit.Record(tx, getUser(ctx), key, sdk.AuditKey, envName+"/"+keyName, audit.Deleted, k, nil); err != nil {
+					return sdk.WrapError(err, "deleteKeyInEnvironmentHandler> Cannot record audit")
+				}
 			}
 		}
 
@@ -136,6 +140,10 @@ func (api *API) addKeyInEnvironmentHandler() Handler {
 			return sdk.WrapError(err, "addKeyInEnvironmentHandler> Cannot update project last modified date")
 		}
 
+		if err := audit.Record(tx, getUser(ctx), key, sdk.AuditKey, envName+"/"+newKey.Name, audit.Added, nil, &newKey); err != nil {
+			return sdk.WrapError(err, "addKeyInEnvironmentHandler> Cannot record audit")
+		}
+
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "addKeyInEnvironmentHandler> Cannot commit transaction")
 		}
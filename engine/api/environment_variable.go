@@ -8,6 +8,7 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/environment"
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/api/sanity"
@@ -213,6 +214,10 @@ func (api *API) deleteVariableFromEnvironmentHandler() Handler {
 			return sdk.WrapError(err, "deleteVariableFromEnvironmentHandler> Cannot update last modified date")
 		}
 
+		if err := audit.Record(tx, getUser(ctx), key, sdk.AuditVariable, envName+"/"+varName, audit.Deleted, varToDelete, nil); err != nil {
+			return sdk.WrapError(err, "deleteVariableFromEnvironmentHandler> Cannot record audit")
+		}
+
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "deleteVariableFromEnvironmentHandler: Cannot commit transaction")
 		}
@@ -265,6 +270,11 @@ func (api *API) updateVariableInEnvironmentHandler() Handler {
 		}
 		defer tx.Rollback()
 
+		oldVar, errV := environment.GetVariable(tx, key, envName, varName)
+		if errV != nil {
+			return sdk.WrapError(errV, "updateVariableInEnvironmentHandler> Cannot load variable %s", varName)
+		}
+
 		if err := environment.UpdateVariable(api.mustDB(), env.ID, &newVar, getUser(ctx)); err != nil {
 			return sdk.WrapError(err, "updateVariableInEnvironmentHandler: Cannot update variable %s for environment %s", varName, envName)
 		}
@@ -277,6 +287,10 @@ func (api *API) updateVariableInEnvironmentHandler() Handler {
 			return sdk.WrapError(err, "updateVariableInEnvironmentHandler: Cannot update last modified date")
 		}
 
+		if err := audit.Record(tx, getUser(ctx), key, sdk.AuditVariable, envName+"/"+varName, audit.Updated, oldVar, &newVar); err != nil {
+			return sdk.WrapError(err, "updateVariableInEnvironmentHandler> Cannot record audit")
+		}
+
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "updateVariableInEnvironmentHandler: Cannot commit transaction")
 		}
@@ -355,6 +369,10 @@ func (api *API) addVariableInEnvironmentHandler() Handler {
 		if err := project.UpdateLastModified(tx, api.Cache, getUser(ctx), p); err != nil {
 			return sdk.WrapError(err, "addVariableInEnvironmentHandler: Cannot update last modified date")
 		}
+		if err := audit.Record(tx, getUser(ctx), key, sdk.AuditVariable, envName+"/"+varName, audit.Added, nil, &newVar); err != nil {
+			return sdk.WrapError(err, "addVariableInEnvironmentHandler> Cannot record audit")
+		}
+
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "addVariableInEnvironmentHandler: cannot commit tx")
 		}
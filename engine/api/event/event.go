@@ -7,6 +7,7 @@ import (
 
 	"github.com/docker/docker/pkg/namesgenerator"
 
+	"github.com/ovh/cds/engine/api/notification"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 )
@@ -41,6 +42,12 @@ func Initialize(k KafkaConfig) error {
 	}
 	cdsname = namesgenerator.GetRandomName(0)
 
+	//Jabber notifications are consumed from events
+	notification.RegisterSender(sdk.JabberUserNotification, notification.SenderFunc(func(n sdk.EventNotif) error {
+		Publish(n)
+		return nil
+	}))
+
 	brokers = []Broker{}
 	if k.Enabled {
 		var errk error
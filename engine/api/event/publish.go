@@ -35,6 +35,11 @@ func PublishJobRun(n *sdk.WorkflowNodeRun, j *sdk.WorkflowNodeJobRun) {
 	//TODO PublishJobRun sends an event
 }
 
+// PublishWorkflowNodeRun sends the notifications of a workflow node run
+func PublishWorkflowNodeRun(db gorp.SqlExecutor, wr *sdk.WorkflowRun, nr *sdk.WorkflowNodeRun, previousStatus string) {
+	notification.SendWorkflowNodeRunNotifications(db, wr, nr, previousStatus)
+}
+
 // PublishActionBuild sends a actionBuild event
 func PublishActionBuild(pb *sdk.PipelineBuild, pbJob *sdk.PipelineBuildJob) {
 	e := sdk.EventJob{
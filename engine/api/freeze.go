@@ -0,0 +1,218 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/freeze"
+	"github.com/ovh/cds/engine/api/notification"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func (api *API) getFreezeWindowsHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		ws, err := freeze.LoadCurrent(api.mustDB(), 0, time.Now())
+		if err != nil {
+			return sdk.WrapError(err, "getFreezeWindowsHandler> Cannot load freeze windows")
+		}
+		return WriteJSON(w, r, ws, http.StatusOK)
+	}
+}
+
+func (api *API) postFreezeWindowHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var fw sdk.FreezeWindow
+		if err := UnmarshalBody(r, &fw); err != nil {
+			return err
+		}
+		fw.ProjectID, fw.EnvironmentID = 0, 0
+		fw.Author = getUser(ctx).Username
+
+		if err := freeze.Insert(api.mustDB(), &fw); err != nil {
+			return sdk.WrapError(err, "postFreezeWindowHandler> Cannot insert freeze window")
+		}
+		freeze.Compute(&fw, time.Now())
+		return WriteJSON(w, r, fw, http.StatusCreated)
+	}
+}
+
+func (api *API) deleteFreezeWindowHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id, err := requestVarInt(r, "id")
+		if err != nil {
+			return err
+		}
+		if err := freeze.Delete(api.mustDB(), 0, id); err != nil {
+			return sdk.WrapError(err, "deleteFreezeWindowHandler> Cannot delete freeze window %d", id)
+		}
+		return nil
+	}
+}
+
+func (api *API) getProjectFreezeWindowsHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		key := mux.Vars(r)["permProjectKey"]
+		p, errP := project.Load(api.mustDB(), api.Cache, key, getUser(ctx))
+		if errP != nil {
+			return sdk.WrapError(errP, "getProjectFreezeWindowsHandler> Cannot load project %s", key)
+		}
+
+		ws, err := freeze.LoadCurrent(api.mustDB(), p.ID, time.Now())
+		if err != nil {
+			return sdk.WrapError(err, "getProjectFreezeWindowsHandler> Cannot load freeze windows")
+		}
+		return WriteJSON(w, r, ws, http.StatusOK)
+	}
+}
+
+func (api *API) postProjectFreezeWindowHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		key := mux.Vars(r)["permProjectKey"]
+		var fw sdk.FreezeWindow
+		if err := UnmarshalBody(r, &fw); err != nil {
+			return err
+		}
+
+		p, errP := project.Load(api.mustDB(), api.Cache, key, getUser(ctx))
+		if errP != nil {
+			return sdk.WrapError(errP, "postProjectFreezeWindowHandler> Cannot load project %s", key)
+		}
+		fw.ProjectID, fw.ProjectKey, fw.EnvironmentID = p.ID, p.Key, 0
+		if fw.EnvironmentName != "" {
+			env, errEnv := environment.LoadEnvironmentByName(api.mustDB(), key, fw.EnvironmentName)
+			if errEnv != nil {
+				return sdk.WrapError(errEnv, "postProjectFreezeWindowHandler> Cannot load environment %s", fw.EnvironmentName)
+			}
+			fw.EnvironmentID = env.ID
+		}
+		fw.Author = getUser(ctx).Username
+
+		if err := freeze.Insert(api.mustDB(), &fw); err != nil {
+			return sdk.WrapError(err, "postProjectFreezeWindowHandler> Cannot insert freeze window")
+		}
+		freeze.Compute(&fw, time.Now())
+		return WriteJSON(w, r, fw, http.StatusCreated)
+	}
+}
+
+func (api *API) deleteProjectFreezeWindowHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		key := mux.Vars(r)["permProjectKey"]
+		id, err := requestVarInt(r, "id")
+		if err != nil {
+			return err
+		}
+
+		p, errP := project.Load(api.mustDB(), api.Cache, key, getUser(ctx))
+		if errP != nil {
+			return sdk.WrapError(errP, "deleteProjectFreezeWindowHandler> Cannot load project %s", key)
+		}
+		if err := freeze.Delete(api.mustDB(), p.ID, id); err != nil {
+			return sdk.WrapError(err, "deleteProjectFreezeWindowHandler> Cannot delete freeze window %d", id)
+		}
+		return nil
+	}
+}
+
+// getWorkflowHookFreezeWindowsHandler returns the freeze windows applying to a workflow run by the hooks service. Like
+// for the runs, only the workflows deploying on an environment are frozen
+func (api *API) getWorkflowHookFreezeWindowsHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["workflow"]
+		p, errP := project.Load(api.mustDB(), api.Cache, key, nil)
+		if errP != nil {
+			return sdk.WrapError(errP, "getWorkflowHookFreezeWindowsHandler> Cannot load project %s", key)
+		}
+
+		wf, errW := workflow.Load(api.mustDB(), api.Cache, key, name, getUser(ctx))
+		if errW != nil {
+			return sdk.WrapError(errW, "getWorkflowHookFreezeWindowsHandler> Cannot load workflow %s", name)
+		}
+		if !workflow.HasDeploymentNode(wf) {
+			return WriteJSON(w, r, []sdk.FreezeWindow{}, http.StatusOK)
+		}
+
+		ws, err := freeze.LoadCurrent(api.mustDB(), p.ID, time.Now())
+		if err != nil {
+			return sdk.WrapError(err, "getWorkflowHookFreezeWindowsHandler> Cannot load freeze windows")
+		}
+		return WriteJSON(w, r, ws, http.StatusOK)
+	}
+}
+
+//freezeHoldRoutine is the go-routine which executes the node runs held by freeze windows which are closed
+func freezeHoldRoutine(c context.Context, DBFunc func() *gorp.DbMap, store cache.Store) {
+	tick := time.NewTicker(30 * time.Second).C
+	for {
+		select {
+		case <-c.Done():
+			if c.Err() != nil {
+				log.Error("Exiting freezeHoldRoutine: %v", c.Err())
+				return
+			}
+		case <-tick:
+			db := DBFunc()
+			if db == nil {
+				continue
+			}
+			hs, err := freeze.LoadHolds(db)
+			if err != nil {
+				log.Warning("freezeHoldRoutine> %s", err)
+				continue
+			}
+			for _, h := range hs {
+				if err := releaseHeldNodeRun(db, store, h.WorkflowNodeRunID); err != nil {
+					log.Warning("freezeHoldRoutine> %s", err)
+				}
+			}
+		}
+	}
+}
+
+// releaseHeldNodeRun executes a node run held by a freeze window if no freeze window covers it anymore
+func releaseHeldNodeRun(db *gorp.DbMap, store cache.Store, nodeRunID int64) error {
+	nodeRun, err := workflow.LoadNodeRunByID(db, nodeRunID)
+	if err != nil {
+		return sdk.WrapError(err, "releaseHeldNodeRun> Unable to load node run %d", nodeRunID)
+	}
+	wr, err := workflow.LoadRunByID(db, nodeRun.WorkflowRunID)
+	if err != nil {
+		return sdk.WrapError(err, "releaseHeldNodeRun> Unable to load run of node run %d", nodeRunID)
+	}
+	p, err := project.LoadProjectByNodeRunID(db, store, nodeRunID, nil, project.LoadOptions.WithVariables)
+	if err != nil {
+		return sdk.WrapError(err, "releaseHeldNodeRun> Unable to load project of node run %d", nodeRunID)
+	}
+
+	held, err := workflow.IsNodeRunHeld(db, p, wr, nodeRun)
+	if err != nil || held {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return sdk.WrapError(err, "releaseHeldNodeRun> Unable to start transaction")
+	}
+	defer tx.Rollback()
+	defer notification.Discard(tx)
+
+	if err := workflow.ReleaseHeldNodeRun(tx, store, p, nodeRunID); err != nil {
+		return sdk.WrapError(err, "releaseHeldNodeRun> Unable to release node run %d", nodeRunID)
+	}
+	if err := tx.Commit(); err != nil {
+		return sdk.WrapError(err, "releaseHeldNodeRun> Cannot commit transaction")
+	}
+	notification.Flush(tx)
+	return nil
+}
@@ -0,0 +1,112 @@
+package freeze
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Insert inserts a freeze window
+func Insert(db gorp.SqlExecutor, w *sdk.FreezeWindow) error {
+	if err := IsValid(w); err != nil {
+		return err
+	}
+	w.Created = time.Now()
+	dbW := dbFreezeWindow(*w)
+	if err := db.Insert(&dbW); err != nil {
+		return sdk.WrapError(err, "Insert> Unable to insert freeze window %s", w.Name)
+	}
+	w.ID = dbW.ID
+	return nil
+}
+
+// Delete deletes a freeze window of a project, or a global one if projectID is 0
+func Delete(db gorp.SqlExecutor, projectID, id int64) error {
+	res, err := db.Exec("DELETE FROM freeze_window WHERE id = $1 AND project_id = $2", id, projectID)
+	if err != nil {
+		return sdk.WrapError(err, "Delete> Unable to delete freeze window %d", id)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sdk.ErrNotFound
+	}
+	return nil
+}
+
+type freezeWindowRow struct {
+	dbFreezeWindow
+	ProjectKey      sql.NullString `db:"projectkey"`
+	EnvironmentName sql.NullString `db:"environment_name"`
+}
+
+// LoadCurrent loads the global freeze windows, and those of a project if projectID is not 0, which are in progress
+// or will open after a date. Their next occurrence is computed
+func LoadCurrent(db gorp.SqlExecutor, projectID int64, t time.Time) ([]sdk.FreezeWindow, error) {
+	var rows []freezeWindowRow
+	query := `SELECT freeze_window.*, project.projectkey, environment.name "environment_name"
+		FROM freeze_window
+		LEFT JOIN project ON project.id = freeze_window.project_id
+		LEFT JOIN environment ON environment.id = freeze_window.environment_id
+		WHERE (freeze_window.project_id = 0 OR freeze_window.project_id = $1) AND freeze_window.end_date > $2
+		ORDER BY freeze_window.start_date`
+	if _, err := db.Select(&rows, query, projectID, t); err != nil {
+		return nil, sdk.WrapError(err, "LoadCurrent> Unable to load freeze windows of project %d", projectID)
+	}
+
+	ws := make([]sdk.FreezeWindow, 0, len(rows))
+	for _, r := range rows {
+		w := sdk.FreezeWindow(r.dbFreezeWindow)
+		w.ProjectKey = r.ProjectKey.String
+		w.EnvironmentName = r.EnvironmentName.String
+		if Compute(&w, t) {
+			ws = append(ws, w)
+		}
+	}
+	return ws, nil
+}
+
+// LoadByID loads a freeze window
+func LoadByID(db gorp.SqlExecutor, id int64) (*sdk.FreezeWindow, error) {
+	var dbW dbFreezeWindow
+	if err := db.SelectOne(&dbW, "SELECT * FROM freeze_window WHERE id = $1", id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.ErrNotFound
+		}
+		return nil, sdk.WrapError(err, "LoadByID> Unable to load freeze window %d", id)
+	}
+	w := sdk.FreezeWindow(dbW)
+	return &w, nil
+}
+
+// InsertHold records a workflow node run held by a freeze window
+func InsertHold(db gorp.SqlExecutor, nodeRunID, windowID int64) error {
+	h := Hold{WorkflowNodeRunID: nodeRunID, FreezeWindowID: windowID, Created: time.Now()}
+	if err := db.Insert(&h); err != nil {
+		return sdk.WrapError(err, "InsertHold> Unable to hold node run %d", nodeRunID)
+	}
+	return nil
+}
+
+// DeleteHold releases a workflow node run held by a freeze window. It returns false if the node run was not held
+func DeleteHold(db gorp.SqlExecutor, nodeRunID int64) (bool, error) {
+	res, err := db.Exec("DELETE FROM freeze_hold WHERE workflow_node_run_id = $1", nodeRunID)
+	if err != nil {
+		return false, sdk.WrapError(err, "DeleteHold> Unable to release node run %d", nodeRunID)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, sdk.WrapError(err, "DeleteHold> Unable to release node run %d", nodeRunID)
+	}
+	return n > 0, nil
+}
+
+// LoadHolds loads the workflow node runs held by freeze windows
+func LoadHolds(db gorp.SqlExecutor) ([]Hold, error) {
+	var hs []Hold
+	if _, err := db.Select(&hs, "SELECT * FROM freeze_hold ORDER BY created"); err != nil {
+		return nil, sdk.WrapError(err, "LoadHolds> Unable to load held node runs")
+	}
+	return hs, nil
+}
@@ -0,0 +1,101 @@
+package freeze
+
+import (
+	"time"
+
+	"github.com/gorhill/cronexpr"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Occurrence returns the occurrence of a freeze window in progress at a date, or else the next one
+func Occurrence(w *sdk.FreezeWindow, t time.Time) (time.Time, time.Time, bool) {
+	if !t.Before(w.End) {
+		return time.Time{}, time.Time{}, false
+	}
+	if w.Recurrence == "" {
+		return w.Start, w.End, true
+	}
+
+	expr, err := cronexpr.Parse(w.Recurrence)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	loc := time.UTC
+	if w.Timezone != "" {
+		if l, err := time.LoadLocation(w.Timezone); err == nil {
+			loc = l
+		}
+	}
+
+	// The first occurrence started less than a duration ago is in progress
+	d := time.Duration(w.Duration) * time.Minute
+	from := t.Add(-d)
+	if from.Before(w.Start) {
+		from = w.Start.Add(-time.Nanosecond)
+	}
+	start := expr.Next(from.In(loc))
+	if start.IsZero() || !start.Before(w.End) {
+		return time.Time{}, time.Time{}, false
+	}
+	end := start.Add(d)
+	if end.After(w.End) {
+		end = w.End
+	}
+	return start, end, true
+}
+
+// Compute sets the occurrence of a freeze window in progress at a date, or else the next one. It returns false if
+// the window will not open anymore
+func Compute(w *sdk.FreezeWindow, t time.Time) bool {
+	start, end, ok := Occurrence(w, t)
+	w.NextStart, w.NextEnd = start, end
+	w.Active = ok && !t.Before(start) && t.Before(end)
+	return ok
+}
+
+// Covers returns true if a freeze window covers the deployments on an environment of a project
+func Covers(w *sdk.FreezeWindow, projectID, environmentID int64) bool {
+	if w.ProjectID == 0 {
+		return true
+	}
+	return w.ProjectID == projectID && (w.EnvironmentID == 0 || w.EnvironmentID == environmentID)
+}
+
+// Active returns the freeze window active at a date covering the deployments on an environment of a project.
+// Windows refusing deployments are preferred to windows holding them
+func Active(ws []sdk.FreezeWindow, projectID, environmentID int64, t time.Time) *sdk.FreezeWindow {
+	var active *sdk.FreezeWindow
+	for i := range ws {
+		w := &ws[i]
+		if !Covers(w, projectID, environmentID) || !Compute(w, t) || !w.Active {
+			continue
+		}
+		if active == nil || (w.Action == sdk.FreezeActionRefuse && active.Action != sdk.FreezeActionRefuse) {
+			active = w
+		}
+	}
+	return active
+}
+
+// IsValid checks a freeze window
+func IsValid(w *sdk.FreezeWindow) error {
+	if w.Name == "" || w.Start.IsZero() || !w.End.After(w.Start) {
+		return sdk.ErrInvalidFreezeWindow
+	}
+	if w.Action != sdk.FreezeActionHold && w.Action != sdk.FreezeActionRefuse {
+		return sdk.ErrInvalidFreezeWindow
+	}
+	if w.Timezone != "" {
+		if _, err := time.LoadLocation(w.Timezone); err != nil {
+			return sdk.ErrInvalidFreezeWindow
+		}
+	}
+	if w.Recurrence == "" {
+		return nil
+	}
+	if _, err := cronexpr.Parse(w.Recurrence); err != nil || w.Duration <= 0 {
+		return sdk.ErrInvalidFreezeWindow
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package freeze
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestOccurrence(t *testing.T) {
+	at := func(s string) time.Time {
+		d, err := time.Parse(time.RFC3339, s)
+		assert.NoError(t, err)
+		return d
+	}
+
+	once := &sdk.FreezeWindow{Start: at("2017-12-20T00:00:00Z"), End: at("2018-01-02T00:00:00Z")}
+	start, end, ok := Occurrence(once, at("2017-12-01T00:00:00Z"))
+	assert.True(t, ok)
+	assert.Equal(t, once.Start, start)
+	assert.Equal(t, once.End, end)
+	_, _, ok = Occurrence(once, at("2018-01-02T00:00:00Z"))
+	assert.False(t, ok)
+
+	// Every friday from 18:00, for 2 days and a half
+	weekly := &sdk.FreezeWindow{
+		Start:      at("2017-10-01T00:00:00Z"),
+		End:        at("2017-10-29T00:00:00Z"),
+		Recurrence: "0 18 * * 5",
+		Duration:   60 * 60,
+	}
+	start, end, ok = Occurrence(weekly, at("2017-10-14T10:00:00Z"))
+	assert.True(t, ok)
+	assert.Equal(t, at("2017-10-13T18:00:00Z"), start.UTC())
+	assert.Equal(t, at("2017-10-16T06:00:00Z"), end.UTC())
+
+	start, _, ok = Occurrence(weekly, at("2017-10-16T06:00:00Z"))
+	assert.True(t, ok)
+	assert.Equal(t, at("2017-10-20T18:00:00Z"), start.UTC())
+
+	// The last occurrence ends with the window
+	_, end, ok = Occurrence(weekly, at("2017-10-28T00:00:00Z"))
+	assert.True(t, ok)
+	assert.Equal(t, weekly.End, end)
+}
+
+func TestActive(t *testing.T) {
+	now := time.Now()
+	ws := []sdk.FreezeWindow{
+		{ID: 1, Start: now.Add(-time.Hour), End: now.Add(time.Hour), Action: sdk.FreezeActionHold},
+		{ID: 2, ProjectID: 1, EnvironmentID: 2, Start: now.Add(-time.Hour), End: now.Add(time.Hour), Action: sdk.FreezeActionRefuse},
+		{ID: 3, ProjectID: 1, Start: now.Add(time.Hour), End: now.Add(2 * time.Hour), Action: sdk.FreezeActionRefuse},
+	}
+	assert.Equal(t, int64(2), Active(ws, 1, 2, now).ID)
+	assert.Equal(t, int64(1), Active(ws, 1, 3, now).ID)
+	assert.Nil(t, Active(ws[1:], 2, 2, now))
+	assert.True(t, ws[0].Active)
+	assert.Equal(t, now.Add(time.Hour), ws[2].NextStart)
+}
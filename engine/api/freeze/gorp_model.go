@@ -0,0 +1,22 @@
+package freeze
+
+import (
+	"time"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+type dbFreezeWindow sdk.FreezeWindow
+
+// Hold is a workflow node run held by a freeze window
+type Hold struct {
+	WorkflowNodeRunID int64     `db:"workflow_node_run_id"`
+	FreezeWindowID    int64     `db:"freeze_window_id"`
+	Created           time.Time `db:"created"`
+}
+
+func init() {
+	gorpmapping.Register(gorpmapping.New(dbFreezeWindow{}, "freeze_window", true, "id"))
+	gorpmapping.Register(gorpmapping.New(Hold{}, "freeze_hold", false, "workflow_node_run_id"))
+}
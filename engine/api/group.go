@@ -7,6 +7,7 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/environment"
 	"github.com/ovh/cds/engine/api/group"
 	"github.com/ovh/cds/engine/api/pipeline"
@@ -16,6 +17,12 @@ import (
 	"github.com/ovh/cds/sdk/log"
 )
 
+// groupMember is the audited value of a group membership
+type groupMember struct {
+	Username string `json:"username"`
+	Admin    bool   `json:"admin"`
+}
+
 func (api *API) getGroupHandler() Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		// Get group name in URL
@@ -268,10 +275,24 @@ func (api *API) removeUserFromGroupHandler() Handler {
 			return sdk.WrapError(sdk.ErrWrongRequest, "User %s is not in group %s", userName, name)
 		}
 
-		if err := group.DeleteUserFromGroup(api.mustDB(), g.ID, userID); err != nil {
+		tx, errb := api.mustDB().Begin()
+		if errb != nil {
+			return sdk.WrapError(errb, "removeUserFromGroupHandler: Cannot start transaction")
+		}
+		defer tx.Rollback()
+
+		if err := group.DeleteUserFromGroup(tx, g.ID, userID); err != nil {
 			return sdk.WrapError(err, "removeUserFromGroupHandler: Cannot delete user %s from group %s", userName, g.Name)
 		}
 
+		if err := audit.Record(tx, getUser(ctx), "", sdk.AuditGroup, name+"/"+userName, audit.Deleted, groupMember{Username: userName}, nil); err != nil {
+			return sdk.WrapError(err, "removeUserFromGroupHandler: Cannot record audit")
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "removeUserFromGroupHandler: Cannot commit transaction")
+		}
+
 		log.Info("User %s removed from group %s", userName, name)
 		return nil
 	}
@@ -295,23 +316,26 @@ func (api *API) addUserInGroupHandler() Handler {
 
 		tx, errb := api.mustDB().Begin()
 		if errb != nil {
-			return errb
+			return sdk.WrapError(errb, "AddUserInGroup: Cannot start transaction")
 		}
 		defer tx.Rollback()
 
 		for _, u := range users {
-			userID, errf := user.FindUserIDByName(api.mustDB(), u)
+			userID, errf := user.FindUserIDByName(tx, u)
 			if errf != nil {
 				return sdk.WrapError(errf, "AddUserInGroup: Unknown user '%s'", u)
 			}
-			userInGroup, errc := group.CheckUserInGroup(api.mustDB(), g.ID, userID)
+			userInGroup, errc := group.CheckUserInGroup(tx, g.ID, userID)
 			if errc != nil {
 				return sdk.WrapError(errc, "AddUserInGroup: Cannot check if user %s is already in the group %s", u, g.Name)
 			}
 			if !userInGroup {
-				if err := group.InsertUserInGroup(api.mustDB(), g.ID, userID, false); err != nil {
+				if err := group.InsertUserInGroup(tx, g.ID, userID, false); err != nil {
 					return sdk.WrapError(err, "AddUserInGroup: Cannot add user %s in group %s", u, g.Name)
 				}
+				if err := audit.Record(tx, getUser(ctx), "", sdk.AuditGroup, name+"/"+u, audit.Added, nil, groupMember{Username: u}); err != nil {
+					return sdk.WrapError(err, "AddUserInGroup: Cannot record audit")
+				}
 			}
 		}
 
@@ -340,10 +364,24 @@ func (api *API) setUserGroupAdminHandler() Handler {
 			return sdk.WrapError(sdk.ErrNotFound, "setUserGroupAdminHandler: Unknown user %s: %s", userName, errf)
 		}
 
-		if err := group.SetUserGroupAdmin(api.mustDB(), g.ID, userID); err != nil {
+		tx, errb := api.mustDB().Begin()
+		if errb != nil {
+			return sdk.WrapError(errb, "setUserGroupAdminHandler: Cannot start transaction")
+		}
+		defer tx.Rollback()
+
+		if err := group.SetUserGroupAdmin(tx, g.ID, userID); err != nil {
 			return sdk.WrapError(err, "setUserGroupAdminHandler: cannot set user group admin")
 		}
 
+		if err := audit.Record(tx, getUser(ctx), "", sdk.AuditGroup, name+"/"+userName, audit.Updated, groupMember{Username: userName}, groupMember{Username: userName, Admin: true}); err != nil {
+			return sdk.WrapError(err, "setUserGroupAdminHandler: Cannot record audit")
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "setUserGroupAdminHandler: Cannot commit transaction")
+		}
+
 		return nil
 	}
 }
@@ -365,10 +403,24 @@ func (api *API) removeUserGroupAdminHandler() Handler {
 			return sdk.WrapError(sdk.ErrNotFound, "removeUserGroupAdminHandler: Unknown user %s: %s", userName, errf)
 		}
 
-		if err := group.RemoveUserGroupAdmin(api.mustDB(), g.ID, userID); err != nil {
+		tx, errb := api.mustDB().Begin()
+		if errb != nil {
+			return sdk.WrapError(errb, "removeUserGroupAdminHandler: Cannot start transaction")
+		}
+		defer tx.Rollback()
+
+		if err := group.RemoveUserGroupAdmin(tx, g.ID, userID); err != nil {
 			return sdk.WrapError(err, "removeUserGroupAdminHandler: cannot remove user group admin privilege")
 		}
 
+		if err := audit.Record(tx, getUser(ctx), "", sdk.AuditGroup, name+"/"+userName, audit.Updated, groupMember{Username: userName, Admin: true}, groupMember{Username: userName}); err != nil {
+			return sdk.WrapError(err, "removeUserGroupAdminHandler: Cannot record audit")
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "removeUserGroupAdminHandler: Cannot commit transaction")
+		}
+
 		return nil
 	}
 }
@@ -22,6 +22,10 @@ func DeleteGroupAndDependencies(db gorp.SqlExecutor, group *sdk.Group) error {
 		return sdk.WrapError(err, "deleteGroupAndDependencies: Cannot delete group env %s: %s", group.Name)
 	}
 
+	if err := deleteGroupWorkflowByGroup(db, group); err != nil {
+		return sdk.WrapError(err, "deleteGroupAndDependencies: Cannot delete group workflow %s", group.Name)
+	}
+
 	if err := deleteGroupPipelineByGroup(db, group); err != nil {
 		return sdk.WrapError(err, "deleteGroupAndDependencies: Cannot delete group pipeline %s: %s", group.Name)
 	}
@@ -0,0 +1,36 @@
+package group
+
+import (
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// LoadQueueSettings loads the job queue settings of all groups having settings
+func LoadQueueSettings(db gorp.SqlExecutor) (map[int64]sdk.GroupQueueSettings, error) {
+	rows, err := db.Query("SELECT group_id, weight, max_building FROM group_queue_settings")
+	if err != nil {
+		return nil, sdk.WrapError(err, "LoadQueueSettings> Cannot load queue settings")
+	}
+	defer rows.Close()
+
+	settings := map[int64]sdk.GroupQueueSettings{}
+	for rows.Next() {
+		var s sdk.GroupQueueSettings
+		if err := rows.Scan(&s.GroupID, &s.Weight, &s.MaxBuilding); err != nil {
+			return nil, sdk.WrapError(err, "LoadQueueSettings> Cannot scan queue settings")
+		}
+		settings[s.GroupID] = s
+	}
+	return settings, nil
+}
+
+// UpsertQueueSettings inserts or updates the job queue settings of a group
+func UpsertQueueSettings(db gorp.SqlExecutor, s sdk.GroupQueueSettings) error {
+	query := `INSERT INTO group_queue_settings (group_id, weight, max_building) VALUES ($1, $2, $3)
+	ON CONFLICT (group_id) DO UPDATE SET weight = $2, max_building = $3`
+	if _, err := db.Exec(query, s.GroupID, s.Weight, s.MaxBuilding); err != nil {
+		return sdk.WrapError(err, "UpsertQueueSettings> Cannot save queue settings of group %d", s.GroupID)
+	}
+	return nil
+}
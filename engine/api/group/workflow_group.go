@@ -0,0 +1,100 @@
+package group
+
+import (
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// LoadGroupsByWorkflow retrieves all groups related to a workflow
+func LoadGroupsByWorkflow(db gorp.SqlExecutor, workflowID int64) ([]sdk.GroupPermission, error) {
+	query := `SELECT "group".id, "group".name, workflow_group.role FROM "group"
+		JOIN workflow_group ON workflow_group.group_id = "group".id
+		WHERE workflow_group.workflow_id = $1 ORDER BY "group".name ASC`
+
+	rows, err := db.Query(query, workflowID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := []sdk.GroupPermission{}
+	for rows.Next() {
+		var group sdk.Group
+		var perm int
+		if err := rows.Scan(&group.ID, &group.Name, &perm); err != nil {
+			return nil, err
+		}
+		groups = append(groups, sdk.GroupPermission{
+			Group:      group,
+			Permission: perm,
+		})
+	}
+	return groups, nil
+}
+
+// LoadAllWorkflowGroupByRole load all group for the given workflow and role
+func LoadAllWorkflowGroupByRole(db gorp.SqlExecutor, workflowID int64, role int) ([]sdk.GroupPermission, error) {
+	groupsPermission := []sdk.GroupPermission{}
+	query := `SELECT workflow_group.group_id, workflow_group.role
+		FROM workflow_group
+		WHERE workflow_group.workflow_id = $1 AND role = $2`
+	rows, err := db.Query(query, workflowID, role)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var gPermission sdk.GroupPermission
+		if err := rows.Scan(&gPermission.Group.ID, &gPermission.Permission); err != nil {
+			return nil, err
+		}
+		groupsPermission = append(groupsPermission, gPermission)
+	}
+	return groupsPermission, nil
+}
+
+// CountWorkflowGroups returns the number of groups having permissions on a workflow
+func CountWorkflowGroups(db gorp.SqlExecutor, projectKey, workflowName string) (int64, error) {
+	query := `SELECT COUNT(workflow_group.id) FROM workflow_group
+		JOIN workflow ON workflow.id = workflow_group.workflow_id
+		JOIN project ON project.id = workflow.project_id
+		WHERE project.projectkey = $1 AND workflow.name = $2`
+	return db.SelectInt(query, projectKey, workflowName)
+}
+
+// IsInWorkflow checks wether groups already has permissions on workflow or not
+func IsInWorkflow(db gorp.SqlExecutor, workflowID, groupID int64) (bool, error) {
+	count, err := db.SelectInt("SELECT COUNT(id) FROM workflow_group WHERE workflow_id = $1 AND group_id = $2", workflowID, groupID)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// InsertGroupInWorkflow add permissions on workflow to group
+func InsertGroupInWorkflow(db gorp.SqlExecutor, workflowID, groupID int64, role int) error {
+	query := `INSERT INTO workflow_group (workflow_id, group_id, role) VALUES ($1, $2, $3)`
+	_, err := db.Exec(query, workflowID, groupID, role)
+	return err
+}
+
+// UpdateGroupRoleInWorkflow update permission on workflow
+func UpdateGroupRoleInWorkflow(db gorp.SqlExecutor, workflowID, groupID int64, role int) error {
+	query := `UPDATE workflow_group SET role = $3 WHERE workflow_id = $1 AND group_id = $2`
+	_, err := db.Exec(query, workflowID, groupID, role)
+	return err
+}
+
+// DeleteGroupFromWorkflow removes access to workflow to group members
+func DeleteGroupFromWorkflow(db gorp.SqlExecutor, workflowID, groupID int64) error {
+	query := `DELETE FROM workflow_group WHERE workflow_id = $1 AND group_id = $2`
+	_, err := db.Exec(query, workflowID, groupID)
+	return err
+}
+
+func deleteGroupWorkflowByGroup(db gorp.SqlExecutor, group *sdk.Group) error {
+	query := `DELETE FROM workflow_group WHERE group_id = $1`
+	_, err := db.Exec(query, group.ID)
+	return err
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/database"
 	"github.com/ovh/cds/engine/api/grpc"
+	"github.com/ovh/cds/engine/api/notification"
 	"github.com/ovh/cds/engine/api/pipeline"
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/api/worker"
@@ -96,6 +97,7 @@ func (h *grpcHandlers) SendResult(c context.Context, res *sdk.Result) (*empty.Em
 		return new(empty.Empty), sdk.WrapError(errb, "postWorkflowJobResultHandler> Cannot begin tx")
 	}
 	defer tx.Rollback()
+	defer notification.Discard(tx)
 
 	//Update worker status
 	if err := worker.UpdateWorkerStatus(tx, workerID, sdk.StatusWaiting); err != nil {
@@ -145,6 +147,7 @@ func (h *grpcHandlers) SendResult(c context.Context, res *sdk.Result) (*empty.Em
 	if err := tx.Commit(); err != nil {
 		return new(empty.Empty), sdk.WrapError(err, "postWorkflowJobResultHandler> Cannot commit tx")
 	}
+	notification.Flush(tx)
 
 	return new(empty.Empty), nil
 }
@@ -14,6 +14,7 @@ import (
 	cdsgrpc "github.com/ovh/cds/engine/api/grpc"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/tracing"
 )
 
 // grpcInit initialize all GRPC services
@@ -67,7 +68,11 @@ func (h *grpcHandlers) streamInterceptor(srv interface{}, stream grpc.ServerStre
 	m := metadata.Pairs(string(keyWorkerID), w.ID, string(keyWorkerName), w.Name)
 	stream.SendHeader(m)
 
-	return handler(srv, stream)
+	_, span := tracing.Start(tracing.ExtractMetadata(c), info.FullMethod)
+	defer span.Finish()
+	err = handler(srv, stream)
+	span.SetError(err)
+	return err
 }
 
 func (h *grpcHandlers) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
@@ -78,7 +83,12 @@ func (h *grpcHandlers) unaryInterceptor(ctx context.Context, req interface{}, in
 	}
 	ctx = context.WithValue(ctx, keyWorkerID, w.ID)
 	ctx = context.WithValue(ctx, keyWorkerName, w.Name)
-	return handler(ctx, req)
+
+	tctx, span := tracing.Start(tracing.ExtractMetadata(ctx), info.FullMethod)
+	defer span.Finish()
+	resp, err = handler(tctx, req)
+	span.SetError(err)
+	return resp, err
 }
 
 func (h *grpcHandlers) authorize(ctx context.Context) (*sdk.Worker, error) {
@@ -0,0 +1,160 @@
+package metrics
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+var (
+	// HTTPRequestDuration is the latency of the handlers of the API router, by route, method and status
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "http_request_duration_seconds", Help: "metrics http_request_duration_seconds: latency of the HTTP handlers"}, []string{"route", "method", "status"})
+
+	jobDuration           = prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "job_duration_seconds", Help: "metrics job_duration_seconds: duration of the jobs from their start, by final status", Buckets: prometheus.ExponentialBuckets(5, 2, 12)}, []string{"status"})
+	hatcherySpawnDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "api_spawn_duration_seconds", Help: "metrics api_spawn_duration_seconds: duration of the successful spawns of workers for jobs, by hatchery", Buckets: prometheus.ExponentialBuckets(1, 2, 10)}, []string{"hatchery"})
+	hatcherySpawnErrors   = prometheus.NewCounterVec(prometheus.CounterOpts{Name: "api_spawn_errors_total", Help: "metrics api_spawn_errors_total: failed spawns of workers for jobs, by hatchery"}, []string{"hatchery"})
+	queueDepth            = prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "queue_depth", Help: "metrics queue_depth: waiting workflow jobs by required model and group allowed to run them"}, []string{"model", "group"})
+	queueWait             = prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "queue_wait_seconds", Help: "metrics queue_wait_seconds: wait time of the oldest waiting workflow job by required model and group allowed to run them"}, []string{"model", "group"})
+	workerHeartbeatLag    = prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "worker_heartbeat_lag_seconds", Help: "metrics worker_heartbeat_lag_seconds: highest time since the last heartbeat of the workers, by model"}, []string{"model"})
+	dbConnections         = prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "db_connections", Help: "metrics db_connections: connections of the database pool, by state"}, []string{"state"})
+	dbWaitCount           = prometheus.NewGauge(prometheus.GaugeOpts{Name: "db_wait_count", Help: "metrics db_wait_count: total number of connections waited for"})
+	dbWaitDuration        = prometheus.NewGauge(prometheus.GaugeOpts{Name: "db_wait_duration_seconds", Help: "metrics db_wait_duration_seconds: total time blocked waiting for a new connection"})
+)
+
+// registerEngineMetrics registers the operational metrics of the engine
+func registerEngineMetrics() {
+	registry.MustRegister(HTTPRequestDuration, jobDuration, hatcherySpawnDuration, hatcherySpawnErrors, queueDepth, queueWait,
+		workerHeartbeatLag, dbConnections, dbWaitCount, dbWaitDuration, cache.OperationDuration)
+}
+
+// ObserveJobDuration records the duration of a job which ended with a status
+func ObserveJobDuration(status string, start, done time.Time) {
+	if start.IsZero() || done.Before(start) {
+		return
+	}
+	jobDuration.WithLabelValues(status).Observe(done.Sub(start).Seconds())
+}
+
+// ObserveSpawnInfos records the spawn durations and errors sent by hatcheries in spawn infos
+func ObserveSpawnInfos(infos []sdk.SpawnInfo) {
+	starts := map[string]time.Time{}
+	for _, i := range infos {
+		if len(i.Message.Args) == 0 {
+			continue
+		}
+		hatchery, _ := i.Message.Args[0].(string)
+		switch i.Message.ID {
+		case sdk.MsgSpawnInfoHatcheryStarts.ID:
+			starts[hatchery] = i.RemoteTime
+		case sdk.MsgSpawnInfoHatcheryStartsSuccessfully.ID:
+			if start, ok := starts[hatchery]; ok {
+				hatcherySpawnDuration.WithLabelValues(hatchery).Observe(i.RemoteTime.Sub(start).Seconds())
+			}
+		case sdk.MsgSpawnInfoHatcheryErrorSpawn.ID:
+			hatcherySpawnErrors.WithLabelValues(hatchery).Inc()
+		}
+	}
+}
+
+// queueMetrics sets the depth and the wait time of the queue of workflow jobs
+func queueMetrics(db *gorp.DbMap) {
+	if db == nil {
+		return
+	}
+	query := `select workflow_node_run_job.job, workflow_node_run_job.queued, "group".name
+	from workflow_node_run_job
+	join workflow_node_run on workflow_node_run.id = workflow_node_run_job.workflow_node_run_id
+	join workflow_run on workflow_run.id = workflow_node_run.workflow_run_id
+	join project_group on project_group.project_id = workflow_run.project_id and project_group.role >= $2
+	join "group" on "group".id = project_group.group_id
+	where workflow_node_run_job.status = $1`
+	// Groups allowed to run the jobs have the execution permission on the project
+	rows, err := db.Query(query, sdk.StatusWaiting.String(), permission.PermissionReadExecute)
+	if err != nil {
+		log.Warning("metrics>Errors while fetching queue: %v", err)
+		return
+	}
+	defer rows.Close()
+
+	type key struct{ model, group string }
+	depth := map[key]float64{}
+	wait := map[key]float64{}
+	now := time.Now()
+	for rows.Next() {
+		var jobJSON, group string
+		var queued time.Time
+		if err := rows.Scan(&jobJSON, &queued, &group); err != nil {
+			log.Warning("metrics>Errors while scanning queue: %v", err)
+			return
+		}
+		var job sdk.ExecutedJob
+		if err := json.Unmarshal([]byte(jobJSON), &job); err != nil {
+			continue
+		}
+		k := key{group: group}
+		for _, r := range job.Action.Requirements {
+			if r.Type == sdk.ModelRequirement {
+				k.model, _ = sdk.ParseModelRequirement(r.Value)
+			}
+		}
+		depth[k]++
+		if w := now.Sub(queued).Seconds(); w > wait[k] {
+			wait[k] = w
+		}
+	}
+
+	queueDepth.Reset()
+	queueWait.Reset()
+	for k, n := range depth {
+		queueDepth.WithLabelValues(k.model, k.group).Set(n)
+		queueWait.WithLabelValues(k.model, k.group).Set(wait[k])
+	}
+}
+
+// workerHeartbeats sets the highest heartbeat lag of the workers of each model
+func workerHeartbeats(db *gorp.DbMap) {
+	if db == nil {
+		return
+	}
+	query := `select coalesce(worker_model.name, ''), coalesce(extract(epoch from max(now() - worker.last_beat)), 0)
+	from worker
+	left join worker_model on worker_model.id = worker.model
+	group by worker_model.name`
+	rows, err := db.Query(query)
+	if err != nil {
+		log.Warning("metrics>Errors while fetching worker heartbeats: %v", err)
+		return
+	}
+	defer rows.Close()
+
+	workerHeartbeatLag.Reset()
+	for rows.Next() {
+		var model string
+		var lag float64
+		if err := rows.Scan(&model, &lag); err != nil {
+			log.Warning("metrics>Errors while scanning worker heartbeats: %v", err)
+			return
+		}
+		workerHeartbeatLag.WithLabelValues(model).Set(lag)
+	}
+}
+
+// dbPool sets the stats of the database connections pool
+func dbPool(db *gorp.DbMap) {
+	if db == nil || db.Db == nil {
+		return
+	}
+	s := db.Db.Stats()
+	dbConnections.WithLabelValues("open").Set(float64(s.OpenConnections))
+	dbConnections.WithLabelValues("in_use").Set(float64(s.InUse))
+	dbConnections.WithLabelValues("idle").Set(float64(s.Idle))
+	dbWaitCount.Set(float64(s.WaitCount))
+	dbWaitDuration.Set(s.WaitDuration.Seconds())
+}
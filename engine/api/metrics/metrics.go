@@ -2,10 +2,13 @@ package metrics
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/go-gorp/gorp"
 
+	"github.com/ovh/cds/engine/api/stats"
+	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -36,7 +39,26 @@ func Initialize(c context.Context, DBFunc func() *gorp.DbMap, instance string) {
 	registry.MustRegister(nbArtifacts)
 	registry.MustRegister(nbWorkerModels)
 
+	workerModelPools := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "worker_model_pool", Help: "metrics worker_model_pool: workers idle and building and pool bounds of worker models with a pool policy", ConstLabels: labels}, []string{"model", "state"})
+	workerModelPoolsUtilization := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "worker_model_pool_utilization", Help: "metrics worker_model_pool_utilization: ratio of building workers of worker models with a pool policy", ConstLabels: labels}, []string{"model"})
+	registry.MustRegister(workerModelPools)
+	registry.MustRegister(workerModelPoolsUtilization)
+
+	registerEngineMetrics()
+
+	deliveryLabels := []string{"project", "workflow"}
+	delivery := map[string]*prometheus.GaugeVec{
+		"deployment_frequency": prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "delivery_deployment_frequency", Help: "metrics delivery_deployment_frequency: successful deployments by day on the last 30 days", ConstLabels: labels}, deliveryLabels),
+		"lead_time":            prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "delivery_lead_time_seconds", Help: "metrics delivery_lead_time_seconds: average duration from a commit to its deployment on the last 30 days", ConstLabels: labels}, deliveryLabels),
+		"mttr":                 prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "delivery_mttr_seconds", Help: "metrics delivery_mttr_seconds: average duration from a failed deployment to the next successful one on the last 30 days", ConstLabels: labels}, deliveryLabels),
+		"change_failure_rate":  prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "delivery_change_failure_rate", Help: "metrics delivery_change_failure_rate: ratio of failed deployments on the last 30 days", ConstLabels: labels}, deliveryLabels),
+	}
+	for _, g := range delivery {
+		registry.MustRegister(g)
+	}
+
 	tick := time.NewTicker(30 * time.Second).C
+	deliveryTick := time.NewTicker(5 * time.Minute).C
 
 	go func(c context.Context, DBFunc func() *gorp.DbMap) {
 		for {
@@ -55,6 +77,12 @@ func Initialize(c context.Context, DBFunc func() *gorp.DbMap, instance string) {
 				count(DBFunc(), "SELECT COUNT(1) FROM workflow", nbWorkflows)
 				count(DBFunc(), "SELECT COUNT(1) FROM artifact", nbArtifacts)
 				count(DBFunc(), "SELECT COUNT(1) FROM worker_model", nbWorkerModels)
+				workerPools(DBFunc(), workerModelPools, workerModelPoolsUtilization)
+				queueMetrics(DBFunc())
+				workerHeartbeats(DBFunc())
+				dbPool(DBFunc())
+			case <-deliveryTick:
+				deliveryMetrics(DBFunc(), delivery)
 			}
 		}
 	}(c, DBFunc)
@@ -72,6 +100,74 @@ func count(db *gorp.DbMap, query string, v prometheus.Summary) {
 	v.Observe(float64(n))
 }
 
+// workerPools sets the usage of the pools of worker models
+func workerPools(db *gorp.DbMap, pools, utilization *prometheus.GaugeVec) {
+	if db == nil {
+		return
+	}
+	query := `select worker_model.name, worker_model.pool_policy,
+		count(worker.id) filter (where worker.status = $1),
+		count(worker.id) filter (where worker.status = $2)
+	from worker_model
+	left join worker on worker.model = worker_model.id
+	where worker_model.pool_policy is not null
+	group by worker_model.id`
+	rows, err := db.Query(query, sdk.StatusWaiting.String(), sdk.StatusBuilding.String())
+	if err != nil {
+		log.Warning("metrics>Errors while fetching worker model pools: %v", err)
+		return
+	}
+	defer rows.Close()
+
+	pools.Reset()
+	utilization.Reset()
+	for rows.Next() {
+		var name, policyJSON string
+		var idle, building float64
+		if err := rows.Scan(&name, &policyJSON, &idle, &building); err != nil {
+			log.Warning("metrics>Errors while scanning worker model pools: %v", err)
+			return
+		}
+		var policy sdk.ModelPoolPolicy
+		if err := json.Unmarshal([]byte(policyJSON), &policy); err != nil || !policy.Enabled() {
+			continue
+		}
+		pools.WithLabelValues(name, "idle").Set(idle)
+		pools.WithLabelValues(name, "building").Set(building)
+		pools.WithLabelValues(name, "min_idle").Set(float64(policy.MinIdle))
+		pools.WithLabelValues(name, "max").Set(float64(policy.Max))
+		if idle+building > 0 {
+			utilization.WithLabelValues(name).Set(building / (idle + building))
+		} else {
+			utilization.WithLabelValues(name).Set(0)
+		}
+	}
+}
+
+// deliveryMetrics sets the delivery metrics of the workflows on the last 30 days
+func deliveryMetrics(db *gorp.DbMap, gauges map[string]*prometheus.GaugeVec) {
+	if db == nil {
+		return
+	}
+	to := time.Now()
+	from := to.AddDate(0, 0, -30)
+	deployments, err := stats.LoadDeployments(db, "", "", from, to)
+	if err != nil {
+		log.Warning("metrics>Errors while fetching deployments: %v", err)
+		return
+	}
+
+	for _, g := range gauges {
+		g.Reset()
+	}
+	for _, m := range stats.ComputeDeliveryMetricsByWorkflow(deployments, from, to) {
+		gauges["deployment_frequency"].WithLabelValues(m.ProjectKey, m.WorkflowName).Set(m.DeploymentFrequency)
+		gauges["lead_time"].WithLabelValues(m.ProjectKey, m.WorkflowName).Set(m.LeadTime)
+		gauges["mttr"].WithLabelValues(m.ProjectKey, m.WorkflowName).Set(m.MTTR)
+		gauges["change_failure_rate"].WithLabelValues(m.ProjectKey, m.WorkflowName).Set(m.ChangeFailureRate)
+	}
+}
+
 // GetGatherer returns CDS API gatherer
 func GetGatherer() prometheus.Gatherer {
 	return registry
@@ -0,0 +1,12 @@
+package notification
+
+import (
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+type workflowNotification sdk.WorkflowNotification
+
+func init() {
+	gorpmapping.Register(gorpmapping.New(workflowNotification{}, "workflow_notification", true, "id"))
+}
@@ -18,3 +18,12 @@ func SendMailNotif(notif sdk.EventNotif) {
 		}
 	}
 }
+
+func sendMail(notif sdk.EventNotif) error {
+	for _, recipient := range notif.Recipients {
+		if err := mail.SendEmail(notif.Subject, bytes.NewBufferString(notif.Body), recipient); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package notification
+
+import (
+	"sync"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database"
+)
+
+// The notifications computed in a transaction are only sent once it is committed
+var (
+	queues      = map[*gorp.Transaction][]func(){}
+	queuesMutex sync.Mutex
+)
+
+// enqueue records a notification to send when the transaction db is committed. It is sent at once if db is not a
+// transaction
+func enqueue(db gorp.SqlExecutor, send func()) {
+	tx, ok := database.Unwrap(db).(*gorp.Transaction)
+	if !ok {
+		go send()
+		return
+	}
+	queuesMutex.Lock()
+	queues[tx] = append(queues[tx], send)
+	queuesMutex.Unlock()
+}
+
+// Flush sends the notifications recorded in a transaction. It must be called once the transaction is committed
+func Flush(tx *gorp.Transaction) {
+	queuesMutex.Lock()
+	sends := queues[tx]
+	delete(queues, tx)
+	queuesMutex.Unlock()
+	for _, send := range sends {
+		go send()
+	}
+}
+
+// Discard drops the notifications recorded in a transaction, it is meant to be deferred with its rollback
+func Discard(tx *gorp.Transaction) {
+	queuesMutex.Lock()
+	delete(queues, tx)
+	queuesMutex.Unlock()
+}
@@ -0,0 +1,108 @@
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// webhookAllowedNetworks are the internal networks which webhooks can be sent to, see InitWebhooks
+var webhookAllowedNetworks []*net.IPNet
+
+var webhookClient = &http.Client{
+	Timeout: 10 * time.Second,
+	Transport: &http.Transport{
+		// No proxy is used: the address is checked once resolved, on each connection, redirects included
+		DialContext: (&net.Dialer{
+			Timeout: 5 * time.Second,
+			Control: func(network, address string, c syscall.RawConn) error {
+				return checkWebhookAddress(address)
+			},
+		}).DialContext,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ResponseHeaderTimeout: 10 * time.Second,
+	},
+}
+
+// InitWebhooks sets the internal networks, in CIDR notation, which webhooks can be sent to. Loopback,
+// private, link-local and multicast addresses are refused otherwise
+func InitWebhooks(allowedNetworks []string) error {
+	nets := make([]*net.IPNet, 0, len(allowedNetworks))
+	for _, n := range allowedNetworks {
+		_, ipnet, err := net.ParseCIDR(n)
+		if err != nil {
+			return fmt.Errorf("invalid webhook allowed network %s: %v", n, err)
+		}
+		nets = append(nets, ipnet)
+	}
+	webhookAllowedNetworks = nets
+	return nil
+}
+
+// checkWebhookAddress refuses to connect to internal addresses which are not explicitly allowed
+func checkWebhookAddress(address string) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("invalid webhook address %s", address)
+	}
+	for _, n := range webhookAllowedNetworks {
+		if n.Contains(ip) {
+			return nil
+		}
+	}
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return fmt.Errorf("webhook address %s is not allowed", host)
+	}
+	return nil
+}
+
+// sendWebhook posts a Slack or Mattermost compatible message on each webhook URL
+func sendWebhook(notif sdk.EventNotif) error {
+	text := notif.Body
+	if notif.Subject != "" {
+		text = "*" + notif.Subject + "*\n" + notif.Body
+	}
+	b, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return err
+	}
+
+	for _, u := range notif.Recipients {
+		if err := postWebhook(u, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func postWebhook(u string, body []byte) error {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return fmt.Errorf("invalid webhook %s: %v", u, err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("invalid webhook %s: only http and https are supported", u)
+	}
+
+	resp, err := webhookClient.Post(parsed.String(), "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned HTTP code %d", u, resp.StatusCode)
+	}
+	return nil
+}
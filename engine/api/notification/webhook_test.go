@@ -0,0 +1,48 @@
+package notification
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestCheckWebhookAddress(t *testing.T) {
+	defer InitWebhooks(nil)
+
+	for _, a := range []string{"127.0.0.1:80", "10.1.2.3:443", "192.168.0.1:80", "169.254.169.254:80", "[::1]:80", "[fe80::1]:80", "0.0.0.0:80"} {
+		assert.Error(t, checkWebhookAddress(a), a)
+	}
+	assert.NoError(t, checkWebhookAddress("8.8.8.8:443"))
+
+	assert.NoError(t, InitWebhooks([]string{"10.0.0.0/8"}))
+	assert.NoError(t, checkWebhookAddress("10.1.2.3:443"))
+	assert.Error(t, checkWebhookAddress("169.254.169.254:80"))
+
+	assert.Error(t, InitWebhooks([]string{"10.0.0.0"}))
+}
+
+func TestSendWebhook(t *testing.T) {
+	defer InitWebhooks(nil)
+
+	var received int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received++
+	}))
+	defer srv.Close()
+
+	notif := sdk.EventNotif{Subject: "subject", Body: "body", Recipients: []string{srv.URL}}
+
+	// The test server listens on a loopback address
+	assert.Error(t, sendWebhook(notif))
+	assert.Equal(t, 0, received)
+
+	assert.NoError(t, InitWebhooks([]string{"127.0.0.0/8"}))
+	assert.NoError(t, sendWebhook(notif))
+	assert.Equal(t, 1, received)
+
+	assert.Error(t, sendWebhook(sdk.EventNotif{Recipients: []string{"file:///etc/passwd"}}))
+}
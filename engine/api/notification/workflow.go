@@ -0,0 +1,165 @@
+package notification
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/engine/api/user"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const (
+	defaultWorkflowSubject = "{{.cds.project}}/{{.cds.workflow}}#{{.cds.version}} {{.cds.node}}: {{.cds.status}}"
+	defaultWorkflowBody    = "Project : {{.cds.project}}\nWorkflow : {{.cds.workflow}}#{{.cds.version}}\nPipeline : {{.cds.node}}\nStatus : {{.cds.status}}\nDetails : {{.cds.buildURL}}"
+)
+
+// Sender delivers notification events of a type
+type Sender interface {
+	Send(sdk.EventNotif) error
+}
+
+// SenderFunc allows to use a function as a Sender
+type SenderFunc func(sdk.EventNotif) error
+
+// Send calls f(notif)
+func (f SenderFunc) Send(notif sdk.EventNotif) error {
+	return f(notif)
+}
+
+var senders = map[sdk.UserNotificationSettingsType]Sender{
+	sdk.EmailUserNotification:   SenderFunc(sendMail),
+	sdk.WebhookUserNotification: SenderFunc(sendWebhook),
+}
+
+// RegisterSender sets the sender of a notification type. It must be called at startup
+func RegisterSender(t sdk.UserNotificationSettingsType, s Sender) {
+	senders[t] = s
+}
+
+// SendWorkflowNodeRunNotifications evaluates the notification rules of the workflow for a node run and
+// sends the matching ones once the transaction db is committed, see Flush. previousStatus is the status of the
+// previous run of the same node, if any
+func SendWorkflowNodeRunNotifications(db gorp.SqlExecutor, wr *sdk.WorkflowRun, nr *sdk.WorkflowNodeRun, previousStatus string) {
+	notifs, err := LoadWorkflowNotifications(db, wr.WorkflowID)
+	if err != nil {
+		log.Error("notification.SendWorkflowNodeRunNotifications> %v", err)
+		return
+	}
+	if len(notifs) == 0 {
+		return
+	}
+
+	node := wr.Workflow.GetNode(nr.WorkflowNodeID)
+	if node == nil {
+		log.Warning("notification.SendWorkflowNodeRunNotifications> Unable to find node %d in workflow run %d", nr.WorkflowNodeID, wr.ID)
+		return
+	}
+
+	params := map[string]string{}
+	for _, p := range nr.BuildParameters {
+		params[p.Name] = p.Value
+	}
+	params["cds.node"] = node.Name
+	params["cds.status"] = nr.Status
+	params["cds.buildURL"] = fmt.Sprintf("%s/project/%s/workflow/%s/run/%d/node/%d", uiURL, wr.Workflow.ProjectKey, wr.Workflow.Name, nr.Number, nr.ID)
+	author := params["cds.triggered_by.username"]
+	if author == "" {
+		author = params["git.author"]
+	}
+	params["cds.author"] = author
+
+	for i := range notifs {
+		n := &notifs[i]
+		if !n.ShouldSend(node.Name, nr.Status, previousStatus) {
+			continue
+		}
+
+		s, ok := senders[n.Type]
+		if !ok {
+			log.Warning("notification.SendWorkflowNodeRunNotifications> No sender for notification type %s", n.Type)
+			continue
+		}
+
+		recipients, err := workflowRecipients(db, wr, n, author)
+		if err != nil {
+			log.Error("notification.SendWorkflowNodeRunNotifications> Unable to compute recipients of notification %d: %v", n.ID, err)
+			continue
+		}
+		if len(recipients) == 0 {
+			continue
+		}
+
+		e := getWorkflowEvent(n.Settings.Template, recipients, params)
+		t := n.Type
+		enqueue(db, func() {
+			if err := s.Send(e); err != nil {
+				log.Warning("notification.SendWorkflowNodeRunNotifications> Unable to send %s notification '%s': %v", t, e.Subject, err)
+			}
+		})
+	}
+}
+
+// workflowRecipients returns the deduplicated recipients of a notification: emails for email
+// notifications, usernames for jabber ones and URLs for webhooks
+func workflowRecipients(db gorp.SqlExecutor, wr *sdk.WorkflowRun, n *sdk.WorkflowNotification, author string) ([]string, error) {
+	recipients := append([]string{}, n.Settings.Recipients...)
+	if n.Type == sdk.WebhookUserNotification {
+		return recipients, nil
+	}
+
+	var recipient = func(u *sdk.User) string {
+		if n.Type == sdk.EmailUserNotification {
+			return u.Email
+		}
+		return u.Username
+	}
+
+	if n.Settings.SendToGroups {
+		users, err := permission.WorkflowUsers(db, wr.WorkflowID, permission.PermissionRead)
+		if err != nil {
+			return nil, err
+		}
+		for i := range users {
+			recipients = append(recipients, recipient(&users[i]))
+		}
+	}
+
+	if n.Settings.SendToAuthor && author != "" {
+		u, err := user.LoadUserWithoutAuth(db, author)
+		if err != nil {
+			log.Warning("notification.workflowRecipients> Cannot load author %s: %s", author, err)
+		} else {
+			recipients = append(recipients, recipient(u))
+		}
+	}
+
+	removeDuplicates(&recipients)
+	return recipients, nil
+}
+
+func getWorkflowEvent(tmpl sdk.UserNotificationTemplate, recipients []string, params map[string]string) sdk.EventNotif {
+	subject := tmpl.Subject
+	if subject == "" {
+		subject = defaultWorkflowSubject
+	}
+	body := tmpl.Body
+	if body == "" {
+		body = defaultWorkflowBody
+	}
+
+	for k, value := range params {
+		key := "{{." + k + "}}"
+		subject = strings.Replace(subject, key, value, -1)
+		body = strings.Replace(body, key, value, -1)
+	}
+
+	return sdk.EventNotif{
+		Subject:    subject,
+		Body:       body,
+		Recipients: recipients,
+	}
+}
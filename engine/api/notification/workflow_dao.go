@@ -0,0 +1,100 @@
+package notification
+
+import (
+	"database/sql"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+// LoadWorkflowNotifications loads all notification rules of a workflow
+func LoadWorkflowNotifications(db gorp.SqlExecutor, workflowID int64) ([]sdk.WorkflowNotification, error) {
+	dbns := []workflowNotification{}
+	if _, err := db.Select(&dbns, "select * from workflow_notification where workflow_id = $1 order by id", workflowID); err != nil {
+		return nil, sdk.WrapError(err, "LoadWorkflowNotifications> Unable to load notifications of workflow %d", workflowID)
+	}
+	ns := make([]sdk.WorkflowNotification, len(dbns))
+	for i := range dbns {
+		if err := dbns[i].PostGet(db); err != nil {
+			return nil, sdk.WrapError(err, "LoadWorkflowNotifications> Unable to load notification %d", dbns[i].ID)
+		}
+		ns[i] = sdk.WorkflowNotification(dbns[i])
+	}
+	return ns, nil
+}
+
+// LoadWorkflowNotification loads a notification rule of a workflow
+func LoadWorkflowNotification(db gorp.SqlExecutor, workflowID, id int64) (*sdk.WorkflowNotification, error) {
+	dbn := workflowNotification{}
+	if err := db.SelectOne(&dbn, "select * from workflow_notification where workflow_id = $1 and id = $2", workflowID, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.ErrWorkflowNotificationNotFound
+		}
+		return nil, sdk.WrapError(err, "LoadWorkflowNotification> Unable to load notification %d", id)
+	}
+	n := sdk.WorkflowNotification(dbn)
+	return &n, nil
+}
+
+// InsertWorkflowNotification inserts a notification rule
+func InsertWorkflowNotification(db gorp.SqlExecutor, n *sdk.WorkflowNotification) error {
+	dbn := workflowNotification(*n)
+	if err := db.Insert(&dbn); err != nil {
+		return sdk.WrapError(err, "InsertWorkflowNotification> Unable to insert notification on workflow %d", n.WorkflowID)
+	}
+	*n = sdk.WorkflowNotification(dbn)
+	return nil
+}
+
+// UpdateWorkflowNotification updates a notification rule
+func UpdateWorkflowNotification(db gorp.SqlExecutor, n *sdk.WorkflowNotification) error {
+	dbn := workflowNotification(*n)
+	if _, err := db.Update(&dbn); err != nil {
+		return sdk.WrapError(err, "UpdateWorkflowNotification> Unable to update notification %d", n.ID)
+	}
+	return nil
+}
+
+// DeleteWorkflowNotification deletes a notification rule
+func DeleteWorkflowNotification(db gorp.SqlExecutor, n *sdk.WorkflowNotification) error {
+	dbn := workflowNotification(*n)
+	if _, err := db.Delete(&dbn); err != nil {
+		return sdk.WrapError(err, "DeleteWorkflowNotification> Unable to delete notification %d", n.ID)
+	}
+	return nil
+}
+
+// PostInsert is a db hook
+func (n *workflowNotification) PostInsert(db gorp.SqlExecutor) error {
+	return n.PostUpdate(db)
+}
+
+// PostUpdate is a db hook
+func (n *workflowNotification) PostUpdate(db gorp.SqlExecutor) error {
+	nodeNames, err := gorpmapping.JSONToNullString(n.NodeNames)
+	if err != nil {
+		return err
+	}
+	settings, err := gorpmapping.JSONToNullString(n.Settings)
+	if err != nil {
+		return err
+	}
+	if _, err := db.Exec("update workflow_notification set node_names = $2, settings = $3 where id = $1", n.ID, nodeNames, settings); err != nil {
+		return err
+	}
+	return nil
+}
+
+// PostGet is a db hook
+func (n *workflowNotification) PostGet(db gorp.SqlExecutor) error {
+	var nodeNames, settings sql.NullString
+	if err := db.QueryRow("select node_names, settings from workflow_notification where id = $1", n.ID).Scan(&nodeNames, &settings); err != nil {
+		return err
+	}
+	if err := gorpmapping.JSONNullString(nodeNames, &n.NodeNames); err != nil {
+		return err
+	}
+	return gorpmapping.JSONNullString(settings, &n.Settings)
+}
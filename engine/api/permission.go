@@ -30,6 +30,7 @@ func permissionFunc(api *API) map[string]PermCheckFunc {
 		"permActionName":      api.checkActionPermissions,
 		"permEnvironmentName": api.checkEnvironmentPermissions,
 		"permModelID":         api.checkWorkerModelPermissions,
+		"workflowName":        api.checkWorkflowPermissions,
 	}
 }
 
@@ -82,13 +83,27 @@ func (api *API) authMiddleware(ctx context.Context, w http.ResponseWriter, req *
 			}
 		default:
 			var err error
-			ctx, err = api.Router.AuthDriver.CheckAuth(ctx, w, req)
+			if headers.Get(sdk.AccessTokenHeader) != "" {
+				ctx, err = auth.CheckAccessTokenAuth(ctx, api.mustDB(), headers)
+			} else {
+				ctx, err = api.Router.AuthDriver.CheckAuth(ctx, w, req)
+			}
 			if err != nil {
 				return ctx, sdk.WrapError(sdk.ErrUnauthorized, "Router> Authorization denied on %s %s for %s agent %s : %s", req.Method, req.URL, req.RemoteAddr, getAgent(req), err)
 			}
 		}
 	}
 
+	//An access token restricts what its user is allowed to do
+	if t := getAccessToken(ctx); t != nil {
+		if !t.Allows(req.Method, rc.Options["isExecution"] == "true") {
+			return ctx, sdk.WrapError(sdk.ErrForbidden, "Router> Access token %s does not allow %s %s", t.Name, req.Method, req.URL)
+		}
+		if !t.HasScope(sdk.AccessTokenScopeAdmin) {
+			getUser(ctx).Admin = false
+		}
+	}
+
 	//Get the permission for either the hatchery, the worker or the user
 	switch {
 	case getHatchery(ctx) != nil:
@@ -283,6 +298,46 @@ func (api *API) checkEnvironmentPermissions(ctx context.Context, envName string,
 	return false
 }
 
+// checkWorkflowPermissions checks permissions on workflows having groups. Workflows without any group
+// are only protected by their project permissions
+func (api *API) checkWorkflowPermissions(ctx context.Context, workflowName string, perm int, routeVar map[string]string) bool {
+	projectKey, ok := routeVar["permProjectKey"]
+	if !ok {
+		log.Warning("Wrong route configuration. need permProjectKey parameter")
+		return false
+	}
+
+	if permission.PermissionReadExecute == perm && getService(ctx) != nil {
+		return true
+	}
+
+	var hasGroups bool
+	for _, g := range getUser(ctx).Groups {
+		for _, w := range g.WorkflowGroups {
+			if workflowName == w.Workflow.Name && projectKey == w.Workflow.ProjectKey {
+				if w.Permission >= perm {
+					return true
+				}
+				hasGroups = true
+			}
+		}
+	}
+
+	if !hasGroups {
+		nb, err := group.CountWorkflowGroups(api.mustDB(), projectKey, workflowName)
+		if err != nil {
+			log.Warning("checkWorkflowPermissions> Unable to count groups of workflow %s/%s: %s", projectKey, workflowName, err)
+			return false
+		}
+		if nb == 0 {
+			return true
+		}
+	}
+
+	log.Warning("Access denied. user %s on workflow %s", getUser(ctx).Username, workflowName)
+	return false
+}
+
 func (api *API) checkApplicationPermissions(ctx context.Context, applicationName string, permission int, routeVar map[string]string) bool {
 	// Check if param key exist
 	if projectKey, ok := routeVar["key"]; ok {
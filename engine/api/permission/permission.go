@@ -217,3 +217,54 @@ func ApplicationPipelineEnvironmentUsers(db gorp.SqlExecutor, appID, pipID, envI
 	}
 	return users, nil
 }
+
+// WorkflowUsers returns users list with expected access to a workflow. Groups of the project are used
+// when the workflow has no groups
+func WorkflowUsers(db gorp.SqlExecutor, workflowID int64, access int) ([]sdk.User, error) {
+	query := `
+		SELECT 	DISTINCT "user".id, "user".username, "user".data
+		FROM 	"group"
+		JOIN	group_user ON "group".id = group_user.group_id
+		JOIN 	"user" ON group_user.user_id = "user".id
+		WHERE	"group".id <> $3
+		AND	"group".id IN (
+			SELECT	workflow_group.group_id
+			FROM	workflow_group
+			WHERE	workflow_group.workflow_id = $1
+			AND	workflow_group.role >= $2
+			UNION
+			SELECT	project_group.group_id
+			FROM	project_group
+			JOIN	workflow ON workflow.project_id = project_group.project_id
+			WHERE	workflow.id = $1
+			AND	project_group.role >= $2
+			AND	NOT EXISTS (SELECT 1 FROM workflow_group WHERE workflow_group.workflow_id = $1)
+		)
+	`
+	rows, err := db.Query(query, workflowID, access, SharedInfraGroupID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return []sdk.User{}, nil
+		}
+		return []sdk.User{}, err
+	}
+	defer rows.Close()
+
+	users := []sdk.User{}
+	for rows.Next() {
+		u := sdk.User{}
+		var data string
+		if err := rows.Scan(&u.ID, &u.Username, &data); err != nil {
+			log.Warning("permission.WorkflowUsers> error while scanning user : %s", err)
+			continue
+		}
+
+		uTemp := &sdk.User{}
+		if err := json.Unmarshal([]byte(data), uTemp); err != nil {
+			log.Warning("permission.WorkflowUsers> error while parsing user : %s", err)
+			continue
+		}
+		users = append(users, *uTemp)
+	}
+	return users, nil
+}
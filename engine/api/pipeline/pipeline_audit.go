@@ -1,6 +1,7 @@
 package pipeline
 
 import (
+	"database/sql"
 	"encoding/json"
 	"time"
 
@@ -10,14 +11,15 @@ import (
 )
 
 const (
-	AuditAddJob         = "addJob"
-	AuditUpdateJob      = "updateJob"
-	AuditDeleteJob      = "deleteJob"
-	AuditAddStage       = "addStage"
-	AuditUpdateStage    = "updateStage"
-	AuditDeleteStage    = "deleteStage"
-	AuditMoveStage      = "moveStage"
-	AuditUpdatePipeline = "updateStage"
+	AuditAddJob          = "addJob"
+	AuditUpdateJob       = "updateJob"
+	AuditDeleteJob       = "deleteJob"
+	AuditAddStage        = "addStage"
+	AuditUpdateStage     = "updateStage"
+	AuditDeleteStage     = "deleteStage"
+	AuditMoveStage       = "moveStage"
+	AuditUpdatePipeline  = "updateStage"
+	AuditRestorePipeline = "restore"
 )
 
 // CreateAudit insert current pipeline version on audit table
@@ -58,6 +60,19 @@ func LoadAudit(db gorp.SqlExecutor, key string, pipName string) ([]sdk.PipelineA
 	return audits, nil
 }
 
+// LoadAuditByID load an audit of the given pipeline
+func LoadAuditByID(db gorp.SqlExecutor, pipID, id int64) (*sdk.PipelineAudit, error) {
+	var a PipelineAudit
+	if err := db.SelectOne(&a, "SELECT * FROM pipeline_audit WHERE pipeline_id = $1 AND id = $2", pipID, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.ErrAuditNotFound
+		}
+		return nil, sdk.WrapError(err, "LoadAuditByID> Unable to load audit %d", id)
+	}
+	pa := sdk.PipelineAudit(a)
+	return &pa, nil
+}
+
 // DeleteAudit delete audit related to given pipeline
 func DeleteAudit(db gorp.SqlExecutor, pipID int64) error {
 	_, err := db.Exec("DELETE FROM pipeline_audit WHERE pipeline_id = $1", pipID)
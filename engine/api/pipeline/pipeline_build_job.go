@@ -55,13 +55,22 @@ func GetPipelineBuildJobByPipelineBuildID(db gorp.SqlExecutor, pbID int64) ([]sd
 
 // GetWaitingPipelineBuildJob Get waiting pipeline build job
 func GetWaitingPipelineBuildJob(db gorp.SqlExecutor) ([]sdk.PipelineBuildJob, error) {
+	return getPipelineBuildJobByStatus(db, sdk.StatusWaiting)
+}
+
+// GetBuildingPipelineBuildJob Get building pipeline build job
+func GetBuildingPipelineBuildJob(db gorp.SqlExecutor) ([]sdk.PipelineBuildJob, error) {
+	return getPipelineBuildJobByStatus(db, sdk.StatusBuilding)
+}
+
+func getPipelineBuildJobByStatus(db gorp.SqlExecutor, status sdk.Status) ([]sdk.PipelineBuildJob, error) {
 	var pbJobsGorp []PipelineBuildJob
 	query := `
 		SELECT *
 		FROM pipeline_build_job
 		WHERE status = $1
 	`
-	if _, err := db.Select(&pbJobsGorp, query, sdk.StatusWaiting.String()); err != nil {
+	if _, err := db.Select(&pbJobsGorp, query, status.String()); err != nil {
 		return nil, err
 	}
 	var pbJobs []sdk.PipelineBuildJob
@@ -88,6 +97,17 @@ func GetPipelineBuildJobForUpdate(db gorp.SqlExecutor, id int64) (*sdk.PipelineB
 	return &pbJob, nil
 }
 
+// LockBuildingPipelineBuildJob locks a building pipeline build job. It returns false if the job is not building
+// anymore, or if it is already locked, ie. by another API instance
+func LockBuildingPipelineBuildJob(db gorp.SqlExecutor, id int64) (bool, error) {
+	query := `SELECT id FROM pipeline_build_job WHERE id = $1 AND status = $2 FOR UPDATE SKIP LOCKED`
+	locked, err := db.SelectInt(query, id, sdk.StatusBuilding.String())
+	if err != nil {
+		return false, sdk.WrapError(err, "LockBuildingPipelineBuildJob> Unable to lock pipeline build job %d", id)
+	}
+	return locked != 0, nil
+}
+
 // GetPipelineBuildJob Get pipeline build job
 func GetPipelineBuildJob(db gorp.SqlExecutor, id int64) (*sdk.PipelineBuildJob, error) {
 	var pbJobGorp PipelineBuildJob
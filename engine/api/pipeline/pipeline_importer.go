@@ -213,8 +213,10 @@ func Import(db gorp.SqlExecutor, proj *sdk.Project, pip *sdk.Pipeline, msgChan c
 				if msgChan != nil {
 					msgChan <- sdk.NewMessage(sdk.MsgPipelineCreationAborted, pip.Name)
 				}
-				for _, m := range *err.(*sdk.Errors) {
-					msgChan <- m
+				if msgChan != nil {
+					for _, m := range *err.(*sdk.Errors) {
+						msgChan <- m
+					}
 				}
 				return sdk.ErrInvalidPipeline
 			default:
@@ -232,7 +234,7 @@ func Import(db gorp.SqlExecutor, proj *sdk.Project, pip *sdk.Pipeline, msgChan c
 	}
 	//Be confident: use the pipeline
 	*pip = *pip2
-	if ok {
+	if ok && msgChan != nil {
 		msgChan <- sdk.NewMessage(sdk.MsgPipelineExists, pip.Name)
 	}
 	return nil
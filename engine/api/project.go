@@ -10,6 +10,7 @@ import (
 
 	"github.com/gorilla/mux"
 
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/group"
 	"github.com/ovh/cds/engine/api/permission"
 	"github.com/ovh/cds/engine/api/project"
@@ -62,11 +63,24 @@ func (api *API) updateProjectHandler() Handler {
 		if errProj != nil {
 			return sdk.WrapError(errProj, "updateProject> Cannot load project from db")
 		}
+		tx, errb := api.mustDB().Begin()
+		if errb != nil {
+			return sdk.WrapError(errb, "updateProject> Cannot start transaction")
+		}
+		defer tx.Rollback()
+
 		// Update in DB is made given the primary key
 		proj.ID = p.ID
-		if errUp := project.Update(api.mustDB(), api.Cache, proj, getUser(ctx)); errUp != nil {
+		if errUp := project.Update(tx, api.Cache, proj, getUser(ctx)); errUp != nil {
 			return sdk.WrapError(errUp, "updateProject> Cannot update project %s", key)
 		}
+		if err := audit.Record(tx, getUser(ctx), key, sdk.AuditProject, key, audit.Updated, p, proj); err != nil {
+			return sdk.WrapError(err, "updateProject> Cannot record audit")
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "updateProject> Cannot commit transaction")
+		}
 
 		return WriteJSON(w, r, p, http.StatusOK)
 	}
@@ -236,6 +250,9 @@ func (api *API) addProjectHandler() Handler {
 			return sdk.WrapError(err, "addProject> Cannot update last modified")
 		}
 
+		if err := audit.Record(tx, getUser(ctx), p.Key, sdk.AuditProject, p.Key, audit.Added, nil, p); err != nil {
+			return sdk.WrapError(err, "addProject> Cannot record audit")
+		}
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "addProject> Cannot commit transaction")
 		}
@@ -275,6 +292,9 @@ func (api *API) deleteProjectHandler() Handler {
 		if err := project.Delete(tx, api.Cache, p.Key); err != nil {
 			return sdk.WrapError(err, "deleteProject> cannot delete project %s", key)
 		}
+		if err := audit.Record(tx, getUser(ctx), key, sdk.AuditProject, key, audit.Deleted, p, nil); err != nil {
+			return sdk.WrapError(err, "deleteProject> Cannot record audit")
+		}
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "deleteProject> Cannot commit transaction")
 		}
@@ -0,0 +1,168 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sort"
+
+	"github.com/go-gorp/gorp"
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/poller"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/scheduler"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+// postProjectExportHandler exports a project with its environments, applications, pipelines and workflows.
+// Secrets are encrypted with the passphrase of the body
+func (api *API) postProjectExportHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["permProjectKey"]
+
+		var opts sdk.ProjectExportOptions
+		if err := UnmarshalBody(r, &opts); err != nil {
+			return sdk.WrapError(err, "postProjectExportHandler> Unable to unmarshal body")
+		}
+		if opts.Passphrase == "" {
+			return sdk.WrapError(sdk.ErrWrongRequest, "postProjectExportHandler> Passphrase is mandatory")
+		}
+
+		// The export contains the secrets of the project
+		if permission.ProjectPermission(key, getUser(ctx)) < permission.PermissionReadWriteExecute {
+			return sdk.WrapError(sdk.ErrForbidden, "postProjectExportHandler> Cannot export project %s", key)
+		}
+
+		p, errP := project.Load(api.mustDB(), api.Cache, key, getUser(ctx),
+			project.LoadOptions.WithGroups, project.LoadOptions.WithVariablesWithClearPassword, project.LoadOptions.WithKeys,
+			project.LoadOptions.WithEnvironments, project.LoadOptions.WithApplications, project.LoadOptions.WithPipelines, project.LoadOptions.WithWorkflows)
+		if errP != nil {
+			return sdk.WrapError(errP, "postProjectExportHandler> Cannot load project %s", key)
+		}
+
+		e := exportentities.NewProject(p)
+		models := map[string]bool{}
+
+		for i := range p.Environments {
+			env, err := exportEnvironment(api.mustDB(), &p.Environments[i])
+			if err != nil {
+				return sdk.WrapError(err, "postProjectExportHandler> Cannot export environment %s", p.Environments[i].Name)
+			}
+			e.Environments = append(e.Environments, *env)
+		}
+
+		for _, pip := range p.Pipelines {
+			ep, err := exportPipeline(api.mustDB(), key, pip.Name, models)
+			if err != nil {
+				return sdk.WrapError(err, "postProjectExportHandler> Cannot export pipeline %s", pip.Name)
+			}
+			e.Pipelines = append(e.Pipelines, *ep)
+		}
+
+		for _, app := range p.Applications {
+			ea, err := exportApplication(api.mustDB(), api.Cache, key, app.Name, getUser(ctx))
+			if err != nil {
+				return sdk.WrapError(err, "postProjectExportHandler> Cannot export application %s", app.Name)
+			}
+			e.Applications = append(e.Applications, *ea)
+		}
+
+		for _, wf := range p.Workflows {
+			ew, err := exportWorkflow(api.mustDB(), api.Cache, key, wf.Name, getUser(ctx))
+			if err != nil {
+				return sdk.WrapError(err, "postProjectExportHandler> Cannot export workflow %s", wf.Name)
+			}
+			e.Workflows = append(e.Workflows, *ew)
+		}
+
+		for m := range models {
+			e.WorkerModels = append(e.WorkerModels, m)
+		}
+		sort.Strings(e.WorkerModels)
+
+		if err := e.EncryptSecrets(opts.Passphrase); err != nil {
+			return sdk.WrapError(err, "postProjectExportHandler> Cannot encrypt secrets")
+		}
+
+		return WriteJSON(w, r, e, http.StatusOK)
+	}
+}
+
+// exportEnvironment returns the exportable environment, with its secrets in clear
+func exportEnvironment(db gorp.SqlExecutor, env *sdk.Environment) (*exportentities.Environment, error) {
+	variables, err := environment.GetAllVariableByID(db, env.ID, environment.WithClearPassword())
+	if err != nil {
+		return nil, sdk.WrapError(err, "exportEnvironment> Cannot load variables")
+	}
+	env.Variable = variables
+	return exportentities.NewEnvironment(env), nil
+}
+
+// exportPipeline returns the exportable pipeline. The worker models it requires are added to models
+func exportPipeline(db gorp.SqlExecutor, key, name string, models map[string]bool) (*exportentities.Pipeline, error) {
+	pip, err := pipeline.LoadPipeline(db, key, name, true)
+	if err != nil {
+		return nil, sdk.WrapError(err, "exportPipeline> Cannot load pipeline")
+	}
+	if err := pipeline.LoadGroupByPipeline(db, pip); err != nil {
+		return nil, sdk.WrapError(err, "exportPipeline> Cannot load groups")
+	}
+
+	for _, s := range pip.Stages {
+		for _, j := range s.Jobs {
+			for _, req := range j.Action.Requirements {
+				if req.Type == sdk.ModelRequirement && models != nil {
+					model, _ := sdk.ParseModelRequirement(req.Value)
+					models[model] = true
+				}
+			}
+		}
+	}
+
+	e := exportentities.NewPipeline(pip)
+	// The name is always exported, it is not computed from the type on import
+	e.Name = pip.Name
+	return e, nil
+}
+
+// exportApplication returns the exportable application, with its secrets in clear. Legacy notifications are
+// not exported
+func exportApplication(db gorp.SqlExecutor, store cache.Store, key, name string, u *sdk.User) (*exportentities.Application, error) {
+	app, err := application.LoadByName(db, store, key, name, u,
+		application.LoadOptions.WithVariablesWithClearPassword, application.LoadOptions.WithPipelines, application.LoadOptions.WithTriggers,
+		application.LoadOptions.WithGroups, application.LoadOptions.WithHooks, application.LoadOptions.WithKeys, application.LoadOptions.WithRepositoryManager)
+	if err != nil {
+		return nil, sdk.WrapError(err, "exportApplication> Cannot load application")
+	}
+
+	schedulers, err := scheduler.GetByApplication(db, app)
+	if err != nil {
+		return nil, sdk.WrapError(err, "exportApplication> Cannot load schedulers")
+	}
+	app.Schedulers = schedulers
+
+	pollers, err := poller.LoadByApplication(db, app.ID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "exportApplication> Cannot load pollers")
+	}
+	app.RepositoryPollers = pollers
+
+	return exportentities.NewApplication(app), nil
+}
+
+// exportWorkflow returns the exportable workflow
+func exportWorkflow(db gorp.SqlExecutor, store cache.Store, key, name string, u *sdk.User) (*exportentities.Workflow, error) {
+	wf, err := workflow.Load(db, store, key, name, u)
+	if err != nil {
+		return nil, sdk.WrapError(err, "exportWorkflow> Cannot load workflow")
+	}
+	return exportentities.NewWorkflow(wf)
+}
@@ -0,0 +1,711 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/audit"
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/hook"
+	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/poller"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/repositoriesmanager"
+	"github.com/ovh/cds/engine/api/scheduler"
+	"github.com/ovh/cds/engine/api/worker"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+// postProjectImportHandler imports a project exported by postProjectExportHandler. The project is created if
+// it does not exist. Entities which do not exist are created, existing ones which differ from the archive are
+// left untouched and reported as conflicts, so an import can be run several times
+func (api *API) postProjectImportHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var payload exportentities.ProjectImport
+		if err := UnmarshalBody(r, &payload); err != nil {
+			return sdk.WrapError(err, "postProjectImportHandler> Unable to unmarshal body")
+		}
+		e := &payload.Project
+
+		if err := e.DecryptSecrets(payload.Passphrase); err != nil {
+			return sdk.WrapError(err, "postProjectImportHandler> Cannot decrypt secrets")
+		}
+
+		if rgxp := regexp.MustCompile(sdk.ProjectKeyPattern); !rgxp.MatchString(e.Key) {
+			return sdk.WrapError(sdk.ErrInvalidProjectKey, "postProjectImportHandler> Project key %s do not respect pattern %s", e.Key, sdk.ProjectKeyPattern)
+		}
+		if e.Name == "" {
+			return sdk.WrapError(sdk.ErrInvalidProjectName, "postProjectImportHandler> Project name must no be empty")
+		}
+
+		tx, errBegin := api.mustDB().Begin()
+		if errBegin != nil {
+			return sdk.WrapError(errBegin, "postProjectImportHandler> Cannot start transaction")
+		}
+		defer tx.Rollback()
+
+		imp := &projectImporter{
+			api:    api,
+			db:     tx,
+			u:      getUser(ctx),
+			e:      e,
+			groups: map[string]*sdk.Group{},
+			report: &sdk.ProjectImportReport{Created: []string{}, Conflicts: []string{}},
+		}
+
+		steps := []func() error{
+			imp.importProject,
+			imp.importVariables,
+			imp.importKeys,
+			imp.importEnvironments,
+			imp.importPipelines,
+			imp.importApplications,
+			imp.importWorkflows,
+			imp.checkWorkerModels,
+		}
+		for _, s := range steps {
+			if err := s(); err != nil {
+				return sdk.WrapError(err, "postProjectImportHandler> Cannot import project %s", e.Key)
+			}
+		}
+
+		if err := project.UpdateLastModified(tx, api.Cache, getUser(ctx), imp.proj); err != nil {
+			return sdk.WrapError(err, "postProjectImportHandler> Cannot update last modified")
+		}
+
+		// Hooks are pushed once everything else is imported, they are only reported on failure
+		for i := range imp.workflows {
+			wf := &imp.workflows[i]
+			if err := api.pushWorkflowHooks(wf, wf.Name); err != nil {
+				imp.conflict("workflow %s: hooks are not registered: %v", wf.Name, err)
+			}
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "postProjectImportHandler> Cannot commit transaction")
+		}
+
+		return WriteJSON(w, r, imp.report, http.StatusOK)
+	}
+}
+
+type projectImporter struct {
+	api    *API
+	db     gorp.SqlExecutor
+	u      *sdk.User
+	e      *exportentities.Project
+	proj   *sdk.Project
+	groups map[string]*sdk.Group
+	report *sdk.ProjectImportReport
+	// workflows created, whose hooks are pushed after the import
+	workflows []sdk.Workflow
+}
+
+func (imp *projectImporter) created(format string, args ...interface{}) {
+	imp.report.Created = append(imp.report.Created, fmt.Sprintf(format, args...))
+}
+
+func (imp *projectImporter) conflict(format string, args ...interface{}) {
+	imp.report.Conflicts = append(imp.report.Conflicts, fmt.Sprintf(format, args...))
+}
+
+// differ returns true, and reports a conflict, if the existing entity differs from the one of the archive
+func (imp *projectImporter) differ(existing, imported interface{}, format string, args ...interface{}) (bool, error) {
+	diff, err := exportentities.Diff(existing, imported)
+	if err != nil {
+		return false, err
+	}
+	if diff {
+		imp.conflict(format+" differs from the archive", args...)
+	}
+	return diff, nil
+}
+
+// group returns the group named name, which is created with the user as admin if it does not exist
+func (imp *projectImporter) group(name string) (*sdk.Group, error) {
+	if g, ok := imp.groups[name]; ok {
+		return g, nil
+	}
+	g, err := group.LoadGroup(imp.db, name)
+	if err == sdk.ErrGroupNotFound {
+		g = &sdk.Group{Name: name}
+		id, _, errA := group.AddGroup(imp.db, g)
+		if errA != nil {
+			return nil, sdk.WrapError(errA, "group> Cannot add group %s", name)
+		}
+		g.ID = id
+		if err := group.InsertUserInGroup(imp.db, g.ID, imp.u.ID, true); err != nil {
+			return nil, sdk.WrapError(err, "group> Cannot add user %s in group %s", imp.u.Username, name)
+		}
+		imp.created("group %s", name)
+	} else if err != nil {
+		return nil, sdk.WrapError(err, "group> Cannot load group %s", name)
+	}
+	imp.groups[name] = g
+	return g, nil
+}
+
+// permissions sets the ID of the groups of permissions, creating the missing ones
+func (imp *projectImporter) permissions(perms []sdk.GroupPermission) error {
+	for i := range perms {
+		g, err := imp.group(perms[i].Group.Name)
+		if err != nil {
+			return err
+		}
+		perms[i].Group = *g
+	}
+	return nil
+}
+
+// importProject creates the project with its groups if it does not exist, else it adds the missing groups
+func (imp *projectImporter) importProject() error {
+	key := imp.e.Key
+	exists, err := project.Exist(imp.db, key)
+	if err != nil {
+		return sdk.WrapError(err, "importProject> Cannot check if project %s exists", key)
+	}
+
+	if !exists {
+		p := imp.e.Project()
+		p.Variable = nil
+		p.Keys = nil
+		if len(p.ProjectGroups) == 0 {
+			return sdk.WrapError(sdk.ErrWrongRequest, "importProject> Project %s has no group", key)
+		}
+		if err := imp.permissions(p.ProjectGroups); err != nil {
+			return err
+		}
+		if err := project.Insert(imp.db, imp.api.Cache, p, imp.u); err != nil {
+			return sdk.WrapError(err, "importProject> Cannot insert project")
+		}
+		for _, gp := range p.ProjectGroups {
+			if err := group.InsertGroupInProject(imp.db, p.ID, gp.Group.ID, gp.Permission); err != nil {
+				return sdk.WrapError(err, "importProject> Cannot add group %s in project %s", gp.Group.Name, key)
+			}
+		}
+		if err := audit.Record(imp.db, imp.u, key, sdk.AuditProject, key, audit.Added, nil, p); err != nil {
+			return sdk.WrapError(err, "importProject> Cannot record audit")
+		}
+		imp.created("project %s", key)
+	} else if permission.ProjectPermission(key, imp.u) < permission.PermissionReadWriteExecute {
+		return sdk.WrapError(sdk.ErrForbidden, "importProject> Cannot import in project %s", key)
+	}
+
+	proj, err := project.Load(imp.db, imp.api.Cache, key, imp.u, project.LoadOptions.WithGroups, project.LoadOptions.WithVariablesWithClearPassword, project.LoadOptions.WithKeys)
+	if err != nil {
+		return sdk.WrapError(err, "importProject> Cannot load project %s", key)
+	}
+	imp.proj = proj
+
+	if !exists {
+		return nil
+	}
+
+	if proj.Name != imp.e.Name {
+		imp.conflict("project %s: name %s differs from the archive", key, proj.Name)
+	}
+	for name, perm := range imp.e.Permissions {
+		var found bool
+		for _, gp := range proj.ProjectGroups {
+			if gp.Group.Name != name {
+				continue
+			}
+			found = true
+			if gp.Permission != perm {
+				imp.conflict("project %s: permission of group %s differs from the archive", key, name)
+			}
+		}
+		if found {
+			continue
+		}
+		g, err := imp.group(name)
+		if err != nil {
+			return err
+		}
+		if err := group.InsertGroupInProject(imp.db, proj.ID, g.ID, perm); err != nil {
+			return sdk.WrapError(err, "importProject> Cannot add group %s in project %s", name, key)
+		}
+		proj.ProjectGroups = append(proj.ProjectGroups, sdk.GroupPermission{Group: *g, Permission: perm})
+		imp.created("permission of group %s on project %s", name, key)
+	}
+	return nil
+}
+
+func (imp *projectImporter) importVariables() error {
+	for name, v := range imp.e.Variables {
+		var existing *sdk.Variable
+		for i := range imp.proj.Variable {
+			if imp.proj.Variable[i].Name == name {
+				existing = &imp.proj.Variable[i]
+			}
+		}
+		if existing != nil {
+			if existing.Type != v.Type || existing.Value != v.Value {
+				imp.conflict("project variable %s differs from the archive", name)
+			}
+			continue
+		}
+		// Key variables are inserted with their value, a new key is not generated
+		variable := &sdk.Variable{Name: name, Type: v.Type, Value: v.Value}
+		if err := project.InsertVariable(imp.db, imp.proj, variable, imp.u); err != nil {
+			return sdk.WrapError(err, "importVariables> Cannot insert variable %s", name)
+		}
+		imp.created("project variable %s", name)
+	}
+	return nil
+}
+
+func (imp *projectImporter) importKeys() error {
+	for name, k := range imp.e.Keys {
+		var existing *sdk.ProjectKey
+		for i := range imp.proj.Keys {
+			if imp.proj.Keys[i].Name == name {
+				existing = &imp.proj.Keys[i]
+			}
+		}
+		if existing != nil {
+			if existing.Type != k.Type || existing.Public != k.Public || existing.Private != k.Private {
+				imp.conflict("project key %s differs from the archive", name)
+			}
+			continue
+		}
+		key := &sdk.ProjectKey{Key: k.Key(name), ProjectID: imp.proj.ID}
+		if err := project.InsertKey(imp.db, key); err != nil {
+			return sdk.WrapError(err, "importKeys> Cannot insert key %s", name)
+		}
+		imp.created("project key %s", name)
+	}
+	return nil
+}
+
+func (imp *projectImporter) importEnvironments() error {
+	envs, err := environment.LoadEnvironments(imp.db, imp.proj.Key, true, imp.u)
+	if err != nil && err != sdk.ErrNoEnvironment {
+		return sdk.WrapError(err, "importEnvironments> Cannot load environments")
+	}
+
+	for i := range imp.e.Environments {
+		ee := &imp.e.Environments[i]
+
+		var existing *sdk.Environment
+		for j := range envs {
+			if envs[j].Name == ee.Name {
+				existing = &envs[j]
+			}
+		}
+		if existing != nil {
+			current, err := exportEnvironment(imp.db, existing)
+			if err != nil {
+				return err
+			}
+			if _, err := imp.differ(current, ee, "environment %s", ee.Name); err != nil {
+				return err
+			}
+			continue
+		}
+
+		env := ee.Environment()
+		keys := env.Keys
+		env.Keys = nil
+		if err := imp.permissions(env.EnvironmentGroups); err != nil {
+			return err
+		}
+		if err := environment.Import(imp.db, imp.proj, env, nil, imp.u); err != nil {
+			return sdk.WrapError(err, "importEnvironments> Cannot import environment %s", env.Name)
+		}
+		for j := range keys {
+			keys[j].EnvironmentID = env.ID
+			if err := environment.InsertKey(imp.db, &keys[j]); err != nil {
+				return sdk.WrapError(err, "importEnvironments> Cannot insert key %s in environment %s", keys[j].Name, env.Name)
+			}
+		}
+		imp.created("environment %s", env.Name)
+	}
+	return nil
+}
+
+func (imp *projectImporter) importPipelines() error {
+	for i := range imp.e.Pipelines {
+		ep := &imp.e.Pipelines[i]
+
+		exists, err := pipeline.ExistPipeline(imp.db, imp.proj.ID, ep.Name)
+		if err != nil {
+			return sdk.WrapError(err, "importPipelines> Cannot check if pipeline %s exists", ep.Name)
+		}
+		if exists {
+			current, err := exportPipeline(imp.db, imp.proj.Key, ep.Name, nil)
+			if err != nil {
+				return err
+			}
+			if _, err := imp.differ(current, ep, "pipeline %s", ep.Name); err != nil {
+				return err
+			}
+			continue
+		}
+
+		pip, err := ep.Pipeline()
+		if err != nil {
+			return sdk.WrapError(err, "importPipelines> Invalid pipeline %s", ep.Name)
+		}
+		if err := imp.permissions(pip.GroupPermission); err != nil {
+			return err
+		}
+		if err := pipeline.Import(imp.db, imp.proj, pip, nil, imp.u); err != nil {
+			return sdk.WrapError(err, "importPipelines> Cannot import pipeline %s", pip.Name)
+		}
+		imp.created("pipeline %s", pip.Name)
+	}
+	return nil
+}
+
+// importApplications creates the missing applications, then their triggers as they can target another
+// application of the archive
+func (imp *projectImporter) importApplications() error {
+	proj, err := project.Load(imp.db, imp.api.Cache, imp.proj.Key, imp.u, project.LoadOptions.WithApplications)
+	if err != nil {
+		return sdk.WrapError(err, "importApplications> Cannot load applications")
+	}
+
+	var created []*sdk.Application
+	var triggers [][]sdk.PipelineTrigger
+	for i := range imp.e.Applications {
+		ea := &imp.e.Applications[i]
+
+		var exists bool
+		for _, a := range proj.Applications {
+			if a.Name == ea.Name {
+				exists = true
+			}
+		}
+		if exists {
+			current, err := exportApplication(imp.db, imp.api.Cache, imp.proj.Key, ea.Name, imp.u)
+			if err != nil {
+				return err
+			}
+			if _, err := imp.differ(current, ea, "application %s", ea.Name); err != nil {
+				return err
+			}
+			continue
+		}
+
+		app, appTriggers, err := imp.importApplication(ea)
+		if err != nil {
+			return err
+		}
+		created = append(created, app)
+		triggers = append(triggers, appTriggers)
+		imp.created("application %s", app.Name)
+	}
+
+	for i, app := range created {
+		var pips []sdk.ApplicationPipeline
+		for _, ap := range app.Pipelines {
+			ap.Triggers = nil
+			for _, t := range triggers[i] {
+				if t.SrcPipeline.ID == ap.Pipeline.ID {
+					ap.Triggers = append(ap.Triggers, t)
+				}
+			}
+			if len(ap.Triggers) > 0 {
+				pips = append(pips, ap)
+			}
+		}
+		if len(pips) == 0 {
+			continue
+		}
+		app.Pipelines = pips
+		if err := application.ImportPipelines(imp.db, imp.api.Cache, imp.proj, app, imp.u, nil); err != nil {
+			return sdk.WrapError(err, "importApplications> Cannot import triggers of application %s", app.Name)
+		}
+	}
+	return nil
+}
+
+// importApplication creates an application with its variables, keys, pipelines, hooks, pollers and schedulers.
+// It returns the triggers of the application, which are created when all applications exist
+func (imp *projectImporter) importApplication(ea *exportentities.Application) (*sdk.Application, []sdk.PipelineTrigger, error) {
+	app := ea.Application()
+
+	var rm *sdk.RepositoriesManager
+	if app.RepositoriesManager != nil {
+		var errRM error
+		rm, errRM = repositoriesmanager.LoadForProject(imp.db, imp.proj.Key, app.RepositoriesManager.Name, imp.api.Cache)
+		if errRM != nil {
+			imp.conflict("application %s: repository manager %s is not found, the repository %s is not attached", app.Name, app.RepositoriesManager.Name, app.RepositoryFullname)
+			rm = nil
+		}
+		app.RepositoriesManager = nil
+	}
+
+	// Variables and keys are inserted with their value, new keys are not generated
+	variables, keys := app.Variable, app.Keys
+	app.Variable, app.Keys = nil, nil
+
+	var triggers []sdk.PipelineTrigger
+	for i := range app.Pipelines {
+		for _, t := range app.Pipelines[i].Triggers {
+			if t.DestProject.Key != "" && t.DestProject.Key != imp.proj.Key {
+				imp.conflict("application %s: trigger of pipeline %s to project %s is not imported", app.Name, app.Pipelines[i].Pipeline.Name, t.DestProject.Key)
+				continue
+			}
+			t.SrcPipeline.Name = app.Pipelines[i].Pipeline.Name
+			triggers = append(triggers, t)
+		}
+		app.Pipelines[i].Triggers = nil
+	}
+
+	if err := imp.permissions(app.ApplicationGroups); err != nil {
+		return nil, nil, err
+	}
+	if err := application.Import(imp.db, imp.api.Cache, imp.proj, app, nil, imp.u, nil); err != nil {
+		return nil, nil, sdk.WrapError(err, "importApplication> Cannot import application %s", app.Name)
+	}
+
+	for _, v := range variables {
+		if err := application.InsertVariable(imp.db, imp.api.Cache, app, v, imp.u); err != nil {
+			return nil, nil, sdk.WrapError(err, "importApplication> Cannot insert variable %s in application %s", v.Name, app.Name)
+		}
+	}
+	for i := range keys {
+		keys[i].ApplicationID = app.ID
+		if err := application.InsertKey(imp.db, &keys[i]); err != nil {
+			return nil, nil, sdk.WrapError(err, "importApplication> Cannot insert key %s in application %s", keys[i].Name, app.Name)
+		}
+	}
+
+	for i := range app.Pipelines {
+		ap := &app.Pipelines[i]
+		if len(ap.Parameters) > 0 {
+			if err := application.UpdatePipelineApplication(imp.db, imp.api.Cache, app, ap.Pipeline.ID, ap.Parameters, imp.u); err != nil {
+				return nil, nil, sdk.WrapError(err, "importApplication> Cannot update parameters of pipeline %s in application %s", ap.Pipeline.Name, app.Name)
+			}
+		}
+		for j := range triggers {
+			if triggers[j].SrcPipeline.Name == ap.Pipeline.Name {
+				triggers[j].SrcPipeline = ap.Pipeline
+			}
+		}
+	}
+
+	if rm != nil {
+		app.RepositoriesManager = rm
+		app.RepositoryFullname = ea.RepositoryName
+		if err := repositoriesmanager.InsertForApplication(imp.db, app, imp.proj.Key); err != nil {
+			return nil, nil, sdk.WrapError(err, "importApplication> Cannot attach repository %s to application %s", app.RepositoryFullname, app.Name)
+		}
+	}
+
+	if err := imp.importApplicationOptions(ea, app); err != nil {
+		return nil, nil, err
+	}
+
+	return app, triggers, nil
+}
+
+// importApplicationOptions creates the hooks, pollers and schedulers of an application
+func (imp *projectImporter) importApplicationOptions(ea *exportentities.Application, app *sdk.Application) error {
+	for i := range app.Pipelines {
+		pip := &app.Pipelines[i].Pipeline
+		for _, o := range ea.Pipelines[pip.Name].Options {
+			env := &sdk.DefaultEnv
+			if o.Environment != nil {
+				var err error
+				env, err = environment.LoadEnvironmentByName(imp.db, imp.proj.Key, *o.Environment)
+				if err != nil {
+					imp.conflict("application %s: options of pipeline %s on environment %s are not imported: %v", app.Name, pip.Name, *o.Environment, err)
+					continue
+				}
+			}
+
+			if o.Hook != nil && *o.Hook {
+				if app.RepositoriesManager == nil {
+					imp.conflict("application %s: hook of pipeline %s is not imported, there is no repository", app.Name, pip.Name)
+				} else if _, err := hook.CreateHook(imp.db, imp.api.Cache, imp.proj.Key, app.RepositoriesManager, app.RepositoryFullname, app, pip); err != nil {
+					imp.conflict("application %s: hook of pipeline %s is not created: %v", app.Name, pip.Name, err)
+				}
+			}
+
+			if o.Polling != nil && *o.Polling {
+				if app.RepositoriesManager == nil {
+					imp.conflict("application %s: poller of pipeline %s is not imported, there is no repository", app.Name, pip.Name)
+				} else {
+					p := &sdk.RepositoryPoller{
+						Name:        app.RepositoriesManager.Name,
+						Application: *app,
+						Pipeline:    *pip,
+						Enabled:     true,
+					}
+					if err := poller.Insert(imp.db, p); err != nil {
+						return sdk.WrapError(err, "importApplicationOptions> Cannot insert poller of pipeline %s in application %s", pip.Name, app.Name)
+					}
+				}
+			}
+
+			for _, s := range o.Schedulers {
+				ps := &sdk.PipelineScheduler{
+					ApplicationID: app.ID,
+					PipelineID:    pip.ID,
+					EnvironmentID: env.ID,
+					Crontab:       s.CronExpr,
+				}
+				for k, v := range s.Parameters {
+					ps.Args = append(ps.Args, sdk.Parameter{Name: k, Type: v.Type, Value: v.Value})
+				}
+				if err := scheduler.Insert(imp.db, ps); err != nil {
+					return sdk.WrapError(err, "importApplicationOptions> Cannot insert scheduler of pipeline %s in application %s", pip.Name, app.Name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (imp *projectImporter) importWorkflows() error {
+	proj, err := project.Load(imp.db, imp.api.Cache, imp.proj.Key, imp.u,
+		project.LoadOptions.WithGroups, project.LoadOptions.WithApplications, project.LoadOptions.WithPipelines, project.LoadOptions.WithEnvironments, project.LoadOptions.WithWorkflows)
+	if err != nil {
+		return sdk.WrapError(err, "importWorkflows> Cannot load project %s", imp.proj.Key)
+	}
+
+	for i := range imp.e.Workflows {
+		ew := &imp.e.Workflows[i]
+
+		var exists bool
+		for _, w := range proj.Workflows {
+			if w.Name == ew.Name {
+				exists = true
+			}
+		}
+		if exists {
+			current, err := exportWorkflow(imp.db, imp.api.Cache, proj.Key, ew.Name, imp.u)
+			if err != nil {
+				return err
+			}
+			if _, err := imp.differ(current, ew, "workflow %s", ew.Name); err != nil {
+				return err
+			}
+			continue
+		}
+
+		wf, err := ew.Workflow()
+		if err != nil {
+			imp.conflict("workflow %s is not imported: %v", ew.Name, err)
+			continue
+		}
+		wf.ProjectID = proj.ID
+		wf.ProjectKey = proj.Key
+
+		if err := workflowResolveNames(proj, wf); err != nil {
+			imp.conflict("workflow %s is not imported: %v", ew.Name, err)
+			continue
+		}
+		if err := imp.permissions(wf.Groups); err != nil {
+			return err
+		}
+
+		if err := workflow.Insert(imp.db, imp.api.Cache, wf, proj, imp.u); err != nil {
+			return sdk.WrapError(err, "importWorkflows> Cannot insert workflow %s", wf.Name)
+		}
+		for _, gp := range wf.Groups {
+			if err := group.InsertGroupInWorkflow(imp.db, wf.ID, gp.Group.ID, gp.Permission); err != nil {
+				return sdk.WrapError(err, "importWorkflows> Cannot add group %s in workflow %s", gp.Group.Name, wf.Name)
+			}
+		}
+		if err := audit.Record(imp.db, imp.u, proj.Key, sdk.AuditWorkflow, wf.Name, audit.Added, nil, wf); err != nil {
+			return sdk.WrapError(err, "importWorkflows> Cannot record audit")
+		}
+		imp.workflows = append(imp.workflows, *wf)
+		imp.created("workflow %s", wf.Name)
+	}
+	return nil
+}
+
+// workflowResolveNames sets the pipelines, applications and environments of the nodes of a workflow from
+// their names
+func workflowResolveNames(proj *sdk.Project, wf *sdk.Workflow) error {
+	var resolve func(n *sdk.WorkflowNode) error
+	resolve = func(n *sdk.WorkflowNode) error {
+		var found bool
+		for _, p := range proj.Pipelines {
+			if p.Name == n.Pipeline.Name {
+				n.Pipeline = p
+				n.PipelineID = p.ID
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("pipeline %s does not exist", n.Pipeline.Name)
+		}
+
+		if n.Context.Application != nil {
+			found = false
+			for i := range proj.Applications {
+				if proj.Applications[i].Name == n.Context.Application.Name {
+					app := proj.Applications[i]
+					n.Context.Application = &app
+					n.Context.ApplicationID = app.ID
+					found = true
+				}
+			}
+			if !found {
+				return fmt.Errorf("application %s does not exist", n.Context.Application.Name)
+			}
+		}
+
+		if n.Context.Environment != nil {
+			found = false
+			for i := range proj.Environments {
+				if proj.Environments[i].Name == n.Context.Environment.Name {
+					env := proj.Environments[i]
+					n.Context.Environment = &env
+					n.Context.EnvironmentID = env.ID
+					found = true
+				}
+			}
+			if !found {
+				return fmt.Errorf("environment %s does not exist", n.Context.Environment.Name)
+			}
+		}
+
+		for i := range n.Triggers {
+			if err := resolve(&n.Triggers[i].WorkflowDestNode); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := resolve(wf.Root); err != nil {
+		return err
+	}
+	for i := range wf.Joins {
+		for j := range wf.Joins[i].Triggers {
+			if err := resolve(&wf.Joins[i].Triggers[j].WorkflowDestNode); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkWorkerModels reports the worker models required by the pipelines which do not exist
+func (imp *projectImporter) checkWorkerModels() error {
+	for _, name := range imp.e.WorkerModels {
+		if _, err := worker.LoadWorkerModelByName(imp.db, name); err != nil {
+			if err != sdk.ErrNoWorkerModel {
+				return sdk.WrapError(err, "checkWorkerModels> Cannot load worker model %s", name)
+			}
+			imp.conflict("worker model %s does not exist", name)
+		}
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/gorilla/mux"
 
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/keys"
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/sdk"
@@ -54,6 +55,9 @@ func (api *API) deleteKeyInProjectHandler() Handler {
 				if err := project.UpdateLastModified(tx, api.Cache, getUser(ctx), p); err != nil {
 					return sdk.WrapError(err, "deleteKeyInProjectHandler> Cannot update project last modified date")
 				}
+				if err := audit.Record(tx, getUser(ctx), key, sdk.AuditKey, keyName, audit.Deleted, k, nil); err != nil {
+					return sdk.WrapError(err, "deleteKeyInProjectHandler> Cannot record audit")
+				}
 			}
 		}
 
@@ -121,6 +125,10 @@ func (api *API) addKeyInProjectHandler() Handler {
 			return sdk.WrapError(err, "addKeyInProjectHandler> Cannot update project last modified date")
 		}
 
+		if err := audit.Record(tx, getUser(ctx), key, sdk.AuditKey, newKey.Name, audit.Added, nil, &newKey); err != nil {
+			return sdk.WrapError(err, "addKeyInProjectHandler> Cannot record audit")
+		}
+
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "addKeyInProjectHandler> Cannot commit transaction")
 		}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/secret"
+	"github.com/ovh/cds/sdk"
+)
+
+func (api *API) getSecretBackendsInProjectHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["permProjectKey"]
+
+		p, err := project.Load(api.mustDB(), api.Cache, key, getUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "getSecretBackendsInProjectHandler> Cannot load project %s", key)
+		}
+
+		bs, err := secret.LoadBackends(api.mustDB(), p.ID)
+		if err != nil {
+			return sdk.WrapError(err, "getSecretBackendsInProjectHandler> Cannot load secret backends")
+		}
+		return WriteJSON(w, r, bs, http.StatusOK)
+	}
+}
+
+func (api *API) addSecretBackendInProjectHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["permProjectKey"]
+
+		var b sdk.ProjectSecretBackend
+		if err := UnmarshalBody(r, &b); err != nil {
+			return sdk.WrapError(err, "addSecretBackendInProjectHandler> Cannot read body")
+		}
+		if err := b.IsValid(); err != nil {
+			return sdk.WrapError(err, "addSecretBackendInProjectHandler> Invalid secret backend")
+		}
+		if err := secret.CheckBackend(b); err != nil {
+			return sdk.WrapError(err, "addSecretBackendInProjectHandler> Secret backend not allowed")
+		}
+
+		p, err := project.Load(api.mustDB(), api.Cache, key, getUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "addSecretBackendInProjectHandler> Cannot load project %s", key)
+		}
+
+		b.ID = 0
+		b.ProjectID = p.ID
+		if err := secret.InsertBackend(api.mustDB(), &b); err != nil {
+			return sdk.WrapError(err, "addSecretBackendInProjectHandler> Cannot add secret backend %s", b.Name)
+		}
+
+		b.Token = ""
+		return WriteJSON(w, r, b, http.StatusCreated)
+	}
+}
+
+func (api *API) updateSecretBackendInProjectHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["permProjectKey"]
+		name := vars["name"]
+
+		var b sdk.ProjectSecretBackend
+		if err := UnmarshalBody(r, &b); err != nil {
+			return sdk.WrapError(err, "updateSecretBackendInProjectHandler> Cannot read body")
+		}
+		if err := b.IsValid(); err != nil {
+			return sdk.WrapError(err, "updateSecretBackendInProjectHandler> Invalid secret backend")
+		}
+		if err := secret.CheckBackend(b); err != nil {
+			return sdk.WrapError(err, "updateSecretBackendInProjectHandler> Secret backend not allowed")
+		}
+
+		p, err := project.Load(api.mustDB(), api.Cache, key, getUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "updateSecretBackendInProjectHandler> Cannot load project %s", key)
+		}
+
+		old, err := secret.LoadBackend(api.mustDB(), p.ID, name, false)
+		if err != nil {
+			return sdk.WrapError(err, "updateSecretBackendInProjectHandler> Cannot load secret backend %s", name)
+		}
+
+		b.ID = old.ID
+		b.ProjectID = p.ID
+		if err := secret.UpdateBackend(api.mustDB(), &b); err != nil {
+			return sdk.WrapError(err, "updateSecretBackendInProjectHandler> Cannot update secret backend %s", name)
+		}
+
+		b.Token = ""
+		return WriteJSON(w, r, b, http.StatusOK)
+	}
+}
+
+func (api *API) deleteSecretBackendInProjectHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["permProjectKey"]
+		name := vars["name"]
+
+		p, err := project.Load(api.mustDB(), api.Cache, key, getUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "deleteSecretBackendInProjectHandler> Cannot load project %s", key)
+		}
+
+		b, err := secret.LoadBackend(api.mustDB(), p.ID, name, false)
+		if err != nil {
+			return sdk.WrapError(err, "deleteSecretBackendInProjectHandler> Cannot load secret backend %s", name)
+		}
+
+		if err := secret.DeleteBackend(api.mustDB(), b); err != nil {
+			return sdk.WrapError(err, "deleteSecretBackendInProjectHandler> Cannot delete secret backend %s", name)
+		}
+		return WriteJSON(w, r, nil, http.StatusOK)
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/gorilla/mux"
 
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/api/sanity"
 	"github.com/ovh/cds/engine/api/secret"
@@ -142,6 +143,9 @@ func (api *API) deleteVariableFromProjectHandler() Handler {
 			return sdk.WrapError(err, "deleteVariableFromProject: Cannot update last modified date")
 		}
 
+		if err := audit.Record(tx, getUser(ctx), key, sdk.AuditVariable, varName, audit.Deleted, varToDelete, nil); err != nil {
+			return sdk.WrapError(err, "deleteVariableFromProject: Cannot record audit")
+		}
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "deleteVariableFromProject: Cannot commit transaction")
 		}
@@ -281,6 +285,10 @@ func (api *API) updateVariableInProjectHandler() Handler {
 		}
 		defer tx.Rollback()
 
+		oldVar, errV := project.GetVariableInProject(tx, p.ID, varName)
+		if errV != nil {
+			return sdk.WrapError(errV, "updateVariableInProject: Cannot load variable %s", varName)
+		}
 		if err := project.UpdateVariable(tx, p, &newVar, getUser(ctx)); err != nil {
 			return sdk.WrapError(err, "updateVariableInProject: Cannot update variable %s in project %s", varName, p.Name)
 		}
@@ -289,6 +297,9 @@ func (api *API) updateVariableInProjectHandler() Handler {
 			return sdk.WrapError(err, "updateVariableInProject: Cannot update last modified date")
 		}
 
+		if err := audit.Record(tx, getUser(ctx), key, sdk.AuditVariable, varName, audit.Updated, oldVar, &newVar); err != nil {
+			return sdk.WrapError(err, "updateVariableInProject: Cannot record audit")
+		}
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "updateVariableInProject: cannot commit transaction")
 
@@ -381,6 +392,10 @@ func (api *API) addVariableInProjectHandler() Handler {
 			return sdk.WrapError(err, "updateVariablesInProjectHandler: Cannot update last modified")
 		}
 
+		if err := audit.Record(tx, getUser(ctx), key, sdk.AuditVariable, varName, audit.Added, nil, &newVar); err != nil {
+			return sdk.WrapError(err, "addVariableInProjectHandler: Cannot record audit")
+		}
+
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "addVariableInProjectHandler: cannot commit tx")
 		}
@@ -11,10 +11,12 @@ import (
 
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/ovh/cds/engine/api/auth"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/tracing"
 )
 
 const nbPanicsBeforeFail = 50
@@ -28,6 +30,7 @@ type Router struct {
 	Prefix           string
 	URL              string
 	Middlewares      []Middleware
+	RequestDuration  *prometheus.HistogramVec
 	mapRouterConfigs map[string]*RouterConfig
 	panicked         bool
 	nbPanic          int
@@ -149,7 +152,7 @@ func DefaultHeaders() map[string]string {
 	return map[string]string{
 		"Access-Control-Allow-Origin":   "*",
 		"Access-Control-Allow-Methods":  "GET,OPTIONS,PUT,POST,DELETE",
-		"Access-Control-Allow-Headers":  "Accept, Origin, Referer, User-Agent, Content-Type, Authorization, Session-Token, Last-Event-Id, If-Modified-Since, Content-Disposition",
+		"Access-Control-Allow-Headers":  "Accept, Origin, Referer, User-Agent, Content-Type, Authorization, Session-Token, Access-Token, Last-Event-Id, If-Modified-Since, Content-Disposition",
 		"Access-Control-Expose-Headers": "Accept, Origin, Referer, User-Agent, Content-Type, Authorization, Session-Token, Last-Event-Id, ETag, Content-Disposition",
 		"X-Api-Time":                    time.Now().Format(time.RFC3339),
 		"ETag":                          fmt.Sprintf("%d", time.Now().Unix()),
@@ -200,7 +203,22 @@ func (r *Router) Handle(uri string, handlers ...*HandlerConfig) {
 
 		//Log request
 		start := time.Now()
+		sw := &statusResponseWriter{ResponseWriter: w}
+		w = sw
+		ctx, span := tracing.Start(tracing.Extract(ctx, req.Header), req.Method+" "+uri)
+		span.SetTag("http.method", req.Method)
+		span.SetTag("http.route", uri)
 		defer func() {
+			status := sw.status
+			re := recover()
+			if re != nil {
+				// The panic is answered by recoverWrap with an internal error
+				status = http.StatusInternalServerError
+			} else if status == 0 {
+				status = http.StatusOK
+			}
+			span.SetTag("http.status_code", status)
+			span.Finish()
 			end := time.Now()
 			latency := end.Sub(start)
 			if rc.IsDeprecated {
@@ -209,6 +227,12 @@ func (r *Router) Handle(uri string, handlers ...*HandlerConfig) {
 			} else {
 				log.Debug("%-7s | %13v | %v", req.Method, latency, req.URL)
 			}
+			if r.RequestDuration != nil {
+				r.RequestDuration.WithLabelValues(uri, req.Method, fmt.Sprintf("%d", status)).Observe(latency.Seconds())
+			}
+			if re != nil {
+				panic(re)
+			}
 		}()
 
 		for _, m := range r.Middlewares {
@@ -230,6 +254,40 @@ func (r *Router) Handle(uri string, handlers ...*HandlerConfig) {
 	r.Mux.HandleFunc(uri, r.compress(r.recoverWrap(f)))
 }
 
+// statusResponseWriter records the status code sent by the handlers
+type statusResponseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records the status code before sending it
+func (w *statusResponseWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write sends the body, with an implicit 200 status if none was sent
+func (w *statusResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher for the streaming handlers
+func (w *statusResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// CloseNotify implements http.CloseNotifier for the streaming handlers
+func (w *statusResponseWriter) CloseNotify() <-chan bool {
+	return w.ResponseWriter.(http.CloseNotifier).CloseNotify()
+}
+
 // DEPRECATED marks the handler as deprecated
 var DEPRECATED = func(rc *HandlerConfig) {
 	rc.Options["isDeprecated"] = "true"
@@ -284,6 +342,18 @@ func (r *Router) PUT(h HandlerFunc, cfg ...HandlerConfigParam) *HandlerConfig {
 	return rc
 }
 
+// HEAD will set given handler only for HEAD request
+func (r *Router) HEAD(h HandlerFunc, cfg ...HandlerConfigParam) *HandlerConfig {
+	rc := NewHandlerConfig()
+	rc.Handler = h()
+	rc.Options["auth"] = "true"
+	rc.Method = "HEAD"
+	for _, c := range cfg {
+		c(rc)
+	}
+	return rc
+}
+
 // DELETE will set given handler only for DELETE request
 func (r *Router) DELETE(h HandlerFunc, cfg ...HandlerConfigParam) *HandlerConfig {
 	rc := NewHandlerConfig()
@@ -11,6 +11,7 @@ import (
 	"github.com/ovh/cds/engine/api/environment"
 	"github.com/ovh/cds/engine/api/pipeline"
 	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/sdk"
 )
 
@@ -49,6 +50,9 @@ func loadUserPermissions(db gorp.SqlExecutor, store cache.Store, user *sdk.User)
 			if err := environment.LoadEnvironmentByGroup(db, &group); err != nil {
 				return sdk.WrapError(err, "loadUserPermissions> Unable to load environment permissions for  %s", user.Username)
 			}
+			if err := workflow.LoadWorkflowByGroup(db, &group); err != nil {
+				return sdk.WrapError(err, "loadUserPermissions> Unable to load workflow permissions for  %s", user.Username)
+			}
 			if admin {
 				usr := *user
 				usr.Groups = nil
@@ -82,6 +86,9 @@ func loadGroupPermissions(db gorp.SqlExecutor, store cache.Store, groupID int64)
 		if err := environment.LoadEnvironmentByGroup(db, group); err != nil {
 			return nil, err
 		}
+		if err := workflow.LoadWorkflowByGroup(db, group); err != nil {
+			return nil, err
+		}
 		store.SetWithTTL(k, group, 30)
 	}
 	return group, nil
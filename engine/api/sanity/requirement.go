@@ -65,7 +65,7 @@ func checkMultipleWorkerModelWarning(proj string, pip string, a *sdk.Action) ([]
 	for _, r := range areqs {
 		if r.Type == sdk.ModelRequirement {
 			modelReq++
-			modelName = r.Value
+			modelName, _ = sdk.ParseModelRequirement(r.Value)
 		}
 		if modelReq > 1 {
 			modelName = r.Value
@@ -0,0 +1,215 @@
+package secret
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Backend reads secrets from an external secret store
+type Backend interface {
+	Read(path, field string) (string, error)
+}
+
+// BackendFactory instanciates the backend of a project secret backend configuration
+type BackendFactory func(b sdk.ProjectSecretBackend) (Backend, error)
+
+var backendFactories = map[string]BackendFactory{
+	sdk.SecretBackendVaultKV1: newVaultKV1Backend,
+	sdk.SecretBackendVaultKV2: newVaultKV2Backend,
+	sdk.SecretBackendFile:     newFileBackend,
+}
+
+var (
+	vaultAddresses []string
+	fileDirectory  string
+)
+
+// InitBackends sets the vault servers and the directory of secret files which project secret backends can use.
+// File backends are disabled if fileDir is empty
+func InitBackends(vaultAddrs []string, fileDir string) {
+	vaultAddresses = vaultAddrs
+	fileDirectory = fileDir
+}
+
+// RegisterBackend sets the factory of a secret backend type. It must be called at startup
+func RegisterBackend(t string, f BackendFactory) {
+	backendFactories[t] = f
+}
+
+// NewBackend instanciates a backend from its configuration
+func NewBackend(b sdk.ProjectSecretBackend) (Backend, error) {
+	f, ok := backendFactories[b.Type]
+	if !ok {
+		return nil, sdk.WrapError(sdk.ErrWrongRequest, "NewBackend> Unknown secret backend type %s", b.Type)
+	}
+	if err := CheckBackend(b); err != nil {
+		return nil, err
+	}
+	return f(b)
+}
+
+// CheckBackend checks that the address of a backend is allowed by the API configuration
+func CheckBackend(b sdk.ProjectSecretBackend) error {
+	switch b.Type {
+	case sdk.SecretBackendVaultKV1, sdk.SecretBackendVaultKV2:
+		for _, a := range vaultAddresses {
+			if strings.TrimSuffix(a, "/") == strings.TrimSuffix(b.Address, "/") {
+				return nil
+			}
+		}
+		return sdk.WrapError(sdk.ErrWrongRequest, "CheckBackend> Vault address %s is not allowed", b.Address)
+	case sdk.SecretBackendFile:
+		if _, err := filePath(b.Address); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// filePath returns the path of a secret file in the configured directory
+func filePath(address string) (string, error) {
+	if fileDirectory == "" {
+		return "", sdk.WrapError(sdk.ErrWrongRequest, "filePath> File secret backends are disabled")
+	}
+	dir := filepath.Clean(fileDirectory)
+	path := filepath.Join(dir, address)
+	if filepath.IsAbs(address) || !strings.HasPrefix(path, dir+string(filepath.Separator)) {
+		return "", sdk.WrapError(sdk.ErrWrongRequest, "filePath> Secret file %s is not allowed", address)
+	}
+	return path, nil
+}
+
+// ResolveReferences replaces the value of secret reference variables by the secrets they reference, using
+// the secret backends of the project. Resolved variables become password variables so they are masked
+func ResolveReferences(db gorp.SqlExecutor, projectID int64, vars []sdk.Variable) error {
+	backends := map[string]Backend{}
+	for i := range vars {
+		v := &vars[i]
+		if v.Type != sdk.SecretReferenceVariable {
+			continue
+		}
+
+		ref, err := sdk.ParseSecretReference(v.Value)
+		if err != nil {
+			return sdk.WrapError(err, "ResolveReferences> Invalid reference in variable %s", v.Name)
+		}
+
+		b, ok := backends[ref.Backend]
+		if !ok {
+			conf, err := LoadBackend(db, projectID, ref.Backend, true)
+			if err != nil {
+				return sdk.WrapError(err, "ResolveReferences> Cannot load secret backend %s of variable %s", ref.Backend, v.Name)
+			}
+			b, err = NewBackend(*conf)
+			if err != nil {
+				return sdk.WrapError(err, "ResolveReferences> Cannot initialize secret backend %s", ref.Backend)
+			}
+			backends[ref.Backend] = b
+		}
+
+		value, err := b.Read(ref.Path, ref.Field)
+		if err != nil {
+			return sdk.WrapError(err, "ResolveReferences> Cannot read secret %s of variable %s", ref, v.Name)
+		}
+		v.Value = value
+		v.Type = sdk.SecretVariable
+	}
+	return nil
+}
+
+type vaultBackend struct {
+	secret *Secret
+	kv2    bool
+}
+
+func newVaultKV1Backend(b sdk.ProjectSecretBackend) (Backend, error) {
+	s, err := New(b.Token, b.Address)
+	if err != nil {
+		return nil, err
+	}
+	return &vaultBackend{secret: s}, nil
+}
+
+func newVaultKV2Backend(b sdk.ProjectSecretBackend) (Backend, error) {
+	s, err := New(b.Token, b.Address)
+	if err != nil {
+		return nil, err
+	}
+	return &vaultBackend{secret: s, kv2: true}, nil
+}
+
+// Read reads a field of a vault KV secret. With KV v2 the path is mount/path and the data
+// is read from mount/data/path
+func (v *vaultBackend) Read(path, field string) (string, error) {
+	if v.kv2 {
+		path = kv2DataPath(path)
+	}
+
+	s, err := v.secret.Client.Logical().Read(path)
+	if err != nil {
+		return "", sdk.WrapError(sdk.ErrSecretStoreUnreachable, "vaultBackend.Read> Cannot read %s: %s", path, err)
+	}
+	if s == nil {
+		return "", fmt.Errorf("no secret found at %s", path)
+	}
+
+	data := s.Data
+	if v.kv2 {
+		d, ok := s.Data["data"].(map[string]interface{})
+		if !ok {
+			return "", fmt.Errorf("no data found at %s", path)
+		}
+		data = d
+	}
+
+	value, ok := data[field]
+	if !ok {
+		return "", fmt.Errorf("no field %s found at %s", field, path)
+	}
+	return fmt.Sprintf("%v", value), nil
+}
+
+func kv2DataPath(path string) string {
+	t := strings.SplitN(path, "/", 2)
+	if len(t) != 2 {
+		return path
+	}
+	return t[0] + "/data/" + t[1]
+}
+
+// fileBackend reads secrets from a JSON file of secrets by path and field, ie. {"kv/team/db": {"password": "..."}}
+type fileBackend struct {
+	secrets map[string]map[string]string
+}
+
+func newFileBackend(b sdk.ProjectSecretBackend) (Backend, error) {
+	path, err := filePath(b.Address)
+	if err != nil {
+		return nil, err
+	}
+	btes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, sdk.WrapError(err, "newFileBackend> Cannot read %s", b.Address)
+	}
+	f := &fileBackend{}
+	if err := json.Unmarshal(btes, &f.secrets); err != nil {
+		return nil, sdk.WrapError(err, "newFileBackend> Cannot parse %s", b.Address)
+	}
+	return f, nil
+}
+
+// Read reads a field of a secret of the file
+func (f *fileBackend) Read(path, field string) (string, error) {
+	value, ok := f.secrets[path][field]
+	if !ok {
+		return "", fmt.Errorf("no field %s found at %s", field, path)
+	}
+	return value, nil
+}
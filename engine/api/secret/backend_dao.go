@@ -0,0 +1,98 @@
+package secret
+
+import (
+	"database/sql"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// LoadBackends loads the secret backends of a project, without their tokens
+func LoadBackends(db gorp.SqlExecutor, projectID int64) ([]sdk.ProjectSecretBackend, error) {
+	dbbs := []dbProjectSecretBackend{}
+	if _, err := db.Select(&dbbs, "select * from project_secret_backend where project_id = $1 order by name", projectID); err != nil {
+		return nil, sdk.WrapError(err, "LoadBackends> Unable to load secret backends of project %d", projectID)
+	}
+	bs := make([]sdk.ProjectSecretBackend, len(dbbs))
+	for i := range dbbs {
+		bs[i] = sdk.ProjectSecretBackend(dbbs[i])
+	}
+	return bs, nil
+}
+
+// LoadBackend loads a secret backend of a project. The token is decrypted if withToken is true
+func LoadBackend(db gorp.SqlExecutor, projectID int64, name string, withToken bool) (*sdk.ProjectSecretBackend, error) {
+	dbb := dbProjectSecretBackend{}
+	if err := db.SelectOne(&dbb, "select * from project_secret_backend where project_id = $1 and name = $2", projectID, name); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.ErrSecretBackendNotFound
+		}
+		return nil, sdk.WrapError(err, "LoadBackend> Unable to load secret backend %s", name)
+	}
+
+	if withToken {
+		var token []byte
+		if err := db.QueryRow("select token from project_secret_backend where id = $1", dbb.ID).Scan(&token); err != nil {
+			return nil, sdk.WrapError(err, "LoadBackend> Unable to load token of secret backend %s", name)
+		}
+		if len(token) > 0 {
+			clear, err := Decrypt(token)
+			if err != nil {
+				return nil, sdk.WrapError(err, "LoadBackend> Unable to decrypt token of secret backend %s", name)
+			}
+			dbb.Token = string(clear)
+		}
+	}
+
+	b := sdk.ProjectSecretBackend(dbb)
+	return &b, nil
+}
+
+// InsertBackend inserts a secret backend
+func InsertBackend(db gorp.SqlExecutor, b *sdk.ProjectSecretBackend) error {
+	dbb := dbProjectSecretBackend(*b)
+	if err := db.Insert(&dbb); err != nil {
+		return sdk.WrapError(err, "InsertBackend> Unable to insert secret backend %s", b.Name)
+	}
+	b.ID = dbb.ID
+	return nil
+}
+
+// UpdateBackend updates a secret backend. The token is kept if it is empty
+func UpdateBackend(db gorp.SqlExecutor, b *sdk.ProjectSecretBackend) error {
+	dbb := dbProjectSecretBackend(*b)
+	if _, err := db.Update(&dbb); err != nil {
+		return sdk.WrapError(err, "UpdateBackend> Unable to update secret backend %s", b.Name)
+	}
+	return nil
+}
+
+// DeleteBackend deletes a secret backend
+func DeleteBackend(db gorp.SqlExecutor, b *sdk.ProjectSecretBackend) error {
+	dbb := dbProjectSecretBackend(*b)
+	if _, err := db.Delete(&dbb); err != nil {
+		return sdk.WrapError(err, "DeleteBackend> Unable to delete secret backend %s", b.Name)
+	}
+	return nil
+}
+
+// PostInsert is a db hook
+func (b *dbProjectSecretBackend) PostInsert(db gorp.SqlExecutor) error {
+	return b.PostUpdate(db)
+}
+
+// PostUpdate is a db hook, it stores the encrypted token
+func (b *dbProjectSecretBackend) PostUpdate(db gorp.SqlExecutor) error {
+	if b.Token == "" || b.Token == sdk.PasswordPlaceholder {
+		return nil
+	}
+	token, err := Encrypt([]byte(b.Token))
+	if err != nil {
+		return err
+	}
+	if _, err := db.Exec("update project_secret_backend set token = $2 where id = $1", b.ID, token); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package secret
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestFileBackend(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cds-secrets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "secrets.json"), []byte(`{"kv/team/db": {"password": "s3cr3t"}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	InitBackends(nil, "")
+	if _, err := NewBackend(sdk.ProjectSecretBackend{Name: "file", Type: sdk.SecretBackendFile, Address: "secrets.json"}); err == nil {
+		t.Fatalf("NewBackend should have failed without secret files directory")
+	}
+
+	InitBackends(nil, dir)
+	defer InitBackends(nil, "")
+	b, err := NewBackend(sdk.ProjectSecretBackend{Name: "file", Type: sdk.SecretBackendFile, Address: "secrets.json"})
+	if err != nil {
+		t.Fatalf("NewBackend failed: %s", err)
+	}
+
+	v, err := b.Read("kv/team/db", "password")
+	if err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	if v != "s3cr3t" {
+		t.Fatalf("Expected s3cr3t, got %s", v)
+	}
+
+	if _, err := b.Read("kv/team/db", "login"); err == nil {
+		t.Fatalf("Read of an unknown field should have failed")
+	}
+
+	for _, a := range []string{"../secrets.json", "team/../../secrets.json", "/etc/passwd", ""} {
+		if _, err := NewBackend(sdk.ProjectSecretBackend{Name: "file", Type: sdk.SecretBackendFile, Address: a}); err == nil {
+			t.Fatalf("Secret file %s should not be allowed", a)
+		}
+	}
+}
+
+func TestCheckBackend(t *testing.T) {
+	InitBackends([]string{"https://vault.example.com/"}, "")
+	defer InitBackends(nil, "")
+
+	if err := CheckBackend(sdk.ProjectSecretBackend{Type: sdk.SecretBackendVaultKV2, Address: "https://vault.example.com"}); err != nil {
+		t.Fatalf("CheckBackend failed: %s", err)
+	}
+	if err := CheckBackend(sdk.ProjectSecretBackend{Type: sdk.SecretBackendVaultKV1, Address: "http://169.254.169.254"}); err == nil {
+		t.Fatalf("CheckBackend should have failed on an address which is not allowed")
+	}
+}
+
+func TestKV2DataPath(t *testing.T) {
+	if p := kv2DataPath("kv/team/db"); p != "kv/data/team/db" {
+		t.Fatalf("Expected kv/data/team/db, got %s", p)
+	}
+	if p := kv2DataPath("kv"); p != "kv" {
+		t.Fatalf("Expected kv, got %s", p)
+	}
+}
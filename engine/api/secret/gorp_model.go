@@ -0,0 +1,12 @@
+package secret
+
+import (
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+type dbProjectSecretBackend sdk.ProjectSecretBackend
+
+func init() {
+	gorpmapping.Register(gorpmapping.New(dbProjectSecretBackend{}, "project_secret_backend", true, "id"))
+}
@@ -197,11 +197,18 @@ func DecryptS(ptype string, val sql.NullString, data []byte, clear bool) (string
 }
 
 // EncryptS wrap Encrypt and:
+// - check the reference if type is a secret reference
 // - return valid string if type is not a password
 // - cipher and returned ciphered value in a []byte if password
 func EncryptS(ptype string, value string) (sql.NullString, []byte, error) {
 	var n sql.NullString
 
+	if ptype == sdk.SecretReferenceVariable {
+		if _, err := sdk.ParseSecretReference(value); err != nil {
+			return n, nil, err
+		}
+	}
+
 	if !sdk.NeedPlaceholder(ptype) {
 		n.String = value
 		n.Valid = true
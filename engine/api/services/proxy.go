@@ -0,0 +1,34 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// ProxyParameters returns the build parameters giving to workers the URLs of the cache service
+// which was the last to heartbeat, or nothing if there is no cache service
+func (r *Repository) ProxyParameters() ([]sdk.Parameter, error) {
+	srvs, err := r.FindByType("cache")
+	if err != nil {
+		return nil, sdk.WrapError(err, "ProxyParameters> Unable to load cache services")
+	}
+	if len(srvs) == 0 {
+		return nil, nil
+	}
+
+	srv := srvs[0]
+	for _, s := range srvs[1:] {
+		if s.LastHeartbeat.After(srv.LastHeartbeat) {
+			srv = s
+		}
+	}
+
+	url := strings.TrimSuffix(srv.HTTPURL, "/")
+	params := []sdk.Parameter{}
+	sdk.AddParameter(&params, "cds.proxy.url", sdk.StringParameter, url)
+	sdk.AddParameter(&params, "cds.proxy.goproxy", sdk.StringParameter, url+"/go")
+	sdk.AddParameter(&params, "cds.proxy.npm", sdk.StringParameter, url+"/npm/")
+	sdk.AddParameter(&params, "cds.proxy.maven", sdk.StringParameter, url+"/maven/")
+	return params, nil
+}
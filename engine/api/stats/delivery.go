@@ -0,0 +1,199 @@
+package stats
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/lib/pq"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Deployment is a run of a deployment node: a workflow node with a deployment pipeline and an environment
+type Deployment struct {
+	ProjectKey    string
+	WorkflowName  string
+	Environment   string
+	WorkflowRunID int64
+	Success       bool
+	Done          time.Time
+	// ChangeDate is the date of the oldest commit of the workflow run, or its start if commits are unknown
+	ChangeDate time.Time
+}
+
+// LoadDeployments loads the deployments done on a period, ordered by date. Project key and workflow name
+// are optional filters. Deployments are the ones recorded on environments when deployment nodes end
+func LoadDeployments(db gorp.SqlExecutor, projectKey, workflowName string, from, to time.Time) ([]Deployment, error) {
+	query := `SELECT project.projectkey, environment_deployment.workflow_name, environment.name,
+			environment_deployment.workflow_run_id, workflow_run.start, environment_deployment.status, environment_deployment.created
+		FROM environment_deployment
+		JOIN environment ON environment.id = environment_deployment.environment_id
+		JOIN workflow_run ON workflow_run.id = environment_deployment.workflow_run_id
+		JOIN workflow ON workflow.id = environment_deployment.workflow_id
+		JOIN project ON project.id = workflow.project_id
+		WHERE environment_deployment.status IN ($1, $2)
+		AND environment_deployment.created >= $3 AND environment_deployment.created < $4
+		AND ($5 = '' OR project.projectkey = $5)
+		AND ($6 = '' OR environment_deployment.workflow_name = $6)
+		ORDER BY environment_deployment.created`
+	rows, err := db.Query(query, sdk.StatusSuccess.String(), sdk.StatusFail.String(), from, to, projectKey, workflowName)
+	if err != nil {
+		return nil, sdk.WrapError(err, "LoadDeployments> Unable to load deployments")
+	}
+	defer rows.Close()
+
+	var deployments []Deployment
+	runIDs := []int64{}
+	starts := map[int64]time.Time{}
+	for rows.Next() {
+		var d Deployment
+		var status string
+		var start time.Time
+		if err := rows.Scan(&d.ProjectKey, &d.WorkflowName, &d.Environment, &d.WorkflowRunID, &start, &status, &d.Done); err != nil {
+			return nil, sdk.WrapError(err, "LoadDeployments> Unable to scan deployment")
+		}
+		d.Success = status == sdk.StatusSuccess.String()
+		if _, ok := starts[d.WorkflowRunID]; !ok {
+			starts[d.WorkflowRunID] = start
+			runIDs = append(runIDs, d.WorkflowRunID)
+		}
+		deployments = append(deployments, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, sdk.WrapError(err, "LoadDeployments> Unable to read deployments")
+	}
+	if len(deployments) == 0 {
+		return nil, nil
+	}
+
+	changes, err := loadChangeDates(db, runIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range deployments {
+		d := &deployments[i]
+		if c, ok := changes[d.WorkflowRunID]; ok {
+			d.ChangeDate = c
+		} else {
+			d.ChangeDate = starts[d.WorkflowRunID]
+		}
+	}
+	return deployments, nil
+}
+
+// loadChangeDates returns the date of the oldest commit of each workflow run which has commits
+func loadChangeDates(db gorp.SqlExecutor, runIDs []int64) (map[int64]time.Time, error) {
+	rows, err := db.Query(`SELECT workflow_run_id, commits FROM workflow_node_run
+		WHERE workflow_run_id = ANY($1) AND commits IS NOT NULL`, pq.Int64Array(runIDs))
+	if err != nil {
+		return nil, sdk.WrapError(err, "loadChangeDates> Unable to load commits")
+	}
+	defer rows.Close()
+
+	changes := map[int64]time.Time{}
+	for rows.Next() {
+		var id int64
+		var s string
+		if err := rows.Scan(&id, &s); err != nil {
+			return nil, sdk.WrapError(err, "loadChangeDates> Unable to scan commits")
+		}
+		var commits []sdk.VCSCommit
+		if err := json.Unmarshal([]byte(s), &commits); err != nil {
+			return nil, sdk.WrapError(err, "loadChangeDates> Unable to unmarshal commits")
+		}
+		for _, c := range commits {
+			if c.Timestamp <= 0 {
+				continue
+			}
+			// Most repositories managers give milliseconds, some give seconds
+			t := time.Unix(c.Timestamp, 0)
+			if c.Timestamp > 1e11 {
+				t = time.Unix(0, c.Timestamp*int64(time.Millisecond))
+			}
+			if d, ok := changes[id]; !ok || t.Before(d) {
+				changes[id] = t
+			}
+		}
+	}
+	return changes, rows.Err()
+}
+
+// ComputeDeliveryMetrics computes the delivery metrics of deployments ordered by date, done on a period
+func ComputeDeliveryMetrics(deployments []Deployment, from, to time.Time) sdk.DeliveryMetrics {
+	m := sdk.DeliveryMetrics{From: from, To: to}
+
+	var leadTime, repairTime time.Duration
+	var repairs int64
+	// date of the first failed deployment not followed by a success, by workflow and environment
+	failures := map[string]time.Time{}
+	for _, d := range deployments {
+		key := d.ProjectKey + "/" + d.WorkflowName + "/" + d.Environment
+		if !d.Success {
+			m.FailedDeployments++
+			if _, ok := failures[key]; !ok {
+				failures[key] = d.Done
+			}
+			continue
+		}
+
+		m.Deployments++
+		leadTime += d.Done.Sub(d.ChangeDate)
+		if f, ok := failures[key]; ok {
+			repairTime += d.Done.Sub(f)
+			repairs++
+			delete(failures, key)
+		}
+	}
+
+	if days := to.Sub(from).Hours() / 24; days > 0 {
+		m.DeploymentFrequency = float64(m.Deployments) / days
+	}
+	if m.Deployments > 0 {
+		m.LeadTime = leadTime.Seconds() / float64(m.Deployments)
+	}
+	if repairs > 0 {
+		m.MTTR = repairTime.Seconds() / float64(repairs)
+	}
+	if total := m.Deployments + m.FailedDeployments; total > 0 {
+		m.ChangeFailureRate = float64(m.FailedDeployments) / float64(total)
+	}
+	return m
+}
+
+// ComputeDeliveryMetricsByWorkflow computes the delivery metrics of each workflow, ordered by project and
+// workflow
+func ComputeDeliveryMetricsByWorkflow(deployments []Deployment, from, to time.Time) []sdk.DeliveryMetrics {
+	byWorkflow := map[[2]string][]Deployment{}
+	for _, d := range deployments {
+		k := [2]string{d.ProjectKey, d.WorkflowName}
+		byWorkflow[k] = append(byWorkflow[k], d)
+	}
+
+	res := make([]sdk.DeliveryMetrics, 0, len(byWorkflow))
+	for k, deps := range byWorkflow {
+		m := ComputeDeliveryMetrics(deps, from, to)
+		m.ProjectKey = k[0]
+		m.WorkflowName = k[1]
+		res = append(res, m)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].ProjectKey != res[j].ProjectKey {
+			return res[i].ProjectKey < res[j].ProjectKey
+		}
+		return res[i].WorkflowName < res[j].WorkflowName
+	})
+	return res
+}
+
+// FilterDeployments returns the deployments on an environment
+func FilterDeployments(deployments []Deployment, environment string) []Deployment {
+	var res []Deployment
+	for _, d := range deployments {
+		if d.Environment == environment {
+			res = append(res, d)
+		}
+	}
+	return res
+}
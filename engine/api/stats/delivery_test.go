@@ -0,0 +1,41 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeDeliveryMetrics(t *testing.T) {
+	from := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 10)
+	at := func(h int) time.Time { return from.Add(time.Duration(h) * time.Hour) }
+
+	deployments := []Deployment{
+		{ProjectKey: "PROJ", WorkflowName: "w1", Environment: "prod", Success: true, ChangeDate: at(0), Done: at(2)},
+		{ProjectKey: "PROJ", WorkflowName: "w1", Environment: "prod", Success: false, ChangeDate: at(3), Done: at(4)},
+		{ProjectKey: "PROJ", WorkflowName: "w1", Environment: "staging", Success: true, ChangeDate: at(3), Done: at(5)},
+		{ProjectKey: "PROJ", WorkflowName: "w1", Environment: "prod", Success: false, ChangeDate: at(5), Done: at(6)},
+		{ProjectKey: "PROJ", WorkflowName: "w1", Environment: "prod", Success: true, ChangeDate: at(5), Done: at(7)},
+		{ProjectKey: "PROJ", WorkflowName: "w2", Environment: "prod", Success: true, ChangeDate: at(10), Done: at(12)},
+	}
+
+	m := ComputeDeliveryMetrics(deployments, from, to)
+	assert.Equal(t, int64(4), m.Deployments)
+	assert.Equal(t, int64(2), m.FailedDeployments)
+	assert.Equal(t, 0.4, m.DeploymentFrequency)
+	assert.Equal(t, (2 * time.Hour).Seconds(), m.LeadTime)
+	// prod of w1 failed at 4 and was repaired at 7
+	assert.Equal(t, (3 * time.Hour).Seconds(), m.MTTR)
+	assert.Equal(t, 2.0/6.0, m.ChangeFailureRate)
+
+	byWorkflow := ComputeDeliveryMetricsByWorkflow(deployments, from, to)
+	assert.Len(t, byWorkflow, 2)
+	assert.Equal(t, "w1", byWorkflow[0].WorkflowName)
+	assert.Equal(t, int64(3), byWorkflow[0].Deployments)
+	assert.Equal(t, "w2", byWorkflow[1].WorkflowName)
+	assert.Equal(t, 0.0, byWorkflow[1].ChangeFailureRate)
+
+	assert.Len(t, FilterDeployments(deployments, "staging"), 1)
+}
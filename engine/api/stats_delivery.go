@@ -0,0 +1,119 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/engine/api/stats"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+// deliveryPeriodFromRequest reads the period of the delivery metrics in query parameters from and to (RFC3339).
+// The default period is the last 90 days
+func deliveryPeriodFromRequest(r *http.Request) (time.Time, time.Time, error) {
+	to := time.Now()
+	if s := r.FormValue("to"); s != "" {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return to, to, sdk.WrapError(sdk.ErrWrongRequest, "deliveryPeriodFromRequest> Invalid to %s", s)
+		}
+		to = t
+	}
+	from := to.AddDate(0, 0, -90)
+	if s := r.FormValue("from"); s != "" {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return from, to, sdk.WrapError(sdk.ErrWrongRequest, "deliveryPeriodFromRequest> Invalid from %s", s)
+		}
+		from = t
+	}
+	if !from.Before(to) {
+		return from, to, sdk.WrapError(sdk.ErrWrongRequest, "deliveryPeriodFromRequest> from must be before to")
+	}
+	return from, to, nil
+}
+
+// filterReadableDeployments returns the deployments of the workflows the user is allowed to read
+func (api *API) filterReadableDeployments(ctx context.Context, deployments []stats.Deployment, vars map[string]string) []stats.Deployment {
+	readable := map[string]bool{}
+	var res []stats.Deployment
+	for _, d := range deployments {
+		ok, checked := readable[d.WorkflowName]
+		if !checked {
+			ok = api.checkWorkflowPermissions(ctx, d.WorkflowName, permission.PermissionRead, vars)
+			readable[d.WorkflowName] = ok
+		}
+		if ok {
+			res = append(res, d)
+		}
+	}
+	return res
+}
+
+func (api *API) getProjectDeliveryMetricsHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["permProjectKey"]
+
+		from, to, err := deliveryPeriodFromRequest(r)
+		if err != nil {
+			return err
+		}
+
+		deployments, err := stats.LoadDeployments(api.mustDB(), key, "", from, to)
+		if err != nil {
+			return sdk.WrapError(err, "getProjectDeliveryMetricsHandler> Cannot load deployments")
+		}
+		if !getUser(ctx).Admin {
+			deployments = api.filterReadableDeployments(ctx, deployments, vars)
+		}
+		env := r.FormValue("environment")
+		if env != "" {
+			deployments = stats.FilterDeployments(deployments, env)
+		}
+
+		m := stats.ComputeDeliveryMetrics(deployments, from, to)
+		m.ProjectKey = key
+		m.Environment = env
+		m.Workflows = stats.ComputeDeliveryMetricsByWorkflow(deployments, from, to)
+		return WriteJSON(w, r, m, http.StatusOK)
+	}
+}
+
+func (api *API) getWorkflowDeliveryMetricsHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["permProjectKey"]
+		name := vars["workflowName"]
+
+		from, to, err := deliveryPeriodFromRequest(r)
+		if err != nil {
+			return err
+		}
+
+		// Checks the workflow exists and the user can read it
+		if _, err := workflow.Load(api.mustDB(), api.Cache, key, name, getUser(ctx)); err != nil {
+			return sdk.WrapError(err, "getWorkflowDeliveryMetricsHandler> Cannot load workflow")
+		}
+
+		deployments, err := stats.LoadDeployments(api.mustDB(), key, name, from, to)
+		if err != nil {
+			return sdk.WrapError(err, "getWorkflowDeliveryMetricsHandler> Cannot load deployments")
+		}
+		env := r.FormValue("environment")
+		if env != "" {
+			deployments = stats.FilterDeployments(deployments, env)
+		}
+
+		m := stats.ComputeDeliveryMetrics(deployments, from, to)
+		m.ProjectKey = key
+		m.WorkflowName = name
+		m.Environment = env
+		return WriteJSON(w, r, m, http.StatusOK)
+	}
+}
@@ -0,0 +1,137 @@
+package user
+
+import (
+	"crypto/sha512"
+	"database/sql"
+	"encoding/hex"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/engine/api/token"
+	"github.com/ovh/cds/sdk"
+)
+
+// HashAccessToken returns the value stored in database for a clear access token
+func HashAccessToken(t string) string {
+	h := sha512.Sum512([]byte(t))
+	return hex.EncodeToString(h[:])
+}
+
+// NewAccessToken generates a new access token for a user. The clear token is only set on
+// the returned struct, only its hash is stored in database
+func NewAccessToken(db gorp.SqlExecutor, u *sdk.User, name string, scopes []string, expireAt time.Time) (*sdk.AccessToken, error) {
+	tk, err := token.GenerateToken()
+	if err != nil {
+		return nil, sdk.WrapError(err, "NewAccessToken> Unable to generate token")
+	}
+
+	t := sdk.AccessToken{
+		Name:     name,
+		UserID:   u.ID,
+		Hash:     HashAccessToken(tk),
+		Scopes:   scopes,
+		Created:  time.Now(),
+		ExpireAt: expireAt,
+	}
+	if err := InsertAccessToken(db, &t); err != nil {
+		return nil, err
+	}
+	t.Token = tk
+	return &t, nil
+}
+
+// InsertAccessToken inserts an access token
+func InsertAccessToken(db gorp.SqlExecutor, t *sdk.AccessToken) error {
+	dbt := accessToken(*t)
+	if err := db.Insert(&dbt); err != nil {
+		return sdk.WrapError(err, "InsertAccessToken> Unable to insert access token %s for user %d", t.Name, t.UserID)
+	}
+	*t = sdk.AccessToken(dbt)
+	return nil
+}
+
+// LoadAccessTokens loads all access tokens of a user
+func LoadAccessTokens(db gorp.SqlExecutor, userID int64) ([]sdk.AccessToken, error) {
+	dbts := []accessToken{}
+	if _, err := db.Select(&dbts, "select * from user_access_token where user_id = $1 order by created", userID); err != nil {
+		return nil, sdk.WrapError(err, "LoadAccessTokens> Unable to load access tokens for user %d", userID)
+	}
+	ts := make([]sdk.AccessToken, len(dbts))
+	for i := range dbts {
+		ts[i] = sdk.AccessToken(dbts[i])
+	}
+	return ts, nil
+}
+
+// LoadAccessTokenByID loads an access token of a user from its id
+func LoadAccessTokenByID(db gorp.SqlExecutor, userID, id int64) (*sdk.AccessToken, error) {
+	dbt := accessToken{}
+	if err := db.SelectOne(&dbt, "select * from user_access_token where user_id = $1 and id = $2", userID, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.ErrInvalidToken
+		}
+		return nil, sdk.WrapError(err, "LoadAccessTokenByID> Unable to load access token %d", id)
+	}
+	t := sdk.AccessToken(dbt)
+	return &t, nil
+}
+
+// LoadAccessToken loads an access token from its clear value
+func LoadAccessToken(db gorp.SqlExecutor, clear string) (*sdk.AccessToken, error) {
+	dbt := accessToken{}
+	if err := db.SelectOne(&dbt, "select * from user_access_token where hash = $1", HashAccessToken(clear)); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.ErrInvalidToken
+		}
+		return nil, sdk.WrapError(err, "LoadAccessToken> Unable to load access token")
+	}
+	t := sdk.AccessToken(dbt)
+	return &t, nil
+}
+
+// UpdateAccessTokenLastUsed sets the last usage date of an access token
+func UpdateAccessTokenLastUsed(db gorp.SqlExecutor, t *sdk.AccessToken) error {
+	now := time.Now()
+	if _, err := db.Exec("update user_access_token set last_used = $2 where id = $1", t.ID, now); err != nil {
+		return sdk.WrapError(err, "UpdateAccessTokenLastUsed> Unable to update access token %d", t.ID)
+	}
+	t.LastUsed = &now
+	return nil
+}
+
+// DeleteAccessToken revokes an access token
+func DeleteAccessToken(db gorp.SqlExecutor, t *sdk.AccessToken) error {
+	dbt := accessToken(*t)
+	if _, err := db.Delete(&dbt); err != nil {
+		return sdk.WrapError(err, "DeleteAccessToken> Unable to delete access token %d", t.ID)
+	}
+	return nil
+}
+
+// PostInsert is a db hook
+func (t *accessToken) PostInsert(db gorp.SqlExecutor) error {
+	return t.PostUpdate(db)
+}
+
+// PostUpdate is a db hook
+func (t *accessToken) PostUpdate(db gorp.SqlExecutor) error {
+	scopes, err := gorpmapping.JSONToNullString(t.Scopes)
+	if err != nil {
+		return err
+	}
+	if _, err := db.Exec("update user_access_token set scopes = $2 where id = $1", t.ID, scopes); err != nil {
+		return err
+	}
+	return nil
+}
+
+// PostGet is a db hook
+func (t *accessToken) PostGet(db gorp.SqlExecutor) error {
+	var scopes sql.NullString
+	if err := db.QueryRow("select scopes from user_access_token where id = $1", t.ID).Scan(&scopes); err != nil {
+		return err
+	}
+	return gorpmapping.JSONNullString(scopes, &t.Scopes)
+}
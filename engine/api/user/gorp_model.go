@@ -7,8 +7,9 @@ import (
 )
 
 type persistentSessionToken sdk.UserToken
+type accessToken sdk.AccessToken
 
 func init() {
 	gorpmapping.Register(gorpmapping.New(persistentSessionToken{}, "user_persistent_session", false, "token"))
-
+	gorpmapping.Register(gorpmapping.New(accessToken{}, "user_access_token", true, "id"))
 }
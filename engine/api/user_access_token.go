@@ -0,0 +1,106 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/user"
+	"github.com/ovh/cds/sdk"
+)
+
+// getUserAccessTokensHandler returns all access tokens of a user, without their values
+func (api *API) getUserAccessTokensHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		username := vars["username"]
+
+		u, err := user.LoadUserWithoutAuth(api.mustDB(), username)
+		if err != nil {
+			return sdk.WrapError(err, "getUserAccessTokensHandler> Cannot load user %s", username)
+		}
+
+		tokens, err := user.LoadAccessTokens(api.mustDB(), u.ID)
+		if err != nil {
+			return sdk.WrapError(err, "getUserAccessTokensHandler> Cannot load access tokens of user %s", username)
+		}
+
+		return WriteJSON(w, r, tokens, http.StatusOK)
+	}
+}
+
+// postUserAccessTokenHandler creates a new access token. Its value is only returned once
+func (api *API) postUserAccessTokenHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		username := vars["username"]
+
+		var t sdk.AccessToken
+		if err := UnmarshalBody(r, &t); err != nil {
+			return err
+		}
+
+		if t.Name == "" {
+			return sdk.WrapError(sdk.ErrWrongRequest, "postUserAccessTokenHandler> Access token name is mandatory")
+		}
+		if len(t.Scopes) == 0 {
+			return sdk.WrapError(sdk.ErrWrongRequest, "postUserAccessTokenHandler> Access token needs at least one scope")
+		}
+		for _, s := range t.Scopes {
+			if !sdk.IsValidAccessTokenScope(s) {
+				return sdk.WrapError(sdk.ErrWrongRequest, "postUserAccessTokenHandler> Invalid scope %s, should be one of %v", s, sdk.AccessTokenScopes)
+			}
+		}
+		if !t.ExpireAt.After(time.Now()) {
+			return sdk.WrapError(sdk.ErrWrongRequest, "postUserAccessTokenHandler> Invalid expiration date %s", t.ExpireAt)
+		}
+
+		u, err := user.LoadUserWithoutAuth(api.mustDB(), username)
+		if err != nil {
+			return sdk.WrapError(err, "postUserAccessTokenHandler> Cannot load user %s", username)
+		}
+
+		//A token can't grant more than the user is allowed to do
+		if t.HasScope(sdk.AccessTokenScopeAdmin) && !u.Admin {
+			return sdk.WrapError(sdk.ErrForbidden, "postUserAccessTokenHandler> User %s is not allowed to create an admin token", username)
+		}
+
+		newToken, err := user.NewAccessToken(api.mustDB(), u, t.Name, t.Scopes, t.ExpireAt)
+		if err != nil {
+			return sdk.WrapError(err, "postUserAccessTokenHandler> Cannot create access token %s", t.Name)
+		}
+
+		return WriteJSON(w, r, newToken, http.StatusCreated)
+	}
+}
+
+// deleteUserAccessTokenHandler revokes an access token
+func (api *API) deleteUserAccessTokenHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		username := vars["username"]
+
+		id, err := requestVarInt(r, "tokenID")
+		if err != nil {
+			return err
+		}
+
+		u, err := user.LoadUserWithoutAuth(api.mustDB(), username)
+		if err != nil {
+			return sdk.WrapError(err, "deleteUserAccessTokenHandler> Cannot load user %s", username)
+		}
+
+		t, err := user.LoadAccessTokenByID(api.mustDB(), u.ID, id)
+		if err != nil {
+			return sdk.WrapError(err, "deleteUserAccessTokenHandler> Cannot load access token %d", id)
+		}
+
+		if err := user.DeleteAccessToken(api.mustDB(), t); err != nil {
+			return sdk.WrapError(err, "deleteUserAccessTokenHandler> Cannot delete access token %d", id)
+		}
+
+		return WriteJSON(w, r, nil, http.StatusOK)
+	}
+}
@@ -19,8 +19,13 @@ func (m *WorkerModel) PostInsert(s gorp.SqlExecutor) error {
 		return err
 	}
 
-	query := "update worker_model set created_by = $2 where id = $1"
-	if _, err := s.Exec(query, m.ID, btes); err != nil {
+	policy, err := json.Marshal(m.PoolPolicy)
+	if err != nil {
+		return err
+	}
+
+	query := "update worker_model set created_by = $2, pool_policy = $3 where id = $1"
+	if _, err := s.Exec(query, m.ID, btes, policy); err != nil {
 		return err
 	}
 
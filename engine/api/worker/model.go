@@ -2,6 +2,7 @@ package worker
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"sort"
 	"time"
@@ -30,21 +31,26 @@ const columns = `
 	worker_model.last_spawn_err,
 	worker_model.nb_spawn_err,
 	worker_model.date_last_spawn_err,
+	worker_model.dockerfile,
+	worker_model.version,
+	worker_model.pool_policy,
 	"group".name as groupname`
 
 type dbResultWMS struct {
 	WorkerModel
-	GroupName string `db:"groupname"`
+	DBPoolPolicy sql.NullString `db:"pool_policy"`
+	GroupName    string         `db:"groupname"`
 }
 
-// InsertWorkerModel insert a new worker model in database
+// InsertWorkerModel insert a new worker model in database, with its first version
 func InsertWorkerModel(db gorp.SqlExecutor, model *sdk.Model) error {
+	model.Version = 1
 	dbmodel := WorkerModel(*model)
 	if err := db.Insert(&dbmodel); err != nil {
 		return err
 	}
 	*model = sdk.Model(dbmodel)
-	return nil
+	return insertWorkerModelVersion(db, *model, model.CreatedBy.Username)
 }
 
 // UpdateWorkerModel update a worker model. If worker model have SpawnErr -> clear them
@@ -133,6 +139,11 @@ func scanWorkerModels(db gorp.SqlExecutor, rows []dbResultWMS) ([]sdk.Model, err
 	for _, row := range rows {
 		m := row.WorkerModel
 		m.Group = sdk.Group{ID: m.GroupID, Name: row.GroupName}
+		if row.DBPoolPolicy.Valid && row.DBPoolPolicy.String != "" {
+			if err := json.Unmarshal([]byte(row.DBPoolPolicy.String), &m.PoolPolicy); err != nil {
+				return nil, sdk.WrapError(err, "scanWorkerModels> Unable to unmarshal pool policy of model %s", m.Name)
+			}
+		}
 		if err := m.PostSelect(db); err != nil {
 			return nil, err
 		}
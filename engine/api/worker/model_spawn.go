@@ -0,0 +1,88 @@
+package worker
+
+import (
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// number of spawn durations kept by model to compute the average spawn duration
+const spawnHistorySize = 10
+
+// spawnDurations returns the durations of the successful spawns reported in spawn infos, by model name
+func spawnDurations(infos []sdk.SpawnInfo) map[string][]time.Duration {
+	durations := map[string][]time.Duration{}
+	starts := map[string]sdk.SpawnInfo{}
+	for _, i := range infos {
+		if len(i.Message.Args) == 0 {
+			continue
+		}
+		hatchery, _ := i.Message.Args[0].(string)
+		switch i.Message.ID {
+		case sdk.MsgSpawnInfoHatcheryStarts.ID:
+			starts[hatchery] = i
+		case sdk.MsgSpawnInfoHatcheryStartsSuccessfully.ID:
+			start, ok := starts[hatchery]
+			if !ok || len(start.Message.Args) < 3 {
+				continue
+			}
+			model, _ := start.Message.Args[2].(string)
+			if model == "" {
+				continue
+			}
+			durations[model] = append(durations[model], i.RemoteTime.Sub(start.RemoteTime))
+			delete(starts, hatchery)
+		}
+	}
+	return durations
+}
+
+// InsertSpawnInfos keeps the durations of the successful spawns reported by hatcheries in spawn infos, so that
+// hatcheries know how long workers of a model take to spawn
+func InsertSpawnInfos(db gorp.SqlExecutor, infos []sdk.SpawnInfo) error {
+	for model, durations := range spawnDurations(infos) {
+		for _, d := range durations {
+			query := `insert into worker_model_spawn (worker_model_id, duration) select id, $2 from worker_model where name = $1`
+			if _, err := db.Exec(query, model, int64(d/time.Millisecond)); err != nil {
+				return sdk.WrapError(err, "InsertSpawnInfos> Unable to insert spawn duration of model %s", model)
+			}
+		}
+
+		query := `delete from worker_model_spawn where worker_model_id = (select id from worker_model where name = $1)
+		and id not in (
+			select worker_model_spawn.id from worker_model_spawn
+			join worker_model on worker_model.id = worker_model_spawn.worker_model_id
+			where worker_model.name = $1
+			order by worker_model_spawn.created desc, worker_model_spawn.id desc limit $2
+		)`
+		if _, err := db.Exec(query, model, spawnHistorySize); err != nil {
+			return sdk.WrapError(err, "InsertSpawnInfos> Unable to purge spawn durations of model %s", model)
+		}
+	}
+	return nil
+}
+
+// loadSpawnDurations returns the average duration of the last spawns of each model
+func loadSpawnDurations(db gorp.SqlExecutor) (map[int64]time.Duration, error) {
+	query := `select worker_model_id, avg(duration)::bigint from (
+		select worker_model_id, duration, row_number() over (partition by worker_model_id order by created desc, id desc) as rank
+		from worker_model_spawn
+	) as spawns where rank <= $1 group by worker_model_id`
+	rows, err := db.Query(query, spawnHistorySize)
+	if err != nil {
+		return nil, sdk.WrapError(err, "loadSpawnDurations> Unable to load spawn durations")
+	}
+	defer rows.Close()
+
+	durations := map[int64]time.Duration{}
+	for rows.Next() {
+		var id, ms int64
+		if err := rows.Scan(&id, &ms); err != nil {
+			return nil, sdk.WrapError(err, "loadSpawnDurations> Unable to scan spawn duration")
+		}
+		durations[id] = time.Duration(ms) * time.Millisecond
+	}
+	return durations, nil
+}
@@ -212,3 +212,22 @@ func TestUpdateWorkerModel(t *testing.T) {
 	assert.Equal(t, sdk.Docker, m3.Type)
 	assert.Equal(t, 2, len(m3.Capabilities))
 }
+
+func TestSpawnDurations(t *testing.T) {
+	start := time.Now()
+	starts := func(hatchery, model string, at time.Time) sdk.SpawnInfo {
+		return sdk.SpawnInfo{RemoteTime: at, Message: sdk.SpawnMsg{ID: sdk.MsgSpawnInfoHatcheryStarts.ID, Args: []interface{}{hatchery, "1", model}}}
+	}
+	success := func(hatchery string, at time.Time) sdk.SpawnInfo {
+		return sdk.SpawnInfo{RemoteTime: at, Message: sdk.SpawnMsg{ID: sdk.MsgSpawnInfoHatcheryStartsSuccessfully.ID, Args: []interface{}{hatchery, "1", "worker", "1s"}}}
+	}
+
+	durations := spawnDurations([]sdk.SpawnInfo{
+		starts("h1", "go", start),
+		success("h1", start.Add(30*time.Second)),
+		starts("h2", "java", start),
+		{RemoteTime: start.Add(time.Second), Message: sdk.SpawnMsg{ID: sdk.MsgSpawnInfoHatcheryErrorSpawn.ID, Args: []interface{}{"h2", "1", "java", "1s", "error"}}},
+		success("h3", start.Add(time.Minute)),
+	})
+	assert.Equal(t, map[string][]time.Duration{"go": {30 * time.Second}}, durations)
+}
@@ -0,0 +1,89 @@
+package worker
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// UpdateWorkerModelVersion updates a worker model as a new version of the model
+func UpdateWorkerModelVersion(db gorp.SqlExecutor, model *sdk.Model, author string) error {
+	var last sql.NullInt64
+	if err := db.QueryRow("select max(version) from worker_model_version where worker_model_id = $1", model.ID).Scan(&last); err != nil {
+		return sdk.WrapError(err, "UpdateWorkerModelVersion> Unable to load last version of model %d", model.ID)
+	}
+	model.Version = last.Int64 + 1
+
+	if err := UpdateWorkerModel(db, *model); err != nil {
+		return sdk.WrapError(err, "UpdateWorkerModelVersion> Unable to update model %d", model.ID)
+	}
+	return insertWorkerModelVersion(db, *model, author)
+}
+
+func insertWorkerModelVersion(db gorp.SqlExecutor, model sdk.Model, author string) error {
+	// The state of the model is not a part of its definition
+	model.NeedRegistration = false
+	model.LastRegistration = time.Time{}
+	model.NbSpawnErr = 0
+	model.LastSpawnErr = ""
+	model.DateLastSpawnErr = nil
+
+	btes, err := json.Marshal(model)
+	if err != nil {
+		return sdk.WrapError(err, "insertWorkerModelVersion> Unable to marshal model")
+	}
+
+	query := `insert into worker_model_version (worker_model_id, version, model, author, created) values ($1, $2, $3, $4, $5)`
+	if _, err := db.Exec(query, model.ID, model.Version, btes, author, time.Now()); err != nil {
+		return sdk.WrapError(err, "insertWorkerModelVersion> Unable to insert version %d of model %d", model.Version, model.ID)
+	}
+	return nil
+}
+
+// LoadWorkerModelVersions returns the versions of a worker model, the last first
+func LoadWorkerModelVersions(db gorp.SqlExecutor, modelID int64) ([]sdk.ModelVersion, error) {
+	query := `select version, model, author, created from worker_model_version where worker_model_id = $1 order by version desc`
+	rows, err := db.Query(query, modelID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "LoadWorkerModelVersions> Unable to load versions of model %d", modelID)
+	}
+	defer rows.Close()
+
+	versions := []sdk.ModelVersion{}
+	for rows.Next() {
+		v, err := scanWorkerModelVersion(rows.Scan, modelID)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, *v)
+	}
+	return versions, nil
+}
+
+// LoadWorkerModelVersion returns a version of a worker model
+func LoadWorkerModelVersion(db gorp.SqlExecutor, modelID, version int64) (*sdk.ModelVersion, error) {
+	query := `select version, model, author, created from worker_model_version where worker_model_id = $1 and version = $2`
+	v, err := scanWorkerModelVersion(db.QueryRow(query, modelID, version).Scan, modelID)
+	if err == sql.ErrNoRows {
+		return nil, sdk.ErrNoWorkerModelVersion
+	}
+	return v, err
+}
+
+func scanWorkerModelVersion(scan func(...interface{}) error, modelID int64) (*sdk.ModelVersion, error) {
+	v := sdk.ModelVersion{ModelID: modelID}
+	var btes []byte
+	var author sql.NullString
+	if err := scan(&v.Version, &btes, &author, &v.Created); err != nil {
+		return nil, err
+	}
+	v.Author = author.String
+	if err := json.Unmarshal(btes, &v.Model); err != nil {
+		return nil, sdk.WrapError(err, "scanWorkerModelVersion> Unable to unmarshal version %d of model %d", v.Version, modelID)
+	}
+	return &v, nil
+}
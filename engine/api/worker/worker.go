@@ -398,23 +398,27 @@ func SetToBuilding(db gorp.SqlExecutor, workerID string, actionBuildID int64) er
 	return err
 }
 
-// LoadWorkerModelsUsableOnGroup returns worker models for a group
+// LoadWorkerModelsUsableOnGroup returns worker models for a group, with the average duration of their last spawns
 func LoadWorkerModelsUsableOnGroup(db gorp.SqlExecutor, groupID, sharedinfraGroupID int64) ([]sdk.Model, error) {
-	ms := []WorkerModel{}
-	if _, err := db.Select(&ms, `
-		SELECT * from worker_model
-		WHERE disabled = FALSE
-		AND (group_id = $1 OR group_id = $2 OR $1 = $2)
-		ORDER by name
-		`, groupID, sharedinfraGroupID); err != nil {
+	wms := []dbResultWMS{}
+	query := fmt.Sprintf(`select %s from worker_model
+		JOIN "group" on worker_model.group_id = "group".id
+		WHERE worker_model.disabled = FALSE
+		AND (worker_model.group_id = $1 OR worker_model.group_id = $2 OR $1 = $2)`, columns)
+	if _, err := db.Select(&wms, query, groupID, sharedinfraGroupID); err != nil {
+		return nil, sdk.WrapError(err, "LoadWorkerModelsUsableOnGroup> Unable to load worker models")
+	}
+	models, err := scanWorkerModels(db, wms)
+	if err != nil {
 		return nil, err
 	}
-	models := []sdk.Model{}
-	for i := range ms {
-		if err := ms[i].PostSelect(db); err != nil {
-			return nil, err
-		}
-		models = append(models, sdk.Model(ms[i]))
+
+	durations, err := loadSpawnDurations(db)
+	if err != nil {
+		return nil, err
+	}
+	for i := range models {
+		models[i].SpawnDuration = durations[models[i].ID]
 	}
 	return models, nil
 }
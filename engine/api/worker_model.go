@@ -5,7 +5,7 @@ import (
 	"net/http"
 
 	"github.com/ovh/cds/engine/api/action"
-
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/group"
 	"github.com/ovh/cds/engine/api/pipeline"
 	"github.com/ovh/cds/engine/api/project"
@@ -35,6 +35,10 @@ func (api *API) addWorkerModelHandler() Handler {
 			return sdk.WrapError(sdk.ErrWrongRequest, "addWorkerModel> groupID should be set")
 		}
 
+		if err := model.PoolPolicy.IsValid(); err != nil {
+			return sdk.WrapError(err, "addWorkerModel> Invalid pool policy")
+		}
+
 		//User must be admin of the group set in the model
 		var ok bool
 		for _, g := range getUser(ctx).Groups {
@@ -62,11 +66,25 @@ func (api *API) addWorkerModelHandler() Handler {
 			Origin:   getUser(ctx).Origin,
 		}
 
+		tx, errb := api.mustDB().Begin()
+		if errb != nil {
+			return sdk.WrapError(errb, "addWorkerModel> unable to start transaction")
+		}
+		defer tx.Rollback()
+
 		// Insert model in db
-		if err := worker.InsertWorkerModel(api.mustDB(), &model); err != nil {
+		if err := worker.InsertWorkerModel(tx, &model); err != nil {
 			return sdk.WrapError(err, "addWorkerModel> cannot add worker model")
 		}
 
+		if err := audit.Record(tx, getUser(ctx), "", sdk.AuditWorkerModel, model.Name, audit.Added, nil, &model); err != nil {
+			return sdk.WrapError(err, "addWorkerModel> cannot record audit")
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "addWorkerModel> unable to commit transaction")
+		}
+
 		return WriteJSON(w, r, model, http.StatusOK)
 	}
 }
@@ -135,9 +153,10 @@ func (api *API) updateWorkerModelHandler() Handler {
 			renamed = true
 		}
 
-		//If the model image has not been set, keep the old image
-		if model.Image == "" {
+		//If the model image has not been set, keep the old image or Dockerfile
+		if model.Image == "" && model.Dockerfile == "" {
 			model.Image = old.Image
+			model.Dockerfile = old.Dockerfile
 		}
 
 		//If the model Capabilities has not been set, keep the old Capabilities
@@ -155,6 +174,10 @@ func (api *API) updateWorkerModelHandler() Handler {
 			model.Type = old.Type
 		}
 
+		if err := model.PoolPolicy.IsValid(); err != nil {
+			return sdk.WrapError(err, "updateWorkerModel> Invalid pool policy")
+		}
+
 		//If the model modelID has not been set, keep the old modelID
 		if model.ID == 0 {
 			model.ID = old.ID
@@ -189,13 +212,13 @@ func (api *API) updateWorkerModelHandler() Handler {
 		defer tx.Rollback()
 
 		// update model in db
-		if err := worker.UpdateWorkerModel(tx, model); err != nil {
+		if err := worker.UpdateWorkerModelVersion(tx, &model, getUser(ctx).Username); err != nil {
 			return sdk.WrapError(err, "updateWorkerModel> cannot update worker model")
 		}
 
 		// update requirements if needed
 		if renamed {
-			actionsID, erru := action.UpdateAllRequirements(tx, old.Name, model.Name, sdk.ModelRequirement)
+			actionsID, erru := action.UpdateAllModelRequirements(tx, old.Name, model.Name)
 			if erru != nil {
 				return sdk.WrapError(erru, "updateWorkerModel> cannot update action requirements")
 			}
@@ -230,6 +253,10 @@ func (api *API) updateWorkerModelHandler() Handler {
 
 		}
 
+		if err := audit.Record(tx, getUser(ctx), "", sdk.AuditWorkerModel, model.Name, audit.Updated, old, &model); err != nil {
+			return sdk.WrapError(err, "updateWorkerModel> cannot record audit")
+		}
+
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "updateWorkerModel> unable to commit transaction")
 		}
@@ -257,6 +284,11 @@ func (api *API) deleteWorkerModelHandler() Handler {
 			return sdk.WrapError(errr, "deleteWorkerModel> Invalid permModelID")
 		}
 
+		old, errLoad := worker.LoadWorkerModelByID(api.mustDB(), workerModelID)
+		if errLoad != nil {
+			return sdk.WrapError(errLoad, "deleteWorkerModel> cannot load worker model by id")
+		}
+
 		tx, err := api.mustDB().Begin()
 		if err != nil {
 			return sdk.WrapError(err, "deleteWorkerModel> Cannot start transaction")
@@ -266,6 +298,10 @@ func (api *API) deleteWorkerModelHandler() Handler {
 			return sdk.WrapError(err, "deleteWorkerModel: cannot delete worker model")
 		}
 
+		if err := audit.Record(tx, getUser(ctx), "", sdk.AuditWorkerModel, old.Name, audit.Deleted, old, nil); err != nil {
+			return sdk.WrapError(err, "deleteWorkerModel> cannot record audit")
+		}
+
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "deleteWorkerModel> Cannot commit transaction")
 		}
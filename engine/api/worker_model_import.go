@@ -0,0 +1,216 @@
+package api
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/hashicorp/hcl"
+	"gopkg.in/yaml.v2"
+
+	"github.com/ovh/cds/engine/api/audit"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/engine/api/worker"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+func (api *API) importWorkerModelHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		format := r.FormValue("format")
+		forceUpdate := FormBool(r, "forceUpdate")
+
+		// Get body
+		data, errRead := ioutil.ReadAll(r.Body)
+		if errRead != nil {
+			return sdk.WrapError(sdk.ErrWrongRequest, "importWorkerModelHandler> Unable to read body")
+		}
+
+		// Compute format
+		f, errF := exportentities.GetFormat(format)
+		if errF != nil {
+			return sdk.WrapError(sdk.ErrWrongRequest, "importWorkerModelHandler> Unable to get format : %s", errF)
+		}
+
+		// Parse the worker model
+		payload := &exportentities.WorkerModel{}
+		var errorParse error
+		switch f {
+		case exportentities.FormatJSON, exportentities.FormatHCL:
+			errorParse = hcl.Unmarshal(data, payload)
+		case exportentities.FormatYAML:
+			errorParse = yaml.Unmarshal(data, payload)
+		}
+		if errorParse != nil {
+			return sdk.WrapError(sdk.ErrWrongRequest, "importWorkerModelHandler> Cannot parse: %s", errorParse)
+		}
+
+		model, errM := payload.Model()
+		if errM != nil {
+			return sdk.WrapError(sdk.ErrWrongRequest, "importWorkerModelHandler> Invalid worker model: %s", errM)
+		}
+		if err := model.PoolPolicy.IsValid(); err != nil {
+			return sdk.WrapError(err, "importWorkerModelHandler> Invalid pool policy")
+		}
+
+		g, errG := group.LoadGroup(api.mustDB(), model.Group.Name)
+		if errG != nil {
+			return sdk.WrapError(errG, "importWorkerModelHandler> Unable to load group %s", model.Group.Name)
+		}
+		model.GroupID = g.ID
+		model.Group = *g
+
+		//User must be admin of the group set in the model
+		if !api.checkWorkerModelPermissionsByUser(model, getUser(ctx), permission.PermissionReadWriteExecute) {
+			return sdk.ErrForbidden
+		}
+
+		old, errLoad := worker.LoadWorkerModelByName(api.mustDB(), model.Name)
+		if errLoad != nil && errLoad != sdk.ErrNoWorkerModel {
+			return sdk.WrapError(errLoad, "importWorkerModelHandler> Unable to load worker model %s", model.Name)
+		}
+
+		tx, errtx := api.mustDB().Begin()
+		if errtx != nil {
+			return sdk.WrapError(errtx, "importWorkerModelHandler> Unable to start transaction")
+		}
+		defer tx.Rollback()
+
+		if old == nil {
+			model.CreatedBy = sdk.User{
+				Email:    getUser(ctx).Email,
+				Username: getUser(ctx).Username,
+				Admin:    getUser(ctx).Admin,
+				Fullname: getUser(ctx).Fullname,
+				ID:       getUser(ctx).ID,
+				Origin:   getUser(ctx).Origin,
+			}
+			if err := worker.InsertWorkerModel(tx, model); err != nil {
+				return sdk.WrapError(err, "importWorkerModelHandler> Unable to add worker model %s", model.Name)
+			}
+			if err := audit.Record(tx, getUser(ctx), "", sdk.AuditWorkerModel, model.Name, audit.Added, nil, model); err != nil {
+				return sdk.WrapError(err, "importWorkerModelHandler> Unable to record audit")
+			}
+		} else {
+			if !forceUpdate {
+				return sdk.WrapError(sdk.ErrAlreadyExist, "importWorkerModelHandler> Worker model %s exists", model.Name)
+			}
+			//User must be admin of the current group of the model too
+			if !api.checkWorkerModelPermissionsByUser(old, getUser(ctx), permission.PermissionReadWriteExecute) {
+				return sdk.ErrForbidden
+			}
+			model.ID = old.ID
+			model.CreatedBy = old.CreatedBy
+			if err := worker.UpdateWorkerModelVersion(tx, model, getUser(ctx).Username); err != nil {
+				return sdk.WrapError(err, "importWorkerModelHandler> Unable to update worker model %s", model.Name)
+			}
+			if err := audit.Record(tx, getUser(ctx), "", sdk.AuditWorkerModel, model.Name, audit.Updated, old, model); err != nil {
+				return sdk.WrapError(err, "importWorkerModelHandler> Unable to record audit")
+			}
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "importWorkerModelHandler> Unable to commit transaction")
+		}
+
+		return WriteJSON(w, r, model, http.StatusOK)
+	}
+}
+
+func (api *API) getWorkerModelVersionsHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		workerModelID, errr := requestVarInt(r, "permModelID")
+		if errr != nil {
+			return sdk.WrapError(errr, "getWorkerModelVersionsHandler> Invalid permModelID")
+		}
+
+		versions, err := worker.LoadWorkerModelVersions(api.mustDB(), workerModelID)
+		if err != nil {
+			return sdk.WrapError(err, "getWorkerModelVersionsHandler> Unable to load versions")
+		}
+		return WriteJSON(w, r, versions, http.StatusOK)
+	}
+}
+
+func (api *API) getWorkerModelVersionHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		workerModelID, errr := requestVarInt(r, "permModelID")
+		if errr != nil {
+			return sdk.WrapError(errr, "getWorkerModelVersionHandler> Invalid permModelID")
+		}
+		version, errv := requestVarInt(r, "version")
+		if errv != nil {
+			return sdk.WrapError(errv, "getWorkerModelVersionHandler> Invalid version")
+		}
+
+		v, err := worker.LoadWorkerModelVersion(api.mustDB(), workerModelID, version)
+		if err != nil {
+			return sdk.WrapError(err, "getWorkerModelVersionHandler> Unable to load version %d", version)
+		}
+		return WriteJSON(w, r, v, http.StatusOK)
+	}
+}
+
+func (api *API) postWorkerModelRollbackHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		workerModelID, errr := requestVarInt(r, "permModelID")
+		if errr != nil {
+			return sdk.WrapError(errr, "postWorkerModelRollbackHandler> Invalid permModelID")
+		}
+		version, errv := requestVarInt(r, "version")
+		if errv != nil {
+			return sdk.WrapError(errv, "postWorkerModelRollbackHandler> Invalid version")
+		}
+
+		old, errLoad := worker.LoadWorkerModelByID(api.mustDB(), workerModelID)
+		if errLoad != nil {
+			return sdk.WrapError(errLoad, "postWorkerModelRollbackHandler> Unable to load worker model %d", workerModelID)
+		}
+
+		v, errV := worker.LoadWorkerModelVersion(api.mustDB(), workerModelID, version)
+		if errV != nil {
+			return sdk.WrapError(errV, "postWorkerModelRollbackHandler> Unable to load version %d", version)
+		}
+
+		//User must be admin of the group of the version too
+		if !api.checkWorkerModelPermissionsByUser(&v.Model, getUser(ctx), permission.PermissionReadWriteExecute) {
+			return sdk.ErrForbidden
+		}
+
+		// The definition of the version is restored as a new version, the name is kept
+		// as requirements use it
+		model := *old
+		model.Type = v.Model.Type
+		model.Image = v.Model.Image
+		model.Dockerfile = v.Model.Dockerfile
+		model.Communication = v.Model.Communication
+		model.GroupID = v.Model.GroupID
+		model.Capabilities = v.Model.Capabilities
+		model.Provision = v.Model.Provision
+		model.PoolPolicy = v.Model.PoolPolicy
+		model.Template = v.Model.Template
+		model.RunScript = v.Model.RunScript
+		model.Disabled = v.Model.Disabled
+
+		tx, errtx := api.mustDB().Begin()
+		if errtx != nil {
+			return sdk.WrapError(errtx, "postWorkerModelRollbackHandler> Unable to start transaction")
+		}
+		defer tx.Rollback()
+
+		if err := worker.UpdateWorkerModelVersion(tx, &model, getUser(ctx).Username); err != nil {
+			return sdk.WrapError(err, "postWorkerModelRollbackHandler> Unable to update worker model")
+		}
+
+		if err := audit.Record(tx, getUser(ctx), "", sdk.AuditWorkerModel, model.Name, audit.Updated, old, &model); err != nil {
+			return sdk.WrapError(err, "postWorkerModelRollbackHandler> Unable to record audit")
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "postWorkerModelRollbackHandler> Unable to commit transaction")
+		}
+
+		return WriteJSON(w, r, model, http.StatusOK)
+	}
+}
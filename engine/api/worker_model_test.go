@@ -4,10 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http/httptest"
+	"sort"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/ovh/cds/engine/api/action"
 	"github.com/ovh/cds/engine/api/bootstrap"
 	"github.com/ovh/cds/engine/api/group"
 	"github.com/ovh/cds/engine/api/test"
@@ -423,6 +425,59 @@ func Test_updateWorkerModel(t *testing.T) {
 
 }
 
+func Test_updateWorkerModelRenameRequirements(t *testing.T) {
+	Test_DeleteAllWorkerModel(t)
+	api, db, router := newTestAPI(t, bootstrap.InitiliazeDB)
+
+	u, pass := assets.InsertAdminUser(db)
+	g, err := group.LoadGroup(db, "shared.infra")
+	test.NoError(t, err)
+
+	model := sdk.Model{
+		Name:    "Test1",
+		GroupID: g.ID,
+		Type:    sdk.Docker,
+		Image:   "buildpack-deps:jessie",
+	}
+	uri := router.GetRoute("POST", api.addWorkerModelHandler, nil)
+	test.NotEmpty(t, uri)
+	w := httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "POST", uri, model))
+	assert.Equal(t, 200, w.Code)
+	json.Unmarshal(w.Body.Bytes(), &model)
+
+	a := sdk.Action{
+		Name:    sdk.RandomString(10),
+		Type:    sdk.DefaultAction,
+		Enabled: true,
+		Requirements: []sdk.Requirement{
+			{Name: "Test1", Type: sdk.ModelRequirement, Value: "Test1"},
+			{Name: "Test1@1", Type: sdk.ModelRequirement, Value: "Test1@1"},
+			{Name: "Test10", Type: sdk.ModelRequirement, Value: "Test10"},
+		},
+	}
+	test.NoError(t, action.InsertAction(db, &a, true))
+
+	model.Name = "Test2"
+	vars := map[string]string{
+		"permModelID": fmt.Sprintf("%d", model.ID),
+	}
+	uri = router.GetRoute("PUT", api.updateWorkerModelHandler, vars)
+	test.NotEmpty(t, uri)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "PUT", uri, model))
+	assert.Equal(t, 200, w.Code)
+
+	reqs, err := action.LoadActionRequirements(db, a.ID)
+	test.NoError(t, err)
+	values := []string{}
+	for _, r := range reqs {
+		values = append(values, r.Value)
+	}
+	sort.Strings(values)
+	assert.Equal(t, []string{"Test10", "Test2", "Test2@1"}, values)
+}
+
 func Test_deleteWorkerModel(t *testing.T) {
 	Test_DeleteAllWorkerModel(t)
 	api, _, router := newTestAPI(t, bootstrap.InitiliazeDB)
@@ -7,6 +7,8 @@ import (
 
 	"github.com/gorilla/mux"
 
+	"github.com/ovh/cds/engine/api/audit"
+	"github.com/ovh/cds/engine/api/permission"
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/api/services"
 	"github.com/ovh/cds/engine/api/workflow"
@@ -25,7 +27,19 @@ func (api *API) getWorkflowsHandler() Handler {
 			return err
 		}
 
-		return WriteJSON(w, r, ws, http.StatusOK)
+		if getUser(ctx).Admin {
+			return WriteJSON(w, r, ws, http.StatusOK)
+		}
+
+		//Only return workflows the user is allowed to read
+		filtered := make([]sdk.Workflow, 0, len(ws))
+		for _, wf := range ws {
+			if api.checkWorkflowPermissions(ctx, wf.Name, permission.PermissionRead, vars) {
+				filtered = append(filtered, wf)
+			}
+		}
+
+		return WriteJSON(w, r, filtered, http.StatusOK)
 	}
 }
 
@@ -77,6 +91,10 @@ func (api *API) postWorkflowHandler() Handler {
 			return sdk.WrapError(err, "Cannot update project last modified date")
 		}
 
+		if err := audit.Record(tx, getUser(ctx), key, sdk.AuditWorkflow, wf.Name, audit.Added, nil, &wf); err != nil {
+			return sdk.WrapError(err, "postWorkflowHandler> Cannot record audit")
+		}
+
 		//Push the hook to hooks µService
 		dao := services.NewRepository(api.mustDB, api.Cache)
 		//Load service "hooks"
@@ -161,42 +179,12 @@ func (api *API) putWorkflowHandler() Handler {
 			return sdk.WrapError(err, "putWorkflowHandler> Cannot update project last modified date")
 		}
 
-		hooks := wf.GetHooks()
-		if len(hooks) > 0 {
-
-			//Push the hook to hooks µService
-			dao := services.NewRepository(api.mustDB, api.Cache)
-			//Load service "hooks"
-			srvs, err := dao.FindByType("hooks")
-			if err != nil {
-				return sdk.WrapError(err, "putWorkflowHandler> Unable to get services dao")
-			}
-
-			if wf.Name != name {
-				// update hook
-				for i := range hooks {
-					h := hooks[i]
-					h.Config["workflow"] = wf.Name
-					hooks[i] = h
-				}
-			}
+		if err := audit.Record(tx, getUser(ctx), key, sdk.AuditWorkflow, wf.Name, audit.Updated, oldW, &wf); err != nil {
+			return sdk.WrapError(err, "putWorkflowHandler> Cannot record audit")
+		}
 
-			//Perform the request on one off the hooks service
-			if len(srvs) < 1 {
-				return sdk.WrapError(fmt.Errorf("putWorkflowHandler> No hooks service available, please try again"), "Unable to get services dao")
-			}
-			var errHooks error
-			for _, s := range srvs {
-				code, errBulk := services.DoJSONRequest(&s, http.MethodPost, "/task/bulk", hooks, nil)
-				errHooks = errBulk
-				if errBulk == nil {
-					log.Debug("putWorkflowHandler> %d hooks created for workflow %s/%s (HTTP status code %d)", len(hooks), wf.ProjectKey, wf.Name, code)
-					break
-				}
-			}
-			if errHooks != nil {
-				return sdk.WrapError(errHooks, "putWorkflowHandler> Unable to create hooks")
-			}
+		if err := api.pushWorkflowHooks(&wf, name); err != nil {
+			return sdk.WrapError(err, "putWorkflowHandler> Unable to push hooks")
 		}
 
 		if err := tx.Commit(); err != nil {
@@ -246,9 +234,56 @@ func (api *API) deleteWorkflowHandler() Handler {
 			return sdk.WrapError(err, "Cannot update project last modified date")
 		}
 
+		if err := audit.Record(tx, getUser(ctx), key, sdk.AuditWorkflow, name, audit.Deleted, oldW, nil); err != nil {
+			return sdk.WrapError(err, "deleteWorkflowHandler> Cannot record audit")
+		}
+
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(errT, "Cannot commit transaction")
 		}
 		return WriteJSON(w, r, nil, http.StatusOK)
 	}
 }
+
+// pushWorkflowHooks sends the hooks of the workflow to the hooks µService. oldName is the name of
+// the workflow before its update
+func (api *API) pushWorkflowHooks(wf *sdk.Workflow, oldName string) error {
+	hooks := wf.GetHooks()
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	//Load service "hooks"
+	dao := services.NewRepository(api.mustDB, api.Cache)
+	srvs, err := dao.FindByType("hooks")
+	if err != nil {
+		return sdk.WrapError(err, "pushWorkflowHooks> Unable to get services dao")
+	}
+	if len(srvs) < 1 {
+		return sdk.WrapError(fmt.Errorf("pushWorkflowHooks> No hooks service available, please try again"), "Unable to get services dao")
+	}
+
+	if wf.Name != oldName {
+		// update hook
+		for i := range hooks {
+			h := hooks[i]
+			h.Config["workflow"] = wf.Name
+			hooks[i] = h
+		}
+	}
+
+	//Perform the request on one off the hooks service
+	var errHooks error
+	for _, s := range srvs {
+		code, errBulk := services.DoJSONRequest(&s, http.MethodPost, "/task/bulk", hooks, nil)
+		errHooks = errBulk
+		if errBulk == nil {
+			log.Debug("pushWorkflowHooks> %d hooks created for workflow %s/%s (HTTP status code %d)", len(hooks), wf.ProjectKey, wf.Name, code)
+			break
+		}
+	}
+	if errHooks != nil {
+		return sdk.WrapError(errHooks, "pushWorkflowHooks> Unable to create hooks")
+	}
+	return nil
+}
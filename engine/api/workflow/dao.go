@@ -11,6 +11,8 @@ import (
 	"github.com/go-gorp/gorp"
 
 	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/notification"
 	"github.com/ovh/cds/engine/api/pipeline"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
@@ -130,6 +132,34 @@ func LoadByEnvName(db gorp.SqlExecutor, projectKey string, envName string) ([]sd
 	return res, nil
 }
 
+// LoadWorkflowByGroup loads all workflows where group has access
+func LoadWorkflowByGroup(db gorp.SqlExecutor, g *sdk.Group) error {
+	query := `SELECT project.projectKey, workflow.id, workflow.name, workflow_group.role
+		FROM workflow
+		JOIN workflow_group ON workflow_group.workflow_id = workflow.id
+		JOIN project ON workflow.project_id = project.id
+		WHERE workflow_group.group_id = $1
+		ORDER BY workflow.name ASC`
+	rows, err := db.Query(query, g.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var w sdk.Workflow
+		var perm int
+		if err := rows.Scan(&w.ProjectKey, &w.ID, &w.Name, &perm); err != nil {
+			return err
+		}
+		g.WorkflowGroups = append(g.WorkflowGroups, sdk.WorkflowGroup{
+			Workflow:   w,
+			Permission: perm,
+		})
+	}
+	return nil
+}
+
 func load(db gorp.SqlExecutor, store cache.Store, u *sdk.User, query string, args ...interface{}) (*sdk.Workflow, error) {
 	t0 := time.Now()
 	dbRes := Workflow{}
@@ -153,6 +183,18 @@ func load(db gorp.SqlExecutor, store cache.Store, u *sdk.User, query string, arg
 
 	res.Joins = joins
 
+	groups, errG := group.LoadGroupsByWorkflow(db, res.ID)
+	if errG != nil {
+		return nil, sdk.WrapError(errG, "Load> Unable to load workflow groups")
+	}
+	res.Groups = groups
+
+	notifs, errN := notification.LoadWorkflowNotifications(db, res.ID)
+	if errN != nil {
+		return nil, sdk.WrapError(errN, "Load> Unable to load workflow notifications")
+	}
+	res.Notifications = notifs
+
 	delta := time.Since(t0).Seconds()
 
 	log.Debug("Load> Load workflow (%s/%s)%d took %.3f seconds", res.ProjectKey, res.Name, res.ID, delta)
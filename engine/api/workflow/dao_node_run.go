@@ -93,6 +93,18 @@ func UpdateNodeRun(db gorp.SqlExecutor, n *sdk.WorkflowNodeRun) error {
 	return nil
 }
 
+//loadPreviousNodeRunStatus returns the status of the last ended run of the same node, or an empty string
+func loadPreviousNodeRunStatus(db gorp.SqlExecutor, n *sdk.WorkflowNodeRun) (string, error) {
+	query := `SELECT status FROM workflow_node_run
+		WHERE workflow_node_id = $1 AND id < $2 AND status IN ($3, $4)
+		ORDER BY id DESC LIMIT 1`
+	status, err := db.SelectNullStr(query, n.WorkflowNodeID, n.ID, sdk.StatusSuccess.String(), sdk.StatusFail.String())
+	if err != nil {
+		return "", sdk.WrapError(err, "loadPreviousNodeRunStatus> Unable to load previous run of node %d", n.WorkflowNodeID)
+	}
+	return status.String, nil
+}
+
 type sqlNodeRun struct {
 	ID                 int64          `db:"id"`
 	HookEvent          sql.NullString `db:"hook_event"`
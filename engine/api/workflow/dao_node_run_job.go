@@ -113,6 +113,17 @@ func LoadAndLockNodeJobRun(db gorp.SqlExecutor, store cache.Store, id int64) (*s
 	return &job, nil
 }
 
+// LockBuildingNodeJobRun locks a building NodeJobRun. It returns false if the job is not building anymore, or
+// if it is already locked, ie. by another API instance
+func LockBuildingNodeJobRun(db gorp.SqlExecutor, id int64) (bool, error) {
+	query := `select id from workflow_node_run_job where id = $1 and status = $2 for update skip locked`
+	locked, err := db.SelectInt(query, id, sdk.StatusBuilding.String())
+	if err != nil {
+		return false, sdk.WrapError(err, "LockBuildingNodeJobRun> Unable to lock job %d", id)
+	}
+	return locked != 0, nil
+}
+
 func insertWorkflowNodeJobRun(db gorp.SqlExecutor, j *sdk.WorkflowNodeJobRun) error {
 	dbj := JobRun(*j)
 	if err := db.Insert(&dbj); err != nil {
@@ -0,0 +1,185 @@
+package workflow
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+const (
+	defaultSearchLimit = 50
+	maxSearchLimit     = 500
+)
+
+// runCursor is the position of the last run of a page of a search, for the order of the search
+type runCursor struct {
+	sort  string
+	value int64
+	id    int64
+}
+
+func (c runCursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%d:%d", c.sort, c.value, c.id)))
+}
+
+func parseRunCursor(s string) (runCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return runCursor{}, sdk.ErrWrongRequest
+	}
+	parts := strings.Split(string(b), ":")
+	if len(parts) != 3 {
+		return runCursor{}, sdk.ErrWrongRequest
+	}
+	value, errV := strconv.ParseInt(parts[1], 10, 64)
+	id, errID := strconv.ParseInt(parts[2], 10, 64)
+	if errV != nil || errID != nil {
+		return runCursor{}, sdk.ErrWrongRequest
+	}
+	return runCursor{sort: parts[0], value: value, id: id}, nil
+}
+
+func newRunCursor(sortBy string, r *sdk.WorkflowRun) runCursor {
+	if sortBy == sdk.WorkflowRunSortNumber {
+		return runCursor{sort: sortBy, value: r.Number, id: r.ID}
+	}
+	return runCursor{sort: sortBy, value: r.Start.UnixNano(), id: r.ID}
+}
+
+// searchRunsQuery builds the query of a search of workflow runs, loading one more run than the limit to know if
+// there is a next page
+func searchRunsQuery(projectkey, workflowname string, s sdk.WorkflowRunSearch, limit int) (string, []interface{}, error) {
+	args := []interface{}{projectkey, workflowname}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	clauses := []string{"project.projectkey = $1", "workflow.name = $2"}
+
+	if len(s.Status) > 0 {
+		clauses = append(clauses, fmt.Sprintf("workflow_run.status = ANY(string_to_array(%s, ','))", arg(strings.Join(s.Status, ","))))
+	}
+	if !s.From.IsZero() {
+		clauses = append(clauses, "workflow_run.start >= "+arg(s.From))
+	}
+	if !s.To.IsZero() {
+		clauses = append(clauses, "workflow_run.start < "+arg(s.To))
+	}
+
+	tags := make(map[string]string, len(s.Tags)+2)
+	for k, v := range s.Tags {
+		tags[k] = v
+	}
+	if s.TriggeredBy != "" {
+		tags[tagTriggeredBy] = s.TriggeredBy
+	}
+	if s.Hook != "" {
+		tags[tagTriggeredByHook] = s.Hook
+	}
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		// The values of a tag are comma separated, the array of the values is indexed
+		clauses = append(clauses, fmt.Sprintf(`workflow_run.id IN (SELECT workflow_run_tag.workflow_run_id FROM workflow_run_tag
+			WHERE workflow_run_tag.tag = %s AND string_to_array(workflow_run_tag.value, ',') @> ARRAY[%s::text])`, arg(k), arg(tags[k])))
+	}
+
+	if s.Node != "" {
+		clauses = append(clauses, fmt.Sprintf(`EXISTS (SELECT 1 FROM workflow_node_run
+			JOIN workflow_node ON workflow_node.id = workflow_node_run.workflow_node_id
+			WHERE workflow_node_run.workflow_run_id = workflow_run.id AND workflow_node.name = %s)`, arg(s.Node)))
+	}
+
+	sortBy := s.Sort
+	if sortBy == "" {
+		sortBy = sdk.WorkflowRunSortStart
+	}
+	direction, comparison := "DESC", "<"
+	if s.Ascending {
+		direction, comparison = "ASC", ">"
+	}
+
+	var order string
+	switch sortBy {
+	case sdk.WorkflowRunSortStart:
+		order = fmt.Sprintf("workflow_run.start %s, workflow_run.id %s", direction, direction)
+	case sdk.WorkflowRunSortNumber:
+		order = fmt.Sprintf("workflow_run.num %s", direction)
+	default:
+		return "", nil, sdk.ErrWrongRequest
+	}
+
+	if s.Cursor != "" {
+		c, err := parseRunCursor(s.Cursor)
+		if err != nil || c.sort != sortBy {
+			return "", nil, sdk.ErrWrongRequest
+		}
+		if sortBy == sdk.WorkflowRunSortNumber {
+			clauses = append(clauses, fmt.Sprintf("workflow_run.num %s %s", comparison, arg(c.value)))
+		} else {
+			clauses = append(clauses, fmt.Sprintf("(workflow_run.start, workflow_run.id) %s (%s, %s)", comparison, arg(time.Unix(0, c.value)), arg(c.id)))
+		}
+	}
+
+	query := fmt.Sprintf(`select workflow_run.*
+	from workflow_run
+	join project on workflow_run.project_id = project.id
+	join workflow on workflow_run.workflow_id = workflow.id
+	where %s
+	order by %s
+	limit %s`, strings.Join(clauses, "\n\tand "), order, arg(limit+1))
+	return query, args, nil
+}
+
+// SearchRuns loads a page of the runs of a workflow matching a search, and the cursor of the next page if any
+func SearchRuns(db gorp.SqlExecutor, projectkey, workflowname string, s sdk.WorkflowRunSearch) ([]sdk.WorkflowRun, string, error) {
+	limit := s.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	query, args, err := searchRunsQuery(projectkey, workflowname, s, limit)
+	if err != nil {
+		return nil, "", sdk.WrapError(err, "SearchRuns> Invalid search")
+	}
+
+	runs := []Run{}
+	if _, err := db.Select(&runs, query, args...); err != nil {
+		return nil, "", sdk.WrapError(err, "SearchRuns> Unable to load runs")
+	}
+
+	var next string
+	if len(runs) > limit {
+		runs = runs[:limit]
+		sortBy := s.Sort
+		if sortBy == "" {
+			sortBy = sdk.WorkflowRunSortStart
+		}
+		last := sdk.WorkflowRun(runs[limit-1])
+		next = newRunCursor(sortBy, &last).String()
+	}
+
+	wruns := make([]sdk.WorkflowRun, len(runs))
+	for i := range runs {
+		wr := sdk.WorkflowRun(runs[i])
+		if err := loadRunTags(db, &wr); err != nil {
+			return nil, "", sdk.WrapError(err, "SearchRuns> Unable to load tags")
+		}
+		wruns[i] = wr
+	}
+
+	return wruns, next, nil
+}
@@ -0,0 +1,44 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestRunCursor(t *testing.T) {
+	r := &sdk.WorkflowRun{ID: 12, Number: 3, Start: time.Unix(1500000000, 123000)}
+	c, err := parseRunCursor(newRunCursor(sdk.WorkflowRunSortStart, r).String())
+	assert.NoError(t, err)
+	assert.Equal(t, runCursor{sort: sdk.WorkflowRunSortStart, value: r.Start.UnixNano(), id: 12}, c)
+
+	_, err = parseRunCursor("not a cursor")
+	assert.Error(t, err)
+}
+
+func TestSearchRunsQuery(t *testing.T) {
+	s := sdk.WorkflowRunSearch{
+		Status:      []string{sdk.StatusFail.String()},
+		Tags:        map[string]string{"git.branch": "master"},
+		TriggeredBy: "alice",
+		Node:        "deploy",
+		Sort:        sdk.WorkflowRunSortNumber,
+		Ascending:   true,
+		Cursor:      runCursor{sort: sdk.WorkflowRunSortNumber, value: 10, id: 42}.String(),
+	}
+	query, args, err := searchRunsQuery("KEY", "wf", s, 20)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"KEY", "wf", "Fail", "git.branch", "master", tagTriggeredBy, "alice", "deploy", int64(10), 21}, args)
+	assert.True(t, strings.Contains(query, "order by workflow_run.num ASC"))
+	assert.True(t, strings.Contains(query, "workflow_run.num > $9"))
+	assert.True(t, strings.Contains(query, "string_to_array(workflow_run_tag.value, ',') @> ARRAY[$5::text]"))
+
+	// A cursor is only valid for the sort it was computed for
+	s.Sort = sdk.WorkflowRunSortStart
+	_, _, err = searchRunsQuery("KEY", "wf", s, 20)
+	assert.Error(t, err)
+}
@@ -0,0 +1,177 @@
+package workflow
+
+import (
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/lib/pq"
+	"github.com/ovh/venom"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// InsertTestCases inserts the test cases of a node run in the tests history of its workflow
+func InsertTestCases(db gorp.SqlExecutor, nodeRun *sdk.WorkflowNodeRun, tests venom.Tests) error {
+	workflowID, err := db.SelectInt("SELECT workflow_id FROM workflow_run WHERE id = $1", nodeRun.WorkflowRunID)
+	if err != nil {
+		return sdk.WrapError(err, "InsertTestCases> Unable to load workflow run %d", nodeRun.WorkflowRunID)
+	}
+
+	hash := sdk.ParameterValue(nodeRun.BuildParameters, "git.hash")
+	now := time.Now()
+	for _, tc := range sdk.NewWorkflowTestCases(tests) {
+		tc.WorkflowID = workflowID
+		tc.WorkflowRunID = nodeRun.WorkflowRunID
+		tc.WorkflowNodeRunID = nodeRun.ID
+		tc.VCSHash = hash
+		tc.Created = now
+		dbTC := TestCase(tc)
+		if err := db.Insert(&dbTC); err != nil {
+			return sdk.WrapError(err, "InsertTestCases> Unable to insert test case %s/%s", tc.Suite, tc.Name)
+		}
+	}
+	return nil
+}
+
+// LoadTestCaseHistory loads the last results of a test case, the most recent first
+func LoadTestCaseHistory(db gorp.SqlExecutor, workflowID int64, suite, name string, limit int) ([]sdk.WorkflowTestCase, error) {
+	var res []TestCase
+	query := `SELECT * FROM workflow_test_history
+		WHERE workflow_id = $1 AND suite = $2 AND name = $3
+		ORDER BY created DESC LIMIT $4`
+	if _, err := db.Select(&res, query, workflowID, suite, name, limit); err != nil {
+		return nil, sdk.WrapError(err, "LoadTestCaseHistory> Unable to load history of %s/%s", suite, name)
+	}
+
+	tcs := make([]sdk.WorkflowTestCase, len(res))
+	for i := range res {
+		tcs[i] = sdk.WorkflowTestCase(res[i])
+	}
+	return tcs, nil
+}
+
+// LoadTestSuiteTrends computes the failure rate and the average duration of the test cases of each suite
+// since a date, by day. Skipped test cases are ignored
+func LoadTestSuiteTrends(db gorp.SqlExecutor, workflowID int64, since time.Time) ([]sdk.WorkflowTestSuiteTrend, error) {
+	query := `SELECT suite, date_trunc('day', created) AS day, COUNT(*),
+			SUM(CASE WHEN status = $3 THEN 1 ELSE 0 END), AVG(duration)
+		FROM workflow_test_history
+		WHERE workflow_id = $1 AND created >= $2 AND status <> $4
+		GROUP BY suite, day
+		ORDER BY suite, day`
+	rows, err := db.Query(query, workflowID, since, sdk.TestStatusFail, sdk.TestStatusSkipped)
+	if err != nil {
+		return nil, sdk.WrapError(err, "LoadTestSuiteTrends> Unable to load trends")
+	}
+	defer rows.Close()
+
+	var trends []sdk.WorkflowTestSuiteTrend
+	var durations float64
+	for rows.Next() {
+		var suite string
+		var p sdk.WorkflowTestTrendPoint
+		if err := rows.Scan(&suite, &p.Day, &p.Total, &p.Failures, &p.AverageDuration); err != nil {
+			return nil, sdk.WrapError(err, "LoadTestSuiteTrends> Unable to scan trend")
+		}
+		p.FailureRate = float64(p.Failures) / float64(p.Total)
+
+		if len(trends) == 0 || trends[len(trends)-1].Suite != suite {
+			trends = append(trends, sdk.WorkflowTestSuiteTrend{Suite: suite})
+			durations = 0
+		}
+		t := &trends[len(trends)-1]
+		t.Days = append(t.Days, p)
+		t.Total += p.Total
+		t.Failures += p.Failures
+		t.FailureRate = float64(t.Failures) / float64(t.Total)
+		durations += p.AverageDuration * float64(p.Total)
+		t.AverageDuration = durations / float64(t.Total)
+	}
+	return trends, rows.Err()
+}
+
+// LoadFlakyTests loads the test cases which both passed and failed on a same commit since a date,
+// the most recently seen first
+func LoadFlakyTests(db gorp.SqlExecutor, workflowID int64, since time.Time) ([]sdk.WorkflowFlakyTest, error) {
+	var res []sdk.WorkflowFlakyTest
+	query := `SELECT h.suite, h.name, COUNT(*) AS commits, SUM(h.successes) AS successes, SUM(h.failures) AS failures,
+			MAX(h.last_seen) AS last_seen, q.id IS NOT NULL AS quarantined
+		FROM (
+			SELECT suite, name, vcs_hash,
+				SUM(CASE WHEN status = $3 THEN 1 ELSE 0 END) AS successes,
+				SUM(CASE WHEN status = $4 THEN 1 ELSE 0 END) AS failures,
+				MAX(created) AS last_seen
+			FROM workflow_test_history
+			WHERE workflow_id = $1 AND created >= $2 AND vcs_hash <> ''
+			GROUP BY suite, name, vcs_hash
+		) h
+		LEFT JOIN workflow_test_quarantine q ON q.workflow_id = $1 AND q.suite = h.suite AND q.name = h.name
+		WHERE h.successes > 0 AND h.failures > 0
+		GROUP BY h.suite, h.name, q.id
+		ORDER BY last_seen DESC`
+	if _, err := db.Select(&res, query, workflowID, since, sdk.TestStatusSuccess, sdk.TestStatusFail); err != nil {
+		return nil, sdk.WrapError(err, "LoadFlakyTests> Unable to load flaky tests")
+	}
+	return res, nil
+}
+
+// LoadTestQuarantine loads the quarantined test cases of a workflow
+func LoadTestQuarantine(db gorp.SqlExecutor, workflowID int64) ([]sdk.WorkflowTestQuarantine, error) {
+	var res []TestQuarantine
+	if _, err := db.Select(&res, "SELECT * FROM workflow_test_quarantine WHERE workflow_id = $1 ORDER BY suite, name", workflowID); err != nil {
+		return nil, sdk.WrapError(err, "LoadTestQuarantine> Unable to load quarantine")
+	}
+
+	qs := make([]sdk.WorkflowTestQuarantine, len(res))
+	for i := range res {
+		qs[i] = sdk.WorkflowTestQuarantine(res[i])
+	}
+	return qs, nil
+}
+
+// LoadTestQuarantineByNodeJobRunID loads the quarantined test cases of the workflow of a job run
+func LoadTestQuarantineByNodeJobRunID(db gorp.SqlExecutor, id int64) ([]sdk.WorkflowTestQuarantine, error) {
+	var res []TestQuarantine
+	query := `SELECT workflow_test_quarantine.*
+		FROM workflow_test_quarantine
+		JOIN workflow_run ON workflow_run.workflow_id = workflow_test_quarantine.workflow_id
+		JOIN workflow_node_run ON workflow_node_run.workflow_run_id = workflow_run.id
+		JOIN workflow_node_run_job ON workflow_node_run_job.workflow_node_run_id = workflow_node_run.id
+		WHERE workflow_node_run_job.id = $1
+		ORDER BY suite, name`
+	if _, err := db.Select(&res, query, id); err != nil {
+		return nil, sdk.WrapError(err, "LoadTestQuarantineByNodeJobRunID> Unable to load quarantine")
+	}
+
+	qs := make([]sdk.WorkflowTestQuarantine, len(res))
+	for i := range res {
+		qs[i] = sdk.WorkflowTestQuarantine(res[i])
+	}
+	return qs, nil
+}
+
+// InsertTestQuarantine puts a test case in quarantine
+func InsertTestQuarantine(db gorp.SqlExecutor, q *sdk.WorkflowTestQuarantine) error {
+	q.Created = time.Now()
+	dbQ := TestQuarantine(*q)
+	if err := db.Insert(&dbQ); err != nil {
+		if errPG, ok := err.(*pq.Error); ok && errPG.Code == "23505" {
+			return sdk.WrapError(sdk.ErrAlreadyExist, "InsertTestQuarantine> %s/%s is already in quarantine", q.Suite, q.Name)
+		}
+		return sdk.WrapError(err, "InsertTestQuarantine> Unable to insert quarantine of %s/%s", q.Suite, q.Name)
+	}
+	*q = sdk.WorkflowTestQuarantine(dbQ)
+	return nil
+}
+
+// DeleteTestQuarantine removes a test case from the quarantine of a workflow
+func DeleteTestQuarantine(db gorp.SqlExecutor, workflowID, id int64) error {
+	res, err := db.Exec("DELETE FROM workflow_test_quarantine WHERE workflow_id = $1 AND id = $2", workflowID, id)
+	if err != nil {
+		return sdk.WrapError(err, "DeleteTestQuarantine> Unable to delete quarantine %d", id)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sdk.WrapError(sdk.ErrNotFound, "DeleteTestQuarantine> Quarantine %d not found", id)
+	}
+	return nil
+}
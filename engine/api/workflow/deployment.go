@@ -0,0 +1,97 @@
+package workflow
+
+import (
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/sdk"
+)
+
+// insertDeployment records the deployment of a node run whose pipeline deploys on an environment
+func insertDeployment(db gorp.SqlExecutor, wr *sdk.WorkflowRun, n *sdk.WorkflowNodeRun) error {
+	node := wr.Workflow.GetNode(n.WorkflowNodeID)
+	if node == nil || node.Context == nil || node.Pipeline.Type != sdk.DeploymentPipeline {
+		return nil
+	}
+	envID := nodeEnvironmentID(node)
+	if envID == 0 || envID == sdk.DefaultEnv.ID {
+		return nil
+	}
+
+	d := sdk.EnvironmentDeployment{
+		EnvironmentID:     envID,
+		Version:           sdk.ParameterValue(n.BuildParameters, "cds.version"),
+		VCSHash:           sdk.ParameterValue(n.BuildParameters, "git.hash"),
+		VCSBranch:         sdk.ParameterValue(n.BuildParameters, "git.branch"),
+		WorkflowID:        wr.WorkflowID,
+		WorkflowName:      wr.Workflow.Name,
+		WorkflowRunID:     wr.ID,
+		WorkflowRunNumber: wr.Number,
+		WorkflowNodeRunID: n.ID,
+		NodeName:          node.Name,
+		Status:            n.Status,
+		Author:            sdk.ParameterValue(n.BuildParameters, "cds.triggered_by.username"),
+	}
+	if node.Context.Application != nil {
+		d.ApplicationID = node.Context.Application.ID
+		d.ApplicationName = node.Context.Application.Name
+	}
+	return environment.InsertDeployment(db, &d)
+}
+
+// nodeEnvironmentID returns the ID of the environment in the context of a node
+func nodeEnvironmentID(n *sdk.WorkflowNode) int64 {
+	if n.Context.Environment != nil && n.Context.Environment.ID != 0 {
+		return n.Context.Environment.ID
+	}
+	return n.Context.EnvironmentID
+}
+
+// HasDeploymentNode returns true if a node of a workflow deploys on an environment
+func HasDeploymentNode(w *sdk.Workflow) bool {
+	if w.Root == nil {
+		return false
+	}
+	for _, id := range append([]int64{w.Root.ID}, w.Nodes()...) {
+		n := w.GetNode(id)
+		if n == nil || n.Context == nil || n.Pipeline.Type != sdk.DeploymentPipeline {
+			continue
+		}
+		if envID := nodeEnvironmentID(n); envID != 0 && envID != sdk.DefaultEnv.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// FindDeploymentNode returns the node of a workflow which deploys an application on an environment, preferably
+// with a given pipeline
+func FindDeploymentNode(w *sdk.Workflow, applicationName string, envID int64, pipelineName string) *sdk.WorkflowNode {
+	if w.Root == nil {
+		return nil
+	}
+	var found *sdk.WorkflowNode
+	for _, id := range append([]int64{w.Root.ID}, w.Nodes()...) {
+		n := w.GetNode(id)
+		if n == nil || n.Context == nil || n.Pipeline.Type != sdk.DeploymentPipeline {
+			continue
+		}
+		if nodeEnvironmentID(n) != envID {
+			continue
+		}
+		var nodeApplication string
+		if n.Context.Application != nil {
+			nodeApplication = n.Context.Application.Name
+		}
+		if nodeApplication != applicationName {
+			continue
+		}
+		if n.Pipeline.Name == pipelineName {
+			return n
+		}
+		if found == nil {
+			found = n
+		}
+	}
+	return found
+}
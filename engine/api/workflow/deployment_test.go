@@ -0,0 +1,105 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestFindDeploymentNode(t *testing.T) {
+	app := &sdk.Application{Name: "app"}
+	deploy := sdk.Pipeline{Name: "deploy", Type: sdk.DeploymentPipeline}
+	w := &sdk.Workflow{
+		Root: &sdk.WorkflowNode{
+			ID:       1,
+			Name:     "build",
+			Pipeline: sdk.Pipeline{Name: "build", Type: sdk.BuildPipeline},
+			Context:  &sdk.WorkflowNodeContext{Application: app},
+			Triggers: []sdk.WorkflowNodeTrigger{
+				{WorkflowDestNode: sdk.WorkflowNode{
+					ID:       2,
+					Name:     "deploy-staging",
+					Pipeline: deploy,
+					Context:  &sdk.WorkflowNodeContext{Application: app, Environment: &sdk.Environment{ID: 10, Name: "staging"}},
+					Triggers: []sdk.WorkflowNodeTrigger{
+						{WorkflowDestNode: sdk.WorkflowNode{
+							ID:       3,
+							Name:     "smoke-prod",
+							Pipeline: sdk.Pipeline{Name: "smoke", Type: sdk.DeploymentPipeline},
+							Context:  &sdk.WorkflowNodeContext{Application: app, EnvironmentID: 11},
+						}},
+						{WorkflowDestNode: sdk.WorkflowNode{
+							ID:       4,
+							Name:     "deploy-prod",
+							Pipeline: deploy,
+							Context:  &sdk.WorkflowNodeContext{Application: app, EnvironmentID: 11},
+						}},
+					},
+				}},
+			},
+		},
+	}
+
+	// The node with the same pipeline is preferred
+	n := FindDeploymentNode(w, "app", 11, "deploy")
+	assert.NotNil(t, n)
+	assert.Equal(t, "deploy-prod", n.Name)
+
+	n = FindDeploymentNode(w, "app", 11, "other")
+	assert.NotNil(t, n)
+	assert.Equal(t, "smoke-prod", n.Name)
+
+	assert.Equal(t, "deploy-staging", FindDeploymentNode(w, "app", 10, "deploy").Name)
+	assert.Nil(t, FindDeploymentNode(w, "other", 11, "deploy"))
+	assert.Nil(t, FindDeploymentNode(w, "app", 12, "deploy"))
+
+	assert.True(t, HasDeploymentNode(w))
+	w.Root.Triggers = nil
+	assert.False(t, HasDeploymentNode(w))
+}
+
+func TestFindRollbackDeployment(t *testing.T) {
+	assert.Nil(t, FindRollbackDeployment(nil))
+
+	ds := []sdk.EnvironmentDeployment{
+		{ID: 5, WorkflowRunNumber: 9, Status: sdk.StatusFail.String()},
+		{ID: 4, WorkflowRunNumber: 9, Status: sdk.StatusSuccess.String()},
+		{ID: 3, WorkflowRunNumber: 8, Status: sdk.StatusFail.String()},
+		{ID: 2, WorkflowRunNumber: 7, Status: sdk.StatusSuccess.String()},
+		{ID: 1, WorkflowRunNumber: 6, Status: sdk.StatusSuccess.String()},
+	}
+	assert.Equal(t, int64(2), FindRollbackDeployment(ds).ID)
+
+	// Rolling back again goes further back
+	ds = append([]sdk.EnvironmentDeployment{{ID: 6, WorkflowRunNumber: 7, Status: sdk.StatusSuccess.String()}}, ds...)
+	assert.Equal(t, int64(1), FindRollbackDeployment(ds).ID)
+
+	assert.Nil(t, FindRollbackDeployment(ds[:2]))
+}
+
+func TestRollbackBuildParameters(t *testing.T) {
+	previous := []sdk.Parameter{
+		{Name: "cds.env.url", Type: sdk.StringParameter, Value: "https://old.example.com"},
+		{Name: "git.hash", Type: sdk.StringParameter, Value: "abc"},
+		{Name: "cds.run", Type: sdk.StringParameter, Value: "7.0"},
+		{Name: "cds.triggered_by.username", Type: sdk.StringParameter, Value: "alice"},
+	}
+	// The environment variable changed since the deployment
+	current := []sdk.Parameter{
+		{Name: "cds.env.url", Type: sdk.StringParameter, Value: "https://new.example.com"},
+		{Name: "cds.env.added", Type: sdk.StringParameter, Value: "new"},
+		{Name: "git.hash", Type: sdk.StringParameter, Value: "def"},
+		{Name: "cds.run", Type: sdk.StringParameter, Value: "7.1"},
+		{Name: "cds.triggered_by.username", Type: sdk.StringParameter, Value: "bob"},
+	}
+
+	params := sdk.ParametersToMap(rollbackBuildParameters(previous, current))
+	assert.Equal(t, map[string]string{
+		"cds.env.url":               "https://old.example.com",
+		"git.hash":                  "abc",
+		"cds.run":                   "7.1",
+		"cds.triggered_by.username": "bob",
+	}, params)
+}
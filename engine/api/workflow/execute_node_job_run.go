@@ -12,6 +12,7 @@ import (
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/environment"
 	"github.com/ovh/cds/engine/api/event"
+	"github.com/ovh/cds/engine/api/metrics"
 	"github.com/ovh/cds/engine/api/secret"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
@@ -56,6 +57,7 @@ func UpdateNodeJobRunStatus(db gorp.SqlExecutor, store cache.Store, p *sdk.Proje
 		job.Done = time.Now()
 		job.Status = status.String()
 		wf.LastExecution = time.Now()
+		metrics.ObserveJobDuration(job.Status, job.Start, job.Done)
 	default:
 		return fmt.Errorf("workflow.UpdateNodeJobRunStatus> Cannot update WorkflowNodeJobRun %d to status %v", job.ID, status.String())
 	}
@@ -267,7 +269,7 @@ func LoadNodeJobRunKeys(db gorp.SqlExecutor, store cache.Store, job *sdk.Workflo
 func LoadNodeJobRunSecrets(db gorp.SqlExecutor, store cache.Store, job *sdk.WorkflowNodeJobRun, nodeRun *sdk.WorkflowNodeRun, w *sdk.WorkflowRun, pv []sdk.Variable) ([]sdk.Variable, error) {
 	var secrets []sdk.Variable
 
-	pv = sdk.VariablesFilter(pv, sdk.SecretVariable, sdk.KeyVariable)
+	pv = sdk.VariablesFilter(pv, sdk.SecretVariable, sdk.KeyVariable, sdk.SecretReferenceVariable)
 	pv = sdk.VariablesPrefix(pv, "cds.proj.")
 	secrets = append(secrets, pv...)
 
@@ -284,7 +286,7 @@ func LoadNodeJobRunSecrets(db gorp.SqlExecutor, store cache.Store, job *sdk.Work
 		if errA != nil {
 			return nil, sdk.WrapError(errA, "LoadNodeJobRunSecrets> Cannot load application variables")
 		}
-		av = sdk.VariablesFilter(appv, sdk.SecretVariable, sdk.KeyVariable)
+		av = sdk.VariablesFilter(appv, sdk.SecretVariable, sdk.KeyVariable, sdk.SecretReferenceVariable)
 		av = sdk.VariablesPrefix(av, "cds.app.")
 	}
 	secrets = append(secrets, av...)
@@ -296,7 +298,7 @@ func LoadNodeJobRunSecrets(db gorp.SqlExecutor, store cache.Store, job *sdk.Work
 		if errE != nil {
 			return nil, sdk.WrapError(errE, "LoadNodeJobRunSecrets> Cannot load environment variables")
 		}
-		ev = sdk.VariablesFilter(envv, sdk.SecretVariable, sdk.KeyVariable)
+		ev = sdk.VariablesFilter(envv, sdk.SecretVariable, sdk.KeyVariable, sdk.SecretReferenceVariable)
 		ev = sdk.VariablesPrefix(ev, "cds.env.")
 	}
 	secrets = append(secrets, ev...)
@@ -308,6 +310,11 @@ func LoadNodeJobRunSecrets(db gorp.SqlExecutor, store cache.Store, job *sdk.Work
 			return nil, sdk.WrapError(err, "LoadNodeJobRunSecrets> Unable to decrypt variables")
 		}
 	}
+
+	//Resolve secret references from the secret backends of the project
+	if err := secret.ResolveReferences(db, w.ProjectID, secrets); err != nil {
+		return nil, sdk.WrapError(err, "LoadNodeJobRunSecrets> Unable to resolve secret references")
+	}
 	return secrets, nil
 }
 
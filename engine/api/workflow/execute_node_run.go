@@ -8,13 +8,23 @@ import (
 
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/event"
+	"github.com/ovh/cds/engine/api/notification"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/tracing"
 )
 
 //execute is called by the scheduler. You should not call this by yourself
 func execute(db gorp.SqlExecutor, store cache.Store, p *sdk.Project, n *sdk.WorkflowNodeRun) (err error) {
 	t0 := time.Now()
+	db, _, span := startSpan(db, n.WorkflowRunID, "workflow.execute")
+	span.SetTag("cds.node_run.id", n.ID)
+	defer func() {
+		span.SetTag("cds.status", n.Status)
+		span.SetError(err)
+		span.Finish()
+	}()
+
 	log.Debug("workflow.execute> Begin [#%d.%d] runID=%d (%s)", n.Number, n.SubNumber, n.WorkflowRunID, n.Status)
 	defer func() {
 		log.Debug("workflow.execute> End [#%d.%d] runID=%d (%s) - %.3fs", n.Number, n.SubNumber, n.WorkflowRunID, n.Status, time.Since(t0).Seconds())
@@ -37,6 +47,7 @@ func execute(db gorp.SqlExecutor, store cache.Store, p *sdk.Project, n *sdk.Work
 		return nil
 	}
 
+	var oldStatus = n.Status
 	var newStatus = n.Status
 
 	//If no stages ==> success
@@ -120,6 +131,22 @@ func execute(db gorp.SqlExecutor, store cache.Store, p *sdk.Project, n *sdk.Work
 		return sdk.WrapError(err, "workflow.execute> Unable to reload workflow run id=%d", n.WorkflowRunID)
 	}
 
+	//Send notifications when the node starts or ends
+	if oldStatus != n.Status && (n.Status == sdk.StatusBuilding.String() || n.Status == sdk.StatusSuccess.String() || n.Status == sdk.StatusFail.String()) {
+		previousStatus, errP := loadPreviousNodeRunStatus(db, n)
+		if errP != nil {
+			log.Warning("workflow.execute> %v", errP)
+		}
+		event.PublishWorkflowNodeRun(db, updatedWorkflowRun, n, previousStatus)
+	}
+
+	//Record the deployments on environments when they are over
+	if oldStatus != n.Status && (n.Status == sdk.StatusSuccess.String() || n.Status == sdk.StatusFail.String()) {
+		if err := insertDeployment(db, updatedWorkflowRun, n); err != nil {
+			return sdk.WrapError(err, "workflow.execute> Unable to record deployment of node run %d", n.ID)
+		}
+	}
+
 	// If pipeline build succeed, reprocess the workflow (in the same transaction)
 	//Delete jobs only when node is over
 	if n.Status == sdk.StatusSuccess.String() || n.Status == sdk.StatusFail.String() {
@@ -138,6 +165,9 @@ func execute(db gorp.SqlExecutor, store cache.Store, p *sdk.Project, n *sdk.Work
 
 func addJobsToQueue(db gorp.SqlExecutor, stage *sdk.Stage, run *sdk.WorkflowNodeRun) error {
 	log.Debug("addJobsToQueue> add %d in stage %s", run.ID, stage.Name)
+	db, ctx, span := startSpan(db, run.WorkflowRunID, "workflow.addJobsToQueue")
+	span.SetTag("cds.stage", stage.Name)
+	defer span.Finish()
 
 	conditionsOK, err := sdk.WorkflowCheckConditions(stage.Conditions(), run.BuildParameters)
 	if err != nil {
@@ -151,10 +181,19 @@ func addJobsToQueue(db gorp.SqlExecutor, stage *sdk.Stage, run *sdk.WorkflowNode
 		stage.Status = sdk.StatusDisabled
 	}
 
+	priority, groupID, errQ := nodeJobRunQueueInfo(db, run)
+	if errQ != nil {
+		return sdk.WrapError(errQ, "addJobsToQueue> Cannot compute queue priority")
+	}
+
 	//Browse the jobs
 	for _, job := range stage.Jobs {
 		//Process variables for the jobs
 		jobParams, errParam := getNodeJobRunParameters(db, job, run, stage)
+		// The trace context is given to the hatcheries and the workers, which attach their spans to the run
+		if traceParent := tracing.FromContext(ctx).TraceParent(); traceParent != "" {
+			sdk.AddParameter(&jobParams, "cds.traceparent", sdk.StringParameter, traceParent)
+		}
 
 		//Create the job run
 		job := sdk.WorkflowNodeJobRun{
@@ -166,6 +205,8 @@ func addJobsToQueue(db gorp.SqlExecutor, stage *sdk.Stage, run *sdk.WorkflowNode
 			Job: sdk.ExecutedJob{
 				Job: job,
 			},
+			Priority: priority,
+			GroupID:  groupID,
 		}
 
 		if !stage.Enabled || !job.Job.Enabled {
@@ -325,6 +366,7 @@ func StopWorkflowNodeRun(db *gorp.DbMap, store cache.Store, proj *sdk.Project, n
 		return sdk.WrapError(errT, "StopWorkflowNodeRun> Cannot start transaction")
 	}
 	defer tx.Rollback()
+	defer notification.Discard(tx)
 
 	for _, nrjID := range ids {
 		njr, errNRJ := LoadAndLockNodeJobRun(tx, store, nrjID)
@@ -344,6 +386,7 @@ func StopWorkflowNodeRun(db *gorp.DbMap, store cache.Store, proj *sdk.Project, n
 	if err := tx.Commit(); err != nil {
 		return sdk.WrapError(err, "StopWorkflowNodeRun> Cannot commit transaction")
 	}
+	notification.Flush(tx)
 
 	return nil
 }
@@ -0,0 +1,99 @@
+package workflow
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/freeze"
+	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/sdk"
+)
+
+// errFreezeRefused is returned when a node triggered by another one is not run because of a freeze window
+var errFreezeRefused = fmt.Errorf("node run refused by a freeze window")
+
+// checkFreeze applies the freeze window active on the environment deployed by a node. It returns the window which
+// holds the node run, if any. Node runs refused by a freeze window return sdk.ErrDeploymentFrozen when they have been
+// triggered by a user or a hook, unless a project owner chose to ignore the freeze, and errFreezeRefused otherwise.
+// The user of a manual run must be the authenticated user
+func checkFreeze(db gorp.SqlExecutor, p *sdk.Project, w *sdk.WorkflowRun, n *sdk.WorkflowNode, h *sdk.WorkflowNodeRunHookEvent, m *sdk.WorkflowNodeRunManual) (*sdk.FreezeWindow, error) {
+	if n.Context == nil || n.Pipeline.Type != sdk.DeploymentPipeline {
+		return nil, nil
+	}
+	envID := nodeEnvironmentID(n)
+	if envID == 0 || envID == sdk.DefaultEnv.ID {
+		return nil, nil
+	}
+
+	now := time.Now()
+	ws, err := freeze.LoadCurrent(db, p.ID, now)
+	if err != nil {
+		return nil, sdk.WrapError(err, "checkFreeze> Unable to load freeze windows")
+	}
+	fw := freeze.Active(ws, p.ID, envID, now)
+	if fw == nil {
+		return nil, nil
+	}
+
+	if m != nil && m.IgnoreFreeze && permission.ProjectPermission(p.Key, &m.User) >= permission.PermissionReadWriteExecute {
+		AddWorkflowRunInfo(w, sdk.SpawnMsg{
+			ID:   sdk.MsgWorkflowNodeFreezeIgnored.ID,
+			Args: []interface{}{n.Name, m.User.Username, fw.Name},
+		})
+		return nil, nil
+	}
+
+	if fw.Action == sdk.FreezeActionHold {
+		AddWorkflowRunInfo(w, sdk.SpawnMsg{
+			ID:   sdk.MsgWorkflowNodeFreezeHold.ID,
+			Args: []interface{}{n.Name, fw.NextEnd.Format(time.RFC3339), fw.Name},
+		})
+		return fw, nil
+	}
+
+	if h != nil || m != nil {
+		return nil, sdk.WrapError(sdk.ErrDeploymentFrozen, "checkFreeze> Node %s refused by freeze window %s", n.Name, fw.Name)
+	}
+	AddWorkflowRunInfo(w, sdk.SpawnMsg{
+		ID:   sdk.MsgWorkflowNodeFreezeRefused.ID,
+		Args: []interface{}{n.Name, fw.Name},
+	})
+	return nil, errFreezeRefused
+}
+
+// IsNodeRunHeld returns true if a freeze window covering a held node run is still active
+func IsNodeRunHeld(db gorp.SqlExecutor, p *sdk.Project, wr *sdk.WorkflowRun, nodeRun *sdk.WorkflowNodeRun) (bool, error) {
+	n := wr.Workflow.GetNode(nodeRun.WorkflowNodeID)
+	if n == nil || n.Context == nil {
+		return false, nil
+	}
+	now := time.Now()
+	ws, err := freeze.LoadCurrent(db, p.ID, now)
+	if err != nil {
+		return false, sdk.WrapError(err, "IsNodeRunHeld> Unable to load freeze windows")
+	}
+	return freeze.Active(ws, p.ID, nodeEnvironmentID(n), now) != nil, nil
+}
+
+// ReleaseHeldNodeRun executes a node run held by a freeze window. Nothing is done if the node run has already been
+// released, by another API instance for example
+func ReleaseHeldNodeRun(db gorp.SqlExecutor, store cache.Store, p *sdk.Project, nodeRunID int64) error {
+	nodeRun, err := LoadAndLockNodeRunByID(db, nodeRunID)
+	if err != nil {
+		return sdk.WrapError(err, "ReleaseHeldNodeRun> Unable to load node run %d", nodeRunID)
+	}
+	released, err := freeze.DeleteHold(db, nodeRunID)
+	if err != nil {
+		return sdk.WrapError(err, "ReleaseHeldNodeRun> Unable to release node run %d", nodeRunID)
+	}
+	if !released {
+		return nil
+	}
+	if err := execute(db, store, p, nodeRun); err != nil {
+		return sdk.WrapError(err, "ReleaseHeldNodeRun> Unable to execute node run %d", nodeRunID)
+	}
+	return nil
+}
@@ -45,6 +45,12 @@ type NodeHook sdk.WorkflowNodeHook
 // NodeHookModel is a gorp wrapper around sdk.WorkflowHookModel
 type NodeHookModel sdk.WorkflowHookModel
 
+// TestCase is a gorp wrapper around sdk.WorkflowTestCase
+type TestCase sdk.WorkflowTestCase
+
+// TestQuarantine is a gorp wrapper around sdk.WorkflowTestQuarantine
+type TestQuarantine sdk.WorkflowTestQuarantine
+
 func init() {
 	gorpmapping.Register(gorpmapping.New(Workflow{}, "workflow", true, "id"))
 	gorpmapping.Register(gorpmapping.New(Node{}, "workflow_node", true, "id"))
@@ -61,4 +67,6 @@ func init() {
 	gorpmapping.Register(gorpmapping.New(NodeRunArtifact{}, "workflow_node_run_artifacts", true, "id"))
 	gorpmapping.Register(gorpmapping.New(RunTag{}, "workflow_run_tag", false, "workflow_run_id", "tag"))
 	gorpmapping.Register(gorpmapping.New(NodeHookModel{}, "workflow_hook_model", true, "id"))
+	gorpmapping.Register(gorpmapping.New(TestCase{}, "workflow_test_history", true, "id"))
+	gorpmapping.Register(gorpmapping.New(TestQuarantine{}, "workflow_test_quarantine", true, "id"))
 }
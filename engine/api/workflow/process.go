@@ -8,6 +8,7 @@ import (
 	"github.com/go-gorp/gorp"
 
 	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/freeze"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 )
@@ -17,6 +18,11 @@ import (
 func processWorkflowRun(db gorp.SqlExecutor, store cache.Store, p *sdk.Project, w *sdk.WorkflowRun, hookEvent *sdk.WorkflowNodeRunHookEvent, manual *sdk.WorkflowNodeRunManual, startingFromNode *int64) error {
 	var nodesRunFailed, nodesRunStopped, nodesRunBuilding, nodesRunSuccess int
 	t0 := time.Now()
+	db, _, span := startSpan(db, w.ID, "workflow.processWorkflowRun")
+	span.SetTag("cds.run.number", w.Number)
+	defer span.Finish()
+
+	previousStatus := w.Status
 	w.Status = string(sdk.StatusBuilding)
 	log.Debug("processWorkflowRun> Begin [#%d]%s", w.Number, w.Workflow.Name)
 	defer func() {
@@ -145,13 +151,13 @@ func processWorkflowRun(db gorp.SqlExecutor, store cache.Store, p *sdk.Project,
 
 					if !abortTrigger {
 						//Keep the subnumber of the previous node in the graph
-						if err := processWorkflowNodeRun(db, store, p, w, &t.WorkflowDestNode, int(nodeRun.SubNumber), []int64{nodeRun.ID}, nil, nil); err != nil {
+						if err := processWorkflowNodeRun(db, store, p, w, &t.WorkflowDestNode, int(nodeRun.SubNumber), []int64{nodeRun.ID}, nil, nil); err != nil && err != errFreezeRefused {
 							log.Error("processWorkflowRun> Unable to process node ID=%d: %s", t.WorkflowDestNode.ID, err)
 							AddWorkflowRunInfo(w, sdk.SpawnMsg{
 								ID:   sdk.MsgWorkflowError.ID,
 								Args: []interface{}{err},
 							})
-						} else {
+						} else if err == nil {
 							nodesRunBuilding++
 						}
 					}
@@ -263,13 +269,13 @@ func processWorkflowRun(db gorp.SqlExecutor, store cache.Store, p *sdk.Project,
 
 				if !abortTrigger {
 					//Keep the subnumber of the previous node in the graph
-					if err := processWorkflowNodeRun(db, store, p, w, &t.WorkflowDestNode, int(maxsn), nodeRunIDs, nil, nil); err != nil {
+					if err := processWorkflowNodeRun(db, store, p, w, &t.WorkflowDestNode, int(maxsn), nodeRunIDs, nil, nil); err != nil && err != errFreezeRefused {
 						AddWorkflowRunInfo(w, sdk.SpawnMsg{
 							ID:   sdk.MsgWorkflowError.ID,
 							Args: []interface{}{err},
 						})
 						log.Error("processWorkflowRun> Unable to process node ID=%d: %v", t.WorkflowDestNode.ID, err)
-					} else {
+					} else if err == nil {
 						nodesRunBuilding++
 					}
 				}
@@ -283,6 +289,10 @@ func processWorkflowRun(db gorp.SqlExecutor, store cache.Store, p *sdk.Project,
 		return sdk.WrapError(err, "processWorkflowRun>")
 	}
 
+	if !isTerminated(previousStatus) && isTerminated(w.Status) {
+		finishRunSpan(w)
+	}
+
 	return nil
 }
 
@@ -394,12 +404,18 @@ func processWorkflowNodeRun(db gorp.SqlExecutor, store cache.Store, p *sdk.Proje
 		}
 		run.BuildParameters = append(run.BuildParameters, parentsParams...)
 	}
-	for _, p := range jobParams {
+	if m != nil && len(m.BuildParameters) > 0 {
+		run.BuildParameters = rollbackBuildParameters(m.BuildParameters, run.BuildParameters)
+	}
+	for _, p := range run.BuildParameters {
 		switch p.Name {
 		case "git.hash", "git.branch", "git.tag", "git.author":
 			w.Tag(p.Name, p.Value)
 		}
 	}
+	if n.Context != nil && n.Context.Environment != nil && n.Context.Environment.ID != sdk.DefaultEnv.ID {
+		w.Tag(tagEnvironment, n.Context.Environment.Name)
+	}
 
 	//Check
 	if h != nil {
@@ -439,6 +455,14 @@ func processWorkflowNodeRun(db gorp.SqlExecutor, store cache.Store, p *sdk.Proje
 		}
 	}
 
+	held, errF := checkFreeze(db, p, w, n, h, m)
+	if errF == errFreezeRefused {
+		return errF
+	}
+	if errF != nil {
+		return sdk.WrapError(errF, "processWorkflowNodeRun> unable to check freeze windows")
+	}
+
 	if err := insertWorkflowNodeRun(db, run); err != nil {
 		return sdk.WrapError(err, "processWorkflowNodeRun> unable to insert run")
 	}
@@ -453,6 +477,14 @@ func processWorkflowNodeRun(db gorp.SqlExecutor, store cache.Store, p *sdk.Proje
 		return sdk.WrapError(err, "processWorkflowNodeRun> unable to update workflow run")
 	}
 
+	//The node run will be executed at the end of the freeze window
+	if held != nil {
+		if err := freeze.InsertHold(db, run.ID, held.ID); err != nil {
+			return sdk.WrapError(err, "processWorkflowNodeRun> unable to hold node run")
+		}
+		return nil
+	}
+
 	//Execute the node run !
 	if err := execute(db, store, p, run); err != nil {
 		return sdk.WrapError(err, "processWorkflowNodeRun> unable to execute workflow run")
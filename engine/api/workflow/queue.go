@@ -0,0 +1,185 @@
+package workflow
+
+import (
+	"database/sql"
+	"sort"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/sdk"
+)
+
+// nodeJobRunQueueInfo computes the priority of the jobs of a node run, inherited from the trigger of the node
+// or else from the workflow, and the group which owns them: the group with the highest role on the project
+func nodeJobRunQueueInfo(db gorp.SqlExecutor, run *sdk.WorkflowNodeRun) (int, int64, error) {
+	var priority int
+	var projectID int64
+	query := `select workflow.priority, workflow.project_id
+	from workflow_run
+	join workflow on workflow.id = workflow_run.workflow_id
+	where workflow_run.id = $1`
+	if err := db.QueryRow(query, run.WorkflowRunID).Scan(&priority, &projectID); err != nil {
+		return 0, 0, sdk.WrapError(err, "nodeJobRunQueueInfo> Unable to load workflow of run %d", run.WorkflowRunID)
+	}
+
+	query = `select coalesce(max(priority), 0) from (
+		select priority from workflow_node_trigger where workflow_dest_node_id = $1
+		union
+		select priority from workflow_node_join_trigger where workflow_dest_node_id = $1
+	) triggers`
+	triggerPriority, err := db.SelectInt(query, run.WorkflowNodeID)
+	if err != nil {
+		return 0, 0, sdk.WrapError(err, "nodeJobRunQueueInfo> Unable to load triggers of node %d", run.WorkflowNodeID)
+	}
+	if triggerPriority > 0 {
+		priority = int(triggerPriority)
+	}
+
+	var sharedInfraGroupID int64
+	if group.SharedInfraGroup != nil {
+		sharedInfraGroupID = group.SharedInfraGroup.ID
+	}
+	var groupID int64
+	query = `select group_id from project_group where project_id = $1 and group_id <> $2 order by role desc, group_id limit 1`
+	if err := db.QueryRow(query, projectID, sharedInfraGroupID).Scan(&groupID); err != nil && err != sql.ErrNoRows {
+		return 0, 0, sdk.WrapError(err, "nodeJobRunQueueInfo> Unable to load groups of project %d", projectID)
+	}
+
+	return priority, groupID, nil
+}
+
+// LoadGroupQueueUsage loads the waiting and building jobs of each group with its queue settings
+func LoadGroupQueueUsage(db gorp.SqlExecutor) (map[int64]sdk.GroupQueueUsage, error) {
+	settings, err := group.LoadQueueSettings(db)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := map[int64]sdk.GroupQueueUsage{}
+	for id, s := range settings {
+		usage[id] = sdk.GroupQueueUsage{GroupQueueSettings: s}
+	}
+
+	query := `select "group".id, "group".name, workflow_node_run_job.status, count(workflow_node_run_job.id)
+	from "group"
+	left join workflow_node_run_job on workflow_node_run_job.group_id = "group".id and workflow_node_run_job.status = ANY(string_to_array($1, ','))
+	group by "group".id, "group".name, workflow_node_run_job.status`
+	rows, err := db.Query(query, sdk.StatusWaiting.String()+","+sdk.StatusBuilding.String())
+	if err != nil {
+		return nil, sdk.WrapError(err, "LoadGroupQueueUsage> Unable to count jobs")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var name string
+		var status sql.NullString
+		var count int
+		if err := rows.Scan(&id, &name, &status, &count); err != nil {
+			return nil, sdk.WrapError(err, "LoadGroupQueueUsage> Unable to scan jobs count")
+		}
+
+		u, ok := usage[id]
+		if !ok {
+			u = sdk.GroupQueueUsage{GroupQueueSettings: sdk.DefaultGroupQueueSettings(id)}
+		}
+		u.GroupName = name
+		switch status.String {
+		case sdk.StatusWaiting.String():
+			u.Waiting = count
+		case sdk.StatusBuilding.String():
+			u.Building = count
+		}
+		usage[id] = u
+	}
+	return usage, nil
+}
+
+// SortNodeJobRunQueue orders jobs by priority. Jobs of a same priority are shared among their groups according
+// to the groups weights and the jobs they are already building. The jobs of groups reaching their quota of
+// building jobs are returned apart
+func SortNodeJobRunQueue(jobs []sdk.WorkflowNodeJobRun, usage map[int64]sdk.GroupQueueUsage) ([]sdk.WorkflowNodeJobRun, []sdk.WorkflowNodeJobRun) {
+	sorted := make([]sdk.WorkflowNodeJobRun, len(jobs))
+	copy(sorted, jobs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Priority != sorted[j].Priority {
+			return sorted[i].Priority > sorted[j].Priority
+		}
+		return sorted[i].Queued.Before(sorted[j].Queued)
+	})
+
+	scheduled := map[int64]int{}
+	settings := map[int64]sdk.GroupQueueSettings{}
+	for _, j := range sorted {
+		if _, ok := settings[j.GroupID]; ok {
+			continue
+		}
+		u, ok := usage[j.GroupID]
+		if !ok {
+			u.GroupQueueSettings = sdk.DefaultGroupQueueSettings(j.GroupID)
+		}
+		if u.Weight <= 0 {
+			u.Weight = 1
+		}
+		settings[j.GroupID] = u.GroupQueueSettings
+		scheduled[j.GroupID] = u.Building
+	}
+
+	queue := make([]sdk.WorkflowNodeJobRun, 0, len(sorted))
+	overQuota := []sdk.WorkflowNodeJobRun{}
+	for start := 0; start < len(sorted); {
+		end := start
+		for end < len(sorted) && sorted[end].Priority == sorted[start].Priority {
+			end++
+		}
+
+		// Jobs of the priority, by group in queued order
+		groups := []int64{}
+		byGroup := map[int64][]sdk.WorkflowNodeJobRun{}
+		for _, j := range sorted[start:end] {
+			if _, ok := byGroup[j.GroupID]; !ok {
+				groups = append(groups, j.GroupID)
+			}
+			byGroup[j.GroupID] = append(byGroup[j.GroupID], j)
+		}
+
+		for n := start; n < end; n++ {
+			// Pick the group with the lowest share of scheduled jobs by weight
+			var next int64
+			found := false
+			for _, g := range groups {
+				if len(byGroup[g]) == 0 {
+					continue
+				}
+				if !found || lowerShare(g, next, scheduled, settings, byGroup) {
+					next = g
+					found = true
+				}
+			}
+
+			j := byGroup[next][0]
+			byGroup[next] = byGroup[next][1:]
+			if s := settings[next]; s.MaxBuilding > 0 && scheduled[next] >= s.MaxBuilding {
+				overQuota = append(overQuota, j)
+				continue
+			}
+			scheduled[next]++
+			queue = append(queue, j)
+		}
+		start = end
+	}
+
+	return queue, overQuota
+}
+
+// lowerShare returns true if group a has a lower share of scheduled jobs by weight than group b. Ties are won
+// by the group with the oldest job
+func lowerShare(a, b int64, scheduled map[int64]int, settings map[int64]sdk.GroupQueueSettings, byGroup map[int64][]sdk.WorkflowNodeJobRun) bool {
+	sa := (scheduled[a] + 1) * settings[b].Weight
+	sb := (scheduled[b] + 1) * settings[a].Weight
+	if sa != sb {
+		return sa < sb
+	}
+	return byGroup[a][0].Queued.Before(byGroup[b][0].Queued)
+}
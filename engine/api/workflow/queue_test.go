@@ -0,0 +1,54 @@
+package workflow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortNodeJobRunQueue(t *testing.T) {
+	now := time.Now()
+	job := func(id, groupID int64, priority int, queued int) sdk.WorkflowNodeJobRun {
+		return sdk.WorkflowNodeJobRun{ID: id, GroupID: groupID, Priority: priority, Queued: now.Add(time.Duration(queued) * time.Second)}
+	}
+	ids := func(jobs []sdk.WorkflowNodeJobRun) []int64 {
+		res := []int64{}
+		for _, j := range jobs {
+			res = append(res, j.ID)
+		}
+		return res
+	}
+
+	jobs := []sdk.WorkflowNodeJobRun{
+		job(1, 1, 0, 0),
+		job(2, 1, 0, 1),
+		job(3, 1, 0, 2),
+		job(4, 2, 0, 3),
+		job(5, 2, 0, 4),
+		job(6, 3, 10, 5),
+	}
+
+	// Same weights: groups alternate, higher priority first
+	queue, overQuota := SortNodeJobRunQueue(jobs, nil)
+	assert.Equal(t, []int64{6, 1, 4, 2, 5, 3}, ids(queue))
+	assert.Empty(t, overQuota)
+
+	// Group 1 weighs twice as group 2
+	usage := map[int64]sdk.GroupQueueUsage{
+		1: {GroupQueueSettings: sdk.GroupQueueSettings{GroupID: 1, Weight: 2}},
+	}
+	queue, overQuota = SortNodeJobRunQueue(jobs, usage)
+	assert.Equal(t, []int64{6, 1, 2, 4, 3, 5}, ids(queue))
+	assert.Empty(t, overQuota)
+
+	// Group 2 already builds jobs and group 1 can build one job at most
+	usage = map[int64]sdk.GroupQueueUsage{
+		1: {GroupQueueSettings: sdk.GroupQueueSettings{GroupID: 1, Weight: 1, MaxBuilding: 1}},
+		2: {GroupQueueSettings: sdk.GroupQueueSettings{GroupID: 2, Weight: 1}, Building: 2},
+	}
+	queue, overQuota = SortNodeJobRunQueue(jobs, usage)
+	assert.Equal(t, []int64{6, 1, 4, 5}, ids(queue))
+	assert.Equal(t, []int64{2, 3}, ids(overQuota))
+}
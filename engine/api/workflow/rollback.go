@@ -0,0 +1,106 @@
+package workflow
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk"
+)
+
+const (
+	tagRollbackOf   = "rollback_of"
+	tagRolledBackTo = "rolled_back_to"
+)
+
+// FindRollbackDeployment returns, from the deployments of a node sorted from the most recent, the last successful
+// deployment of a workflow run older than the run of the last deployment
+func FindRollbackDeployment(ds []sdk.EnvironmentDeployment) *sdk.EnvironmentDeployment {
+	if len(ds) == 0 {
+		return nil
+	}
+	for i := range ds[1:] {
+		d := &ds[i+1]
+		if d.Status == sdk.StatusSuccess.String() && d.WorkflowRunNumber < ds[0].WorkflowRunNumber {
+			return d
+		}
+	}
+	return nil
+}
+
+// Rollback runs again the node run of a previous deployment in its own workflow run, with its payload and
+// build parameters, so that the artifacts of this run are deployed. Both workflow runs are tagged and keep an info
+// about the rollback
+func Rollback(db gorp.SqlExecutor, store cache.Store, p *sdk.Project, w *sdk.Workflow, envName string, current, previous *sdk.EnvironmentDeployment, u *sdk.User) (*sdk.WorkflowRun, error) {
+	currentRun, err := LoadRun(db, w.ProjectKey, w.Name, current.WorkflowRunNumber)
+	if err != nil {
+		return nil, sdk.WrapError(err, "Rollback> Unable to load run %d", current.WorkflowRunNumber)
+	}
+	run, err := LoadRun(db, w.ProjectKey, w.Name, previous.WorkflowRunNumber)
+	if err != nil {
+		return nil, sdk.WrapError(err, "Rollback> Unable to load run %d", previous.WorkflowRunNumber)
+	}
+
+	var nodeRun *sdk.WorkflowNodeRun
+	for k := range run.WorkflowNodeRuns {
+		for i := range run.WorkflowNodeRuns[k] {
+			if run.WorkflowNodeRuns[k][i].ID == previous.WorkflowNodeRunID {
+				nodeRun = &run.WorkflowNodeRuns[k][i]
+			}
+		}
+	}
+	if nodeRun == nil {
+		return nil, sdk.WrapError(sdk.ErrWorkflowNodeNotFound, "Rollback> Node run %d not found in run %d", previous.WorkflowNodeRunID, run.Number)
+	}
+
+	currentRun.Tag(tagRolledBackTo, fmt.Sprintf("%d", run.Number))
+	AddWorkflowRunInfo(currentRun, sdk.SpawnMsg{
+		ID:   sdk.MsgWorkflowRolledBack.ID,
+		Args: []interface{}{previous.NodeName, envName, u.Username, fmt.Sprintf("%d", run.Number)},
+	})
+	if err := updateWorkflowRun(db, currentRun); err != nil {
+		return nil, sdk.WrapError(err, "Rollback> Unable to update run %d", currentRun.Number)
+	}
+
+	run.Tag(tagTriggeredBy, u.Username)
+	run.Tag(tagRollbackOf, fmt.Sprintf("%d", currentRun.Number))
+	AddWorkflowRunInfo(run, sdk.SpawnMsg{
+		ID:   sdk.MsgWorkflowRollback.ID,
+		Args: []interface{}{previous.NodeName, u.Username, envName, fmt.Sprintf("%d", currentRun.Number)},
+	})
+
+	manual := &sdk.WorkflowNodeRunManual{
+		User:               *u,
+		Payload:            nodeRun.Payload,
+		PipelineParameters: nodeRun.PipelineParameters,
+		BuildParameters:    nodeRun.BuildParameters,
+	}
+	if err := processWorkflowRun(db, store, p, run, nil, manual, &nodeRun.WorkflowNodeID); err != nil {
+		return nil, sdk.WrapError(err, "Rollback> Unable to process workflow run")
+	}
+
+	return LoadRunByIDAndProjectKey(db, w.ProjectKey, run.ID)
+}
+
+// rollbackBuildParameters returns the build parameters of the rolled back node run, so that it runs with the
+// variables of the time it was deployed. Only the parameters describing the new node run are taken from current
+func rollbackBuildParameters(previous, current []sdk.Parameter) []sdk.Parameter {
+	params := make([]sdk.Parameter, 0, len(previous))
+	for _, p := range previous {
+		if !isRunParameter(p.Name) {
+			params = append(params, p)
+		}
+	}
+	for _, p := range current {
+		if isRunParameter(p.Name) {
+			params = append(params, p)
+		}
+	}
+	return params
+}
+
+func isRunParameter(name string) bool {
+	return name == "cds.version" || name == "cds.run" || strings.HasPrefix(name, "cds.run.") || strings.HasPrefix(name, "cds.triggered_by.")
+}
@@ -10,7 +10,9 @@ import (
 )
 
 const (
-	tagTriggeredBy = "triggered_by"
+	tagTriggeredBy     = "triggered_by"
+	tagTriggeredByHook = "triggered_by_hook"
+	tagEnvironment     = "environment"
 )
 
 //RunFromHook is the entry point to trigger a workflow from a hook
@@ -43,6 +45,7 @@ func RunFromHook(db gorp.SqlExecutor, store cache.Store, p *sdk.Project, w *sdk.
 			ProjectID:    w.ProjectID,
 			Status:       string(sdk.StatusWaiting),
 		}
+		wr.Tag(tagTriggeredByHook, h.WorkflowHookModel.Name)
 
 		//Insert it
 		if err := insertWorkflowRun(db, wr); err != nil {
@@ -71,6 +74,7 @@ func RunFromHook(db gorp.SqlExecutor, store cache.Store, p *sdk.Project, w *sdk.
 		}
 
 		//Process the workflow run from the node ID
+		lastWorkflowRun.Tag(tagTriggeredByHook, h.WorkflowHookModel.Name)
 		if err := processWorkflowRun(db, store, p, lastWorkflowRun, e, nil, &oldH.WorkflowNodeID); err != nil {
 			return nil, sdk.WrapError(err, "RunFromHook> Unable to process workflow run")
 		}
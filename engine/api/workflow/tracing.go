@@ -0,0 +1,43 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/tracing"
+)
+
+// startSpan starts a span of the processing of a workflow run, child of the span carried by the executor if it
+// is traced, else of the root span of the run. The database calls of the returned executor are children of the span
+func startSpan(db gorp.SqlExecutor, runID int64, name string) (gorp.SqlExecutor, context.Context, *tracing.Span) {
+	if !tracing.Enabled() {
+		return db, context.Background(), nil
+	}
+	ctx, ok := database.TracingContext(db)
+	if !ok {
+		ctx = tracing.ContextWithRemote(context.Background(), tracing.WorkflowRunContext(runID))
+	}
+	ctx, span := tracing.Start(ctx, name)
+	return database.WithTracing(ctx, db), ctx, span
+}
+
+// finishRunSpan records the root span of a workflow run, from its start to its end
+func finishRunSpan(w *sdk.WorkflowRun) {
+	span := tracing.NewSpan("workflow.run", tracing.WorkflowRunContext(w.ID), tracing.SpanContext{}, w.Start)
+	span.SetTag("cds.project", w.Workflow.ProjectKey)
+	span.SetTag("cds.workflow", w.Workflow.Name)
+	span.SetTag("cds.run.number", w.Number)
+	span.SetTag("cds.status", w.Status)
+	if w.Status == sdk.StatusFail.String() {
+		span.SetError(errors.New("workflow run failed"))
+	}
+	span.Finish()
+}
+
+func isTerminated(status string) bool {
+	return status == sdk.StatusSuccess.String() || status == sdk.StatusFail.String() || status == sdk.StatusStopped.String()
+}
@@ -0,0 +1,165 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+// isValidWorkflowPermission checks that a permission given to a group on a workflow is a known one
+func isValidWorkflowPermission(p int) bool {
+	switch p {
+	case permission.PermissionRead, permission.PermissionReadExecute, permission.PermissionReadWriteExecute:
+		return true
+	}
+	return false
+}
+
+// postWorkflowGroupHandler gives permissions on a workflow to a group
+func (api *API) postWorkflowGroupHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["permProjectKey"]
+		name := vars["workflowName"]
+
+		var gp sdk.GroupPermission
+		if err := UnmarshalBody(r, &gp); err != nil {
+			return sdk.WrapError(err, "postWorkflowGroupHandler> Cannot read body")
+		}
+		if !isValidWorkflowPermission(gp.Permission) {
+			return sdk.WrapError(sdk.ErrWrongRequest, "postWorkflowGroupHandler> Invalid permission %d", gp.Permission)
+		}
+
+		wf, err := workflow.Load(api.mustDB(), api.Cache, key, name, getUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "postWorkflowGroupHandler> Cannot load workflow %s", name)
+		}
+
+		//The first group restricts the workflow, so it must be able to manage it
+		if len(wf.Groups) == 0 && gp.Permission != permission.PermissionReadWriteExecute {
+			return sdk.WrapError(sdk.ErrGroupNeedWrite, "postWorkflowGroupHandler> First group on workflow %s must have write permission", name)
+		}
+
+		g, err := group.LoadGroup(api.mustDB(), gp.Group.Name)
+		if err != nil {
+			return sdk.WrapError(err, "postWorkflowGroupHandler> Cannot find group %s", gp.Group.Name)
+		}
+
+		alreadyAdded, err := group.IsInWorkflow(api.mustDB(), wf.ID, g.ID)
+		if err != nil {
+			return sdk.WrapError(err, "postWorkflowGroupHandler> Cannot check if group is in workflow")
+		}
+		if alreadyAdded {
+			return sdk.WrapError(sdk.ErrGroupPresent, "postWorkflowGroupHandler> Group %s already in workflow %s", g.Name, name)
+		}
+
+		if err := group.InsertGroupInWorkflow(api.mustDB(), wf.ID, g.ID, gp.Permission); err != nil {
+			return sdk.WrapError(err, "postWorkflowGroupHandler> Cannot add group %s in workflow %s", g.Name, name)
+		}
+
+		groups, err := group.LoadGroupsByWorkflow(api.mustDB(), wf.ID)
+		if err != nil {
+			return sdk.WrapError(err, "postWorkflowGroupHandler> Cannot load groups of workflow %s", name)
+		}
+		return WriteJSON(w, r, groups, http.StatusOK)
+	}
+}
+
+// putWorkflowGroupHandler updates permissions of a group on a workflow
+func (api *API) putWorkflowGroupHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["permProjectKey"]
+		name := vars["workflowName"]
+		groupName := vars["groupName"]
+
+		var gp sdk.GroupPermission
+		if err := UnmarshalBody(r, &gp); err != nil {
+			return sdk.WrapError(err, "putWorkflowGroupHandler> Cannot read body")
+		}
+		if !isValidWorkflowPermission(gp.Permission) {
+			return sdk.WrapError(sdk.ErrWrongRequest, "putWorkflowGroupHandler> Invalid permission %d", gp.Permission)
+		}
+
+		wf, err := workflow.Load(api.mustDB(), api.Cache, key, name, getUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "putWorkflowGroupHandler> Cannot load workflow %s", name)
+		}
+
+		g, err := group.LoadGroup(api.mustDB(), groupName)
+		if err != nil {
+			return sdk.WrapError(err, "putWorkflowGroupHandler> Cannot find group %s", groupName)
+		}
+
+		if gp.Permission != permission.PermissionReadWriteExecute {
+			if err := checkWorkflowKeepsWriteGroup(api, wf, g); err != nil {
+				return sdk.WrapError(err, "putWorkflowGroupHandler> Cannot remove write permission on group %s for workflow %s", groupName, name)
+			}
+		}
+
+		if err := group.UpdateGroupRoleInWorkflow(api.mustDB(), wf.ID, g.ID, gp.Permission); err != nil {
+			return sdk.WrapError(err, "putWorkflowGroupHandler> Cannot update permission for group %s in workflow %s", groupName, name)
+		}
+
+		groups, err := group.LoadGroupsByWorkflow(api.mustDB(), wf.ID)
+		if err != nil {
+			return sdk.WrapError(err, "putWorkflowGroupHandler> Cannot load groups of workflow %s", name)
+		}
+		return WriteJSON(w, r, groups, http.StatusOK)
+	}
+}
+
+// deleteWorkflowGroupHandler removes permissions of a group on a workflow. Removing the last group
+// of a workflow makes it available again to all groups of the project
+func (api *API) deleteWorkflowGroupHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["permProjectKey"]
+		name := vars["workflowName"]
+		groupName := vars["groupName"]
+
+		wf, err := workflow.Load(api.mustDB(), api.Cache, key, name, getUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "deleteWorkflowGroupHandler> Cannot load workflow %s", name)
+		}
+
+		g, err := group.LoadGroup(api.mustDB(), groupName)
+		if err != nil {
+			return sdk.WrapError(err, "deleteWorkflowGroupHandler> Cannot find group %s", groupName)
+		}
+
+		if len(wf.Groups) > 1 {
+			if err := checkWorkflowKeepsWriteGroup(api, wf, g); err != nil {
+				return sdk.WrapError(err, "deleteWorkflowGroupHandler> Cannot remove group %s from workflow %s", groupName, name)
+			}
+		}
+
+		if err := group.DeleteGroupFromWorkflow(api.mustDB(), wf.ID, g.ID); err != nil {
+			return sdk.WrapError(err, "deleteWorkflowGroupHandler> Cannot delete group %s from workflow %s", groupName, name)
+		}
+
+		groups, err := group.LoadGroupsByWorkflow(api.mustDB(), wf.ID)
+		if err != nil {
+			return sdk.WrapError(err, "deleteWorkflowGroupHandler> Cannot load groups of workflow %s", name)
+		}
+		return WriteJSON(w, r, groups, http.StatusOK)
+	}
+}
+
+// checkWorkflowKeepsWriteGroup returns an error if g is the last group with write permission on the workflow
+func checkWorkflowKeepsWriteGroup(api *API, wf *sdk.Workflow, g *sdk.Group) error {
+	permissions, err := group.LoadAllWorkflowGroupByRole(api.mustDB(), wf.ID, permission.PermissionReadWriteExecute)
+	if err != nil {
+		return err
+	}
+	if len(permissions) == 1 && permissions[0].Group.ID == g.ID {
+		return sdk.ErrGroupNeedWrite
+	}
+	return nil
+}
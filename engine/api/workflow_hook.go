@@ -6,6 +6,7 @@ import (
 
 	"github.com/gorilla/mux"
 
+	"github.com/ovh/cds/engine/api/notification"
 	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/sdk"
 )
@@ -55,6 +56,7 @@ func (api *API) postWorkflowHookModelHandler() Handler {
 			return sdk.WrapError(errtx, "postWorkflowHookModelHandler> Unable to start transaction")
 		}
 		defer tx.Rollback()
+		defer notification.Discard(tx)
 
 		if err := workflow.InsertHookModel(tx, m); err != nil {
 			return sdk.WrapError(err, "postWorkflowHookModelHandler")
@@ -63,6 +65,7 @@ func (api *API) postWorkflowHookModelHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "postWorkflowHookModelHandler> Unable to commit transaction")
 		}
+		notification.Flush(tx)
 
 		return WriteJSON(w, r, m, http.StatusCreated)
 	}
@@ -81,6 +84,7 @@ func (api *API) putWorkflowHookModelHandler() Handler {
 		}
 
 		defer tx.Rollback()
+		defer notification.Discard(tx)
 
 		if err := workflow.UpdateHookModel(tx, m); err != nil {
 			return sdk.WrapError(err, "putWorkflowHookModelHandler")
@@ -89,6 +93,7 @@ func (api *API) putWorkflowHookModelHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(errtx, "putWorkflowHookModelHandler> Unable to commit transaction")
 		}
+		notification.Flush(tx)
 
 		return WriteJSON(w, r, m, http.StatusOK)
 	}
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/notification"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// jobRunWatchdogSlack is the time left to workers to stop a job by themselves before the API fails it
+const jobRunWatchdogSlack = time.Minute
+
+//jobRunWatchdog is the go-routine which fails building jobs exceeding their timeout or whose worker is lost, for
+//workflows and pipelines. Jobs are locked before being failed, so that a job is only failed by one API instance
+func jobRunWatchdog(c context.Context, DBFunc func() *gorp.DbMap, store cache.Store) {
+	tick := time.NewTicker(30 * time.Second).C
+	for {
+		select {
+		case <-c.Done():
+			if c.Err() != nil {
+				log.Error("Exiting jobRunWatchdog: %v", c.Err())
+				return
+			}
+		case <-tick:
+			db := DBFunc()
+			if db == nil || group.SharedInfraGroup == nil {
+				continue
+			}
+			if err := checkBuildingJobRuns(db, store); err != nil {
+				log.Warning("jobRunWatchdog> %s", err)
+			}
+			if err := checkBuildingPipelineBuildJobs(db); err != nil {
+				log.Warning("jobRunWatchdog> %s", err)
+			}
+		}
+	}
+}
+
+// checkBuildingJobRuns fails the building jobs which exceeded their timeout or lost their worker
+func checkBuildingJobRuns(db *gorp.DbMap, store cache.Store) error {
+	jobs, err := workflow.LoadNodeJobRunQueue(db, store, []int64{group.SharedInfraGroup.ID}, nil, sdk.StatusBuilding.String())
+	if err != nil {
+		return sdk.WrapError(err, "checkBuildingJobRuns> Unable to load building jobs")
+	}
+
+	for i := range jobs {
+		j := &jobs[i]
+		if time.Since(j.Start) < jobRunWatchdogSlack {
+			continue
+		}
+
+		var msg sdk.SpawnMsg
+		if timeout := j.Job.Action.JobTimeout(); time.Since(j.Start) > timeout+jobRunWatchdogSlack {
+			msg = sdk.SpawnMsg{ID: sdk.MsgSpawnInfoJobTimeout.ID, Args: []interface{}{timeout.String()}}
+		} else {
+			n, err := db.SelectInt("select count(id) from worker where id = $1", j.Job.WorkerID)
+			if err != nil {
+				return sdk.WrapError(err, "checkBuildingJobRuns> Unable to load worker %s", j.Job.WorkerID)
+			}
+			if n > 0 {
+				continue
+			}
+			msg = sdk.SpawnMsg{ID: sdk.MsgSpawnInfoWorkerLost.ID, Args: []interface{}{j.Job.WorkerName}}
+		}
+
+		failed, err := failJobRun(db, store, j, msg)
+		if err != nil {
+			log.Warning("checkBuildingJobRuns> Unable to fail job %d: %s", j.ID, err)
+			continue
+		}
+		if failed {
+			log.Info("checkBuildingJobRuns> job %d failed: %s", j.ID, msg.ID)
+		}
+	}
+	return nil
+}
+
+// failJobRun stops a job with a spawn info explaining why. It returns false if the job is not building anymore
+// or is being failed by another API instance
+func failJobRun(db *gorp.DbMap, store cache.Store, j *sdk.WorkflowNodeJobRun, msg sdk.SpawnMsg) (bool, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return false, sdk.WrapError(err, "failJobRun> Cannot start transaction")
+	}
+	defer tx.Rollback()
+	defer notification.Discard(tx)
+
+	locked, err := workflow.LockBuildingNodeJobRun(tx, j.ID)
+	if err != nil || !locked {
+		return false, err
+	}
+
+	p, err := project.LoadProjectByNodeJobRunID(tx, store, j.ID, nil, project.LoadOptions.WithVariables)
+	if err != nil {
+		return false, sdk.WrapError(err, "failJobRun> Cannot load project")
+	}
+
+	infos := []sdk.SpawnInfo{{RemoteTime: time.Now(), Message: msg}}
+	job, err := workflow.AddSpawnInfosNodeJobRun(tx, store, p, j.ID, infos)
+	if err != nil {
+		return false, sdk.WrapError(err, "failJobRun> Cannot save spawn info")
+	}
+
+	if err := workflow.UpdateNodeJobRunStatus(tx, store, p, job, sdk.StatusFail); err != nil {
+		return false, sdk.WrapError(err, "failJobRun> Cannot update job status")
+	}
+	if err := tx.Commit(); err != nil {
+		return false, sdk.WrapError(err, "failJobRun> Cannot commit transaction")
+	}
+	notification.Flush(tx)
+	return true, nil
+}
+
+// checkBuildingPipelineBuildJobs fails the building pipeline build jobs which exceeded their timeout or lost
+// their worker
+func checkBuildingPipelineBuildJobs(db *gorp.DbMap) error {
+	jobs, err := pipeline.GetBuildingPipelineBuildJob(db)
+	if err != nil {
+		return sdk.WrapError(err, "checkBuildingPipelineBuildJobs> Unable to load building jobs")
+	}
+
+	for i := range jobs {
+		j := &jobs[i]
+		if time.Since(j.Start) < jobRunWatchdogSlack {
+			continue
+		}
+
+		var msg sdk.SpawnMsg
+		if timeout := j.Job.Action.JobTimeout(); time.Since(j.Start) > timeout+jobRunWatchdogSlack {
+			msg = sdk.SpawnMsg{ID: sdk.MsgSpawnInfoJobTimeout.ID, Args: []interface{}{timeout.String()}}
+		} else {
+			n, err := db.SelectInt("select count(id) from worker where name = $1", j.Job.WorkerName)
+			if err != nil {
+				return sdk.WrapError(err, "checkBuildingPipelineBuildJobs> Unable to load worker %s", j.Job.WorkerName)
+			}
+			if n > 0 {
+				continue
+			}
+			msg = sdk.SpawnMsg{ID: sdk.MsgSpawnInfoWorkerLost.ID, Args: []interface{}{j.Job.WorkerName}}
+		}
+
+		failed, err := failPipelineBuildJob(db, j.ID, msg)
+		if err != nil {
+			log.Warning("checkBuildingPipelineBuildJobs> Unable to fail pipeline build job %d: %s", j.ID, err)
+			continue
+		}
+		if failed {
+			log.Info("checkBuildingPipelineBuildJobs> pipeline build job %d failed: %s", j.ID, msg.ID)
+		}
+	}
+	return nil
+}
+
+// failPipelineBuildJob stops a pipeline build job with a spawn info explaining why. It returns false if the job is
+// not building anymore or is being failed by another API instance
+func failPipelineBuildJob(db *gorp.DbMap, id int64, msg sdk.SpawnMsg) (bool, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return false, sdk.WrapError(err, "failPipelineBuildJob> Cannot start transaction")
+	}
+	defer tx.Rollback()
+
+	locked, err := pipeline.LockBuildingPipelineBuildJob(tx, id)
+	if err != nil || !locked {
+		return false, err
+	}
+
+	infos := []sdk.SpawnInfo{{RemoteTime: time.Now(), Message: msg}}
+	pbJob, err := pipeline.AddSpawnInfosPipelineBuildJob(tx, id, infos)
+	if err != nil {
+		return false, sdk.WrapError(err, "failPipelineBuildJob> Cannot save spawn info")
+	}
+
+	reason := sdk.NewMessage(sdk.Messages[msg.ID], msg.Args...)
+	pbJob.Job.Reason = reason.String("")
+	if err := pipeline.UpdatePipelineBuildJobStatus(tx, pbJob, sdk.StatusFail); err != nil {
+		return false, sdk.WrapError(err, "failPipelineBuildJob> Cannot update job status")
+	}
+	if err := tx.Commit(); err != nil {
+		return false, sdk.WrapError(err, "failPipelineBuildJob> Cannot commit transaction")
+	}
+	return true, nil
+}
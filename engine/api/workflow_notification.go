@@ -0,0 +1,156 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/notification"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+// getWorkflowNotificationsHandler returns all notification rules of a workflow
+func (api *API) getWorkflowNotificationsHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["permProjectKey"]
+		name := vars["workflowName"]
+
+		wf, err := workflow.Load(api.mustDB(), api.Cache, key, name, getUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "getWorkflowNotificationsHandler> Cannot load workflow %s", name)
+		}
+
+		return WriteJSON(w, r, wf.Notifications, http.StatusOK)
+	}
+}
+
+// postWorkflowNotificationHandler adds a notification rule on a workflow
+func (api *API) postWorkflowNotificationHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["permProjectKey"]
+		name := vars["workflowName"]
+
+		var n sdk.WorkflowNotification
+		if err := UnmarshalBody(r, &n); err != nil {
+			return sdk.WrapError(err, "postWorkflowNotificationHandler> Cannot read body")
+		}
+
+		wf, err := workflow.Load(api.mustDB(), api.Cache, key, name, getUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "postWorkflowNotificationHandler> Cannot load workflow %s", name)
+		}
+
+		if err := checkWorkflowNotification(wf, &n); err != nil {
+			return sdk.WrapError(err, "postWorkflowNotificationHandler> Invalid notification")
+		}
+
+		n.ID = 0
+		n.WorkflowID = wf.ID
+		if err := notification.InsertWorkflowNotification(api.mustDB(), &n); err != nil {
+			return sdk.WrapError(err, "postWorkflowNotificationHandler> Cannot add notification on workflow %s", name)
+		}
+
+		return WriteJSON(w, r, n, http.StatusCreated)
+	}
+}
+
+// putWorkflowNotificationHandler updates a notification rule of a workflow
+func (api *API) putWorkflowNotificationHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["permProjectKey"]
+		name := vars["workflowName"]
+
+		id, err := requestVarInt(r, "notificationID")
+		if err != nil {
+			return err
+		}
+
+		var n sdk.WorkflowNotification
+		if err := UnmarshalBody(r, &n); err != nil {
+			return sdk.WrapError(err, "putWorkflowNotificationHandler> Cannot read body")
+		}
+
+		wf, err := workflow.Load(api.mustDB(), api.Cache, key, name, getUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "putWorkflowNotificationHandler> Cannot load workflow %s", name)
+		}
+
+		if _, err := notification.LoadWorkflowNotification(api.mustDB(), wf.ID, id); err != nil {
+			return sdk.WrapError(err, "putWorkflowNotificationHandler> Cannot load notification %d", id)
+		}
+
+		if err := checkWorkflowNotification(wf, &n); err != nil {
+			return sdk.WrapError(err, "putWorkflowNotificationHandler> Invalid notification")
+		}
+
+		n.ID = id
+		n.WorkflowID = wf.ID
+		if err := notification.UpdateWorkflowNotification(api.mustDB(), &n); err != nil {
+			return sdk.WrapError(err, "putWorkflowNotificationHandler> Cannot update notification %d", id)
+		}
+
+		return WriteJSON(w, r, n, http.StatusOK)
+	}
+}
+
+// deleteWorkflowNotificationHandler deletes a notification rule of a workflow
+func (api *API) deleteWorkflowNotificationHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["permProjectKey"]
+		name := vars["workflowName"]
+
+		id, err := requestVarInt(r, "notificationID")
+		if err != nil {
+			return err
+		}
+
+		wf, err := workflow.Load(api.mustDB(), api.Cache, key, name, getUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "deleteWorkflowNotificationHandler> Cannot load workflow %s", name)
+		}
+
+		n, err := notification.LoadWorkflowNotification(api.mustDB(), wf.ID, id)
+		if err != nil {
+			return sdk.WrapError(err, "deleteWorkflowNotificationHandler> Cannot load notification %d", id)
+		}
+
+		if err := notification.DeleteWorkflowNotification(api.mustDB(), n); err != nil {
+			return sdk.WrapError(err, "deleteWorkflowNotificationHandler> Cannot delete notification %d", id)
+		}
+
+		return WriteJSON(w, r, nil, http.StatusOK)
+	}
+}
+
+// getWorkflowNotificationTypesHandler returns notification types available on workflows
+func (api *API) getWorkflowNotificationTypesHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		return WriteJSON(w, r, sdk.WorkflowNotificationTypes, http.StatusOK)
+	}
+}
+
+// checkWorkflowNotification checks the notification and its node filters against the workflow
+func checkWorkflowNotification(wf *sdk.Workflow, n *sdk.WorkflowNotification) error {
+	if err := n.IsValid(); err != nil {
+		return err
+	}
+
+	names := map[string]bool{}
+	for _, id := range wf.Nodes() {
+		if node := wf.GetNode(id); node != nil {
+			names[node.Name] = true
+		}
+	}
+	for _, name := range n.NodeNames {
+		if !names[name] {
+			return sdk.WrapError(sdk.ErrWrongRequest, "Unknown node %s in workflow %s", name, wf.Name)
+		}
+	}
+	return nil
+}
@@ -14,8 +14,11 @@ import (
 	"github.com/ovh/venom"
 
 	"github.com/ovh/cds/engine/api/artifact"
+	"github.com/ovh/cds/engine/api/metrics"
+	"github.com/ovh/cds/engine/api/notification"
 	"github.com/ovh/cds/engine/api/objectstore"
 	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/services"
 	"github.com/ovh/cds/engine/api/worker"
 	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/sdk"
@@ -59,12 +62,17 @@ func (api *API) postTakeWorkflowJobHandler() Handler {
 			return sdk.WrapError(errP, "postTakeWorkflowJobHandler> Cannot load project")
 		}
 
+		if err := api.checkGroupQueueQuota(id); err != nil {
+			return sdk.WrapError(err, "postTakeWorkflowJobHandler> Cannot take job %d", id)
+		}
+
 		// Start a tx
 		tx, errBegin := api.mustDB().Begin()
 		if errBegin != nil {
 			return sdk.WrapError(errBegin, "postTakeWorkflowJobHandler> Cannot start transaction")
 		}
 		defer tx.Rollback()
+		defer notification.Discard(tx)
 
 		//Load worker model
 		workerModel := getWorker(ctx).Name
@@ -147,9 +155,16 @@ func (api *API) postTakeWorkflowJobHandler() Handler {
 		pbji.Secrets = append(pbji.Secrets, secretsKeys...)
 		pbji.NodeJobRun.Parameters = append(pbji.NodeJobRun.Parameters, params...)
 
+		proxyParams, errProxy := services.NewRepository(api.mustDB, api.Cache).ProxyParameters()
+		if errProxy != nil {
+			return sdk.WrapError(errProxy, "postTakeWorkflowJobHandler> Cannot load proxy parameters")
+		}
+		pbji.NodeJobRun.Parameters = append(pbji.NodeJobRun.Parameters, proxyParams...)
+
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "postTakeWorkflowJobHandler> Cannot commit transaction")
 		}
+		notification.Flush(tx)
 
 		return WriteJSON(w, r, pbji, http.StatusOK)
 	}
@@ -204,14 +219,21 @@ func (api *API) postSpawnInfosWorkflowJobHandler() Handler {
 			return sdk.WrapError(errBegin, "postSpawnInfosWorkflowJobHandler> Cannot start transaction")
 		}
 		defer tx.Rollback()
+		defer notification.Discard(tx)
 
 		if _, err := workflow.AddSpawnInfosNodeJobRun(tx, api.Cache, p, id, s); err != nil {
 			return sdk.WrapError(err, "postSpawnInfosWorkflowJobHandler> Cannot save job %d", id)
 		}
 
+		if err := worker.InsertSpawnInfos(tx, s); err != nil {
+			return sdk.WrapError(err, "postSpawnInfosWorkflowJobHandler> Cannot save spawn durations")
+		}
+
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "addSpawnInfosPipelineBuildJobHandler> Cannot commit tx")
 		}
+		notification.Flush(tx)
+		metrics.ObserveSpawnInfos(s)
 
 		return WriteJSON(w, r, nil, http.StatusOK)
 	}
@@ -246,6 +268,7 @@ func (api *API) postWorkflowJobResultHandler() Handler {
 			return sdk.WrapError(errb, "postWorkflowJobResultHandler> Cannot begin tx")
 		}
 		defer tx.Rollback()
+		defer notification.Discard(tx)
 
 		//Update worker status
 		if err := worker.UpdateWorkerStatus(tx, getWorker(ctx).ID, sdk.StatusWaiting); err != nil {
@@ -278,6 +301,7 @@ func (api *API) postWorkflowJobResultHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "postWorkflowJobResultHandler> Cannot commit tx")
 		}
+		notification.Flush(tx)
 
 		return nil
 	}
@@ -347,12 +371,17 @@ func (api *API) postWorkflowJobStepStatusHandler() Handler {
 			return sdk.WrapError(errB, "postWorkflowJobStepStatusHandler> Cannot start transaction")
 		}
 		defer tx.Rollback()
+		defer notification.Discard(tx)
 
 		if err := workflow.UpdateNodeJobRun(tx, api.Cache, p, nodeJobRun); err != nil {
 			return sdk.WrapError(err, "postWorkflowJobStepStatusHandler> Error while update job run")
 		}
 
-		return tx.Commit()
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "postWorkflowJobStepStatusHandler> Cannot commit transaction")
+		}
+		notification.Flush(tx)
+		return nil
 	}
 }
 
@@ -373,7 +402,18 @@ func (api *API) getWorkflowJobQueueHandler() Handler {
 			return sdk.WrapError(err, "getWorkflowJobQueueHandler> Unable to load queue")
 		}
 
-		return WriteJSON(w, r, jobs, http.StatusOK)
+		usage, err := workflow.LoadGroupQueueUsage(api.mustDB())
+		if err != nil {
+			return sdk.WrapError(err, "getWorkflowJobQueueHandler> Unable to load groups queue usage")
+		}
+
+		// Jobs of groups which reached their quota are hidden to hatcheries and workers
+		queue, overQuota := workflow.SortNodeJobRunQueue(jobs, usage)
+		if getHatchery(ctx) == nil && getWorker(ctx) == nil {
+			queue = append(queue, overQuota...)
+		}
+
+		return WriteJSON(w, r, queue, http.StatusOK)
 	}
 }
 
@@ -401,12 +441,18 @@ func (api *API) postWorkflowJobTestsResultsHandler() Handler {
 			return sdk.WrapError(errB, "postWorkflowJobTestsResultsHandler> Cannot start transaction")
 		}
 		defer tx.Rollback()
+		defer notification.Discard(tx)
 
 		wnjr, err := workflow.LoadAndLockNodeRunByID(tx, nodeRunJob.WorkflowNodeRunID)
 		if err != nil {
 			return sdk.WrapError(err, "postWorkflowJobTestsResultsHandler> Cannot load node job")
 		}
 
+		// Keep the history of the test cases before suites are renamed
+		if err := workflow.InsertTestCases(tx, wnjr, new); err != nil {
+			return sdk.WrapError(err, "postWorkflowJobTestsResultsHandler> Cannot insert tests history")
+		}
+
 		if wnjr.Tests == nil {
 			wnjr.Tests = &venom.Tests{}
 		}
@@ -442,6 +488,7 @@ func (api *API) postWorkflowJobTestsResultsHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "postWorkflowJobTestsResultsHandler> Cannot update node run")
 		}
+		notification.Flush(tx)
 		return nil
 	}
 }
@@ -469,6 +516,7 @@ func (api *API) postWorkflowJobVariableHandler() Handler {
 			return sdk.WrapError(errb, "postWorkflowJobVariableHandler> Unable to start tx")
 		}
 		defer tx.Rollback()
+		defer notification.Discard(tx)
 
 		job, errj := workflow.LoadAndLockNodeJobRun(tx, api.Cache, id)
 		if errj != nil {
@@ -517,6 +565,7 @@ func (api *API) postWorkflowJobVariableHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "postWorkflowJobVariableHandler> Unable to commit tx")
 		}
+		notification.Flush(tx)
 
 		return nil
 	}
@@ -624,3 +673,23 @@ func (api *API) postWorkflowJobArtifactHandler() Handler {
 		return nil
 	}
 }
+
+// checkGroupQueueQuota checks the group owning a job has not reached its quota of building jobs
+func (api *API) checkGroupQueueQuota(id int64) error {
+	job, err := workflow.LoadNodeJobRun(api.mustDB(), api.Cache, id)
+	if err != nil {
+		return err
+	}
+	if job.GroupID == 0 {
+		return nil
+	}
+
+	usage, err := workflow.LoadGroupQueueUsage(api.mustDB())
+	if err != nil {
+		return err
+	}
+	if u, ok := usage[job.GroupID]; ok && u.QuotaReached() {
+		return sdk.ErrGroupQueueQuotaReached
+	}
+	return nil
+}
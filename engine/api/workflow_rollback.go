@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/notification"
+	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+func (api *API) postWorkflowRollbackHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["permProjectKey"]
+		name := vars["workflowName"]
+
+		var rollback sdk.WorkflowRollback
+		if err := UnmarshalBody(r, &rollback); err != nil {
+			return err
+		}
+		if rollback.NodeName == "" || rollback.Environment == "" {
+			return sdk.ErrWrongRequest
+		}
+
+		p, errP := project.Load(api.mustDB(), api.Cache, key, getUser(ctx), project.LoadOptions.WithVariables)
+		if errP != nil {
+			return sdk.WrapError(errP, "postWorkflowRollbackHandler> Cannot load project")
+		}
+
+		tx, errb := api.mustDB().Begin()
+		if errb != nil {
+			return sdk.WrapError(errb, "postWorkflowRollbackHandler> Cannot start transaction")
+		}
+		defer tx.Rollback()
+		defer notification.Discard(tx)
+
+		wf, errl := workflow.Load(tx, api.Cache, key, name, getUser(ctx))
+		if errl != nil {
+			return sdk.WrapError(errl, "postWorkflowRollbackHandler> Unable to load workflow")
+		}
+
+		env, errEnv := environment.LoadEnvironmentByName(tx, key, rollback.Environment)
+		if errEnv != nil {
+			return sdk.WrapError(errEnv, "postWorkflowRollbackHandler> Cannot load environment %s", rollback.Environment)
+		}
+		if !permission.AccessToEnvironment(env.ID, getUser(ctx), permission.PermissionReadExecute) {
+			return sdk.WrapError(sdk.ErrForbidden, "postWorkflowRollbackHandler> Not allowed to deploy on environment %s", env.Name)
+		}
+
+		ds, errD := environment.LoadNodeDeployments(tx, env.ID, wf.ID, rollback.NodeName, 100)
+		if errD != nil {
+			return sdk.WrapError(errD, "postWorkflowRollbackHandler> Cannot load deployments of node %s", rollback.NodeName)
+		}
+		if len(ds) == 0 {
+			return sdk.WrapError(sdk.ErrEnvironmentDeploymentNotFound, "postWorkflowRollbackHandler> Node %s never deployed on environment %s", rollback.NodeName, env.Name)
+		}
+		previous := workflow.FindRollbackDeployment(ds)
+		if previous == nil {
+			return sdk.WrapError(sdk.ErrNoPreviousDeployment, "postWorkflowRollbackHandler> No deployment of node %s on environment %s before run %d", rollback.NodeName, env.Name, ds[0].WorkflowRunNumber)
+		}
+
+		wr, errR := workflow.Rollback(tx, api.Cache, p, wf, env.Name, &ds[0], previous, getUser(ctx))
+		if errR != nil {
+			return sdk.WrapError(errR, "postWorkflowRollbackHandler> Unable to rollback node %s", rollback.NodeName)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "postWorkflowRollbackHandler> Unable to commit transaction")
+		}
+		notification.Flush(tx)
+
+		wr.Translate(r.Header.Get("Accept-Language"))
+		return WriteJSON(w, r, wr, http.StatusOK)
+	}
+}
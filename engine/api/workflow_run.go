@@ -11,6 +11,7 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/ovh/cds/engine/api/artifact"
+	"github.com/ovh/cds/engine/api/notification"
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/sdk"
@@ -164,6 +165,8 @@ func (api *API) resyncWorkflowRunPipelinesHandler() Handler {
 		if errT != nil {
 			return sdk.WrapError(errT, "resyncWorkflowRunPipelinesHandler> Cannot start transaction")
 		}
+		defer tx.Rollback()
+		defer notification.Discard(tx)
 
 		if err := workflow.ResyncPipeline(tx, run); err != nil {
 			return sdk.WrapError(err, "resyncWorkflowRunPipelinesHandler> Cannot resync pipelines")
@@ -172,6 +175,7 @@ func (api *API) resyncWorkflowRunPipelinesHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "resyncWorkflowRunPipelinesHandler> Cannot commit transaction")
 		}
+		notification.Flush(tx)
 		return WriteJSON(w, r, run, http.StatusOK)
 	}
 }
@@ -300,6 +304,7 @@ func (api *API) stopWorkflowNodeRunHandler() Handler {
 			return sdk.WrapError(errTx, "stopWorkflowNodeRunHandler> Unable to create transaction")
 		}
 		defer tx.Rollback()
+		defer notification.Discard(tx)
 
 		stopInfos := sdk.SpawnInfo{
 			APITime:    time.Now(),
@@ -322,6 +327,7 @@ func (api *API) stopWorkflowNodeRunHandler() Handler {
 		if errC := tx.Commit(); errC != nil {
 			return sdk.WrapError(errC, "stopWorkflowNodeRunHandler> Unable to commit")
 		}
+		notification.Flush(tx)
 
 		return nil
 	}
@@ -365,6 +371,7 @@ func (api *API) postWorkflowRunHandler() Handler {
 			return errb
 		}
 		defer tx.Rollback()
+		defer notification.Discard(tx)
 
 		opts := &sdk.WorkflowRunPostHandlerOption{}
 		if err := UnmarshalBody(r, opts); err != nil {
@@ -397,10 +404,10 @@ func (api *API) postWorkflowRunHandler() Handler {
 		} else {
 			//Default manual run
 			if opts.Manual == nil {
-				opts.Manual = &sdk.WorkflowNodeRunManual{
-					User: *getUser(ctx),
-				}
+				opts.Manual = &sdk.WorkflowNodeRunManual{}
 			}
+			//The user of the body is not trusted, the run is triggered by the authenticated user
+			opts.Manual.User = *getUser(ctx)
 
 			//If payload is not set, keep the default payload
 			if opts.Manual.Payload == interface{}(nil) {
@@ -451,6 +458,7 @@ func (api *API) postWorkflowRunHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "postWorkflowRunHandler> Unable to commit transaction")
 		}
+		notification.Flush(tx)
 
 		wr.Translate(r.Header.Get("Accept-Language"))
 		return WriteJSON(w, r, wr, http.StatusOK)
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+func (api *API) searchWorkflowRunsHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["permProjectKey"]
+		name := vars["workflowName"]
+
+		s, err := parseWorkflowRunSearch(r)
+		if err != nil {
+			return sdk.WrapError(err, "searchWorkflowRunsHandler> Invalid search")
+		}
+
+		runs, next, err := workflow.SearchRuns(api.mustDB(), key, name, s)
+		if err != nil {
+			return sdk.WrapError(err, "searchWorkflowRunsHandler> Unable to search workflow runs")
+		}
+
+		for i := range runs {
+			runs[i].Translate(r.Header.Get("Accept-Language"))
+		}
+		return WriteJSON(w, r, sdk.WorkflowRunSearchResult{Runs: runs, NextCursor: next}, http.StatusOK)
+	}
+}
+
+// parseWorkflowRunSearch reads a search of workflow runs from the query of a request. Statuses are comma separated,
+// tags are given as key=value and dates in RFC3339
+func parseWorkflowRunSearch(r *http.Request) (sdk.WorkflowRunSearch, error) {
+	if err := r.ParseForm(); err != nil {
+		return sdk.WorkflowRunSearch{}, sdk.ErrWrongRequest
+	}
+	s := sdk.WorkflowRunSearch{
+		TriggeredBy: r.Form.Get("triggered_by"),
+		Hook:        r.Form.Get("hook"),
+		Node:        r.Form.Get("node"),
+		Sort:        r.Form.Get("sort"),
+		Cursor:      r.Form.Get("cursor"),
+	}
+
+	for _, status := range r.Form["status"] {
+		for _, st := range strings.Split(status, ",") {
+			if st != "" {
+				s.Status = append(s.Status, st)
+			}
+		}
+	}
+
+	for _, tag := range r.Form["tag"] {
+		kv := strings.SplitN(tag, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return s, sdk.ErrWrongRequest
+		}
+		if s.Tags == nil {
+			s.Tags = map[string]string{}
+		}
+		s.Tags[kv[0]] = kv[1]
+	}
+
+	for param, t := range map[string]*time.Time{"from": &s.From, "to": &s.To} {
+		if v := r.Form.Get(param); v != "" {
+			d, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return s, sdk.ErrWrongRequest
+			}
+			*t = d
+		}
+	}
+
+	switch r.Form.Get("order") {
+	case "", "desc":
+	case "asc":
+		s.Ascending = true
+	default:
+		return s, sdk.ErrWrongRequest
+	}
+
+	if v := r.Form.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			return s, sdk.ErrWrongRequest
+		}
+		s.Limit = limit
+	}
+	return s, nil
+}
@@ -0,0 +1,194 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+// testsSince returns the start of the period of the tests analytics, given in days by the days parameter
+func testsSince(r *http.Request) (time.Time, error) {
+	days := 30
+	if s := r.FormValue("days"); s != "" {
+		d, err := strconv.Atoi(s)
+		if err != nil || d <= 0 {
+			return time.Time{}, sdk.ErrWrongRequest
+		}
+		days = d
+	}
+	return time.Now().AddDate(0, 0, -days), nil
+}
+
+func (api *API) getWorkflowTestsTrendsHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["permProjectKey"]
+		name := vars["workflowName"]
+
+		since, err := testsSince(r)
+		if err != nil {
+			return sdk.WrapError(err, "getWorkflowTestsTrendsHandler> Invalid days")
+		}
+
+		wf, errW := workflow.Load(api.mustDB(), api.Cache, key, name, getUser(ctx))
+		if errW != nil {
+			return sdk.WrapError(errW, "getWorkflowTestsTrendsHandler> Cannot load workflow")
+		}
+
+		trends, errT := workflow.LoadTestSuiteTrends(api.mustDB(), wf.ID, since)
+		if errT != nil {
+			return sdk.WrapError(errT, "getWorkflowTestsTrendsHandler> Cannot load trends")
+		}
+		return WriteJSON(w, r, trends, http.StatusOK)
+	}
+}
+
+func (api *API) getWorkflowTestsFlakyHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["permProjectKey"]
+		name := vars["workflowName"]
+
+		since, err := testsSince(r)
+		if err != nil {
+			return sdk.WrapError(err, "getWorkflowTestsFlakyHandler> Invalid days")
+		}
+
+		wf, errW := workflow.Load(api.mustDB(), api.Cache, key, name, getUser(ctx))
+		if errW != nil {
+			return sdk.WrapError(errW, "getWorkflowTestsFlakyHandler> Cannot load workflow")
+		}
+
+		flaky, errF := workflow.LoadFlakyTests(api.mustDB(), wf.ID, since)
+		if errF != nil {
+			return sdk.WrapError(errF, "getWorkflowTestsFlakyHandler> Cannot load flaky tests")
+		}
+		return WriteJSON(w, r, flaky, http.StatusOK)
+	}
+}
+
+func (api *API) getWorkflowTestHistoryHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["permProjectKey"]
+		name := vars["workflowName"]
+
+		suite := r.FormValue("suite")
+		testName := r.FormValue("name")
+		if suite == "" || testName == "" {
+			return sdk.WrapError(sdk.ErrWrongRequest, "getWorkflowTestHistoryHandler> suite and name are mandatory")
+		}
+		limit := 50
+		if s := r.FormValue("limit"); s != "" {
+			l, err := strconv.Atoi(s)
+			if err != nil || l <= 0 {
+				return sdk.WrapError(sdk.ErrWrongRequest, "getWorkflowTestHistoryHandler> Invalid limit %s", s)
+			}
+			limit = l
+		}
+
+		wf, errW := workflow.Load(api.mustDB(), api.Cache, key, name, getUser(ctx))
+		if errW != nil {
+			return sdk.WrapError(errW, "getWorkflowTestHistoryHandler> Cannot load workflow")
+		}
+
+		history, errH := workflow.LoadTestCaseHistory(api.mustDB(), wf.ID, suite, testName, limit)
+		if errH != nil {
+			return sdk.WrapError(errH, "getWorkflowTestHistoryHandler> Cannot load history")
+		}
+		return WriteJSON(w, r, history, http.StatusOK)
+	}
+}
+
+func (api *API) getWorkflowTestsQuarantineHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["permProjectKey"]
+		name := vars["workflowName"]
+
+		wf, errW := workflow.Load(api.mustDB(), api.Cache, key, name, getUser(ctx))
+		if errW != nil {
+			return sdk.WrapError(errW, "getWorkflowTestsQuarantineHandler> Cannot load workflow")
+		}
+
+		qs, errQ := workflow.LoadTestQuarantine(api.mustDB(), wf.ID)
+		if errQ != nil {
+			return sdk.WrapError(errQ, "getWorkflowTestsQuarantineHandler> Cannot load quarantine")
+		}
+		return WriteJSON(w, r, qs, http.StatusOK)
+	}
+}
+
+func (api *API) postWorkflowTestsQuarantineHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["permProjectKey"]
+		name := vars["workflowName"]
+
+		var q sdk.WorkflowTestQuarantine
+		if err := UnmarshalBody(r, &q); err != nil {
+			return sdk.WrapError(err, "postWorkflowTestsQuarantineHandler> Cannot unmarshal body")
+		}
+		if q.Suite == "" || q.Name == "" {
+			return sdk.WrapError(sdk.ErrWrongRequest, "postWorkflowTestsQuarantineHandler> suite and name are mandatory")
+		}
+
+		wf, errW := workflow.Load(api.mustDB(), api.Cache, key, name, getUser(ctx))
+		if errW != nil {
+			return sdk.WrapError(errW, "postWorkflowTestsQuarantineHandler> Cannot load workflow")
+		}
+
+		q.WorkflowID = wf.ID
+		q.Author = getUser(ctx).Username
+		if err := workflow.InsertTestQuarantine(api.mustDB(), &q); err != nil {
+			return sdk.WrapError(err, "postWorkflowTestsQuarantineHandler> Cannot insert quarantine")
+		}
+		return WriteJSON(w, r, q, http.StatusCreated)
+	}
+}
+
+func (api *API) deleteWorkflowTestsQuarantineHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["permProjectKey"]
+		name := vars["workflowName"]
+
+		id, errI := requestVarInt(r, "quarantineID")
+		if errI != nil {
+			return sdk.WrapError(sdk.ErrInvalidID, "deleteWorkflowTestsQuarantineHandler> Invalid quarantine id")
+		}
+
+		wf, errW := workflow.Load(api.mustDB(), api.Cache, key, name, getUser(ctx))
+		if errW != nil {
+			return sdk.WrapError(errW, "deleteWorkflowTestsQuarantineHandler> Cannot load workflow")
+		}
+
+		if err := workflow.DeleteTestQuarantine(api.mustDB(), wf.ID, id); err != nil {
+			return sdk.WrapError(err, "deleteWorkflowTestsQuarantineHandler> Cannot delete quarantine")
+		}
+		return nil
+	}
+}
+
+// getWorkflowJobTestsQuarantineHandler returns the quarantined tests of the workflow of a job, to the worker
+// which runs it
+func (api *API) getWorkflowJobTestsQuarantineHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id, errI := requestVarInt(r, "id")
+		if errI != nil {
+			return sdk.WrapError(errI, "getWorkflowJobTestsQuarantineHandler> Invalid node job run ID")
+		}
+
+		qs, errQ := workflow.LoadTestQuarantineByNodeJobRunID(api.mustDB(), id)
+		if errQ != nil {
+			return sdk.WrapError(errQ, "getWorkflowJobTestsQuarantineHandler> Cannot load quarantine")
+		}
+		return WriteJSON(w, r, qs, http.StatusOK)
+	}
+}
@@ -0,0 +1,302 @@
+package workflowv0
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/poller"
+	"github.com/ovh/cds/engine/api/scheduler"
+	"github.com/ovh/cds/engine/api/trigger"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+// Legacy is the list of the triggers, pollers and schedulers of an application which are translated in
+// workflows by Migrate. Repository hooks are not part of it: they are called until the webhook is replaced in
+// the repository manager, so they must keep running
+type Legacy struct {
+	Triggers   []sdk.PipelineTrigger
+	Pollers    []sdk.RepositoryPoller
+	Schedulers []sdk.PipelineScheduler
+}
+
+type migration struct {
+	proj   *sdk.Project
+	app    *sdk.Application
+	report *sdk.ApplicationMigration
+	legacy *Legacy
+	// Hooks, pollers and schedulers are loaded by application and pipeline, so they are on all the roots of a
+	// pipeline run on several environments: they are translated only once
+	translated map[string]bool
+	// schedulers which run on another environment than the one of their root, they are reported if they are
+	// not translated on another root
+	otherEnv []sdk.PipelineScheduler
+}
+
+// Migrate converts the CD trees of an application to workflows, one workflow by root pipeline. Everything
+// which cannot be translated is listed in the report. workflows are the existing workflows of the project
+func Migrate(proj *sdk.Project, app *sdk.Application, trees []sdk.CDPipeline, workflows []sdk.Workflow) (*sdk.ApplicationMigration, *Legacy) {
+	m := &migration{
+		proj:       proj,
+		app:        app,
+		report:     &sdk.ApplicationMigration{Workflows: []sdk.Workflow{}},
+		legacy:     &Legacy{},
+		translated: map[string]bool{},
+	}
+
+	for _, root := range trees {
+		name := app.Name
+		if len(trees) > 1 {
+			name = app.Name + "-" + root.Pipeline.Name
+			if root.Environment.ID != sdk.DefaultEnv.ID {
+				name += "-" + root.Environment.Name
+			}
+		}
+
+		var exists bool
+		for _, w := range workflows {
+			if w.Name == name {
+				exists = true
+				break
+			}
+		}
+		if exists {
+			m.untranslated("pipeline %s: workflow %s already exists", m.describe(root), name)
+			continue
+		}
+
+		var params []sdk.Parameter
+		for _, ap := range app.Pipelines {
+			if ap.Pipeline.ID == root.Pipeline.ID {
+				params = ap.Parameters
+			}
+		}
+
+		w := sdk.Workflow{
+			Name:       name,
+			ProjectID:  proj.ID,
+			ProjectKey: proj.Key,
+			Root:       m.node(root, params, true),
+		}
+		m.report.Workflows = append(m.report.Workflows, w)
+	}
+
+	for _, s := range m.otherEnv {
+		if !m.translated[fmt.Sprintf("scheduler-%d", s.ID)] {
+			m.untranslated("scheduler %s of %s/%s: the pipeline is not a root on environment %s", s.Crontab, app.Name, pipelineName(trees, s.PipelineID), s.EnvironmentName)
+			m.translated[fmt.Sprintf("scheduler-%d", s.ID)] = true
+		}
+	}
+
+	return m.report, m.legacy
+}
+
+func pipelineName(trees []sdk.CDPipeline, id int64) string {
+	for _, t := range trees {
+		if t.Pipeline.ID == id {
+			return t.Pipeline.Name
+		}
+	}
+	return fmt.Sprintf("%d", id)
+}
+
+func (m *migration) untranslated(format string, args ...interface{}) {
+	m.report.Untranslated = append(m.report.Untranslated, fmt.Sprintf(format, args...))
+}
+
+// describe returns the application, pipeline and environment of a CD pipeline
+func (m *migration) describe(cd sdk.CDPipeline) string {
+	s := cd.Application.Name + "/" + cd.Pipeline.Name
+	if cd.Environment.ID != sdk.DefaultEnv.ID {
+		s += " on " + cd.Environment.Name
+	}
+	return s
+}
+
+// node converts a CD pipeline and its children to a workflow node
+func (m *migration) node(cd sdk.CDPipeline, params []sdk.Parameter, root bool) *sdk.WorkflowNode {
+	app := cd.Application
+	n := &sdk.WorkflowNode{
+		PipelineID: cd.Pipeline.ID,
+		Pipeline:   cd.Pipeline,
+		Context: &sdk.WorkflowNodeContext{
+			ApplicationID:             app.ID,
+			Application:               &app,
+			DefaultPipelineParameters: params,
+		},
+	}
+	if cd.Environment.ID != sdk.DefaultEnv.ID {
+		env := cd.Environment
+		n.Context.EnvironmentID = env.ID
+		n.Context.Environment = &env
+	}
+
+	m.hooks(cd, n, root)
+
+	for _, child := range cd.SubPipelines {
+		t := child.Trigger
+		if t.DestProject.ID != m.proj.ID {
+			m.untranslated("trigger from %s to %s: pipelines of project %s cannot be triggered by a workflow of project %s", m.describe(cd), m.describe(child), t.DestProject.Key, m.proj.Key)
+			continue
+		}
+
+		wt := sdk.WorkflowNodeTrigger{Manual: t.Manual}
+		for _, p := range t.Prerequisites {
+			c, ok := prerequisiteCondition(p)
+			if !ok {
+				m.untranslated("trigger from %s to %s: prerequisite %s=%s has no condition equivalent, the trigger is manual", m.describe(cd), m.describe(child), p.Parameter, p.ExpectedValue)
+				wt.Manual = true
+				continue
+			}
+			wt.Conditions = append(wt.Conditions, c)
+		}
+		wt.WorkflowDestNode = *m.node(child, t.Parameters, false)
+		n.Triggers = append(n.Triggers, wt)
+
+		if !t.Manual {
+			m.legacy.Triggers = append(m.legacy.Triggers, t)
+		}
+	}
+
+	return n
+}
+
+// hooks converts the hooks, poller and schedulers of a CD pipeline to hooks of a workflow node. Hooks always
+// start a workflow from its root, so only the ones of a root pipeline are translated
+func (m *migration) hooks(cd sdk.CDPipeline, n *sdk.WorkflowNode, root bool) {
+	for _, h := range cd.Hooks {
+		key := fmt.Sprintf("hook-%d", h.ID)
+		if m.translated[key] {
+			continue
+		}
+		m.translated[key] = true
+		switch {
+		case !root:
+			m.untranslated("hook of %s: hooks can only start a workflow from its root pipeline", m.describe(cd))
+		case !h.Enabled:
+			m.untranslated("hook of %s: the hook is disabled", m.describe(cd))
+		default:
+			n.Hooks = append(n.Hooks, sdk.WorkflowNodeHook{
+				WorkflowHookModel: *workflow.WebHookModel,
+				Config:            sdk.WorkflowNodeHookConfig{"method": "POST"},
+			})
+			m.untranslated("hook of %s: the webhook of repository %s/%s on %s must be replaced by the URL of the workflow webhook, the hook is kept enabled until then", m.describe(cd), h.Project, h.Repository, h.Host)
+		}
+	}
+
+	if p := cd.Poller; p != nil && !m.translated[fmt.Sprintf("poller-%d-%d", cd.Application.ID, cd.Pipeline.ID)] {
+		m.translated[fmt.Sprintf("poller-%d-%d", cd.Application.ID, cd.Pipeline.ID)] = true
+		switch {
+		case !root:
+			m.untranslated("poller of %s: pollers can only start a workflow from its root pipeline", m.describe(cd))
+		case !p.Enabled:
+			m.untranslated("poller of %s: the poller is disabled", m.describe(cd))
+		default:
+			n.Hooks = append(n.Hooks, sdk.WorkflowNodeHook{
+				WorkflowHookModel: *workflow.GitPollerModel,
+				Config:            sdk.WorkflowNodeHookConfig{},
+			})
+			legacy := *p
+			legacy.Application.ID = cd.Application.ID
+			legacy.Pipeline.ID = cd.Pipeline.ID
+			m.legacy.Pollers = append(m.legacy.Pollers, legacy)
+		}
+	}
+
+	for _, s := range cd.Schedulers {
+		key := fmt.Sprintf("scheduler-%d", s.ID)
+		if m.translated[key] {
+			continue
+		}
+		if root && s.EnvironmentID != cd.Environment.ID {
+			m.otherEnv = append(m.otherEnv, s)
+			continue
+		}
+		m.translated[key] = true
+		switch {
+		case !root:
+			m.untranslated("scheduler %s of %s: schedulers can only start a workflow from its root pipeline", s.Crontab, m.describe(cd))
+		case s.Disabled:
+			m.untranslated("scheduler %s of %s: the scheduler is disabled", s.Crontab, m.describe(cd))
+		default:
+			n.Hooks = append(n.Hooks, sdk.WorkflowNodeHook{
+				WorkflowHookModel: *workflow.SchedulerModel,
+				Config:            schedulerConfig(s),
+			})
+			m.legacy.Schedulers = append(m.legacy.Schedulers, s)
+		}
+	}
+}
+
+// schedulerConfig returns the configuration of a scheduler hook. The arguments of the scheduler are given
+// in the payload, which overrides the pipeline parameters
+func schedulerConfig(s sdk.PipelineScheduler) sdk.WorkflowNodeHookConfig {
+	timezone := s.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	config := sdk.WorkflowNodeHookConfig{
+		"cron":     s.Crontab,
+		"timezone": timezone,
+	}
+	for _, a := range s.Args {
+		name := a.Name
+		if !strings.HasPrefix(name, "cds.") && !strings.HasPrefix(name, "git.") {
+			name = "cds.pip." + name
+		}
+		config[name] = a.Value
+	}
+	return config
+}
+
+// prerequisiteCondition returns the condition equivalent to a trigger prerequisite, whose expected value is a
+// regular expression matching the whole value, or not matching it with the prefix "not "
+func prerequisiteCondition(p sdk.Prerequisite) (sdk.WorkflowTriggerCondition, bool) {
+	if strings.HasPrefix(p.ExpectedValue, "not ") {
+		value := strings.TrimPrefix(p.ExpectedValue, "not ")
+		// There is no negative regex condition, only a literal value can be translated
+		if regexp.QuoteMeta(value) != value {
+			return sdk.WorkflowTriggerCondition{}, false
+		}
+		return sdk.WorkflowTriggerCondition{
+			Variable: p.Parameter,
+			Operator: sdk.WorkflowConditionsOperatorNotEquals,
+			Value:    value,
+		}, true
+	}
+	return sdk.WorkflowTriggerCondition{
+		Variable: p.Parameter,
+		Operator: sdk.WorkflowConditionsOperatorRegex,
+		Value:    "^" + p.ExpectedValue + "$",
+	}, true
+}
+
+// DisableLegacy disables the triggers, pollers and schedulers translated by Migrate. Triggers become manual, as
+// they cannot be disabled
+func DisableLegacy(db gorp.SqlExecutor, l *Legacy) error {
+	for i := range l.Triggers {
+		t := &l.Triggers[i]
+		t.Manual = true
+		if err := trigger.UpdateTrigger(db, t); err != nil {
+			return sdk.WrapError(err, "DisableLegacy> Cannot update trigger %d", t.ID)
+		}
+	}
+	for i := range l.Pollers {
+		p := &l.Pollers[i]
+		p.Enabled = false
+		if err := poller.Update(db, p); err != nil {
+			return sdk.WrapError(err, "DisableLegacy> Cannot update poller %s", p.Name)
+		}
+	}
+	for i := range l.Schedulers {
+		s := &l.Schedulers[i]
+		s.Disabled = true
+		if err := scheduler.Update(db, s); err != nil {
+			return sdk.WrapError(err, "DisableLegacy> Cannot update scheduler %d", s.ID)
+		}
+	}
+	return nil
+}
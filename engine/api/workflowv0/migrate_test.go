@@ -0,0 +1,99 @@
+package workflowv0
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestMigrate(t *testing.T) {
+	proj := &sdk.Project{ID: 1, Key: "PROJ"}
+	app := &sdk.Application{ID: 10, Name: "app"}
+	prod := sdk.Environment{ID: 100, Name: "prod"}
+
+	build := sdk.CDPipeline{
+		Project:     *proj,
+		Application: *app,
+		Pipeline:    sdk.Pipeline{ID: 20, Name: "build"},
+		Environment: sdk.DefaultEnv,
+		Hooks:       []sdk.Hook{{ID: 1, Enabled: true, Host: "stash", Project: "PRJ", Repository: "repo"}},
+		Schedulers: []sdk.PipelineScheduler{
+			{ID: 1, EnvironmentID: sdk.DefaultEnv.ID, Crontab: "0 2 * * *", Args: []sdk.Parameter{{Name: "mode", Value: "nightly"}}},
+			{ID: 2, EnvironmentID: sdk.DefaultEnv.ID, Crontab: "0 3 * * *", Disabled: true},
+		},
+		SubPipelines: []sdk.CDPipeline{
+			{
+				Application: *app,
+				Pipeline:    sdk.Pipeline{ID: 21, Name: "deploy"},
+				Environment: prod,
+				Trigger: sdk.PipelineTrigger{
+					ID:          1,
+					DestProject: *proj,
+					Parameters:  []sdk.Parameter{{Name: "target", Value: "prod"}},
+					Prerequisites: []sdk.Prerequisite{
+						{Parameter: "git.branch", ExpectedValue: "master"},
+						{Parameter: "cds.pip.skip", ExpectedValue: "not true"},
+					},
+				},
+				SubPipelines: []sdk.CDPipeline{
+					{
+						Application: sdk.Application{ID: 11, Name: "other"},
+						Pipeline:    sdk.Pipeline{ID: 22, Name: "check"},
+						Environment: sdk.DefaultEnv,
+						Trigger: sdk.PipelineTrigger{
+							ID:            2,
+							DestProject:   *proj,
+							Prerequisites: []sdk.Prerequisite{{Parameter: "git.branch", ExpectedValue: "not release/.*"}},
+						},
+					},
+					{
+						Trigger: sdk.PipelineTrigger{ID: 3, DestProject: sdk.Project{ID: 2, Key: "OTHER"}},
+					},
+				},
+			},
+		},
+	}
+
+	report, legacy := Migrate(proj, app, []sdk.CDPipeline{build}, nil)
+	assert.Len(t, report.Workflows, 1)
+
+	w := report.Workflows[0]
+	assert.Equal(t, "app", w.Name)
+	assert.Equal(t, "PROJ", w.ProjectKey)
+	assert.Equal(t, int64(20), w.Root.PipelineID)
+	assert.Nil(t, w.Root.Context.Environment)
+	assert.Len(t, w.Root.Hooks, 2)
+	assert.Equal(t, "WebHook", w.Root.Hooks[0].WorkflowHookModel.Name)
+	assert.Equal(t, "Scheduler", w.Root.Hooks[1].WorkflowHookModel.Name)
+	assert.Equal(t, "0 2 * * *", w.Root.Hooks[1].Config["cron"])
+	assert.Equal(t, "UTC", w.Root.Hooks[1].Config["timezone"])
+	assert.Equal(t, "nightly", w.Root.Hooks[1].Config["cds.pip.mode"])
+
+	assert.Len(t, w.Root.Triggers, 1)
+	deploy := w.Root.Triggers[0]
+	assert.False(t, deploy.Manual)
+	assert.Equal(t, []sdk.WorkflowTriggerCondition{
+		{Variable: "git.branch", Operator: sdk.WorkflowConditionsOperatorRegex, Value: "^master$"},
+		{Variable: "cds.pip.skip", Operator: sdk.WorkflowConditionsOperatorNotEquals, Value: "true"},
+	}, deploy.Conditions)
+	assert.Equal(t, int64(100), deploy.WorkflowDestNode.Context.EnvironmentID)
+	assert.Equal(t, "target", deploy.WorkflowDestNode.Context.DefaultPipelineParameters[0].Name)
+
+	assert.Len(t, deploy.WorkflowDestNode.Triggers, 1)
+	check := deploy.WorkflowDestNode.Triggers[0]
+	assert.True(t, check.Manual)
+	assert.Equal(t, int64(11), check.WorkflowDestNode.Context.ApplicationID)
+
+	// webhook URL, disabled scheduler, negative regex and trigger to another project
+	assert.Len(t, report.Untranslated, 4)
+
+	assert.Len(t, legacy.Triggers, 2)
+	assert.Contains(t, report.Untranslated[0], "the hook is kept enabled")
+	assert.Len(t, legacy.Schedulers, 1)
+
+	report, _ = Migrate(proj, app, []sdk.CDPipeline{build}, []sdk.Workflow{{Name: "app"}})
+	assert.Len(t, report.Workflows, 0)
+	assert.Len(t, report.Untranslated, 1)
+}
@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api"
+	"github.com/ovh/cds/sdk/cdsclient"
+	"github.com/ovh/cds/sdk/hatchery"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// New returns a new service
+func New() *Service {
+	s := new(Service)
+	s.Router = &api.Router{
+		Mux: mux.NewRouter(),
+	}
+	return s
+}
+
+// ApplyConfiguration apply an object of type cache.Configuration after checking it
+func (s *Service) ApplyConfiguration(config interface{}) error {
+	if err := s.CheckConfiguration(config); err != nil {
+		return err
+	}
+	var ok bool
+	s.Cfg, ok = config.(Configuration)
+	if !ok {
+		return fmt.Errorf("Invalid configuration")
+	}
+	return nil
+}
+
+// CheckConfiguration checks the validity of the configuration object
+func (s *Service) CheckConfiguration(config interface{}) error {
+	sConfig, ok := config.(Configuration)
+	if !ok {
+		return fmt.Errorf("Invalid configuration")
+	}
+
+	if sConfig.URL == "" {
+		return fmt.Errorf("your CDS configuration seems to be empty. Please use environment variables, file or Consul to set your configuration")
+	}
+
+	if sConfig.Storage.Directory == "" {
+		return fmt.Errorf("storage directory is mandatory")
+	}
+
+	return nil
+}
+
+// Serve will start the http cache server
+func (s *Service) Serve(c context.Context) error {
+	if s.Cfg.Name == "" {
+		s.Cfg.Name = hatchery.GenerateName("cache", "")
+	}
+
+	ctx, cancel := context.WithCancel(c)
+	defer cancel()
+
+	log.Info("Cache> Starting service %s...", s.Cfg.Name)
+
+	//Instanciate a cds client
+	s.cds = cdsclient.NewService(s.Cfg.API.HTTP.URL)
+
+	//Init the store
+	var errStore error
+	s.Store, errStore = newDiskStore(s.Cfg.Storage.Directory, s.Cfg.Storage.MaxSize*1024*1024)
+	if errStore != nil {
+		return errStore
+	}
+	entries, size := s.Store.Stats()
+	log.Info("Cache> %d resources (%d bytes) loaded from %s", entries, size, s.Cfg.Storage.Directory)
+
+	s.client = &http.Client{Timeout: 10 * time.Minute}
+
+	//First register(heartbeat)
+	if err := s.doHeartbeat(); err != nil {
+		log.Error("Cache> Unable to register: %v", err)
+		return err
+	}
+	log.Info("Cache> Service registered")
+
+	//Start the heartbeat gorourine
+	go func() {
+		if err := s.heartbeat(ctx); err != nil {
+			log.Error("%v", err)
+			cancel()
+		}
+	}()
+
+	//Init the http server
+	s.initRouter(ctx)
+	server := &http.Server{
+		Addr:           fmt.Sprintf(":%d", s.Cfg.HTTP.Port),
+		Handler:        s.Router.Mux,
+		ReadTimeout:    10 * time.Minute,
+		WriteTimeout:   10 * time.Minute,
+		MaxHeaderBytes: 1 << 20,
+	}
+
+	//Gracefully shutdown the http server
+	go func() {
+		select {
+		case <-ctx.Done():
+			log.Info("Cache> Shutdown HTTP Server")
+			server.Shutdown(ctx)
+		}
+	}()
+
+	//Start the http server
+	log.Info("Cache> Starting HTTP Server on port %d", s.Cfg.HTTP.Port)
+	if err := server.ListenAndServe(); err != nil {
+		log.Fatalf("Cache> Cannot start cds-cache: %s", err)
+	}
+
+	return ctx.Err()
+}
@@ -0,0 +1,144 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/ovh/cds/engine/api"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// upstreamPath returns the path of a request relative to the prefix of a proxy, with its query
+func upstreamPath(r *http.Request, prefix string) string {
+	p := strings.TrimPrefix(r.URL.EscapedPath(), prefix)
+	if r.URL.RawQuery != "" {
+		p += "?" + r.URL.RawQuery
+	}
+	return p
+}
+
+// goCacheable returns true for the immutable resources of a Go modules proxy: the versions
+// of a module. Lists of versions and latest versions change
+func goCacheable(p string) bool {
+	if !strings.Contains(p, "/@v/") {
+		return false
+	}
+	switch path.Ext(p) {
+	case ".info", ".mod", ".zip":
+		return true
+	}
+	return false
+}
+
+// npmCacheable returns true for package tarballs. Packages documents change with new versions
+func npmCacheable(p string) bool {
+	return strings.Contains(p, "/-/") && strings.HasSuffix(p, ".tgz")
+}
+
+// mavenCacheable returns true for released artifacts. Metadata and snapshots change
+func mavenCacheable(p string) bool {
+	return !strings.HasPrefix(path.Base(p), "maven-metadata") && !strings.Contains(p, "-SNAPSHOT")
+}
+
+func (s *Service) goProxyHandler() api.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		p := upstreamPath(r, "/go")
+		return s.proxy(ctx, w, r, strings.TrimSuffix(s.Cfg.Upstreams.GoProxy, "/")+p, goCacheable(p), nil)
+	}
+}
+
+func (s *Service) npmProxyHandler() api.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		p := upstreamPath(r, "/npm")
+		upstream := strings.TrimSuffix(s.Cfg.Upstreams.NPM, "/")
+		// Packages documents link tarballs on the registry, they are rewritten to be downloaded through the cache
+		rewrite := func(btes []byte) []byte {
+			return bytes.Replace(btes, []byte(upstream+"/"), []byte(strings.TrimSuffix(s.Cfg.URL, "/")+"/npm/"), -1)
+		}
+		return s.proxy(ctx, w, r, upstream+p, npmCacheable(p), rewrite)
+	}
+}
+
+func (s *Service) mavenProxyHandler() api.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		p := upstreamPath(r, "/maven")
+		return s.proxy(ctx, w, r, strings.TrimSuffix(s.Cfg.Upstreams.Maven, "/")+p, mavenCacheable(p), nil)
+	}
+}
+
+// proxy serves a resource from the store, or from the upstream. Cacheable resources are stored while they are
+// served, other resources are rewritten if needed. HEAD requests only send the headers, and never store anything
+func (s *Service) proxy(ctx context.Context, w http.ResponseWriter, r *http.Request, url string, cacheable bool, rewrite func([]byte) []byte) error {
+	head := r.Method == http.MethodHead
+	if cacheable {
+		if e, content, ok := s.Store.Get(url); ok {
+			defer content.Close()
+			log.Debug("cache.proxy> HIT %s", url)
+			w.Header().Set("Content-Type", e.ContentType)
+			w.Header().Set("X-Cache", "HIT")
+			w.WriteHeader(http.StatusOK)
+			if head {
+				return nil
+			}
+			_, err := io.Copy(w, content)
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(r.Method, url, nil)
+	if err != nil {
+		return sdk.WrapError(sdk.ErrWrongRequest, "proxy> Invalid url %s: %v", url, err)
+	}
+	req = req.WithContext(ctx)
+	if accept := r.Header.Get("Accept"); accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return sdk.WrapError(err, "proxy> Unable to get %s", url)
+	}
+	defer resp.Body.Close()
+
+	w.Header().Set("Content-Type", resp.Header.Get("Content-Type"))
+	w.Header().Set("X-Cache", "MISS")
+
+	if head {
+		w.WriteHeader(resp.StatusCode)
+		return nil
+	}
+
+	if resp.StatusCode != http.StatusOK || !cacheable {
+		var body io.Reader = resp.Body
+		if rewrite != nil && resp.StatusCode == http.StatusOK {
+			btes, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				return sdk.WrapError(err, "proxy> Unable to read %s", url)
+			}
+			body = bytes.NewReader(rewrite(btes))
+		}
+		w.WriteHeader(resp.StatusCode)
+		_, err := io.Copy(w, body)
+		return err
+	}
+
+	log.Debug("cache.proxy> MISS %s", url)
+	w.WriteHeader(http.StatusOK)
+	if err := s.Store.Put(url, resp.Header.Get("Content-Type"), resp.Body, w); err != nil {
+		log.Warning("cache.proxy> %v", err)
+	}
+	return nil
+}
+
+func (s *Service) getStatsHandler() api.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		entries, size := s.Store.Stats()
+		return api.WriteJSON(w, r, Stats{Entries: entries, Size: size, MaxSize: s.Cfg.Storage.MaxSize * 1024 * 1024}, http.StatusOK)
+	}
+}
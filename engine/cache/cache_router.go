@@ -0,0 +1,21 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/ovh/cds/engine/api"
+)
+
+func (s *Service) initRouter(ctx context.Context) {
+	r := s.Router
+	r.Background = ctx
+	r.URL = s.Cfg.URL
+	r.Middlewares = append(r.Middlewares, s.authMiddleware)
+
+	// Package managers do not authenticate on the proxies
+	r.Handle("/go/{path:.*}", r.GET(s.goProxyHandler, api.Auth(false)), r.HEAD(s.goProxyHandler, api.Auth(false)))
+	r.Handle("/npm/{path:.*}", r.GET(s.npmProxyHandler, api.Auth(false)), r.HEAD(s.npmProxyHandler, api.Auth(false)))
+	r.Handle("/maven/{path:.*}", r.GET(s.mavenProxyHandler, api.Auth(false)), r.HEAD(s.mavenProxyHandler, api.Auth(false)))
+
+	r.Handle("/stats", r.GET(s.getStatsHandler))
+}
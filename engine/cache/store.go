@@ -0,0 +1,231 @@
+package cache
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ovh/cds/sdk/log"
+)
+
+// entry is a cached resource. Its content is stored in a blob named by the sha256 of the content,
+// so a same content downloaded from several URLs is stored once
+type entry struct {
+	Key         string    `json:"key"`
+	URL         string    `json:"url"`
+	Blob        string    `json:"blob"`
+	Size        int64     `json:"size"`
+	ContentType string    `json:"content_type"`
+	LastAccess  time.Time `json:"last_access"`
+}
+
+// diskStore is a content addressed store on a local disk. Least recently used entries are evicted
+// when the size of the blobs exceeds the maximum size
+type diskStore struct {
+	mutex   sync.Mutex
+	dir     string
+	maxSize int64
+	size    int64
+	lru     *list.List
+	entries map[string]*list.Element
+	blobs   map[string]int
+}
+
+// newDiskStore loads the entries stored in a directory
+func newDiskStore(dir string, maxSize int64) (*diskStore, error) {
+	d := &diskStore{
+		dir:     dir,
+		maxSize: maxSize,
+		lru:     list.New(),
+		entries: map[string]*list.Element{},
+		blobs:   map[string]int{},
+	}
+	for _, sub := range []string{"entries", "blobs", "tmp"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			return nil, fmt.Errorf("newDiskStore> Unable to create directory: %v", err)
+		}
+	}
+
+	files, err := ioutil.ReadDir(filepath.Join(dir, "entries"))
+	if err != nil {
+		return nil, fmt.Errorf("newDiskStore> Unable to read entries: %v", err)
+	}
+	entries := []entry{}
+	for _, f := range files {
+		btes, err := ioutil.ReadFile(filepath.Join(dir, "entries", f.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("newDiskStore> Unable to read entry %s: %v", f.Name(), err)
+		}
+		var e entry
+		if err := json.Unmarshal(btes, &e); err != nil {
+			log.Warning("newDiskStore> Invalid entry %s removed: %v", f.Name(), err)
+			os.Remove(filepath.Join(dir, "entries", f.Name()))
+			continue
+		}
+		// The access time of an entry is the modification time of its file, see Get
+		if f.ModTime().After(e.LastAccess) {
+			e.LastAccess = f.ModTime()
+		}
+		if _, err := os.Stat(d.blobPath(e.Blob)); err != nil {
+			os.Remove(filepath.Join(dir, "entries", f.Name()))
+			continue
+		}
+		entries = append(entries, e)
+	}
+
+	// Most recently used first
+	sort.Slice(entries, func(i, j int) bool { return entries[i].LastAccess.After(entries[j].LastAccess) })
+	for _, e := range entries {
+		d.add(e)
+	}
+	d.evict()
+	return d, nil
+}
+
+// storeKey returns the key of the resource of an URL
+func storeKey(url string) string {
+	h := sha256.Sum256([]byte(url))
+	return hex.EncodeToString(h[:])
+}
+
+func (d *diskStore) entryPath(key string) string {
+	return filepath.Join(d.dir, "entries", key)
+}
+
+func (d *diskStore) blobPath(blob string) string {
+	return filepath.Join(d.dir, "blobs", blob)
+}
+
+// add indexes an entry as the least recently used
+func (d *diskStore) add(e entry) {
+	if d.blobs[e.Blob] == 0 {
+		d.size += e.Size
+	}
+	d.blobs[e.Blob]++
+	d.entries[e.Key] = d.lru.PushBack(e)
+}
+
+// remove removes an entry, and its blob if it is not used anymore
+func (d *diskStore) remove(el *list.Element) {
+	e := el.Value.(entry)
+	d.lru.Remove(el)
+	delete(d.entries, e.Key)
+	os.Remove(d.entryPath(e.Key))
+
+	d.blobs[e.Blob]--
+	if d.blobs[e.Blob] <= 0 {
+		delete(d.blobs, e.Blob)
+		d.size -= e.Size
+		os.Remove(d.blobPath(e.Blob))
+	}
+}
+
+// evict removes the least recently used entries until the store fits its maximum size
+func (d *diskStore) evict() {
+	for d.maxSize > 0 && d.size > d.maxSize && d.lru.Len() > 0 {
+		e := d.lru.Back().Value.(entry)
+		log.Debug("diskStore.evict> %s", e.URL)
+		d.remove(d.lru.Back())
+	}
+}
+
+// Get opens the content of the resource of an URL
+func (d *diskStore) Get(url string) (*entry, io.ReadCloser, bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	el, ok := d.entries[storeKey(url)]
+	if !ok {
+		return nil, nil, false
+	}
+	e := el.Value.(entry)
+	f, err := os.Open(d.blobPath(e.Blob))
+	if err != nil {
+		log.Warning("diskStore.Get> Unable to open blob of %s: %v", url, err)
+		d.remove(el)
+		return nil, nil, false
+	}
+
+	e.LastAccess = time.Now()
+	el.Value = e
+	d.lru.MoveToFront(el)
+	// Touch the entry file instead of rewriting it on every hit
+	if err := os.Chtimes(d.entryPath(e.Key), e.LastAccess, e.LastAccess); err != nil {
+		log.Warning("diskStore.Get> Unable to update access time of %s: %v", url, err)
+	}
+	return &e, f, true
+}
+
+// Put stores the content of the resource of an URL while copying it to w
+func (d *diskStore) Put(url, contentType string, r io.Reader, w io.Writer) error {
+	tmp, err := ioutil.TempFile(filepath.Join(d.dir, "tmp"), "blob")
+	if err != nil {
+		return fmt.Errorf("diskStore.Put> Unable to create file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, h, w), r)
+	if errClose := tmp.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		return fmt.Errorf("diskStore.Put> Unable to write %s: %v", url, err)
+	}
+
+	e := entry{
+		Key:         storeKey(url),
+		URL:         url,
+		Blob:        hex.EncodeToString(h.Sum(nil)),
+		Size:        size,
+		ContentType: contentType,
+		LastAccess:  time.Now(),
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.maxSize > 0 && e.Size > d.maxSize {
+		return nil
+	}
+	if el, ok := d.entries[e.Key]; ok {
+		d.remove(el)
+	}
+	if d.blobs[e.Blob] == 0 {
+		if err := os.Rename(tmp.Name(), d.blobPath(e.Blob)); err != nil {
+			return fmt.Errorf("diskStore.Put> Unable to store blob of %s: %v", url, err)
+		}
+	}
+	d.add(e)
+	d.lru.MoveToFront(d.entries[e.Key])
+	d.saveEntry(e)
+	d.evict()
+	return nil
+}
+
+// saveEntry writes an entry on disk so that the store survives restarts
+func (d *diskStore) saveEntry(e entry) {
+	btes, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	if err := ioutil.WriteFile(d.entryPath(e.Key), btes, 0644); err != nil {
+		log.Warning("diskStore.saveEntry> Unable to save entry of %s: %v", e.URL, err)
+	}
+}
+
+// Stats returns the number of entries and the size of the store
+func (d *diskStore) Stats() (int, int64) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.lru.Len(), d.size
+}
@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiskStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cds-cache")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	d, err := newDiskStore(dir, 10)
+	assert.NoError(t, err)
+
+	get := func(url string) string {
+		_, content, ok := d.Get(url)
+		if !ok {
+			return ""
+		}
+		defer content.Close()
+		btes, _ := ioutil.ReadAll(content)
+		return string(btes)
+	}
+
+	// Resources are served while they are stored
+	out := new(bytes.Buffer)
+	assert.NoError(t, d.Put("http://a", "text/plain", strings.NewReader("abcd"), out))
+	assert.Equal(t, "abcd", out.String())
+	assert.Equal(t, "abcd", get("http://a"))
+
+	// A same content is stored once
+	assert.NoError(t, d.Put("http://b", "text/plain", strings.NewReader("abcd"), ioutil.Discard))
+	entries, size := d.Stats()
+	assert.Equal(t, 2, entries)
+	assert.Equal(t, int64(4), size)
+
+	// Least recently used resources are evicted
+	assert.NoError(t, d.Put("http://c", "text/plain", strings.NewReader("efgh"), ioutil.Discard))
+	get("http://a")
+	assert.NoError(t, d.Put("http://d", "text/plain", strings.NewReader("ijkl"), ioutil.Discard))
+	assert.Equal(t, "abcd", get("http://a"))
+	assert.Equal(t, "", get("http://b"))
+	assert.Equal(t, "", get("http://c"))
+	assert.Equal(t, "ijkl", get("http://d"))
+
+	// Resources bigger than the store are not stored
+	assert.NoError(t, d.Put("http://e", "text/plain", strings.NewReader("0123456789ab"), ioutil.Discard))
+	assert.Equal(t, "", get("http://e"))
+
+	// The store is loaded on startup
+	d, err = newDiskStore(dir, 10)
+	assert.NoError(t, err)
+	entries, size = d.Stats()
+	assert.Equal(t, 2, entries)
+	assert.Equal(t, int64(8), size)
+	assert.Equal(t, "ijkl", get("http://d"))
+
+	// Accesses are kept across restarts
+	assert.Equal(t, "abcd", get("http://a"))
+	d, err = newDiskStore(dir, 10)
+	assert.NoError(t, err)
+	assert.NoError(t, d.Put("http://f", "text/plain", strings.NewReader("mnop"), ioutil.Discard))
+	assert.Equal(t, "abcd", get("http://a"))
+	assert.Equal(t, "", get("http://d"))
+}
+
+func TestCacheable(t *testing.T) {
+	assert.True(t, goCacheable("/github.com/ovh/cds/@v/v1.0.0.zip"))
+	assert.True(t, goCacheable("/github.com/ovh/cds/@v/v1.0.0.info"))
+	assert.False(t, goCacheable("/github.com/ovh/cds/@v/list"))
+	assert.False(t, goCacheable("/github.com/ovh/cds/@latest"))
+
+	assert.True(t, npmCacheable("/lodash/-/lodash-4.17.4.tgz"))
+	assert.False(t, npmCacheable("/lodash"))
+
+	assert.True(t, mavenCacheable("/org/ovh/lib/1.0/lib-1.0.jar"))
+	assert.False(t, mavenCacheable("/org/ovh/lib/maven-metadata.xml"))
+	assert.False(t, mavenCacheable("/org/ovh/lib/1.0-SNAPSHOT/lib-1.0-20170101.jar"))
+}
@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"net/http"
+
+	"github.com/ovh/cds/engine/api"
+	"github.com/ovh/cds/sdk/cdsclient"
+)
+
+// Service is the stuct representing a cache µService
+type Service struct {
+	Cfg    Configuration
+	Router *api.Router
+	Store  *diskStore
+	cds    cdsclient.Interface
+	client *http.Client
+	hash   string
+}
+
+// Configuration is the cache configuration structure
+type Configuration struct {
+	Name string `toml:"name" comment:"Name of this CDS Cache Service"`
+	HTTP struct {
+		Port int `toml:"port" default:"8084"`
+	} `toml:"http" comment:"######################\n CDS Cache HTTP Configuration \n######################\n"`
+	URL     string `default:"http://localhost:8084" comment:"URL of this service, it must be reachable by workers"`
+	Storage struct {
+		Directory string `toml:"directory" default:"/tmp/cds/cache" comment:"Directory where cached resources are stored"`
+		MaxSize   int64  `toml:"maxSize" default:"10240" comment:"Maximum size of the cache in MB, least recently used resources are evicted"`
+	} `toml:"storage" comment:"######################\n CDS Cache Storage Settings \n######################\n"`
+	Upstreams struct {
+		GoProxy string `toml:"goproxy" default:"https://proxy.golang.org" comment:"Go modules proxy"`
+		NPM     string `toml:"npm" default:"https://registry.npmjs.org" comment:"NPM registry"`
+		Maven   string `toml:"maven" default:"https://repo1.maven.org/maven2" comment:"Maven repository"`
+	} `toml:"upstreams" comment:"######################\n CDS Cache Upstreams \n######################\n"`
+	API struct {
+		HTTP struct {
+			URL      string `toml:"url" default:"http://localhost:8081"`
+			Insecure bool   `toml:"insecure" commented:"true"`
+		} `toml:"http"`
+		Token                string `toml:"token" default:"************"`
+		RequestTimeout       int    `toml:"requestTimeout" default:"10"`
+		MaxHeartbeatFailures int    `toml:"maxHeartbeatFailures" default:"10"`
+	} `toml:"api" comment:"######################\n CDS API Settings \n######################\n"`
+}
+
+// Stats is the status of the cache
+type Stats struct {
+	Entries int   `json:"entries"`
+	Size    int64 `json:"size"`
+	MaxSize int64 `json:"max_size"`
+}
@@ -88,12 +88,18 @@ func (*HatcheryDocker) ModelType() string {
 }
 
 // CanSpawn return wether or not hatchery can spawn model
-// requirement are not supported
+// memory requirement are not supported
 func (h *HatcheryDocker) CanSpawn(model *sdk.Model, jobID int64, requirements []sdk.Requirement) bool {
 	for _, r := range requirements {
-		if r.Type == sdk.ServiceRequirement || r.Type == sdk.MemoryRequirement {
+		if r.Type == sdk.MemoryRequirement {
 			return false
 		}
+		if r.Type == sdk.ServiceRequirement {
+			if _, err := sdk.NewJobService(r); err != nil {
+				log.Warning("CanSpawn> %s", err)
+				return false
+			}
+		}
 	}
 	return true
 }
@@ -231,6 +237,11 @@ func (h *HatcheryDocker) SpawnWorker(wm *sdk.Model, jobID int64, requirements []
 		log.Info("spawnWorker> spawning worker %s (%s) - %s", wm.Name, wm.Image, logInfo)
 	}
 
+	image, errImage := h.modelImage(wm)
+	if errImage != nil {
+		return "", errImage
+	}
+
 	name, errs := randSeq(16)
 	if errs != nil {
 		return "", fmt.Errorf("cannot create worker name: %s", errs)
@@ -263,6 +274,10 @@ func (h *HatcheryDocker) SpawnWorker(wm *sdk.Model, jobID int64, requirements []
 	if h.Configuration().Provision.WorkerLogsOptions.Graylog.ExtraValue != "" {
 		args = append(args, "-e", fmt.Sprintf("CDS_GRAYLOG_EXTRA_VALUE=%s", h.Configuration().Provision.WorkerLogsOptions.Graylog.ExtraValue))
 	}
+	if h.Configuration().Tracing.Enable {
+		args = append(args, "-e", fmt.Sprintf("CDS_TRACING_EXPORTER=%s", h.Configuration().Tracing.Exporter))
+		args = append(args, "-e", fmt.Sprintf("CDS_TRACING_ENDPOINT=%s", h.Configuration().Tracing.Endpoint))
+	}
 	if h.Configuration().API.GRPC.URL != "" && wm.Communication == sdk.GRPC {
 		args = append(args, "-e", fmt.Sprintf("CDS_GRPC_API=%s", h.Configuration().API.GRPC.URL))
 		args = append(args, "-e", fmt.Sprintf("CDS_GRPC_INSECURE=%t", h.Configuration().API.GRPC.Insecure))
@@ -275,7 +290,23 @@ func (h *HatcheryDocker) SpawnWorker(wm *sdk.Model, jobID int64, requirements []
 	if h.Config.DockerAddHost != "" {
 		args = append(args, fmt.Sprintf("--add-host=%s", h.Config.DockerAddHost))
 	}
-	args = append(args, wm.Image)
+
+	// Services are started on a network dedicated to the worker
+	var services []string
+	var network string
+	if jobID > 0 {
+		var err error
+		services, network, err = h.startServices(name, requirements)
+		if err != nil {
+			h.removeServices(services, network)
+			return "", err
+		}
+		if network != "" {
+			args = append(args, fmt.Sprintf("--network=%s", network))
+		}
+	}
+
+	args = append(args, image)
 	args = append(args, "sh", "-c", fmt.Sprintf("rm -f worker && echo 'Download worker' && curl %s/download/worker/`uname -m` -o worker && echo 'chmod worker' && chmod +x worker && echo 'starting worker' && ./worker", h.Client().APIURL()))
 
 	if registerOnly {
@@ -285,6 +316,7 @@ func (h *HatcheryDocker) SpawnWorker(wm *sdk.Model, jobID int64, requirements []
 	log.Debug("Running %s", cmd.Args)
 
 	if err := cmd.Start(); err != nil {
+		h.removeServices(services, network)
 		return "", err
 	}
 	h.Lock()
@@ -295,6 +327,7 @@ func (h *HatcheryDocker) SpawnWorker(wm *sdk.Model, jobID int64, requirements []
 	// ProcessState is then checked in nextAvailableLocalID
 	go func() {
 		cmd.Wait()
+		h.removeServices(services, network)
 	}()
 
 	// Do not spam docker daemon
@@ -302,6 +335,111 @@ func (h *HatcheryDocker) SpawnWorker(wm *sdk.Model, jobID int64, requirements []
 	return name, nil
 }
 
+// modelImage returns the image of a worker model. The image of a model with a Dockerfile is
+// built if it doesn't exist yet
+func (h *HatcheryDocker) modelImage(wm *sdk.Model) (string, error) {
+	if wm.Dockerfile == "" {
+		return wm.Image, nil
+	}
+
+	image := wm.BuildImageName()
+	if err := exec.Command("docker", "image", "inspect", image).Run(); err == nil {
+		return image, nil
+	}
+
+	log.Info("modelImage> Building image %s of worker model %s", image, wm.Name)
+	cmd := exec.Command("docker", "build", "--pull", "-t", image, "-")
+	cmd.Stdin = strings.NewReader(wm.Dockerfile)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("cannot build image %s: %s (%s)", image, err, strings.TrimSpace(string(out)))
+	}
+	return image, nil
+}
+
+// startServices starts the services required by a job on a new network. It returns the names of the
+// containers and the name of the network, which is empty if there is no service
+func (h *HatcheryDocker) startServices(name string, requirements []sdk.Requirement) ([]string, string, error) {
+	jobServices, err := sdk.JobServices(requirements)
+	if err != nil || len(jobServices) == 0 {
+		return nil, "", err
+	}
+
+	network := name + "-net"
+	if out, err := exec.Command("docker", "network", "create", network).CombinedOutput(); err != nil {
+		return nil, "", fmt.Errorf("cannot create network %s: %s (%s)", network, err, strings.TrimSpace(string(out)))
+	}
+
+	var services []string
+	for _, s := range jobServices {
+		serviceName := s.Name + "-" + name
+		args := []string{"run", "-d", "--rm", fmt.Sprintf("--name=%s", serviceName), fmt.Sprintf("--network=%s", network), fmt.Sprintf("--network-alias=%s", s.Name)}
+		if m, ok := s.Env["CDS_SERVICE_MEMORY"]; ok {
+			args = append(args, fmt.Sprintf("--memory=%sm", m))
+		}
+		for _, e := range s.EnvList() {
+			args = append(args, "-e", e)
+		}
+		for _, p := range s.Ports {
+			args = append(args, fmt.Sprintf("--expose=%s", p))
+		}
+		if s.HealthCheck != "" {
+			args = append(args, fmt.Sprintf("--health-cmd=%s", s.HealthCheck), fmt.Sprintf("--health-interval=%s", sdk.JobServiceHealthInterval), fmt.Sprintf("--health-timeout=%s", sdk.JobServiceHealthTimeout))
+		}
+		args = append(args, s.Image)
+
+		log.Info("startServices> Starting service %s (%s) for worker %s", s.Name, s.Image, name)
+		if out, err := exec.Command("docker", args...).CombinedOutput(); err != nil {
+			return services, network, fmt.Errorf("cannot start service %s: %s (%s)", s.Name, err, strings.TrimSpace(string(out)))
+		}
+		services = append(services, serviceName)
+	}
+
+	// Health checks are run by docker in the containers of the services, the worker is started once they succeed
+	timeout := time.Now().Add(sdk.JobServiceReadyTimeout)
+	for i, s := range jobServices {
+		if s.HealthCheck == "" {
+			continue
+		}
+		if err := waitServiceHealthy(services[i], timeout); err != nil {
+			return services, network, fmt.Errorf("service %s is not ready: %s", s.Name, err)
+		}
+	}
+	return services, network, nil
+}
+
+// waitServiceHealthy waits until the health check of a service container succeeds
+func waitServiceHealthy(container string, timeout time.Time) error {
+	var status string
+	for {
+		out, err := exec.Command("docker", "inspect", "--format={{.State.Health.Status}}", container).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("cannot inspect container %s: %s (%s)", container, err, strings.TrimSpace(string(out)))
+		}
+		status = strings.TrimSpace(string(out))
+		if status == "healthy" {
+			return nil
+		}
+		if time.Now().After(timeout) {
+			return fmt.Errorf("health check status is %s after %s", status, sdk.JobServiceReadyTimeout)
+		}
+		time.Sleep(sdk.JobServiceHealthInterval)
+	}
+}
+
+// removeServices removes the services of a worker and their network
+func (h *HatcheryDocker) removeServices(services []string, network string) {
+	for _, s := range services {
+		if err := exec.Command("docker", "rm", "-f", s).Run(); err != nil {
+			log.Warning("removeServices> cannot rm container %s: %s", s, err)
+		}
+	}
+	if network != "" {
+		if err := exec.Command("docker", "network", "rm", network).Run(); err != nil {
+			log.Warning("removeServices> cannot rm network %s: %s", network, err)
+		}
+	}
+}
+
 func randSeq(n int) (string, error) {
 	b := make([]byte, 64)
 	if _, err := rand.Read(b); err != nil {
@@ -168,6 +168,10 @@ func (h *HatcheryLocal) SpawnWorker(wm *sdk.Model, jobID int64, requirements []s
 	if h.Config.Provision.WorkerLogsOptions.Graylog.ExtraValue != "" {
 		args = append(args, fmt.Sprintf("--graylog-extra-value=%s", h.Config.Provision.WorkerLogsOptions.Graylog.ExtraValue))
 	}
+	if h.Config.Tracing.Enable {
+		args = append(args, fmt.Sprintf("--tracing-exporter=%s", h.Config.Tracing.Exporter))
+		args = append(args, fmt.Sprintf("--tracing-endpoint=%s", h.Config.Tracing.Endpoint))
+	}
 	if h.Config.API.GRPC.URL != "" && wm.Communication == sdk.GRPC {
 		args = append(args, fmt.Sprintf("--grpc-api=%s", h.Config.API.GRPC.URL))
 		args = append(args, fmt.Sprintf("--grpc-insecure=%t", h.Config.API.GRPC.Insecure))
@@ -229,6 +229,10 @@ func (h *HatcheryMarathon) SpawnWorker(model *sdk.Model, jobID int64, requiremen
 	if h.Configuration().Provision.WorkerLogsOptions.Graylog.ExtraValue != "" {
 		env["CDS_GRAYLOG_EXTRA_VALUE"] = h.Configuration().Provision.WorkerLogsOptions.Graylog.ExtraValue
 	}
+	if h.Configuration().Tracing.Enable {
+		env["CDS_TRACING_EXPORTER"] = h.Configuration().Tracing.Exporter
+		env["CDS_TRACING_ENDPOINT"] = h.Configuration().Tracing.Endpoint
+	}
 	if h.Configuration().API.GRPC.URL != "" && model.Communication == sdk.GRPC {
 		env["CDS_GRPC_API"] = h.Configuration().API.GRPC.URL
 		env["CDS_GRPC_INSECURE"] = strconv.FormatBool(h.Configuration().API.GRPC.Insecure)
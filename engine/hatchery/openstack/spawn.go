@@ -77,6 +77,10 @@ func (h *HatcheryOpenstack) SpawnWorker(model *sdk.Model, jobID int64, requireme
 	if h.Configuration().Provision.WorkerLogsOptions.Graylog.ExtraValue != "" {
 		graylog += fmt.Sprintf("export CDS_GRAYLOG_EXTRA_VALUE=%s ", h.Configuration().Provision.WorkerLogsOptions.Graylog.ExtraValue)
 	}
+	if h.Configuration().Tracing.Enable {
+		graylog += fmt.Sprintf("export CDS_TRACING_EXPORTER=%s ", h.Configuration().Tracing.Exporter)
+		graylog += fmt.Sprintf("export CDS_TRACING_ENDPOINT=%s ", h.Configuration().Tracing.Endpoint)
+	}
 
 	grpc := ""
 	if h.Configuration().API.GRPC.URL != "" && model.Communication == sdk.GRPC {
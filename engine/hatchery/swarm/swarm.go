@@ -233,6 +233,7 @@ func (h *HatcherySwarm) SpawnWorker(model *sdk.Model, jobID int64, requirements
 	memory := int64(h.Config.DefaultMemory)
 
 	services := []string{}
+	healthChecked := []bool{}
 
 	if jobID > 0 {
 		for _, r := range requirements {
@@ -245,23 +246,19 @@ func (h *HatcherySwarm) SpawnWorker(model *sdk.Model, jobID int64, requirements
 				}
 			} else if r.Type == sdk.ServiceRequirement {
 				//name= <alias> => the name of the host put in /etc/hosts of the worker
-				//value= "postgres:latest env_1=blabla env_2=blabla"" => we can add env variables in requirement name
-				tuple := strings.Split(r.Value, " ")
-				img := tuple[0]
-				env := []string{}
-				serviceMemory := int64(1024)
-				if len(tuple) > 1 {
-					env = append(env, tuple[1:]...)
+				//value= "postgres:latest env_1=blabla env_2=blabla"" or a json service, see sdk.JobService
+				service, err := sdk.NewJobService(r)
+				if err != nil {
+					log.Warning("SpawnWorker> Unable to read service requirement: %s", err)
+					return "", err
 				}
+				serviceMemory := int64(1024)
 				//option for power user : set the service memory with CDS_SERVICE_MEMORY=1024
-				for _, e := range env {
-					if strings.HasPrefix(e, "CDS_SERVICE_MEMORY=") {
-						m := strings.Replace(e, "CDS_SERVICE_MEMORY=", "", -1)
-						i, err := strconv.Atoi(m)
-						if err != nil {
-							log.Warning("SpawnWorker> Unable to parse service option %s : %s", e, err)
-							continue
-						}
+				if m, ok := service.Env["CDS_SERVICE_MEMORY"]; ok {
+					i, err := strconv.Atoi(m)
+					if err != nil {
+						log.Warning("SpawnWorker> Unable to parse service option CDS_SERVICE_MEMORY=%s : %s", m, err)
+					} else {
 						serviceMemory = int64(i)
 					}
 				}
@@ -273,15 +270,28 @@ func (h *HatcherySwarm) SpawnWorker(model *sdk.Model, jobID int64, requirements
 					"service_name":   serviceName,
 				}
 				//Start the services
-				if err := h.createAndStartContainer(serviceName, img, network, r.Name, []string{}, env, labels, serviceMemory); err != nil {
+				if err := h.createAndStartContainer(serviceName, service.Image, network, r.Name, []string{}, service.EnvList(), service.Ports, service.HealthCheck, labels, serviceMemory); err != nil {
 					log.Warning("SpawnWorker>Unable to start required container: %s", err)
 					return "", err
 				}
 				services = append(services, serviceName)
+				healthChecked = append(healthChecked, service.HealthCheck != "")
 			}
 		}
 	}
 
+	//The worker is started once the health checks of the services succeed
+	timeout := time.Now().Add(sdk.JobServiceReadyTimeout)
+	for i, s := range services {
+		if !healthChecked[i] {
+			continue
+		}
+		if err := h.waitServiceHealthy(s, timeout); err != nil {
+			log.Warning("SpawnWorker> Service %s is not ready: %s", s, err)
+			return "", err
+		}
+	}
+
 	var registerCmd string
 	if registerOnly {
 		registerCmd = " register"
@@ -314,6 +324,10 @@ func (h *HatcherySwarm) SpawnWorker(model *sdk.Model, jobID int64, requirements
 	if h.Configuration().Provision.WorkerLogsOptions.Graylog.ExtraValue != "" {
 		env = append(env, "CDS_GRAYLOG_EXTRA_VALUE"+"="+h.Configuration().Provision.WorkerLogsOptions.Graylog.ExtraValue)
 	}
+	if h.Configuration().Tracing.Enable {
+		env = append(env, fmt.Sprintf("CDS_TRACING_EXPORTER=%s", h.Configuration().Tracing.Exporter))
+		env = append(env, fmt.Sprintf("CDS_TRACING_ENDPOINT=%s", h.Configuration().Tracing.Endpoint))
+	}
 	if h.Configuration().API.GRPC.URL != "" && model.Communication == sdk.GRPC {
 		env = append(env, fmt.Sprintf("CDS_GRPC_API=%s", h.Configuration().API.GRPC.URL))
 		env = append(env, fmt.Sprintf("CDS_GRPC_INSECURE=%t", h.Configuration().API.GRPC.Insecure))
@@ -330,14 +344,37 @@ func (h *HatcherySwarm) SpawnWorker(model *sdk.Model, jobID int64, requirements
 		"worker_requirements": strings.Join(services, ","),
 	}
 
+	image, errImage := h.modelImage(model)
+	if errImage != nil {
+		log.Warning("SpawnWorker> %s", errImage)
+		return "", errImage
+	}
+
 	//start the worker
-	if err := h.createAndStartContainer(name, model.Image, network, "worker", cmd, env, labels, memory); err != nil {
-		log.Warning("SpawnWorker> Unable to start container named %s with image %s err:%s", name, model.Image, err)
+	if err := h.createAndStartContainer(name, image, network, "worker", cmd, env, nil, "", labels, memory); err != nil {
+		log.Warning("SpawnWorker> Unable to start container named %s with image %s err:%s", name, image, err)
 	}
 
 	return name, nil
 }
 
+//wait until the health check of a service container succeeds
+func (h *HatcherySwarm) waitServiceHealthy(name string, timeout time.Time) error {
+	for {
+		c, err := h.dockerClient.InspectContainer(name)
+		if err != nil {
+			return err
+		}
+		if c.State.Health.Status == "healthy" {
+			return nil
+		}
+		if time.Now().After(timeout) {
+			return fmt.Errorf("health check status of %s is %s after %s", name, c.State.Health.Status, sdk.JobServiceReadyTimeout)
+		}
+		time.Sleep(sdk.JobServiceHealthInterval)
+	}
+}
+
 //create the docker bridge
 func (h *HatcherySwarm) createNetwork(name string) error {
 	log.Debug("createAndStartContainer> Create network %s", name)
@@ -358,7 +395,7 @@ func (h *HatcherySwarm) createNetwork(name string) error {
 }
 
 //shortcut to create+start(=run) a container
-func (h *HatcherySwarm) createAndStartContainer(name, image, network, networkAlias string, cmd, env []string, labels map[string]string, memory int64) error {
+func (h *HatcherySwarm) createAndStartContainer(name, image, network, networkAlias string, cmd, env, ports []string, healthCheck string, labels map[string]string, memory int64) error {
 	//Memory is set to 1GB by default
 	if memory <= 4 {
 		memory = 1024
@@ -367,15 +404,29 @@ func (h *HatcherySwarm) createAndStartContainer(name, image, network, networkAli
 		memory = memory * 110 / 100
 	}
 	log.Info("createAndStartContainer> Create container %s from %s on network %s as %s (memory=%dMB)", name, image, network, networkAlias, memory)
+
+	//Ports are exposed on the network of the worker, not published on the host
+	var exposedPorts map[docker.Port]struct{}
+	for _, p := range ports {
+		if exposedPorts == nil {
+			exposedPorts = map[docker.Port]struct{}{}
+		}
+		if !strings.Contains(p, "/") {
+			p += "/tcp"
+		}
+		exposedPorts[docker.Port(p)] = struct{}{}
+	}
+
 	opts := docker.CreateContainerOptions{
 		Name: name,
 		Config: &docker.Config{
-			Image:      image,
-			Cmd:        cmd,
-			Env:        env,
-			Labels:     labels,
-			Memory:     memory * 1024 * 1024, //from MB to B
-			MemorySwap: -1,
+			Image:        image,
+			Cmd:          cmd,
+			Env:          env,
+			Labels:       labels,
+			ExposedPorts: exposedPorts,
+			Memory:       memory * 1024 * 1024, //from MB to B
+			MemorySwap:   -1,
 		},
 		NetworkingConfig: &docker.NetworkingConfig{
 			EndpointsConfig: map[string]*docker.EndpointConfig{
@@ -386,6 +437,15 @@ func (h *HatcherySwarm) createAndStartContainer(name, image, network, networkAli
 		},
 	}
 
+	//The health check is run by docker in the container
+	if healthCheck != "" {
+		opts.Config.Healthcheck = &docker.HealthConfig{
+			Test:     []string{"CMD-SHELL", healthCheck},
+			Interval: sdk.JobServiceHealthInterval,
+			Timeout:  sdk.JobServiceHealthTimeout,
+		}
+	}
+
 	c, err := h.dockerClient.CreateContainer(opts)
 	if err != nil {
 		log.Warning("startAndCreateContainer> Unable to create container with opts: %+v err:%s", opts, err)
@@ -423,7 +483,12 @@ func (h *HatcherySwarm) CanSpawn(model *sdk.Model, jobID int64, requirements []s
 
 	for _, r := range requirements {
 		if r.Type == sdk.ServiceRequirement {
-			links[r.Name] = strings.Split(r.Value, " ")[0]
+			service, err := sdk.NewJobService(r)
+			if err != nil {
+				log.Warning("CanSpawn> %s", err)
+				return false
+			}
+			links[r.Name] = service.Image
 		}
 	}
 
@@ -480,7 +545,8 @@ func (h *HatcherySwarm) CanSpawn(model *sdk.Model, jobID int64, requirements []s
 		}
 	}
 
-	if !imageFound {
+	// images of models with a Dockerfile are built on spawn
+	if !imageFound && model.Dockerfile == "" {
 		//Pull the worker image
 		opts := docker.PullImageOptions{
 			Repository:   model.Image,
@@ -0,0 +1,59 @@
+package swarm
+
+import (
+	"archive/tar"
+	"bytes"
+	"io/ioutil"
+	"strconv"
+	"time"
+
+	docker "github.com/fsouza/go-dockerclient"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// modelImage returns the image of a worker model. The image of a model with a Dockerfile is built
+// if it does not exist yet
+func (h *HatcherySwarm) modelImage(model *sdk.Model) (string, error) {
+	if model.Dockerfile == "" {
+		return model.Image, nil
+	}
+
+	image := model.BuildImageName()
+	if _, err := h.dockerClient.InspectImage(image); err == nil {
+		return image, nil
+	} else if err != docker.ErrNoSuchImage {
+		return "", sdk.WrapError(err, "modelImage> Unable to inspect image %s", image)
+	}
+
+	// The build context only contains the Dockerfile
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+	if err := tw.WriteHeader(&tar.Header{Name: "Dockerfile", Mode: 0644, Size: int64(len(model.Dockerfile)), ModTime: time.Now()}); err != nil {
+		return "", sdk.WrapError(err, "modelImage> Unable to write build context")
+	}
+	if _, err := tw.Write([]byte(model.Dockerfile)); err != nil {
+		return "", sdk.WrapError(err, "modelImage> Unable to write build context")
+	}
+	if err := tw.Close(); err != nil {
+		return "", sdk.WrapError(err, "modelImage> Unable to write build context")
+	}
+
+	log.Info("modelImage> Building image %s for model %s", image, model.Name)
+	opts := docker.BuildImageOptions{
+		Name:           image,
+		InputStream:    buf,
+		OutputStream:   ioutil.Discard,
+		RmTmpContainer: true,
+		Pull:           true,
+		Labels: map[string]string{
+			"worker_model":         model.Name,
+			"worker_model_version": strconv.FormatInt(model.Version, 10),
+		},
+	}
+	if err := h.dockerClient.BuildImage(opts); err != nil {
+		return "", sdk.WrapError(err, "modelImage> Unable to build image %s", image)
+	}
+	return image, nil
+}
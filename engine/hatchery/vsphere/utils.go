@@ -22,6 +22,10 @@ func (h *HatcheryVSphere) getGraylogGrpcEnv(model *sdk.Model) []string {
 	if h.Configuration().Provision.WorkerLogsOptions.Graylog.ExtraValue != "" {
 		env = append(env, fmt.Sprintf("export CDS_GRAYLOG_EXTRA_VALUE=%s", h.Configuration().Provision.WorkerLogsOptions.Graylog.ExtraValue))
 	}
+	if h.Configuration().Tracing.Enable {
+		env = append(env, fmt.Sprintf("export CDS_TRACING_EXPORTER=%s", h.Configuration().Tracing.Exporter))
+		env = append(env, fmt.Sprintf("export CDS_TRACING_ENDPOINT=%s", h.Configuration().Tracing.Endpoint))
+	}
 
 	if h.Configuration().API.GRPC.URL != "" && model.Communication == sdk.GRPC {
 		env = append(env, fmt.Sprintf("export CDS_GRPC_API=%s", h.Configuration().API.GRPC.URL))
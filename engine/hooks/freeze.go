@@ -0,0 +1,42 @@
+package hooks
+
+import (
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// activeFreezeWindow returns the freeze window active on the workflow of a scheduled task execution, if any. Like the
+// API, which only freezes deployment nodes, the API returns no freeze window for workflows which deploy nothing. Only
+// the freeze windows covering the whole project are considered here, those on an environment are applied by the API
+func (s *Service) activeFreezeWindow(t *TaskExecution) (*sdk.FreezeWindow, error) {
+	if t.ScheduledTask == nil || t.Config["project"] == "" || t.Config["workflow"] == "" {
+		return nil, nil
+	}
+	ws, err := s.cds.WorkflowHookFreezeWindows(t.Config["project"], t.Config["workflow"])
+	if err != nil {
+		return nil, sdk.WrapError(err, "activeFreezeWindow> Unable to get freeze windows of workflow %s/%s", t.Config["project"], t.Config["workflow"])
+	}
+
+	var active *sdk.FreezeWindow
+	for i := range ws {
+		w := &ws[i]
+		if !w.Active || w.EnvironmentID != 0 {
+			continue
+		}
+		if active == nil || (w.Action == sdk.FreezeActionRefuse && active.Action != sdk.FreezeActionRefuse) {
+			active = w
+		}
+	}
+	return active, nil
+}
+
+// deferTaskExecution postpones a scheduled task execution to the end of a freeze window
+func (s *Service) deferTaskExecution(t *TaskExecution, w *sdk.FreezeWindow) {
+	s.Dao.DeleteTaskExecution(t)
+	t.Timestamp = w.NextEnd.UnixNano()
+	t.ScheduledTask.DateScheduledExecution = w.NextEnd.Format(time.RFC3339)
+	s.Dao.SaveTaskExecution(t)
+	log.Info("Hooks> Scheduled task %s deferred to %v by freeze window %s", t.UUID, w.NextEnd, w.Name)
+}
@@ -13,13 +13,15 @@ import (
 	"github.com/ovh/cds/sdk/cdsclient"
 	"github.com/ovh/cds/sdk/hatchery"
 	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/tracing"
 )
 
 // New returns a new service
 func New() *Service {
 	s := new(Service)
 	s.Router = &api.Router{
-		Mux: mux.NewRouter(),
+		Mux:             mux.NewRouter(),
+		RequestDuration: requestDuration,
 	}
 	return s
 }
@@ -62,6 +64,10 @@ func (s *Service) Serve(c context.Context) error {
 
 	log.Info("Hooks> Starting service %s...", s.Cfg.Name)
 
+	if err := tracing.Init(s.Cfg.Tracing, "cds-hooks"); err != nil {
+		return err
+	}
+
 	//Instanciate a cds client
 	s.cds = cdsclient.NewService(s.Cfg.API.HTTP.URL)
 
@@ -122,6 +128,7 @@ func (s *Service) Serve(c context.Context) error {
 		case <-ctx.Done():
 			log.Info("Hooks> Shutdown HTTP Server")
 			server.Shutdown(ctx)
+			tracing.Shutdown()
 		}
 	}()
 
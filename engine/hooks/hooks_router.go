@@ -19,4 +19,6 @@ func (s *Service) initRouter(ctx context.Context) {
 	r.Handle("/task/bulk", r.POST(s.postTaskBulkHandler))
 	r.Handle("/task/{uuid}", r.GET(s.getTaskHandler), r.PUT(s.putTaskHandler), r.DELETE(s.deleteTaskHandler))
 	r.Handle("/task/{uuid}/execution", r.GET(s.getTaskExecutionsHandler))
+
+	r.Handle("/mon/metrics", r.GET(s.getMetricsHandler, api.Auth(false)))
 }
@@ -0,0 +1,57 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/ovh/cds/engine/api"
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	registry = prometheus.NewRegistry()
+
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "http_request_duration_seconds", Help: "metrics http_request_duration_seconds: latency of the HTTP handlers"}, []string{"route", "method", "status"})
+	taskExecutions  = prometheus.NewCounterVec(prometheus.CounterOpts{Name: "hooks_task_executions_total", Help: "metrics hooks_task_executions_total: executions of the hook tasks, by type and status"}, []string{"type", "status"})
+)
+
+func init() {
+	registry.MustRegister(requestDuration, taskExecutions, cache.OperationDuration)
+}
+
+// observeTaskExecution counts an execution of a task
+func observeTaskExecution(taskType string, err error) {
+	status := "success"
+	if err != nil {
+		status = "error"
+	}
+	taskExecutions.WithLabelValues(taskType, status).Inc()
+}
+
+func (s *Service) getMetricsHandler() api.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		mfs, err := registry.Gather()
+		if err != nil {
+			return sdk.WrapError(err, "Hooks> getMetricsHandler> An error has occurred during metrics gathering")
+		}
+		contentType := expfmt.Negotiate(r.Header)
+		writer := &bytes.Buffer{}
+		enc := expfmt.NewEncoder(writer, contentType)
+		for _, mf := range mfs {
+			if err := enc.Encode(mf); err != nil {
+				return sdk.WrapError(err, "Hooks> getMetricsHandler> An error has occurred during metrics encoding")
+			}
+		}
+		header := w.Header()
+		header.Set("Content-Type", string(contentType))
+		header.Set("Content-Length", fmt.Sprint(writer.Len()))
+		w.Write(writer.Bytes())
+		return nil
+	}
+}
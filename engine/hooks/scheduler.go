@@ -5,6 +5,7 @@ import (
 	"sort"
 	"time"
 
+	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 )
 
@@ -133,10 +134,23 @@ func (s *Service) dequeueTaskExecutions(c context.Context) error {
 		} else if task.Stopped {
 			t.LastError = "Executions skipped: Task has been stopped"
 			t.NbErrors++
+		} else if w, err := s.activeFreezeWindow(&t); err != nil {
+			log.Error("Hooks> dequeueTaskExecutions failed: %v", err)
+			t.LastError = err.Error()
+			t.NbErrors++
+		} else if w != nil && w.Action == sdk.FreezeActionHold {
+			s.deferTaskExecution(&t, w)
+			continue
+		} else if w != nil {
+			log.Info("Hooks> Scheduled task %s skipped by freeze window %s", t.UUID, w.Name)
+			t.ScheduledTask.SkippedBy = w.Name
 		} else if err := s.doTask(c, task, &t); err != nil {
 			log.Error("Hooks> dequeueTaskExecutions failed: %v", err)
 			t.LastError = err.Error()
 			t.NbErrors++
+			observeTaskExecution(t.Type, err)
+		} else {
+			observeTaskExecution(t.Type, nil)
 		}
 
 		//Save the execution
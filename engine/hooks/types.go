@@ -5,6 +5,7 @@ import (
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/cdsclient"
+	"github.com/ovh/cds/sdk/tracing"
 )
 
 // Service is the stuct representing a hooks µService
@@ -47,6 +48,7 @@ type Configuration struct {
 			Password string `toml:"password"`
 		} `toml:"redis" comment:"Connect CDS to a redis cache If you more than one CDS instance and to avoid losing data at startup"`
 	} `toml:"cache" comment:"######################\n CDS Hooks Cache Settings \n######################\nIf your CDS is made of a unique instance, a local cache if enough, but rememeber that all cached data will be lost on startup."`
+	Tracing tracing.Configuration `toml:"tracing" comment:"######################\n CDS Hooks Distributed Tracing Settings \n######################"`
 }
 
 // Task is a generic hook tasks such as webhook, scheduler,... which will be started and wait for execution
@@ -81,4 +83,5 @@ type WebHookExecution struct {
 // ScheduledTaskExecution contains specific data for a scheduled task execution
 type ScheduledTaskExecution struct {
 	DateScheduledExecution string
+	SkippedBy              string
 }
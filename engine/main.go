@@ -17,6 +17,7 @@ import (
 
 	"github.com/ovh/cds/engine/api"
 	"github.com/ovh/cds/engine/api/database"
+	"github.com/ovh/cds/engine/cache"
 	"github.com/ovh/cds/engine/hatchery/docker"
 	"github.com/ovh/cds/engine/hatchery/local"
 	"github.com/ovh/cds/engine/hatchery/marathon"
@@ -107,6 +108,7 @@ Comming soon...`,
 		conf.Hatchery.Swarm.API.Token = conf.API.Auth.SharedInfraToken
 		conf.Hatchery.Marathon.API.Token = conf.API.Auth.SharedInfraToken
 		conf.Hooks.API.Token = conf.API.Auth.SharedInfraToken
+		conf.Cache.API.Token = conf.API.Auth.SharedInfraToken
 
 		if !configNewAsEnvFlag {
 			btes, err := toml.Marshal(*conf)
@@ -187,6 +189,13 @@ var configCheckCmd = &cobra.Command{
 			}
 		}
 
+		if conf.Cache.API.HTTP.URL != "" {
+			if err := cache.New().CheckConfiguration(conf.Cache); err != nil {
+				fmt.Println(err)
+				hasError = true
+			}
+		}
+
 		if !hasError {
 			fmt.Println("Configuration file OK")
 		}
@@ -210,9 +219,11 @@ Start CDS Engine Services:
 	 * Vsphere
  * Hooks:
  	This component operates CDS workflow hooks
+ * Cache:
+ 	This component caches the dependencies downloaded by workers: Go modules, NPM packages and Maven artifacts
 
 Start all of this with a single command:
-	$ engine start [api] [hatchery:local] [hatchery:docker] [hatchery:marathon] [hatchery:openstack] [hatchery:swarm] [hatchery:vsphere] [hooks]
+	$ engine start [api] [hatchery:local] [hatchery:docker] [hatchery:marathon] [hatchery:openstack] [hatchery:swarm] [hatchery:vsphere] [hooks] [cache]
 All the services are using the same configuration file format.
 You have to specify where the toml configuration is. It can be a local file, provided by consul or vault.
 You can also use or override toml file with environment variable.
@@ -290,6 +301,9 @@ See $ engine config command for more details.
 			case "hooks":
 				s = hooks.New()
 				cfg = conf.Hooks
+			case "cache":
+				s = cache.New()
+				cfg = conf.Cache
 			default:
 				fmt.Printf("Error: service '%s' unknown\n", a)
 				os.Exit(1)
@@ -0,0 +1,18 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "user_access_token" (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    name VARCHAR(256) NOT NULL,
+    hash VARCHAR(256) NOT NULL,
+    scopes JSONB,
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+    expire_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_used TIMESTAMP WITH TIME ZONE
+);
+
+SELECT create_foreign_key_idx_cascade('FK_USER_ACCESS_TOKEN_USER', 'user_access_token', 'user', 'user_id', 'id');
+SELECT create_unique_index('user_access_token', 'IDX_USER_ACCESS_TOKEN_HASH', 'hash');
+SELECT create_unique_index('user_access_token', 'IDX_USER_ACCESS_TOKEN_USER_NAME', 'user_id,name');
+
+-- +migrate Down
+DROP TABLE user_access_token;
@@ -0,0 +1,14 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "workflow_group" (
+    id BIGSERIAL PRIMARY KEY,
+    workflow_id BIGINT NOT NULL,
+    group_id BIGINT NOT NULL,
+    role INT NOT NULL
+);
+
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_GROUP_WORKFLOW', 'workflow_group', 'workflow', 'workflow_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_GROUP_GROUP', 'workflow_group', 'group', 'group_id', 'id');
+SELECT create_unique_index('workflow_group', 'IDX_WORKFLOW_GROUP_UNIQUE', 'group_id,workflow_id');
+
+-- +migrate Down
+DROP TABLE workflow_group;
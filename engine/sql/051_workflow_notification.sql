@@ -0,0 +1,13 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "workflow_notification" (
+    id BIGSERIAL PRIMARY KEY,
+    workflow_id BIGINT NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    node_names JSONB,
+    settings JSONB
+);
+
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_NOTIFICATION_WORKFLOW', 'workflow_notification', 'workflow', 'workflow_id', 'id');
+
+-- +migrate Down
+DROP TABLE workflow_notification;
@@ -0,0 +1,19 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "audit" (
+    id BIGSERIAL PRIMARY KEY,
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+    username VARCHAR(256),
+    project_key VARCHAR(256),
+    entity_type VARCHAR(50) NOT NULL,
+    entity_name VARCHAR(256) NOT NULL,
+    action VARCHAR(50) NOT NULL,
+    before JSONB,
+    after JSONB
+);
+
+select create_index('audit', 'IDX_AUDIT_PROJECT_KEY', 'project_key,created');
+select create_index('audit', 'IDX_AUDIT_ENTITY', 'entity_type,entity_name');
+select create_index('audit', 'IDX_AUDIT_CREATED', 'created');
+
+-- +migrate Down
+DROP TABLE audit;
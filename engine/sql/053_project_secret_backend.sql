@@ -0,0 +1,15 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "project_secret_backend" (
+    id BIGSERIAL PRIMARY KEY,
+    project_id BIGINT NOT NULL,
+    name VARCHAR(256) NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    address TEXT,
+    token BYTEA
+);
+
+SELECT create_foreign_key_idx_cascade('FK_PROJECT_SECRET_BACKEND_PROJECT', 'project_secret_backend', 'project', 'project_id', 'id');
+SELECT create_unique_index('project_secret_backend', 'IDX_PROJECT_SECRET_BACKEND_NAME', 'project_id,name');
+
+-- +migrate Down
+DROP TABLE project_secret_backend;
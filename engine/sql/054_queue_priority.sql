@@ -0,0 +1,23 @@
+-- +migrate Up
+ALTER TABLE workflow ADD COLUMN priority INT DEFAULT 0;
+ALTER TABLE workflow_node_trigger ADD COLUMN priority INT DEFAULT 0;
+ALTER TABLE workflow_node_join_trigger ADD COLUMN priority INT DEFAULT 0;
+ALTER TABLE workflow_node_run_job ADD COLUMN priority INT DEFAULT 0;
+ALTER TABLE workflow_node_run_job ADD COLUMN group_id BIGINT DEFAULT 0;
+select create_index('workflow_node_run_job', 'IDX_WORKFLOW_NODE_RUN_JOB_GROUP_STATUS', 'group_id,status');
+
+CREATE TABLE IF NOT EXISTS "group_queue_settings" (
+    group_id BIGINT PRIMARY KEY,
+    weight INT NOT NULL DEFAULT 1,
+    max_building INT NOT NULL DEFAULT 0
+);
+
+SELECT create_foreign_key_idx_cascade('FK_GROUP_QUEUE_SETTINGS_GROUP', 'group_queue_settings', 'group', 'group_id', 'id');
+
+-- +migrate Down
+DROP TABLE group_queue_settings;
+ALTER TABLE workflow_node_run_job DROP COLUMN group_id;
+ALTER TABLE workflow_node_run_job DROP COLUMN priority;
+ALTER TABLE workflow_node_join_trigger DROP COLUMN priority;
+ALTER TABLE workflow_node_trigger DROP COLUMN priority;
+ALTER TABLE workflow DROP COLUMN priority;
@@ -0,0 +1,29 @@
+-- +migrate Up
+ALTER TABLE worker_model ADD COLUMN dockerfile TEXT NOT NULL DEFAULT '';
+ALTER TABLE worker_model ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+
+CREATE TABLE IF NOT EXISTS "worker_model_version" (
+    id BIGSERIAL PRIMARY KEY,
+    worker_model_id BIGINT NOT NULL,
+    version BIGINT NOT NULL,
+    model JSONB,
+    author TEXT,
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+SELECT create_foreign_key_idx_cascade('FK_WORKER_MODEL_VERSION_WORKER_MODEL', 'worker_model_version', 'worker_model', 'worker_model_id', 'id');
+SELECT create_unique_index('worker_model_version', 'IDX_WORKER_MODEL_VERSION_UNIQ', 'worker_model_id,version');
+
+-- The current definition of the existing models is their version 1, without their state like UpdateWorkerModelVersion
+INSERT INTO worker_model_version (worker_model_id, version, model, author, created)
+SELECT worker_model.id, 1,
+    (to_jsonb(worker_model) - 'owner_id' - 'need_registration' - 'last_registration' - 'nb_spawn_err' - 'last_spawn_err' - 'date_last_spawn_err')
+    || jsonb_build_object('capabilities', COALESCE((
+        SELECT jsonb_agg(jsonb_build_object('name', worker_capability.name, 'type', worker_capability.type, 'value', worker_capability.argument))
+        FROM worker_capability WHERE worker_capability.worker_model_id = worker_model.id), '[]'::jsonb)),
+    NULL, LOCALTIMESTAMP
+FROM worker_model;
+
+-- +migrate Down
+DROP TABLE worker_model_version;
+ALTER TABLE worker_model DROP COLUMN dockerfile;
+ALTER TABLE worker_model DROP COLUMN version;
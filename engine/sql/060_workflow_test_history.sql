@@ -0,0 +1,31 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "workflow_test_history" (
+    id BIGSERIAL PRIMARY KEY,
+    workflow_id BIGINT NOT NULL,
+    workflow_run_id BIGINT NOT NULL,
+    workflow_node_run_id BIGINT NOT NULL,
+    suite TEXT NOT NULL,
+    name TEXT NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    duration DOUBLE PRECISION NOT NULL DEFAULT 0,
+    vcs_hash TEXT NOT NULL DEFAULT '',
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_TEST_HISTORY_WORKFLOW', 'workflow_test_history', 'workflow', 'workflow_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_TEST_HISTORY_WORKFLOW_RUN', 'workflow_test_history', 'workflow_run', 'workflow_run_id', 'id');
+SELECT create_index('workflow_test_history', 'IDX_WORKFLOW_TEST_HISTORY_TESTCASE', 'workflow_id,suite,name');
+
+CREATE TABLE IF NOT EXISTS "workflow_test_quarantine" (
+    id BIGSERIAL PRIMARY KEY,
+    workflow_id BIGINT NOT NULL,
+    suite TEXT NOT NULL,
+    name TEXT NOT NULL,
+    author TEXT,
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_TEST_QUARANTINE_WORKFLOW', 'workflow_test_quarantine', 'workflow', 'workflow_id', 'id');
+SELECT create_unique_index('workflow_test_quarantine', 'IDX_WORKFLOW_TEST_QUARANTINE_UNIQ', 'workflow_id,suite,name');
+
+-- +migrate Down
+DROP TABLE workflow_test_history;
+DROP TABLE workflow_test_quarantine;
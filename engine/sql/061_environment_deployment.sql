@@ -0,0 +1,24 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "environment_deployment" (
+    id BIGSERIAL PRIMARY KEY,
+    environment_id BIGINT NOT NULL,
+    application_id BIGINT NOT NULL DEFAULT 0,
+    application_name TEXT NOT NULL DEFAULT '',
+    version TEXT NOT NULL DEFAULT '',
+    vcs_hash TEXT NOT NULL DEFAULT '',
+    vcs_branch TEXT NOT NULL DEFAULT '',
+    workflow_id BIGINT NOT NULL,
+    workflow_name TEXT NOT NULL,
+    workflow_run_id BIGINT NOT NULL,
+    workflow_run_number BIGINT NOT NULL,
+    workflow_node_run_id BIGINT NOT NULL,
+    node_name TEXT NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    author TEXT NOT NULL DEFAULT '',
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+SELECT create_foreign_key_idx_cascade('FK_ENVIRONMENT_DEPLOYMENT_ENVIRONMENT', 'environment_deployment', 'environment', 'environment_id', 'id');
+SELECT create_index('environment_deployment', 'IDX_ENVIRONMENT_DEPLOYMENT_APPLICATION', 'environment_id,application_name,created');
+
+-- +migrate Down
+DROP TABLE environment_deployment;
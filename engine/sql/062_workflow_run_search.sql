@@ -0,0 +1,13 @@
+-- +migrate Up
+SELECT create_index('workflow_run', 'IDX_WORKFLOW_RUN_WORKFLOW_START', 'workflow_id,start,id');
+SELECT create_index('workflow_run', 'IDX_WORKFLOW_RUN_WORKFLOW_NUM', 'workflow_id,num');
+SELECT create_index('workflow_run', 'IDX_WORKFLOW_RUN_WORKFLOW_STATUS', 'workflow_id,status');
+ALTER TABLE workflow_run_tag ALTER COLUMN value TYPE TEXT;
+CREATE INDEX IF NOT EXISTS IDX_WORKFLOW_RUN_TAG_VALUES ON workflow_run_tag USING GIN (string_to_array(value, ','));
+
+-- +migrate Down
+DROP INDEX IF EXISTS IDX_WORKFLOW_RUN_WORKFLOW_START;
+DROP INDEX IF EXISTS IDX_WORKFLOW_RUN_WORKFLOW_NUM;
+DROP INDEX IF EXISTS IDX_WORKFLOW_RUN_WORKFLOW_STATUS;
+DROP INDEX IF EXISTS IDX_WORKFLOW_RUN_TAG_VALUES;
+ALTER TABLE workflow_run_tag ALTER COLUMN value TYPE VARCHAR(256);
@@ -0,0 +1,28 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "freeze_window" (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    project_id BIGINT NOT NULL DEFAULT 0,
+    environment_id BIGINT NOT NULL DEFAULT 0,
+    start_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    recurrence TEXT NOT NULL DEFAULT '',
+    duration BIGINT NOT NULL DEFAULT 0,
+    timezone TEXT NOT NULL DEFAULT '',
+    action VARCHAR(50) NOT NULL,
+    author TEXT NOT NULL DEFAULT '',
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+SELECT create_index('freeze_window', 'IDX_FREEZE_WINDOW_PROJECT', 'project_id,end_date');
+
+CREATE TABLE IF NOT EXISTS "freeze_hold" (
+    workflow_node_run_id BIGINT PRIMARY KEY,
+    freeze_window_id BIGINT NOT NULL,
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+SELECT create_foreign_key_idx_cascade('FK_FREEZE_HOLD_WORKFLOW_NODE_RUN', 'freeze_hold', 'workflow_node_run', 'workflow_node_run_id', 'id');
+
+-- +migrate Down
+DROP TABLE freeze_hold;
+DROP TABLE freeze_window;
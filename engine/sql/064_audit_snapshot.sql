@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE audit ADD COLUMN IF NOT EXISTS snapshot BYTEA;
+
+-- +migrate Down
+ALTER TABLE audit DROP COLUMN IF EXISTS snapshot;
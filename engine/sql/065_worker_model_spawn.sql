@@ -0,0 +1,11 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "worker_model_spawn" (
+    id BIGSERIAL PRIMARY KEY,
+    worker_model_id BIGINT NOT NULL,
+    duration BIGINT NOT NULL,
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+SELECT create_foreign_key_idx_cascade('FK_WORKER_MODEL_SPAWN_WORKER_MODEL', 'worker_model_spawn', 'worker_model', 'worker_model_id', 'id');
+
+-- +migrate Down
+DROP TABLE worker_model_spawn;
@@ -9,6 +9,7 @@ import (
 	"github.com/fatih/structs"
 
 	"github.com/ovh/cds/engine/api"
+	"github.com/ovh/cds/engine/cache"
 	"github.com/ovh/cds/engine/hatchery/docker"
 	"github.com/ovh/cds/engine/hatchery/local"
 	"github.com/ovh/cds/engine/hatchery/marathon"
@@ -37,6 +38,7 @@ type Configuration struct {
 		VSphere   vsphere.HatcheryConfiguration   `toml:"vsphere" comment:"Hatchery VShpere. Doc: https://ovh.github.io/cds/advanced/advanced.hatcheries.vsphere/"`
 	} `toml:"hatchery"`
 	Hooks hooks.Configuration `toml:"hooks"`
+	Cache cache.Configuration `toml:"cache"`
 }
 
 type ServiceServeOptions struct {
@@ -52,6 +52,11 @@ func (w *currentWorker) runBuiltin(ctx context.Context, a *sdk.Action, buildID i
 	//Define a loggin function
 	sendLog := getLogger(w, buildID, stepOrder)
 
+	if w.local != nil && localSkippedActions[a.Name] {
+		sendLog(fmt.Sprintf("%s is not run locally", a.Name))
+		return sdk.Result{Status: sdk.StatusSuccess.String()}
+	}
+
 	f, ok := mapBuiltinActions[a.Name]
 	if !ok {
 		res := sdk.Result{
@@ -65,6 +70,15 @@ func (w *currentWorker) runBuiltin(ctx context.Context, a *sdk.Action, buildID i
 }
 
 func (w *currentWorker) runPlugin(ctx context.Context, a *sdk.Action, buildID int64, params *[]sdk.Parameter, stepOrder int, sendLog LoggerFunc) sdk.Result {
+	if w.local != nil {
+		res := sdk.Result{
+			Status: sdk.StatusFail.String(),
+			Reason: fmt.Sprintf("Plugin %s can't be run locally", a.Name),
+		}
+		sendLog(res.Reason)
+		return res
+	}
+
 	chanRes := make(chan sdk.Result)
 
 	go func(buildID int64, params []sdk.Parameter) {
@@ -15,6 +15,9 @@ import (
 )
 
 func runArtifactUpload(w *currentWorker) BuiltInAction {
+	if w.local != nil {
+		return runLocalArtifactUpload(w)
+	}
 	if w.currentJob.wJob == nil {
 		return func(ctx context.Context, a *sdk.Action, buildID int64, params *[]sdk.Parameter, sendLog LoggerFunc) sdk.Result {
 			res := sdk.Result{Status: sdk.StatusSuccess.String()}
@@ -129,6 +132,9 @@ func runArtifactUpload(w *currentWorker) BuiltInAction {
 }
 
 func runArtifactDownload(w *currentWorker) BuiltInAction {
+	if w.local != nil {
+		return runLocalArtifactDownload(w)
+	}
 	if w.currentJob.wJob == nil {
 		return func(ctx context.Context, a *sdk.Action, buildID int64, params *[]sdk.Parameter, sendLog LoggerFunc) sdk.Result {
 			res := sdk.Result{Status: sdk.StatusSuccess.String()}
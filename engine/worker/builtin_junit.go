@@ -69,6 +69,21 @@ func runParseJunitTestResultAction(w *currentWorker) BuiltInAction {
 			sendLog(r)
 		}
 
+		if res.Status == sdk.StatusFail.String() && w.currentJob.wJob != nil {
+			quarantine, err := loadTestsQuarantine(w.currentJob.wJob.ID)
+			if err != nil {
+				sendLog(fmt.Sprintf("JUnit parser: cannot load quarantined tests: %s", err))
+			}
+			for _, r := range ignoreQuarantinedTests(&res, &tests, quarantine) {
+				sendLog(r)
+			}
+		}
+
+		if w.local != nil {
+			w.local.addTests(tests)
+			return res
+		}
+
 		data, err := json.Marshal(tests)
 		if err != nil {
 			res.Reason = fmt.Sprintf("JUnit parse: failed to send tests details: %s", err)
@@ -189,6 +204,55 @@ func computeStats(res *sdk.Result, v *venom.Tests) []string {
 	return reasons
 }
 
+// loadTestsQuarantine loads the quarantined tests of the workflow of a job
+func loadTestsQuarantine(jobID int64) ([]sdk.WorkflowTestQuarantine, error) {
+	b, code, err := sdk.Request("GET", fmt.Sprintf("/queue/workflows/%d/test/quarantine", jobID), nil)
+	if err == nil && code >= 300 {
+		err = fmt.Errorf("HTTP %d", code)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var quarantine []sdk.WorkflowTestQuarantine
+	if err := json.Unmarshal(b, &quarantine); err != nil {
+		return nil, err
+	}
+	return quarantine, nil
+}
+
+// ignoreQuarantinedTests sets result.Status to success if all the failed testcases are quarantined,
+// and returns a list of log to send to API
+func ignoreQuarantinedTests(res *sdk.Result, v *venom.Tests, quarantine []sdk.WorkflowTestQuarantine) []string {
+	if len(quarantine) == 0 {
+		return nil
+	}
+	quarantined := make(map[string]bool, len(quarantine))
+	for _, q := range quarantine {
+		quarantined[q.Suite+"/"+q.Name] = true
+	}
+
+	reasons := []string{}
+	var nbKO, nbIgnored int
+	for _, ts := range v.TestSuites {
+		for _, tc := range ts.TestCases {
+			if len(tc.Failures) == 0 && len(tc.Errors) == 0 {
+				continue
+			}
+			nbKO++
+			if quarantined[ts.Name+"/"+tc.Name] {
+				nbIgnored++
+				reasons = append(reasons, fmt.Sprintf("JUnit parser: testcase %s of testsuite %s is quarantined, its failure is ignored", tc.Name, ts.Name))
+			}
+		}
+	}
+
+	// Failures counted on testsuites only can not be quarantined
+	if nbIgnored > 0 && nbIgnored == nbKO && v.TotalKO <= nbKO {
+		res.Status = sdk.StatusSuccess.String()
+	}
+	return reasons
+}
+
 func parseTestsuiteAlone(data []byte) (venom.TestSuite, bool) {
 	var s venom.TestSuite
 	err := xml.Unmarshal([]byte(data), &s)
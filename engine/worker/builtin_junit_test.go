@@ -216,3 +216,42 @@ func Test_computeStats(t *testing.T) {
 		})
 	}
 }
+
+func Test_ignoreQuarantinedTests(t *testing.T) {
+	v := &venom.Tests{
+		TestSuites: []venom.TestSuite{
+			{
+				Name: "myTestSuite",
+				TestCases: []venom.TestCase{
+					{Name: "myTestCase 1"},
+					{Name: "myTestCase 2", Failures: []venom.Failure{{Value: "Foo"}}},
+					{Name: "myTestCase 3", Errors: []venom.Failure{{Value: "Foo"}}},
+				},
+			},
+		},
+	}
+	res := &sdk.Result{}
+	computeStats(res, v)
+	if res.Status != sdk.StatusFail.String() {
+		t.Fatalf("status = %v, want %v", res.Status, sdk.StatusFail)
+	}
+
+	ignoreQuarantinedTests(res, v, []sdk.WorkflowTestQuarantine{{Suite: "myTestSuite", Name: "myTestCase 2"}})
+	if res.Status != sdk.StatusFail.String() {
+		t.Errorf("status = %v, want %v", res.Status, sdk.StatusFail)
+	}
+
+	reasons := ignoreQuarantinedTests(res, v, []sdk.WorkflowTestQuarantine{
+		{Suite: "myTestSuite", Name: "myTestCase 2"},
+		{Suite: "myTestSuite", Name: "myTestCase 3"},
+	})
+	if res.Status != sdk.StatusSuccess.String() {
+		t.Errorf("status = %v, want %v", res.Status, sdk.StatusSuccess)
+	}
+	if len(reasons) != 2 {
+		t.Errorf("reasons = %v, want 2 reasons", reasons)
+	}
+	if v.TotalKO != 2 {
+		t.Errorf("totalKO = %v, want 2", v.TotalKO)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"path"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/kardianos/osext"
 
@@ -27,6 +28,7 @@ func runScriptAction(w *currentWorker) BuiltInAction {
 
 		go func() {
 			res := sdk.Result{Status: sdk.StatusSuccess.String()}
+			var containerName string
 
 			// Get script content
 			var scriptContent string
@@ -123,14 +125,11 @@ func runScriptAction(w *currentWorker) BuiltInAction {
 				chanRes <- res
 			}
 
-			log.Info("runScriptAction> %s %s", shell, strings.Trim(fmt.Sprint(opts), "[]"))
-			cmd := exec.CommandContext(ctx, shell, opts...)
 			res.Status = sdk.StatusUnknown.String()
 
-			env := os.Environ()
-			cmd.Env = []string{}
+			hostEnv := []string{}
 			// filter technical env variables
-			for _, e := range env {
+			for _, e := range os.Environ() {
 				if strings.HasPrefix(e, "CDS_MODEL=") ||
 					strings.HasPrefix(e, "CDS_TTL=") ||
 					strings.HasPrefix(e, "CDS_SINGLE_USE=") ||
@@ -140,20 +139,20 @@ func runScriptAction(w *currentWorker) BuiltInAction {
 					strings.HasPrefix(e, "CDS_HATCHERY=") {
 					continue
 				}
-				cmd.Env = append(cmd.Env, e)
+				hostEnv = append(hostEnv, e)
 			}
 
 			//We have to let it here for some legacy reason
-			cmd.Env = append(cmd.Env, "CDS_KEY=********")
+			jobEnv := []string{"CDS_KEY=********"}
 
 			// worker export http port
-			cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%d", WorkerServerPort, w.exportPort))
+			jobEnv = append(jobEnv, fmt.Sprintf("%s=%d", WorkerServerPort, w.exportPort))
 
 			//DEPRECATED - BEGIN
 			// manage keys
 			if w.currentJob.pkey != "" && w.currentJob.gitsshPath != "" {
-				cmd.Env = append(cmd.Env, fmt.Sprintf("PKEY=%s", w.currentJob.pkey))
-				cmd.Env = append(cmd.Env, fmt.Sprintf("GIT_SSH=%s", w.currentJob.gitsshPath))
+				jobEnv = append(jobEnv, fmt.Sprintf("PKEY=%s", w.currentJob.pkey))
+				jobEnv = append(jobEnv, fmt.Sprintf("GIT_SSH=%s", w.currentJob.gitsshPath))
 			}
 			//DEPRECATED - END
 
@@ -165,13 +164,13 @@ func runScriptAction(w *currentWorker) BuiltInAction {
 				}
 				envName := strings.Replace(p.Name, ".", "_", -1)
 				envName = strings.ToUpper(envName)
-				cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", envName, p.Value))
+				jobEnv = append(jobEnv, fmt.Sprintf("%s=%s", envName, p.Value))
 			}
 
 			for _, p := range w.currentJob.buildVariables {
 				envName := strings.Replace(p.Name, ".", "_", -1)
 				envName = strings.ToUpper(envName)
-				cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", envName, p.Value))
+				jobEnv = append(jobEnv, fmt.Sprintf("%s=%s", envName, p.Value))
 			}
 
 			workerpath, err := osext.Executable()
@@ -183,13 +182,34 @@ func runScriptAction(w *currentWorker) BuiltInAction {
 				chanRes <- res
 			}
 
-			log.Info("Worker binary path: %s", path.Dir(workerpath))
-			for i := range cmd.Env {
-				if strings.HasPrefix(cmd.Env[i], "PATH") {
-					cmd.Env[i] = fmt.Sprintf("%s:%s", cmd.Env[i], path.Dir(workerpath))
-					break
+			var cmd *exec.Cmd
+			if image := w.currentJob.stepImage; image != "" {
+				// The step runs in a container of the image of the step
+				containerName = fmt.Sprintf("cds-step-%d-%d", buildID, time.Now().UnixNano())
+				var errd error
+				cmd, errd = dockerCommand(ctx, image, containerName, w.basedir, workerpath, shell, opts, hostEnv, jobEnv)
+				if errd != nil {
+					res.Reason = fmt.Sprintf("cannot run step in image %s: %s\n", image, errd)
+					sendLog(res.Reason)
+					res.Status = sdk.StatusFail.String()
+					chanRes <- res
+					return
+				}
+				sendLog(fmt.Sprintf("Running step in image %s\n", image))
+			} else {
+				log.Info("runScriptAction> %s %s", shell, strings.Trim(fmt.Sprint(opts), "[]"))
+				cmd = exec.CommandContext(ctx, shell, opts...)
+				cmd.Env = append(hostEnv, jobEnv...)
+
+				log.Info("Worker binary path: %s", path.Dir(workerpath))
+				for i := range cmd.Env {
+					if strings.HasPrefix(cmd.Env[i], "PATH") {
+						cmd.Env[i] = fmt.Sprintf("%s:%s", cmd.Env[i], path.Dir(workerpath))
+						break
+					}
 				}
 			}
+			setProcessGroup(cmd)
 
 			stdout, err := cmd.StdoutPipe()
 			if err != nil {
@@ -245,6 +265,23 @@ func runScriptAction(w *currentWorker) BuiltInAction {
 				chanRes <- res
 			}
 
+			// Kill the processes started by the script when the step is canceled, they would keep the outputs open
+			done := make(chan struct{})
+			defer close(done)
+			go func() {
+				select {
+				case <-ctx.Done():
+					if err := killProcessGroup(cmd); err != nil {
+						log.Warning("runScriptAction> cannot kill process group: %s", err)
+					}
+					// Killing the docker client does not stop the container
+					if containerName != "" {
+						removeStepContainer(containerName)
+					}
+				case <-done:
+				}
+			}()
+
 			<-outchan
 			<-errchan
 			if err := cmd.Wait(); err != nil {
@@ -252,6 +289,7 @@ func runScriptAction(w *currentWorker) BuiltInAction {
 				sendLog(res.Reason)
 				res.Status = sdk.StatusFail.String()
 				chanRes <- res
+				return
 			}
 
 			res.Status = sdk.StatusSuccess.String()
@@ -264,11 +302,15 @@ func runScriptAction(w *currentWorker) BuiltInAction {
 		// Wait for a result
 		select {
 		case <-ctx.Done():
-			log.Error("CDS Worker execution canceled: %v", ctx.Err())
-			sendLog("CDS Worker execution canceled")
+			reason := "CDS Worker execution canceled"
+			if ctx.Err() == context.DeadlineExceeded {
+				reason = "CDS Worker execution canceled: timeout exceeded"
+			}
+			log.Error("%s: %v", reason, ctx.Err())
+			sendLog(reason)
 			res = sdk.Result{
 				Status: sdk.StatusFail.String(),
-				Reason: "CDS Worker execution canceled",
+				Reason: reason,
 			}
 			break
 
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/ovh/cds/sdk/log"
+)
+
+// stepWorkerPath is the path of the worker binary in the containers of the steps
+const stepWorkerPath = "/usr/local/bin/worker"
+
+// dockerEnvFile is created by docker at the root of the filesystem of its containers
+const dockerEnvFile = "/.dockerenv"
+
+// dockerCommand returns the command running a script in a container of the image of a step. The base directory
+// of the worker, which contains the workspace and the script, is bind-mounted at the same path, and the container
+// uses the network of the host so that the worker commands reach the worker. Job variables are given by name
+// to the docker client, so that their values don't appear in the command line.
+// As the bind mounts are paths of the docker host, step images are not supported when the worker itself runs in
+// a container, ie. with the docker socket of the host
+func dockerCommand(ctx context.Context, image, name, basedir, workerpath, shell string, opts []string, hostEnv, jobEnv []string) (*exec.Cmd, error) {
+	if runtime.GOOS == "windows" {
+		return nil, fmt.Errorf("step images are not supported on windows")
+	}
+	if _, err := os.Stat(dockerEnvFile); err == nil {
+		return nil, fmt.Errorf("step images are not supported when the worker runs in a container")
+	}
+	if _, err := exec.LookPath("docker"); err != nil {
+		return nil, fmt.Errorf("docker not found on this worker")
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"run", "--rm",
+		"--name", name,
+		"--network", "host",
+		"-v", basedir + ":" + basedir,
+		"-v", workerpath + ":" + stepWorkerPath + ":ro",
+		"-w", wd,
+	}
+	for _, e := range jobEnv {
+		args = append(args, "-e", strings.SplitN(e, "=", 2)[0])
+	}
+	args = append(args, "--entrypoint", shell, image)
+	args = append(args, opts...)
+
+	log.Info("runScriptAction> docker run %s %s %s", name, image, strings.Trim(fmt.Sprint(opts), "[]"))
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	cmd.Env = append(hostEnv, jobEnv...)
+	return cmd, nil
+}
+
+// removeStepContainer removes the container of a canceled step
+func removeStepContainer(name string) {
+	if out, err := exec.Command("docker", "rm", "-f", name).CombinedOutput(); err != nil {
+		log.Warning("removeStepContainer> cannot remove container %s: %s (%s)", name, err, strings.TrimSpace(string(out)))
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+var cmdExecReport string
+
+func cmdExec(w *currentWorker) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "exec",
+		Short: "worker exec [--report <file>]",
+		Long:  "Run the job read on the standard input in the current directory, without CDS API. It is used by cdsctl pipeline exec",
+		Run:   execCmd(w),
+	}
+	c.Flags().StringVar(&cmdExecReport, "report", "", "File where the report of the job is written, the standard output if empty")
+	return c
+}
+
+func execCmd(w *currentWorker) func(cmd *cobra.Command, args []string) {
+	return func(cmd *cobra.Command, args []string) {
+		log.Initialize(&log.Conf{Level: viper.GetString("log_level")})
+
+		data, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			sdk.Exit("cannot read job: %s\n", err)
+		}
+		var job sdk.LocalJob
+		if err := json.Unmarshal(data, &job); err != nil {
+			sdk.Exit("invalid job: %s\n", err)
+		}
+
+		w.status.Name, _ = os.Hostname()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		sigs := make(chan os.Signal, 1)
+		signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-sigs
+			cancel()
+		}()
+
+		report := w.runLocalJob(ctx, job)
+
+		out, err := json.Marshal(report)
+		if err != nil {
+			sdk.Exit("cannot marshal report: %s\n", err)
+		}
+		if cmdExecReport == "" {
+			os.Stdout.Write(out)
+			return
+		}
+		if err := ioutil.WriteFile(cmdExecReport, out, 0600); err != nil {
+			sdk.Exit("cannot write report: %s\n", err)
+		}
+	}
+}
@@ -17,10 +17,16 @@ import (
 	"github.com/ovh/cds/sdk/log"
 )
 
-var cmdExport = &cobra.Command{
-	Use:   "export",
-	Short: "worker export <varname> <value>",
-	Run:   exportCmd,
+var cmdExportSecret bool
+
+func cmdExport() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "export",
+		Short: "worker export [--secret] <varname> <value>",
+		Run:   exportCmd,
+	}
+	c.Flags().BoolVar(&cmdExportSecret, "secret", false, "Export the variable as a secret, its value is hidden in logs")
+	return c
 }
 
 func exportCmd(cmd *cobra.Command, args []string) {
@@ -43,6 +49,9 @@ func exportCmd(cmd *cobra.Command, args []string) {
 		Type:  sdk.StringVariable,
 		Value: args[1],
 	}
+	if cmdExportSecret {
+		v.Type = sdk.SecretVariable
+	}
 
 	data, err := json.Marshal(v)
 	if err != nil {
@@ -88,6 +97,11 @@ func (wk *currentWorker) addBuildVarHandler(w http.ResponseWriter, r *http.Reque
 }
 
 func (wk *currentWorker) addVariableInPipelineBuild(v sdk.Variable, params *[]sdk.Parameter) (int, error) {
+	// Exported secrets must be hidden in logs like the secrets of the job
+	if sdk.NeedPlaceholder(v.Type) {
+		wk.logger.secrets.Add(v)
+	}
+
 	// OK, so now we got our new variable. We need to:
 	// - add it as a build var in API
 	if strings.HasPrefix(v.Name, "cds.build") {
@@ -100,6 +114,11 @@ func (wk *currentWorker) addVariableInPipelineBuild(v sdk.Variable, params *[]sd
 		})
 	}
 
+	if wk.local != nil {
+		wk.local.report.Variables = append(wk.local.report.Variables, v)
+		return http.StatusOK, nil
+	}
+
 	// - add it in current building Action
 	data, errm := json.Marshal(v)
 	if errm != nil {
@@ -16,6 +16,7 @@ import (
 	"github.com/ovh/cds/engine/api/worker"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/tracing"
 )
 
 func cmdMain(w *currentWorker) *cobra.Command {
@@ -86,6 +87,12 @@ func cmdMain(w *currentWorker) *cobra.Command {
 	flags.String("graylog-extra-value", "", "Ex: --graylog-extra-value=xxxx-yyyy")
 	viper.BindPFlag("graylog_extra_value", flags.Lookup("graylog-extra-value"))
 
+	flags.String("tracing-exporter", "", "Exporter of the spans of the jobs, otlp or stdout. Ex: --tracing-exporter=otlp")
+	viper.BindPFlag("tracing_exporter", flags.Lookup("tracing-exporter"))
+
+	flags.String("tracing-endpoint", "", "Ex: --tracing-endpoint=http://localhost:4318/v1/traces")
+	viper.BindPFlag("tracing_endpoint", flags.Lookup("tracing-endpoint"))
+
 	return mainCmd
 }
 
@@ -154,6 +161,7 @@ func mainCommandRun(w *currentWorker) func(cmd *cobra.Command, args []string) {
 
 		// start logger routine with a large buffer
 		w.logger.logChan = make(chan sdk.Log, 100000)
+		w.logger.secrets = newSecretMasker()
 		go w.logProcessor(ctx)
 
 		// start queue polling
@@ -180,6 +188,7 @@ func mainCommandRun(w *currentWorker) func(cmd *cobra.Command, args []string) {
 			w.drainLogsAndCloseLogger(ctx)
 			registerTick.Stop()
 			w.unregister()
+			tracing.Shutdown()
 			cancel()
 
 			if viper.GetBool("force_exit") {
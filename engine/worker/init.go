@@ -11,6 +11,7 @@ import (
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/cdsclient"
 	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/tracing"
 )
 
 func initViper(w *currentWorker) {
@@ -26,6 +27,13 @@ func initViper(w *currentWorker) {
 		GraylogExtraValue: viper.GetString("graylog_extra_value"),
 	})
 
+	if exporter := viper.GetString("tracing_exporter"); exporter != "" {
+		cfg := tracing.Configuration{Enable: true, Exporter: exporter, Endpoint: viper.GetString("tracing_endpoint")}
+		if err := tracing.Init(cfg, "cds-worker"); err != nil {
+			log.Error("Cannot initialize tracing: %s", err)
+		}
+	}
+
 	var errN error
 	w.status.Name, errN = os.Hostname()
 	if errN != nil {
@@ -109,6 +117,7 @@ func (w *currentWorker) initGRPCConn() {
 		if viper.GetBool("grpc_insecure") {
 			opts = append(opts, grpc.WithInsecure())
 		}
+		opts = append(opts, grpc.WithUnaryInterceptor(tracing.UnaryClientInterceptor), grpc.WithStreamInterceptor(tracing.StreamClientInterceptor))
 
 		var err error
 		w.grpc.conn, err = grpc.Dial(w.grpc.address, opts...)
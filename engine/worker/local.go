@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ovh/venom"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// localRun is the state of a job run by "worker exec": logs are written on the standard output, artifacts
+// are copied in a local directory and nothing is sent to the API
+type localRun struct {
+	job     sdk.LocalJob
+	report  sdk.LocalJobReport
+	started map[int]time.Time
+}
+
+// localSkippedActions are the builtin actions which are not run by "worker exec", as the current directory
+// is used as workspace and nothing must be pushed from the machine of a user
+var localSkippedActions = map[string]bool{
+	sdk.GitCloneAction: true,
+	sdk.GitTagAction:   true,
+	sdk.ReleaseAction:  true,
+}
+
+// runLocalJob runs a job in the current directory
+func (w *currentWorker) runLocalJob(ctx context.Context, job sdk.LocalJob) sdk.LocalJobReport {
+	t0 := time.Now()
+	w.local = &localRun{
+		job:     job,
+		report:  sdk.LocalJobReport{Name: job.Name},
+		started: map[int]time.Time{},
+	}
+	for _, s := range job.Action.Actions {
+		w.local.report.Steps = append(w.local.report.Steps, sdk.LocalStepReport{Name: s.Name})
+	}
+	report := &w.local.report
+	fail := func(reason string) sdk.LocalJobReport {
+		report.Status = sdk.StatusFail.String()
+		report.Reason = reason
+		report.Duration = time.Since(t0)
+		return *report
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return fail(fmt.Sprintf("Error: cannot get current directory: %s", err))
+	}
+	w.basedir = wd
+	w.logger.secrets = newSecretMasker()
+
+	keysDirectory, err = ioutil.TempDir("", "cds-keys")
+	if err != nil {
+		return fail(fmt.Sprintf("Error: cannot setup keys directory: %s", err))
+	}
+	defer os.RemoveAll(keysDirectory)
+
+	port, err := w.serve(ctx)
+	if err != nil {
+		return fail(fmt.Sprintf("Error: cannot bind port for worker export: %s", err))
+	}
+	w.exportPort = port
+
+	ctx, cancel := context.WithTimeout(ctx, job.Action.JobTimeout())
+	defer cancel()
+
+	params := job.Parameters
+	sdk.AddParameter(&params, "cds.workspace", sdk.StringParameter, wd)
+	sdk.AddParameter(&params, "cds.worker", sdk.StringParameter, w.status.Name)
+	processJobParameter(&params, nil)
+	if err := w.processActionVariables(&job.Action, nil, params, nil); err != nil {
+		return fail(fmt.Sprintf("Error: cannot process action %s parameters", job.Action.Name))
+	}
+	for _, p := range params {
+		if sdk.NeedPlaceholder(p.Type) {
+			w.logger.secrets.Add(sdk.Variable{Name: p.Name, Type: p.Type, Value: p.Value})
+		}
+	}
+
+	if err := w.setupServices(ctx, &job.Action, &params); err != nil {
+		return fail(fmt.Sprintf("Error: %s", err))
+	}
+
+	res := w.startAction(ctx, &job.Action, 0, &params, -1, "")
+	w.jobTimedOut(ctx, &job.Action, &res)
+
+	report.Status = res.Status
+	report.Reason = res.Reason
+	report.Duration = time.Since(t0)
+	return *report
+}
+
+// stepStatus records the status of a step in the report
+func (l *localRun) stepStatus(stepOrder int, status string) {
+	if stepOrder < 0 || stepOrder >= len(l.report.Steps) {
+		return
+	}
+	step := &l.report.Steps[stepOrder]
+	if status == sdk.StatusBuilding.String() {
+		l.started[stepOrder] = time.Now()
+	} else if t, ok := l.started[stepOrder]; ok {
+		step.Duration = time.Since(t)
+	}
+	step.Status = status
+}
+
+// addTests adds the results of tests in the report
+func (l *localRun) addTests(tests venom.Tests) {
+	if l.report.Tests == nil {
+		l.report.Tests = &venom.Tests{}
+	}
+	l.report.Tests.TestSuites = append(l.report.Tests.TestSuites, tests.TestSuites...)
+	l.report.Tests.Total += tests.Total
+	l.report.Tests.TotalOK += tests.TotalOK
+	l.report.Tests.TotalKO += tests.TotalKO
+	l.report.Tests.TotalSkipped += tests.TotalSkipped
+}
+
+// runLocalArtifactUpload copies artifacts in the artifacts directory of a local run
+func runLocalArtifactUpload(w *currentWorker) BuiltInAction {
+	return func(ctx context.Context, a *sdk.Action, buildID int64, params *[]sdk.Parameter, sendLog LoggerFunc) sdk.Result {
+		res := sdk.Result{Status: sdk.StatusSuccess.String()}
+
+		path := sdk.ParameterValue(a.Parameters, "path")
+		if path == "" {
+			path = "."
+		}
+
+		filesPath, err := filepath.Glob(path)
+		if err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("cannot perform globbing of pattern '%s': %s", path, err)
+			sendLog(res.Reason)
+			return res
+		}
+
+		if len(filesPath) == 0 {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("Pattern '%s' matched no file", path)
+			sendLog(res.Reason)
+			return res
+		}
+
+		dir := w.local.job.ArtifactsDirectory
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("cannot create artifacts directory %s: %s", dir, err)
+			sendLog(res.Reason)
+			return res
+		}
+
+		for _, filePath := range filesPath {
+			filename := filepath.Base(filePath)
+			sendLog(fmt.Sprintf("Copying '%s' in %s", filename, dir))
+			if err := copyFile(filePath, filepath.Join(dir, filename)); err != nil {
+				res.Status = sdk.StatusFail.String()
+				res.Reason = fmt.Sprintf("Error while copying artifact: %s\n", err)
+				sendLog(res.Reason)
+				return res
+			}
+			w.local.report.Artifacts = append(w.local.report.Artifacts, filename)
+		}
+
+		return res
+	}
+}
+
+// runLocalArtifactDownload copies the artifacts of the previous jobs of a local run
+func runLocalArtifactDownload(w *currentWorker) BuiltInAction {
+	return func(ctx context.Context, a *sdk.Action, buildID int64, params *[]sdk.Parameter, sendLog LoggerFunc) sdk.Result {
+		res := sdk.Result{Status: sdk.StatusSuccess.String()}
+
+		enabled := sdk.ParameterValue(*params, "enabled") != "false"
+		destPath := sdk.ParameterValue(a.Parameters, "path")
+
+		if !enabled {
+			sendLog("Artifact Download is disabled.")
+			return res
+		}
+
+		dir := w.local.job.ArtifactsDirectory
+		files, err := ioutil.ReadDir(dir)
+		if err != nil && !os.IsNotExist(err) {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("cannot read artifacts directory %s: %s", dir, err)
+			sendLog(res.Reason)
+			return res
+		}
+
+		sendLog(fmt.Sprintf("Copying artifacts from %s into '%s'...", dir, destPath))
+		for _, f := range files {
+			if f.IsDir() {
+				continue
+			}
+			if err := copyFile(filepath.Join(dir, f.Name()), filepath.Join(destPath, f.Name())); err != nil {
+				res.Status = sdk.StatusFail.String()
+				res.Reason = err.Error()
+				sendLog(res.Reason)
+				return res
+			}
+		}
+
+		return res
+	}
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	fi, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(dst, os.O_RDWR|os.O_CREATE|os.O_TRUNC, fi.Mode())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	log.Debug("copyFile> %s copied to %s", src, dst)
+	return nil
+}
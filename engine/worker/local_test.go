@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_runLocalJobMasksSecrets(t *testing.T) {
+	script := sdk.NewScriptAction("echo password={{.cds.proj.password}} message={{.cds.pip.message}}")
+	script.Enabled = true
+	job := sdk.LocalJob{
+		Name: "job",
+		Action: sdk.Action{
+			Name:    "job",
+			Enabled: true,
+			Actions: []sdk.Action{script},
+		},
+		Parameters: []sdk.Parameter{
+			{Name: "cds.proj.password", Type: sdk.SecretVariable, Value: "my-s3cr3t"},
+			{Name: "cds.pip.message", Type: sdk.StringParameter, Value: "hello"},
+		},
+	}
+
+	r, wr, err := os.Pipe()
+	assert.NoError(t, err)
+	stdout := os.Stdout
+	os.Stdout = wr
+	w := &currentWorker{}
+	report := w.runLocalJob(context.Background(), job)
+	os.Stdout = stdout
+	wr.Close()
+	out, err := ioutil.ReadAll(r)
+	assert.NoError(t, err)
+
+	assert.Equal(t, sdk.StatusSuccess.String(), report.Status, report.Reason)
+	assert.Contains(t, string(out), "password=**cds.proj.password** message=hello")
+	assert.False(t, strings.Contains(string(out), "my-s3cr3t"), string(out))
+}
@@ -16,13 +16,18 @@ import (
 	"github.com/ovh/cds/sdk/log"
 )
 
-var logsecrets []sdk.Variable
-
 func (w *currentWorker) sendLog(buildID int64, value string, stepOrder int, final bool) error {
-	for i := range logsecrets {
-		if len(logsecrets[i].Value) >= 6 {
-			value = strings.Replace(value, logsecrets[i].Value, "**"+logsecrets[i].Name+"**", -1)
+	value = w.logger.secrets.MaskStep(stepOrder, value, final)
+	if value == "" && !final {
+		return nil
+	}
+
+	if w.local != nil {
+		if !strings.HasSuffix(value, "\n") {
+			value += "\n"
 		}
+		fmt.Print(value)
+		return nil
 	}
 
 	var id = w.currentJob.pbJob.PipelineBuildID
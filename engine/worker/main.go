@@ -21,6 +21,7 @@ type currentWorker struct {
 	logger        struct {
 		logChan chan sdk.Log
 		llist   *list.List
+		secrets *secretMasker
 	}
 	exportPort int
 	hatchery   struct {
@@ -39,12 +40,14 @@ type currentWorker struct {
 		pkey           string
 		gitsshPath     string
 		params         []sdk.Parameter
+		stepImage      string
 	}
 	status struct {
 		Name   string `json:"name"`
 		Status string `json:"status"`
 	}
 	client cdsclient.Interface
+	local  *localRun
 }
 
 func main() {
@@ -52,10 +55,11 @@ func main() {
 
 	w := &currentWorker{}
 	cmd := cmdMain(w)
-	cmd.AddCommand(cmdExport)
+	cmd.AddCommand(cmdExport())
 	cmd.AddCommand(cmdUpload(w))
 	cmd.AddCommand(cmdTmpl(w))
 	cmd.AddCommand(cmdVersion)
 	cmd.AddCommand(cmdRegister(w))
+	cmd.AddCommand(cmdExec(w))
 	cmd.Execute()
 }
@@ -125,7 +125,8 @@ func checkBinaryRequirement(w *currentWorker, r sdk.Requirement) (bool, error) {
 }
 
 func checkModelRequirement(w *currentWorker, r sdk.Requirement) (bool, error) {
-	wm, err := sdk.GetWorkerModel(r.Value)
+	name, _ := sdk.ParseModelRequirement(r.Value)
+	wm, err := sdk.GetWorkerModel(name)
 	if err != nil {
 		return false, nil
 	}
@@ -207,14 +207,35 @@ func (w *currentWorker) runSteps(ctx context.Context, steps []sdk.Action, a *sdk
 			continue
 		}
 
-		if !criticalStepFailed || child.AlwaysExecuted {
+		run, skipped, errCond := stepShouldRun(child, params, criticalStepFailed)
+		if errCond != nil {
+			if err := w.updateStepStatus(buildID, w.currentJob.currentStep, sdk.StatusFail.String()); err != nil {
+				log.Warning("Cannot update step (%d) status (%s) for build %d: %s", w.currentJob.currentStep, sdk.StatusFail.String(), buildID, err)
+			}
+			w.sendLog(buildID, fmt.Sprintf("End of step %s [%s]: invalid conditions: %s\n", childName, sdk.StatusFail, errCond), w.currentJob.currentStep, true)
+			if !child.Optional {
+				criticalStepFailed = true
+			}
+			continue
+		}
+
+		if skipped {
+			// Update step status
+			if err := w.updateStepStatus(buildID, w.currentJob.currentStep, sdk.StatusSkipped.String()); err != nil {
+				log.Warning("Cannot update step (%d) status (%s) for build %d: %s", w.currentJob.currentStep, sdk.StatusSkipped.String(), buildID, err)
+			}
+			w.sendLog(buildID, fmt.Sprintf("End of step %s [%s]: conditions not met\n", childName, sdk.StatusSkipped), w.currentJob.currentStep, true)
+			continue
+		}
+
+		if run {
 			// Update step status
 			if err := w.updateStepStatus(buildID, w.currentJob.currentStep, sdk.StatusBuilding.String()); err != nil {
 				log.Warning("Cannot update step (%d) status (%s) for build %d: %s\n", w.currentJob.currentStep, sdk.StatusDisabled.String(), buildID, err)
 			}
 			w.sendLog(buildID, fmt.Sprintf("Starting step %s\n", childName), w.currentJob.currentStep, false)
 
-			r = w.startAction(ctx, &child, buildID, params, w.currentJob.currentStep, childName)
+			r = w.startStep(ctx, &child, buildID, params, w.currentJob.currentStep, childName)
 			if r.Status != sdk.StatusSuccess.String() && !child.Optional {
 				criticalStepFailed = true
 			}
@@ -225,7 +246,7 @@ func (w *currentWorker) runSteps(ctx context.Context, steps []sdk.Action, a *sdk
 			if err := w.updateStepStatus(buildID, w.currentJob.currentStep, r.Status); err != nil {
 				log.Warning("Cannot update step (%d) status (%s) for build %d: %s", w.currentJob.currentStep, sdk.StatusDisabled.String(), buildID, err)
 			}
-		} else if criticalStepFailed && !child.AlwaysExecuted { // Update status of steps which are never built
+		} else { // Update status of steps which are never built
 			// Update step status
 			if err := w.updateStepStatus(buildID, w.currentJob.currentStep, sdk.StatusNeverBuilt.String()); err != nil {
 				log.Warning("Cannot update step (%d) status (%s) for build %d: %s", w.currentJob.currentStep, sdk.StatusNeverBuilt.String(), buildID, err)
@@ -242,7 +263,87 @@ func (w *currentWorker) runSteps(ctx context.Context, steps []sdk.Action, a *sdk
 	return r, nbDisabledChildren
 }
 
+// stepShouldRun returns if a step must run or is skipped because its conditions are not met. Steps which are not
+// always executed never run once a step has failed, unless their conditions are on the status of the job
+func stepShouldRun(step sdk.Action, params *[]sdk.Parameter, failed bool) (bool, bool, error) {
+	var statusCondition bool
+	for _, c := range step.Conditions {
+		if c.Variable == sdk.JobStatusParameter {
+			statusCondition = true
+		}
+	}
+	if failed && !step.AlwaysExecuted && !statusCondition {
+		return false, false, nil
+	}
+	if len(step.Conditions) == 0 {
+		return true, false, nil
+	}
+
+	jobStatus := sdk.StatusSuccess
+	if failed {
+		jobStatus = sdk.StatusFail
+	}
+	var conditionParams []sdk.Parameter
+	if params != nil {
+		conditionParams = append(conditionParams, *params...)
+	}
+	sdk.AddParameter(&conditionParams, sdk.JobStatusParameter, sdk.StringParameter, jobStatus.String())
+
+	ok, err := sdk.WorkflowCheckConditions(step.Conditions, conditionParams)
+	if err != nil {
+		return false, false, err
+	}
+	return ok, !ok, nil
+}
+
+// startStep starts a step, canceled when its timeout is exceeded. The scripts of a step with an image
+// run in a container of this image
+func (w *currentWorker) startStep(ctx context.Context, a *sdk.Action, buildID int64, params *[]sdk.Parameter, stepOrder int, stepName string) sdk.Result {
+	if a.Image != "" {
+		image := a.Image
+		for _, p := range *params {
+			image = strings.Replace(image, "{{."+p.Name+"}}", p.Value, -1)
+		}
+		previousImage := w.currentJob.stepImage
+		w.currentJob.stepImage = image
+		defer func() {
+			w.currentJob.stepImage = previousImage
+		}()
+	}
+
+	if a.Timeout <= 0 {
+		return w.startAction(ctx, a, buildID, params, stepOrder, stepName)
+	}
+
+	timeout := time.Duration(a.Timeout) * time.Second
+	stepCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	r := w.startAction(stepCtx, a, buildID, params, stepOrder, stepName)
+	if stepCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+		r.Status = sdk.StatusFail.String()
+		r.Reason = fmt.Sprintf("Step %s timed out after %s", stepName, timeout)
+		w.sendLog(buildID, r.Reason+"\n", stepOrder, false)
+	}
+	return r
+}
+
+// jobTimedOut sets the result of a job canceled because its timeout was exceeded
+func (w *currentWorker) jobTimedOut(ctx context.Context, a *sdk.Action, res *sdk.Result) {
+	if ctx.Err() != context.DeadlineExceeded {
+		return
+	}
+	res.Status = sdk.StatusFail.String()
+	res.Reason = fmt.Sprintf("Job %s timed out after %s", a.Name, a.JobTimeout())
+	log.Warning("%s", res.Reason)
+}
+
 func (w *currentWorker) updateStepStatus(pbJobID int64, stepOrder int, status string) error {
+	if w.local != nil {
+		w.local.stepStatus(stepOrder, status)
+		return nil
+	}
+
 	step := sdk.StepStatus{
 		StepOrder: stepOrder,
 		Status:    status,
@@ -311,7 +412,7 @@ func workingDirectory(basedir, jobPath string) string {
 
 func (w *currentWorker) processJob(ctx context.Context, jobInfo *worker.WorkflowNodeJobRunInfo) sdk.Result {
 	t0 := time.Now()
-	ctx, cancel := context.WithTimeout(ctx, 6*time.Hour)
+	ctx, cancel := context.WithTimeout(ctx, jobInfo.NodeJobRun.Job.Action.JobTimeout())
 
 	log.Debug("processJob> Begin %p", ctx)
 	defer log.Debug("processJob> End %p", ctx)
@@ -400,9 +501,19 @@ func (w *currentWorker) processJob(ctx context.Context, jobInfo *worker.Workflow
 		}
 	}
 
-	logsecrets = jobInfo.Secrets
+	// Wait for the services of the job before the first step
+	if err := w.setupServices(ctx, &jobInfo.NodeJobRun.Job.Action, &jobInfo.NodeJobRun.Parameters); err != nil {
+		log.Warning("processJob> %s", err)
+		return sdk.Result{
+			Status: sdk.StatusFail.String(),
+			Reason: fmt.Sprintf("Error: %s", err),
+		}
+	}
+
+	w.logger.secrets.Reset(jobInfo.Secrets)
 	res := w.startAction(ctx, &jobInfo.NodeJobRun.Job.Action, jobInfo.NodeJobRun.ID, &jobInfo.NodeJobRun.Parameters, -1, "")
-	logsecrets = nil
+	w.logger.secrets.Reset(nil)
+	w.jobTimedOut(ctx, &jobInfo.NodeJobRun.Job.Action, &res)
 
 	if err := teardownBuildDirectory(wd); err != nil {
 		log.Error("Cannot remove build directory: %s", err)
@@ -411,7 +522,7 @@ func (w *currentWorker) processJob(ctx context.Context, jobInfo *worker.Workflow
 }
 
 func (w *currentWorker) run(ctx context.Context, pbji *worker.PipelineBuildJobInfo) sdk.Result {
-	ctx, cancel := context.WithTimeout(ctx, 6*time.Hour)
+	ctx, cancel := context.WithTimeout(ctx, pbji.PipelineBuildJob.Job.Action.JobTimeout())
 	defer cancel()
 
 	log.Debug("run> Begin %p", ctx)
@@ -504,10 +615,20 @@ func (w *currentWorker) run(ctx context.Context, pbji *worker.PipelineBuildJobIn
 		}
 	}
 
-	logsecrets = pbji.Secrets
+	// Wait for the services of the job before the first step
+	if err := w.setupServices(ctx, &pbji.PipelineBuildJob.Job.Action, &pbji.PipelineBuildJob.Parameters); err != nil {
+		log.Warning("run> %s", err)
+		return sdk.Result{
+			Status: sdk.StatusFail.String(),
+			Reason: fmt.Sprintf("Error: %s", err),
+		}
+	}
+
+	w.logger.secrets.Reset(pbji.Secrets)
 
 	res := w.startAction(ctx, &pbji.PipelineBuildJob.Job.Action, pbji.PipelineBuildJob.ID, &pbji.PipelineBuildJob.Parameters, -1, "")
-	logsecrets = nil
+	w.logger.secrets.Reset(nil)
+	w.jobTimedOut(ctx, &pbji.PipelineBuildJob.Job.Action, &res)
 
 	if err := teardownBuildDirectory(wd); err != nil {
 		log.Error("Cannot remove build directory: %s", err)
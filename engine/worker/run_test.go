@@ -50,3 +50,35 @@ func Test_processJobParameter(t *testing.T) {
 		assert.EqualValues(t, tt.want, tt.args.pbJob.Parameters)
 	}
 }
+
+func Test_stepShouldRun(t *testing.T) {
+	params := &[]sdk.Parameter{{Name: "git.branch", Value: "master"}}
+	onFailure := []sdk.WorkflowTriggerCondition{{Variable: sdk.JobStatusParameter, Operator: sdk.WorkflowConditionsOperatorEquals, Value: sdk.StatusFail.String()}}
+	onMaster := []sdk.WorkflowTriggerCondition{{Variable: "git.branch", Operator: sdk.WorkflowConditionsOperatorEquals, Value: "master"}}
+	onDevelop := []sdk.WorkflowTriggerCondition{{Variable: "git.branch", Operator: sdk.WorkflowConditionsOperatorEquals, Value: "develop"}}
+
+	testcases := []struct {
+		name    string
+		step    sdk.Action
+		failed  bool
+		run     bool
+		skipped bool
+	}{
+		{name: "no conditions", step: sdk.Action{}, run: true},
+		{name: "no conditions after failure", step: sdk.Action{}, failed: true},
+		{name: "always executed after failure", step: sdk.Action{AlwaysExecuted: true}, failed: true, run: true},
+		{name: "on failure without failure", step: sdk.Action{Conditions: onFailure}, skipped: true},
+		{name: "on failure after failure", step: sdk.Action{Conditions: onFailure}, failed: true, run: true},
+		{name: "conditions met", step: sdk.Action{Conditions: onMaster}, run: true},
+		{name: "conditions not met", step: sdk.Action{Conditions: onDevelop}, skipped: true},
+		{name: "conditions met after failure", step: sdk.Action{Conditions: onMaster}, failed: true},
+		{name: "always executed with conditions not met", step: sdk.Action{AlwaysExecuted: true, Conditions: onDevelop}, failed: true, skipped: true},
+	}
+
+	for _, tc := range testcases {
+		run, skipped, err := stepShouldRun(tc.step, params, tc.failed)
+		assert.NoError(t, err, tc.name)
+		assert.Equal(t, tc.run, run, tc.name)
+		assert.Equal(t, tc.skipped, skipped, tc.name)
+	}
+}
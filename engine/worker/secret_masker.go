@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// secrets shorter than this are not masked, they would hide too much of the logs
+const secretMinLength = 6
+
+// width of the lines of base64 encoded values, as wrapped by the base64 command
+const base64LineWidth = 76
+
+// maskedValue is a secret or one of its encoded forms
+type maskedValue struct {
+	value       string
+	replacement string
+}
+
+// secretMasker hides secrets and their derived forms in logs. Secrets spanning several lines are
+// matched across the log lines of a step: lines which may be the beginning of a secret are held
+// until the secret is complete or the step ends
+type secretMasker struct {
+	mutex   sync.Mutex
+	values  []maskedValue
+	pending map[int]string
+}
+
+func newSecretMasker() *secretMasker {
+	return &secretMasker{pending: map[int]string{}}
+}
+
+// Reset replaces the secrets to mask and drops held lines
+func (m *secretMasker) Reset(secrets []sdk.Variable) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.values = nil
+	m.pending = map[int]string{}
+	for _, s := range secrets {
+		m.add(s)
+	}
+}
+
+// Add masks a new secret, ie. a secret variable exported during the job
+func (m *secretMasker) Add(secret sdk.Variable) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.add(secret)
+}
+
+func (m *secretMasker) add(secret sdk.Variable) {
+	replacement := "**" + secret.Name + "**"
+	exist := make(map[string]bool, len(m.values))
+	for _, v := range m.values {
+		exist[v.value] = true
+	}
+	for _, v := range secretVariants(secret.Value) {
+		if len(v) < secretMinLength || exist[v] {
+			continue
+		}
+		exist[v] = true
+		m.values = append(m.values, maskedValue{value: v, replacement: replacement})
+	}
+	// Longest values first, so that a value is not partially replaced by a shorter one
+	sort.SliceStable(m.values, func(i, j int) bool { return len(m.values[i].value) > len(m.values[j].value) })
+}
+
+// secretVariants returns a secret and the forms it may take in logs once encoded
+func secretVariants(secret string) []string {
+	if len(secret) < secretMinLength {
+		return nil
+	}
+
+	variants := []string{secret}
+	if strings.Contains(secret, "\n") {
+		variants = append(variants, strings.Replace(secret, "\n", "\r\n", -1))
+		// Lines of a multiline secret may be printed alone, the headers of PEM blocks are not secret
+		for _, l := range strings.Split(secret, "\n") {
+			l = strings.TrimSpace(l)
+			if !strings.HasPrefix(l, "-----") {
+				variants = append(variants, l)
+			}
+		}
+	}
+
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		variants = append(variants, enc.EncodeToString([]byte(secret)))
+	}
+	if encoded := base64.StdEncoding.EncodeToString([]byte(secret)); len(encoded) > base64LineWidth {
+		var wrapped []string
+		for len(encoded) > base64LineWidth {
+			wrapped = append(wrapped, encoded[:base64LineWidth])
+			encoded = encoded[base64LineWidth:]
+		}
+		variants = append(variants, strings.Join(append(wrapped, encoded), "\n"))
+	}
+
+	variants = append(variants, url.QueryEscape(secret), url.PathEscape(secret))
+	// JSON escaped, with and without escaping of HTML characters
+	if b, err := json.Marshal(secret); err == nil {
+		variants = append(variants, strings.Trim(string(b), `"`))
+	}
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(secret); err == nil {
+		variants = append(variants, strings.Trim(strings.TrimSpace(buf.String()), `"`))
+	}
+	return variants
+}
+
+// Mask hides the secrets contained in a string
+func (m *secretMasker) Mask(s string) string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.mask(s)
+}
+
+func (m *secretMasker) mask(s string) string {
+	for _, v := range m.values {
+		s = strings.Replace(s, v.value, v.replacement, -1)
+	}
+	return s
+}
+
+// MaskStep hides the secrets in the logs of a step. The end of the logs which may be the beginning of a
+// multiline secret is held and returned with the next logs of the step, or with the final logs
+func (m *secretMasker) MaskStep(stepOrder int, s string, final bool) string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	s = m.pending[stepOrder] + s
+	delete(m.pending, stepOrder)
+	if !final {
+		if i := m.heldIndex(s); i >= 0 {
+			m.pending[stepOrder] = s[i:]
+			s = s[:i]
+		}
+	}
+	return m.mask(s)
+}
+
+// heldIndex returns the index of the first end of s spanning several lines which is the beginning of a
+// multiline secret, -1 if there is none
+func (m *secretMasker) heldIndex(s string) int {
+	last := strings.LastIndex(s, "\n")
+	if last < 0 {
+		return -1
+	}
+	for i := 0; i < last; i++ {
+		end := s[i:]
+		for _, v := range m.values {
+			if len(v.value) > len(end) && strings.HasPrefix(v.value, end) {
+				return i
+			}
+		}
+	}
+	return -1
+}
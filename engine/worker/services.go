@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// serviceCheckInterval is the time between two checks of a service
+const serviceCheckInterval = 2 * time.Second
+
+// setupServices adds the hostnames of the services of the job in the parameters as cds.service.<name>
+// and waits until the services are reachable. Their health checks are run in their containers by the
+// hatchery, before the worker is started
+func (w *currentWorker) setupServices(ctx context.Context, a *sdk.Action, params *[]sdk.Parameter) error {
+	services, err := sdk.JobServices(a.Requirements)
+	if err != nil {
+		return err
+	}
+	if len(services) == 0 {
+		return nil
+	}
+
+	for _, s := range services {
+		sdk.AddParameter(params, sdk.JobServiceParameterPrefix+s.Name, sdk.StringParameter, s.Name)
+	}
+
+	// Services are not started by "worker exec"
+	if w.local != nil {
+		log.Warning("setupServices> Services are not started locally, they must be reachable by their name")
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sdk.JobServiceReadyTimeout)
+	defer cancel()
+
+	for _, s := range services {
+		t0 := time.Now()
+		log.Info("setupServices> Waiting for service %s (%s)", s.Name, s.Image)
+		if err := waitService(ctx, s); err != nil {
+			return fmt.Errorf("service %s is not ready: %v", s.Name, err)
+		}
+		log.Info("setupServices> Service %s is ready (%s)", s.Name, sdk.Round(time.Since(t0), time.Second).String())
+	}
+	return nil
+}
+
+// waitService checks a service until it is ready: its hostname is resolved and its ports accept
+// connections
+func waitService(ctx context.Context, s sdk.JobService) error {
+	var lastErr error
+	for {
+		if lastErr = checkService(s); lastErr == nil {
+			return nil
+		}
+		log.Debug("waitService> %s: %v", s.Name, lastErr)
+
+		select {
+		case <-ctx.Done():
+			return lastErr
+		case <-time.After(serviceCheckInterval):
+		}
+	}
+}
+
+func checkService(s sdk.JobService) error {
+	if _, err := net.LookupIP(s.Name); err != nil {
+		return err
+	}
+
+	for _, p := range s.Ports {
+		// Only tcp ports can be checked
+		port := strings.TrimSuffix(p, "/tcp")
+		if strings.Contains(port, "/") {
+			continue
+		}
+		conn, err := net.DialTimeout("tcp", net.JoinHostPort(s.Name, port), 5*time.Second)
+		if err != nil {
+			return err
+		}
+		conn.Close()
+	}
+	return nil
+}
@@ -11,9 +11,22 @@ import (
 	"github.com/ovh/cds/engine/api/grpc"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/tracing"
 )
 
 func (w *currentWorker) takeWorkflowJob(ctx context.Context, job sdk.WorkflowNodeJobRun) error {
+	// The job span is a child of the span given by the API in the job parameters, the calls to the API during
+	// the job propagate it
+	parent, _ := tracing.ParseTraceParent(sdk.ParameterValue(job.Parameters, "cds.traceparent"))
+	ctx, span := tracing.Start(tracing.ContextWithRemote(ctx, parent), "worker.job")
+	span.SetTag("cds.job.id", job.ID)
+	span.SetTag("cds.worker", w.status.Name)
+	tracing.SetProcessSpan(tracing.FromContext(ctx))
+	defer func() {
+		tracing.SetProcessSpan(tracing.SpanContext{})
+		span.Finish()
+	}()
+
 	info, err := w.client.QueueTakeJob(job, w.bookedJobID == job.ID)
 	if err != nil {
 		return sdk.WrapError(err, "takeWorkflowJob> Unable to take workflob node run job")
@@ -68,6 +81,7 @@ func (w *currentWorker) takeWorkflowJob(ctx context.Context, job sdk.WorkflowNod
 	//Run !
 	res := w.processJob(ctx, info)
 	tick.Stop()
+	span.SetTag("cds.status", res.Status)
 
 	now, _ := ptypes.TimestampProto(time.Now())
 	res.RemoteTime = now
@@ -21,6 +21,25 @@ type Action struct {
 	Optional       bool          `json:"optional" yaml:"-"`
 	AlwaysExecuted bool          `json:"always_executed" yaml:"-"`
 	LastModified   int64         `json:"last_modified" cli:"modified"`
+	Timeout        int64         `json:"timeout,omitempty" yaml:"-" cli:"-"` // in seconds, 0 for no timeout on steps and the default timeout on jobs
+	// Conditions of a step over the job parameters and JobStatusParameter, the step is skipped if they are not met
+	Conditions []WorkflowTriggerCondition `json:"conditions,omitempty" yaml:"-" cli:"-"`
+	// Image of the container running the scripts of a step, on workers with a docker client
+	Image string `json:"image,omitempty" yaml:"-" cli:"-"`
+}
+
+// JobStatusParameter gives to step conditions the status of the job: Success, or Fail once a step which is not optional has failed
+const JobStatusParameter = "cds.job.status"
+
+// DefaultJobTimeout is the timeout of jobs without timeout
+const DefaultJobTimeout = 6 * time.Hour
+
+// JobTimeout returns the timeout of a job
+func (a Action) JobTimeout() time.Duration {
+	if a.Timeout > 0 {
+		return time.Duration(a.Timeout) * time.Second
+	}
+	return DefaultJobTimeout
 }
 
 // ActionAudit Audit on action
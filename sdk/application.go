@@ -59,6 +59,14 @@ type ApplicationPipeline struct {
 	Triggers     []PipelineTrigger `json:"triggers,omitempty"`
 }
 
+// ApplicationMigration is the result of the migration of the pipelines and triggers of an application to workflows
+type ApplicationMigration struct {
+	DryRun         bool       `json:"dry_run"`
+	LegacyDisabled bool       `json:"legacy_disabled"`
+	Workflows      []Workflow `json:"workflows"`
+	Untranslated   []string   `json:"untranslated,omitempty"`
+}
+
 // NewApplication instanciate a new NewApplication
 func NewApplication(name string) *Application {
 	a := &Application{
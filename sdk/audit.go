@@ -1,8 +1,60 @@
 package sdk
 
+import (
+	"encoding/json"
+	"time"
+)
+
 // Different type of Audit event
 const (
 	AuditAdd    = "add"
 	AuditUpdate = "update"
 	AuditDelete = "delete"
 )
+
+// Entities tracked by the audit trail
+const (
+	AuditProject     = "project"
+	AuditApplication = "application"
+	AuditEnvironment = "environment"
+	AuditPipeline    = "pipeline"
+	AuditWorkflow    = "workflow"
+	AuditVariable    = "variable"
+	AuditKey         = "key"
+	AuditWorkerModel = "worker_model"
+	AuditGroup       = "group"
+)
+
+// Audit is an entry of the audit trail. Before and After are the JSON values of the entity,
+// with secrets masked
+type Audit struct {
+	ID         int64           `json:"id" db:"id" cli:"id,key"`
+	Created    time.Time       `json:"created" db:"created" cli:"created"`
+	Username   string          `json:"username" db:"username" cli:"username"`
+	ProjectKey string          `json:"project_key,omitempty" db:"project_key" cli:"project"`
+	EntityType string          `json:"entity_type" db:"entity_type" cli:"type"`
+	EntityName string          `json:"entity_name" db:"entity_name" cli:"name"`
+	Action     string          `json:"action" db:"action" cli:"action"`
+	Before     json.RawMessage `json:"before,omitempty" db:"-" cli:"-"`
+	After      json.RawMessage `json:"after,omitempty" db:"-" cli:"-"`
+	Diff       []AuditDiff     `json:"diff,omitempty" db:"-" cli:"-"`
+}
+
+// AuditDiff is a value changed between two versions of an entity. Path is the JSON path of the value
+type AuditDiff struct {
+	Path   string      `json:"path"`
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+}
+
+// AuditFilter filters audit entries. Empty fields are ignored
+type AuditFilter struct {
+	ProjectKey string
+	EntityType string
+	EntityName string
+	Username   string
+	Since      time.Time
+	Until      time.Time
+	Offset     int
+	Limit      int
+}
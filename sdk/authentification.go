@@ -20,6 +20,68 @@ type UserToken struct {
 	UserID             int64     `json:"-" db:"user_id"`
 }
 
+// Access token scopes
+const (
+	AccessTokenScopeRead  = "read"
+	AccessTokenScopeRun   = "run"
+	AccessTokenScopeAdmin = "admin"
+)
+
+// AccessTokenScopes lists all valid access token scopes
+var AccessTokenScopes = []string{AccessTokenScopeRead, AccessTokenScopeRun, AccessTokenScopeAdmin}
+
+// AccessToken is a named personal token used by scripts and CI integrations
+// to call the API on behalf of a user
+type AccessToken struct {
+	ID       int64      `json:"id" db:"id" cli:"id"`
+	Name     string     `json:"name" db:"name" cli:"name"`
+	UserID   int64      `json:"-" db:"user_id" cli:"-"`
+	Hash     string     `json:"-" db:"hash" cli:"-"`
+	Token    string     `json:"token,omitempty" db:"-" cli:"token"`
+	Scopes   []string   `json:"scopes" db:"-" cli:"scopes"`
+	Created  time.Time  `json:"created" db:"created" cli:"created"`
+	ExpireAt time.Time  `json:"expire_at" db:"expire_at" cli:"expire_at"`
+	LastUsed *time.Time `json:"last_used,omitempty" db:"last_used" cli:"last_used"`
+}
+
+// IsValidAccessTokenScope returns true if s is a known access token scope
+func IsValidAccessTokenScope(s string) bool {
+	for _, scope := range AccessTokenScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// HasScope returns true if the token has been granted the given scope
+func (t *AccessToken) HasScope(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Allows returns true if the token scopes grant access to a route given its http method.
+// The read scope only allows GET requests, the run scope allows also execution requests
+// (running or stopping workflows) and the admin scope allows everything.
+func (t *AccessToken) Allows(method string, isExecution bool) bool {
+	if t.HasScope(AccessTokenScopeAdmin) {
+		return true
+	}
+	if method == "GET" {
+		return t.HasScope(AccessTokenScopeRead) || t.HasScope(AccessTokenScopeRun)
+	}
+	return isExecution && t.HasScope(AccessTokenScopeRun)
+}
+
+// IsExpired returns true if the token is not valid anymore
+func (t *AccessToken) IsExpired() bool {
+	return time.Now().After(t.ExpireAt)
+}
+
 // NewAuth instanciate a new Authentification struct
 func NewAuth(hashedToken string) *Auth {
 	a := &Auth{
@@ -0,0 +1,28 @@
+package sdk
+
+import "testing"
+
+func TestAccessTokenAllows(t *testing.T) {
+	tests := []struct {
+		name        string
+		scopes      []string
+		method      string
+		isExecution bool
+		want        bool
+	}{
+		{name: "read scope on GET", scopes: []string{AccessTokenScopeRead}, method: "GET", want: true},
+		{name: "read scope on POST", scopes: []string{AccessTokenScopeRead}, method: "POST", want: false},
+		{name: "read scope on execution", scopes: []string{AccessTokenScopeRead}, method: "POST", isExecution: true, want: false},
+		{name: "run scope on GET", scopes: []string{AccessTokenScopeRun}, method: "GET", want: true},
+		{name: "run scope on execution", scopes: []string{AccessTokenScopeRun}, method: "POST", isExecution: true, want: true},
+		{name: "run scope on PUT", scopes: []string{AccessTokenScopeRun}, method: "PUT", want: false},
+		{name: "admin scope on DELETE", scopes: []string{AccessTokenScopeAdmin}, method: "DELETE", want: true},
+		{name: "no scope on GET", method: "GET", want: false},
+	}
+	for _, tt := range tests {
+		tk := &AccessToken{Scopes: tt.scopes}
+		if got := tk.Allows(tt.method, tt.isExecution); got != tt.want {
+			t.Errorf("%s: AccessToken.Allows() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
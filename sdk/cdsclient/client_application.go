@@ -53,3 +53,16 @@ func (c *client) ApplicationList(key string) ([]sdk.Application, error) {
 	}
 	return apps, nil
 }
+
+func (c *client) ApplicationMigrate(key string, appName string, dryRun, disableLegacy bool) (*sdk.ApplicationMigration, error) {
+	path := fmt.Sprintf("/project/%s/application/%s/migrate?dryRun=%t&disableLegacy=%t", key, appName, dryRun, disableLegacy)
+	report := &sdk.ApplicationMigration{}
+	code, err := c.PostJSON(path, nil, report)
+	if err != nil {
+		return nil, err
+	}
+	if code >= 300 {
+		return nil, fmt.Errorf("HTTP Code %d", code)
+	}
+	return report, nil
+}
@@ -0,0 +1,78 @@
+package cdsclient
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func (c *client) ProjectAuditList(projectKey string, f sdk.AuditFilter) ([]sdk.Audit, error) {
+	audits := []sdk.Audit{}
+	code, err := c.GetJSON("/project/"+projectKey+"/audit", &audits, auditFilterModifier(f, ""))
+	if err != nil {
+		return nil, err
+	}
+	if code >= 300 {
+		return nil, fmt.Errorf("HTTP Code %d", code)
+	}
+	return audits, nil
+}
+
+func (c *client) ProjectAuditExport(projectKey string, f sdk.AuditFilter, w io.Writer) error {
+	reader, code, err := c.Stream("GET", "/project/"+projectKey+"/audit", nil, true, auditFilterModifier(f, "csv"))
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	if code >= 300 {
+		return fmt.Errorf("HTTP Code %d", code)
+	}
+	_, err = io.Copy(w, reader)
+	return err
+}
+
+func (c *client) WorkflowRestore(projectKey, workflowName string, auditID int64) (*sdk.Workflow, error) {
+	url := fmt.Sprintf("/project/%s/workflows/%s/audit/%d/restore", projectKey, workflowName, auditID)
+	wf := &sdk.Workflow{}
+	code, err := c.PostJSON(url, nil, wf)
+	if err != nil {
+		return nil, err
+	}
+	if code >= 300 {
+		return nil, fmt.Errorf("HTTP Code %d", code)
+	}
+	return wf, nil
+}
+
+func auditFilterModifier(f sdk.AuditFilter, format string) RequestModifier {
+	return func(r *http.Request) {
+		q := url.Values{}
+		set := func(k, v string) {
+			if v != "" {
+				q.Set(k, v)
+			}
+		}
+		set("type", f.EntityType)
+		set("name", f.EntityName)
+		set("username", f.Username)
+		set("format", format)
+		if !f.Since.IsZero() {
+			q.Set("since", f.Since.Format(time.RFC3339))
+		}
+		if !f.Until.IsZero() {
+			q.Set("until", f.Until.Format(time.RFC3339))
+		}
+		if f.Offset > 0 {
+			q.Set("offset", strconv.Itoa(f.Offset))
+		}
+		if f.Limit > 0 {
+			q.Set("limit", strconv.Itoa(f.Limit))
+		}
+		r.URL.RawQuery = q.Encode()
+	}
+}
@@ -60,3 +60,35 @@ func (c *client) EnvironmentList(key string) ([]sdk.Environment, error) {
 	}
 	return envs, nil
 }
+
+func (c *client) EnvironmentDeployments(key string, envName string, applicationName string) ([]sdk.EnvironmentDeployment, error) {
+	ds := []sdk.EnvironmentDeployment{}
+	path := "/project/" + key + "/environment/" + url.QueryEscape(envName) + "/deployment"
+	if applicationName != "" {
+		path += "?application=" + url.QueryEscape(applicationName)
+	}
+	code, err := c.GetJSON(path, &ds)
+	if code != 200 {
+		if err == nil {
+			return nil, fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ds, nil
+}
+
+func (c *client) EnvironmentPromote(key string, envName string, promotion sdk.EnvironmentPromotion) (*sdk.WorkflowRun, error) {
+	wr := &sdk.WorkflowRun{}
+	code, err := c.PostJSON("/project/"+key+"/environment/"+url.QueryEscape(envName)+"/deployment/promote", promotion, wr)
+	if code != 200 {
+		if err == nil {
+			return nil, fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return wr, nil
+}
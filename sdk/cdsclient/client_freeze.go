@@ -0,0 +1,43 @@
+package cdsclient
+
+import (
+	"fmt"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// freezePath returns the path of the freeze windows of a project, or of the global ones if projectKey is empty
+func freezePath(projectKey string) string {
+	if projectKey == "" {
+		return "/freeze"
+	}
+	return "/project/" + projectKey + "/freeze"
+}
+
+func (c *client) FreezeWindowList(projectKey string) ([]sdk.FreezeWindow, error) {
+	ws := []sdk.FreezeWindow{}
+	if _, err := c.GetJSON(freezePath(projectKey), &ws); err != nil {
+		return nil, err
+	}
+	return ws, nil
+}
+
+func (c *client) FreezeWindowCreate(projectKey string, w *sdk.FreezeWindow) error {
+	code, err := c.PostJSON(freezePath(projectKey), w, w)
+	if code != 201 {
+		if err == nil {
+			return fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	return err
+}
+
+func (c *client) FreezeWindowDelete(projectKey string, id int64) error {
+	_, code, err := c.Request("DELETE", fmt.Sprintf("%s/%d", freezePath(projectKey), id), nil)
+	if code != 200 {
+		if err == nil {
+			return fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	return err
+}
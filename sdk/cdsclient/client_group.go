@@ -59,3 +59,30 @@ func (c *client) GroupList() ([]sdk.Group, error) {
 	}
 	return groups, nil
 }
+
+func (c *client) GroupQueueList() ([]sdk.GroupQueueUsage, error) {
+	usage := []sdk.GroupQueueUsage{}
+	code, err := c.GetJSON("/admin/queue/groups", &usage)
+	if code != 200 {
+		if err == nil {
+			return nil, fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+func (c *client) GroupQueueSettingsSet(groupName string, settings sdk.GroupQueueSettings) error {
+	code, err := c.PutJSON("/admin/queue/groups/"+groupName, settings, nil)
+	if code != 200 {
+		if err == nil {
+			return fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
 )
 
 func (c *client) ProjectCreate(p *sdk.Project) error {
@@ -59,3 +60,27 @@ func (c *client) ProjectList() ([]sdk.Project, error) {
 	}
 	return p, nil
 }
+
+func (c *client) ProjectExport(key string, passphrase string) (*exportentities.Project, error) {
+	p := &exportentities.Project{}
+	code, err := c.PostJSON("/project/"+key+"/export", sdk.ProjectExportOptions{Passphrase: passphrase}, p)
+	if err != nil {
+		return nil, err
+	}
+	if code >= 300 {
+		return nil, fmt.Errorf("HTTP Code %d", code)
+	}
+	return p, nil
+}
+
+func (c *client) ProjectImport(p *exportentities.Project, passphrase string) (*sdk.ProjectImportReport, error) {
+	report := &sdk.ProjectImportReport{}
+	code, err := c.PostJSON("/project/import", exportentities.ProjectImport{Passphrase: passphrase, Project: *p}, report)
+	if err != nil {
+		return nil, err
+	}
+	if code >= 300 {
+		return nil, fmt.Errorf("HTTP Code %d", code)
+	}
+	return report, nil
+}
@@ -0,0 +1,42 @@
+package cdsclient
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func (c *client) ProjectSecretBackendList(projectKey string) ([]sdk.ProjectSecretBackend, error) {
+	bs := []sdk.ProjectSecretBackend{}
+	code, err := c.GetJSON("/project/"+projectKey+"/secretbackend", &bs)
+	if code != 200 {
+		if err == nil {
+			return nil, fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return bs, nil
+}
+
+func (c *client) ProjectSecretBackendCreate(projectKey string, b *sdk.ProjectSecretBackend) error {
+	code, err := c.PostJSON("/project/"+projectKey+"/secretbackend", b, b)
+	if code != 201 {
+		if err == nil {
+			return fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	return err
+}
+
+func (c *client) ProjectSecretBackendDelete(projectKey, name string) error {
+	_, code, err := c.Request("DELETE", "/project/"+projectKey+"/secretbackend/"+url.QueryEscape(name), nil)
+	if code != 200 {
+		if err == nil {
+			return fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	return err
+}
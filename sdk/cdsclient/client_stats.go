@@ -0,0 +1,32 @@
+package cdsclient
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func (c *client) ProjectDeliveryMetrics(projectKey string, from, to time.Time, environment string) (*sdk.DeliveryMetrics, error) {
+	q := url.Values{}
+	if !from.IsZero() {
+		q.Set("from", from.Format(time.RFC3339))
+	}
+	if !to.IsZero() {
+		q.Set("to", to.Format(time.RFC3339))
+	}
+	if environment != "" {
+		q.Set("environment", environment)
+	}
+
+	m := &sdk.DeliveryMetrics{}
+	code, err := c.GetJSON("/project/"+projectKey+"/metrics/delivery?"+q.Encode(), m)
+	if err != nil {
+		return nil, err
+	}
+	if code >= 300 {
+		return nil, fmt.Errorf("HTTP Code %d", code)
+	}
+	return m, nil
+}
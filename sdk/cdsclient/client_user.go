@@ -130,3 +130,41 @@ func (c *client) UserConfirm(username, token string) (bool, string, error) {
 
 	return true, res.Password, nil
 }
+
+func (c *client) UserAccessTokenList(username string) ([]sdk.AccessToken, error) {
+	res := []sdk.AccessToken{}
+	code, err := c.GetJSON("/user/"+url.QueryEscape(username)+"/token", &res)
+	if err != nil {
+		return nil, err
+	}
+	if code != http.StatusOK {
+		return nil, fmt.Errorf("Error %d", code)
+	}
+
+	return res, nil
+}
+
+func (c *client) UserAccessTokenCreate(username string, t *sdk.AccessToken) (*sdk.AccessToken, error) {
+	res := sdk.AccessToken{}
+	code, err := c.PostJSON("/user/"+url.QueryEscape(username)+"/token", t, &res)
+	if err != nil {
+		return nil, err
+	}
+	if code != http.StatusCreated {
+		return nil, fmt.Errorf("Error %d", code)
+	}
+
+	return &res, nil
+}
+
+func (c *client) UserAccessTokenDelete(username string, id int64) error {
+	code, err := c.DeleteJSON(fmt.Sprintf("/user/%s/token/%d", url.QueryEscape(username), id), nil)
+	if err != nil {
+		return err
+	}
+	if code != http.StatusOK {
+		return fmt.Errorf("Error %d", code)
+	}
+
+	return nil
+}
@@ -22,6 +22,19 @@ func (c *client) WorkerList() ([]sdk.Worker, error) {
 	return p, nil
 }
 
+func (c *client) WorkerDisable(id string) error {
+	code, err := c.PostJSON(fmt.Sprintf("/worker/%s/disable", id), nil, nil)
+	if code != 200 {
+		if err == nil {
+			return fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
 func (c *client) WorkerRegister(r worker.RegistrationForm) (*sdk.Worker, bool, error) {
 	var w sdk.Worker
 	code, err := c.PostJSON("/worker", r, &w)
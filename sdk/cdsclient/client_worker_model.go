@@ -1,9 +1,12 @@
 package cdsclient
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/url"
 
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
 )
 
 // WorkerModelsEnabled retrieves all worker models enabled and available to user
@@ -41,3 +44,75 @@ func (c *client) WorkerModelSpawnError(id int64, info string) error {
 	}
 	return nil
 }
+
+// WorkerModel retrieves a worker model by its name
+func (c *client) WorkerModel(name string) (*sdk.Model, error) {
+	var model sdk.Model
+	if _, err := c.GetJSON("/worker/model?name="+url.QueryEscape(name), &model); err != nil {
+		return nil, err
+	}
+	return &model, nil
+}
+
+// WorkerModelExport returns the definition of a worker model
+func (c *client) WorkerModelExport(name, format string) ([]byte, error) {
+	model, err := c.WorkerModel(name)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := exportentities.GetFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	return exportentities.Marshal(exportentities.NewWorkerModel(model), f)
+}
+
+// WorkerModelImport creates a worker model from its definition, or updates it with force
+func (c *client) WorkerModelImport(content []byte, format string, force bool) (*sdk.Model, error) {
+	uri := fmt.Sprintf("/worker/model/import?format=%s", format)
+	if force {
+		uri += "&forceUpdate=true"
+	}
+
+	btes, code, err := c.Request("POST", uri, content)
+	if err != nil {
+		return nil, err
+	}
+	if code >= 300 {
+		return nil, fmt.Errorf("HTTP Code %d", code)
+	}
+
+	var model sdk.Model
+	if err := json.Unmarshal(btes, &model); err != nil {
+		return nil, err
+	}
+	return &model, nil
+}
+
+// WorkerModelVersions retrieves the versions of a worker model, the last first
+func (c *client) WorkerModelVersions(id int64) ([]sdk.ModelVersion, error) {
+	var versions []sdk.ModelVersion
+	if _, err := c.GetJSON(fmt.Sprintf("/worker/model/%d/version", id), &versions); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// WorkerModelVersion retrieves a version of a worker model
+func (c *client) WorkerModelVersion(id, version int64) (*sdk.ModelVersion, error) {
+	var v sdk.ModelVersion
+	if _, err := c.GetJSON(fmt.Sprintf("/worker/model/%d/version/%d", id, version), &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// WorkerModelRollback restores a version of a worker model as a new version
+func (c *client) WorkerModelRollback(id, version int64) (*sdk.Model, error) {
+	var model sdk.Model
+	if _, err := c.PostJSON(fmt.Sprintf("/worker/model/%d/version/%d/rollback", id, version), nil, &model); err != nil {
+		return nil, err
+	}
+	return &model, nil
+}
@@ -4,6 +4,10 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/ovh/cds/sdk"
 )
@@ -26,6 +30,44 @@ func (c *client) WorkflowGet(projectKey, workflowName string) (*sdk.Workflow, er
 	return w, nil
 }
 
+func (c *client) WorkflowRunSearch(projectKey string, workflowName string, s sdk.WorkflowRunSearch) (*sdk.WorkflowRunSearchResult, error) {
+	q := url.Values{}
+	if len(s.Status) > 0 {
+		q.Set("status", strings.Join(s.Status, ","))
+	}
+	for k, v := range s.Tags {
+		q.Add("tag", k+"="+v)
+	}
+	if !s.From.IsZero() {
+		q.Set("from", s.From.Format(time.RFC3339))
+	}
+	if !s.To.IsZero() {
+		q.Set("to", s.To.Format(time.RFC3339))
+	}
+	for param, v := range map[string]string{"triggered_by": s.TriggeredBy, "hook": s.Hook, "node": s.Node, "sort": s.Sort, "cursor": s.Cursor} {
+		if v != "" {
+			q.Set(param, v)
+		}
+	}
+	if s.Ascending {
+		q.Set("order", "asc")
+	}
+	if s.Limit > 0 {
+		q.Set("limit", strconv.Itoa(s.Limit))
+	}
+
+	path := fmt.Sprintf("/project/%s/workflows/%s/runs/search?%s", projectKey, workflowName, q.Encode())
+	res := &sdk.WorkflowRunSearchResult{}
+	code, err := c.GetJSON(path, res)
+	if err != nil {
+		return nil, err
+	}
+	if code >= 300 {
+		return nil, fmt.Errorf("Cannot search workflow runs. HTTP code error: %d", code)
+	}
+	return res, nil
+}
+
 func (c *client) WorkflowRunGet(projectKey string, workflowName string, number int64) (*sdk.WorkflowRun, error) {
 	url := fmt.Sprintf("/project/%s/workflows/%s/runs/%d", projectKey, workflowName, number)
 	run := sdk.WorkflowRun{}
@@ -138,3 +180,144 @@ func (c *client) WorkflowRunFromManual(projectKey string, workflowName string, m
 
 	return run, nil
 }
+
+func (c *client) WorkflowRollback(projectKey string, workflowName string, rollback sdk.WorkflowRollback) (*sdk.WorkflowRun, error) {
+	url := fmt.Sprintf("/project/%s/workflows/%s/rollback", projectKey, workflowName)
+	run := &sdk.WorkflowRun{}
+	code, err := c.PostJSON(url, &rollback, run)
+	if err != nil {
+		return nil, err
+	}
+	if code >= 300 {
+		return nil, fmt.Errorf("Cannot rollback workflow node. HTTP code error: %d", code)
+	}
+	return run, nil
+}
+
+func (c *client) WorkflowGroupAdd(projectKey, workflowName, groupName string, permission int) error {
+	gp := sdk.GroupPermission{
+		Group:      sdk.Group{Name: groupName},
+		Permission: permission,
+	}
+	url := fmt.Sprintf("/project/%s/workflows/%s/groups", projectKey, workflowName)
+	code, err := c.PostJSON(url, gp, nil)
+	if err != nil {
+		return err
+	}
+	if code >= 300 {
+		return fmt.Errorf("Cannot add group on workflow. HTTP code error : %d", code)
+	}
+	return nil
+}
+
+func (c *client) WorkflowGroupUpdate(projectKey, workflowName, groupName string, permission int) error {
+	gp := sdk.GroupPermission{
+		Group:      sdk.Group{Name: groupName},
+		Permission: permission,
+	}
+	url := fmt.Sprintf("/project/%s/workflows/%s/groups/%s", projectKey, workflowName, groupName)
+	code, err := c.PutJSON(url, gp, nil)
+	if err != nil {
+		return err
+	}
+	if code >= 300 {
+		return fmt.Errorf("Cannot update group on workflow. HTTP code error : %d", code)
+	}
+	return nil
+}
+
+func (c *client) WorkflowGroupDelete(projectKey, workflowName, groupName string) error {
+	url := fmt.Sprintf("/project/%s/workflows/%s/groups/%s", projectKey, workflowName, groupName)
+	code, err := c.DeleteJSON(url, nil)
+	if err != nil {
+		return err
+	}
+	if code >= 300 {
+		return fmt.Errorf("Cannot delete group on workflow. HTTP code error : %d", code)
+	}
+	return nil
+}
+
+func (c *client) WorkflowNotificationList(projectKey, workflowName string) ([]sdk.WorkflowNotification, error) {
+	url := fmt.Sprintf("/project/%s/workflows/%s/notifications", projectKey, workflowName)
+	notifs := []sdk.WorkflowNotification{}
+	if _, err := c.GetJSON(url, &notifs); err != nil {
+		return nil, err
+	}
+	return notifs, nil
+}
+
+func (c *client) WorkflowNotificationAdd(projectKey, workflowName string, n *sdk.WorkflowNotification) error {
+	url := fmt.Sprintf("/project/%s/workflows/%s/notifications", projectKey, workflowName)
+	code, err := c.PostJSON(url, n, n)
+	if err != nil {
+		return err
+	}
+	if code >= 300 {
+		return fmt.Errorf("Cannot add notification on workflow. HTTP code error : %d", code)
+	}
+	return nil
+}
+
+func (c *client) WorkflowNotificationDelete(projectKey, workflowName string, id int64) error {
+	url := fmt.Sprintf("/project/%s/workflows/%s/notifications/%d", projectKey, workflowName, id)
+	code, err := c.DeleteJSON(url, nil)
+	if err != nil {
+		return err
+	}
+	if code >= 300 {
+		return fmt.Errorf("Cannot delete notification on workflow. HTTP code error : %d", code)
+	}
+	return nil
+}
+
+func (c *client) WorkflowTestsTrends(projectKey, workflowName string, days int) ([]sdk.WorkflowTestSuiteTrend, error) {
+	url := fmt.Sprintf("/project/%s/workflows/%s/tests/trends?days=%d", projectKey, workflowName, days)
+	trends := []sdk.WorkflowTestSuiteTrend{}
+	if _, err := c.GetJSON(url, &trends); err != nil {
+		return nil, err
+	}
+	return trends, nil
+}
+
+func (c *client) WorkflowTestsFlaky(projectKey, workflowName string, days int) ([]sdk.WorkflowFlakyTest, error) {
+	url := fmt.Sprintf("/project/%s/workflows/%s/tests/flaky?days=%d", projectKey, workflowName, days)
+	flaky := []sdk.WorkflowFlakyTest{}
+	if _, err := c.GetJSON(url, &flaky); err != nil {
+		return nil, err
+	}
+	return flaky, nil
+}
+
+func (c *client) WorkflowTestsQuarantineList(projectKey, workflowName string) ([]sdk.WorkflowTestQuarantine, error) {
+	url := fmt.Sprintf("/project/%s/workflows/%s/tests/quarantine", projectKey, workflowName)
+	qs := []sdk.WorkflowTestQuarantine{}
+	if _, err := c.GetJSON(url, &qs); err != nil {
+		return nil, err
+	}
+	return qs, nil
+}
+
+func (c *client) WorkflowTestsQuarantineAdd(projectKey, workflowName string, q *sdk.WorkflowTestQuarantine) error {
+	url := fmt.Sprintf("/project/%s/workflows/%s/tests/quarantine", projectKey, workflowName)
+	code, err := c.PostJSON(url, q, q)
+	if err != nil {
+		return err
+	}
+	if code >= 300 {
+		return fmt.Errorf("Cannot quarantine test. HTTP code error : %d", code)
+	}
+	return nil
+}
+
+func (c *client) WorkflowTestsQuarantineDelete(projectKey, workflowName string, id int64) error {
+	url := fmt.Sprintf("/project/%s/workflows/%s/tests/quarantine/%d", projectKey, workflowName, id)
+	code, err := c.DeleteJSON(url, nil)
+	if err != nil {
+		return err
+	}
+	if code >= 300 {
+		return fmt.Errorf("Cannot delete quarantine. HTTP code error : %d", code)
+	}
+	return nil
+}
@@ -14,3 +14,11 @@ func (c *client) WorkflowAllHooksList() ([]sdk.WorkflowNodeHook, error) {
 	}
 	return w, nil
 }
+
+func (c *client) WorkflowHookFreezeWindows(projectKey, workflowName string) ([]sdk.FreezeWindow, error) {
+	ws := []sdk.FreezeWindow{}
+	if _, err := c.GetJSON("/workflow/hook/freeze/"+projectKey+"/"+workflowName, &ws); err != nil {
+		return nil, err
+	}
+	return ws, nil
+}
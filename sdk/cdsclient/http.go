@@ -12,11 +12,14 @@ import (
 	"strings"
 
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/tracing"
 )
 
 const (
 	//SessionTokenHeader is user as HTTP header
 	SessionTokenHeader = "Session-Token"
+	// AccessTokenHeader is used as HTTP header to authenticate with a user access token
+	AccessTokenHeader = "Access-Token"
 	// AuthHeader is used as HTTP header
 	AuthHeader = "X_AUTH_HEADER"
 	// RequestedWithHeader is used as HTTP header
@@ -152,6 +155,9 @@ func (c *client) Stream(method string, path string, args []byte, noTimeout bool,
 		if c.name != "" {
 			req.Header.Add(RequestedNameHeader, c.name)
 		}
+		if span := tracing.ProcessSpan(); span.IsValid() {
+			req.Header.Set(tracing.TraceParentHeader, span.TraceParent())
+		}
 
 		for i := range mods {
 			if mods[i] != nil {
@@ -165,7 +171,9 @@ func (c *client) Stream(method string, path string, args []byte, noTimeout bool,
 				basedHash := base64.StdEncoding.EncodeToString([]byte(c.config.Hash))
 				req.Header.Set(AuthHeader, basedHash)
 			}
-			if c.config.User != "" && c.config.Token != "" {
+			if c.config.AccessToken != "" {
+				req.Header.Set(AccessTokenHeader, c.config.AccessToken)
+			} else if c.config.User != "" && c.config.Token != "" {
 				req.Header.Add(SessionTokenHeader, c.config.Token)
 				req.SetBasicAuth(c.config.User, c.config.Token)
 			}
@@ -252,7 +260,9 @@ func (c *client) UploadMultiPart(method string, path string, body *bytes.Buffer,
 			basedHash := base64.StdEncoding.EncodeToString([]byte(c.config.Hash))
 			req.Header.Set(AuthHeader, basedHash)
 		}
-		if c.config.User != "" && c.config.Token != "" {
+		if c.config.AccessToken != "" {
+			req.Header.Set(AccessTokenHeader, c.config.AccessToken)
+		} else if c.config.User != "" && c.config.Token != "" {
 			req.Header.Add(SessionTokenHeader, c.config.Token)
 			req.SetBasicAuth(c.config.User, c.config.Token)
 		}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/ovh/cds/engine/api/worker"
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
 )
 
 // Interface is the main interface for cdsclient package
@@ -19,6 +20,7 @@ type Interface interface {
 	ApplicationDelete(string, string) error
 	ApplicationGet(string, string, ...RequestModifier) (*sdk.Application, error)
 	ApplicationList(string) ([]sdk.Application, error)
+	ApplicationMigrate(key string, appName string, dryRun, disableLegacy bool) (*sdk.ApplicationMigration, error)
 	ApplicationKeysList(string, string) ([]sdk.ApplicationKey, error)
 	ApplicationKeyCreate(string, string, *sdk.ApplicationKey) error
 	ApplicationKeysDelete(string, string, string) error
@@ -30,11 +32,18 @@ type Interface interface {
 	EnvironmentKeysList(string, string) ([]sdk.EnvironmentKey, error)
 	EnvironmentKeyCreate(string, string, *sdk.EnvironmentKey) error
 	EnvironmentKeysDelete(string, string, string) error
+	EnvironmentDeployments(key string, envName string, applicationName string) ([]sdk.EnvironmentDeployment, error)
+	EnvironmentPromote(key string, envName string, promotion sdk.EnvironmentPromotion) (*sdk.WorkflowRun, error)
+	FreezeWindowList(projectKey string) ([]sdk.FreezeWindow, error)
+	FreezeWindowCreate(projectKey string, w *sdk.FreezeWindow) error
+	FreezeWindowDelete(projectKey string, id int64) error
 	GroupCreate(group *sdk.Group) error
 	GroupDelete(name string) error
 	GroupGenerateToken(groupName, expiration string) (*sdk.Token, error)
 	GroupGet(name string, mods ...RequestModifier) (*sdk.Group, error)
 	GroupList() ([]sdk.Group, error)
+	GroupQueueList() ([]sdk.GroupQueueUsage, error)
+	GroupQueueSettingsSet(groupName string, settings sdk.GroupQueueSettings) error
 	GroupUserAdminSet(groupname string, username string) error
 	GroupUserAdminRemove(groupname, username string) error
 	GroupUserAdd(groupname string, users []string) error
@@ -43,16 +52,25 @@ type Interface interface {
 	HatcheryRegister(sdk.Hatchery) (*sdk.Hatchery, bool, error)
 	MonStatus() ([]string, error)
 	PipelineDelete(projectKey, name string) error
+	PipelineGet(projectKey, name string) (*sdk.Pipeline, error)
 	PipelineExport(projectKey, name string, exportWithPermissions bool, exportFormat string) ([]byte, error)
 	PipelineImport(projectKey string, content []byte, format string, force bool) ([]string, error)
 	PipelineList(projectKey string) ([]sdk.Pipeline, error)
 	ProjectCreate(*sdk.Project) error
 	ProjectDelete(string) error
 	ProjectGet(string, ...RequestModifier) (*sdk.Project, error)
+	ProjectAuditList(projectKey string, f sdk.AuditFilter) ([]sdk.Audit, error)
+	ProjectAuditExport(projectKey string, f sdk.AuditFilter, w io.Writer) error
 	ProjectList() ([]sdk.Project, error)
+	ProjectExport(key string, passphrase string) (*exportentities.Project, error)
+	ProjectImport(p *exportentities.Project, passphrase string) (*sdk.ProjectImportReport, error)
+	ProjectDeliveryMetrics(projectKey string, from, to time.Time, environment string) (*sdk.DeliveryMetrics, error)
 	ProjectKeysList(string) ([]sdk.ProjectKey, error)
 	ProjectKeyCreate(string, *sdk.ProjectKey) error
 	ProjectKeysDelete(string, string) error
+	ProjectSecretBackendList(projectKey string) ([]sdk.ProjectSecretBackend, error)
+	ProjectSecretBackendCreate(projectKey string, b *sdk.ProjectSecretBackend) error
+	ProjectSecretBackendDelete(projectKey, name string) error
 	Queue() ([]sdk.WorkflowNodeJobRun, []sdk.PipelineBuildJob, error)
 	QueuePolling(context.Context, chan<- sdk.WorkflowNodeJobRun, chan<- sdk.PipelineBuildJob, chan<- error, time.Duration, int) error
 	QueueTakeJob(sdk.WorkflowNodeJobRun, bool) (*worker.WorkflowNodeJobRunInfo, error)
@@ -64,6 +82,9 @@ type Interface interface {
 	Requirements() ([]sdk.Requirement, error)
 	ServiceRegister(sdk.Service) (string, error)
 	UserLogin(username, password string) (bool, string, error)
+	UserAccessTokenList(username string) ([]sdk.AccessToken, error)
+	UserAccessTokenCreate(username string, t *sdk.AccessToken) (*sdk.AccessToken, error)
+	UserAccessTokenDelete(username string, id int64) error
 	UserList() ([]sdk.User, error)
 	UserSignup(username, fullname, email, callback string) error
 	UserGet(username string) (*sdk.User, error)
@@ -71,22 +92,44 @@ type Interface interface {
 	UserReset(username, email, callback string) error
 	UserConfirm(username, token string) (bool, string, error)
 	Version() (*sdk.Version, error)
+	WorkerDisable(id string) error
 	WorkerList() ([]sdk.Worker, error)
 	WorkerModelSpawnError(id int64, info string) error
 	WorkerModelsEnabled() ([]sdk.Model, error)
 	WorkerModels() ([]sdk.Model, error)
+	WorkerModel(name string) (*sdk.Model, error)
+	WorkerModelExport(name, format string) ([]byte, error)
+	WorkerModelImport(content []byte, format string, force bool) (*sdk.Model, error)
+	WorkerModelVersions(id int64) ([]sdk.ModelVersion, error)
+	WorkerModelVersion(id, version int64) (*sdk.ModelVersion, error)
+	WorkerModelRollback(id, version int64) (*sdk.Model, error)
 	WorkerRegister(worker.RegistrationForm) (*sdk.Worker, bool, error)
 	WorkerSetStatus(sdk.Status) error
 	WorkflowList(projectKey string) ([]sdk.Workflow, error)
 	WorkflowGet(projectKey, name string) (*sdk.Workflow, error)
 	WorkflowRunGet(projectKey string, name string, number int64) (*sdk.WorkflowRun, error)
+	WorkflowRunSearch(projectKey string, workflowName string, s sdk.WorkflowRunSearch) (*sdk.WorkflowRunSearchResult, error)
 	WorkflowRunArtifacts(projectKey string, name string, number int64) ([]sdk.Artifact, error)
 	WorkflowRunFromHook(projectKey string, workflowName string, hook sdk.WorkflowNodeRunHookEvent) (*sdk.WorkflowRun, error)
 	WorkflowRunFromManual(projectKey string, workflowName string, manual sdk.WorkflowNodeRunManual, number, fromNodeID int64) (*sdk.WorkflowRun, error)
+	WorkflowRollback(projectKey string, workflowName string, rollback sdk.WorkflowRollback) (*sdk.WorkflowRun, error)
 	WorkflowNodeRun(projectKey string, name string, number int64, nodeRunID int64) (*sdk.WorkflowNodeRun, error)
 	WorkflowNodeRunArtifacts(projectKey string, name string, number int64, nodeRunID int64) ([]sdk.Artifact, error)
 	WorkflowNodeRunArtifactDownload(projectKey string, name string, artifactID int64, w io.Writer) error
 	WorkflowNodeRunJobStep(projectKey string, workflowName string, number int64, nodeRunID, job int64, step int) (*sdk.BuildState, error)
 	WorkflowNodeRunRelease(projectKey string, workflowName string, runNumber int64, nodeRunID int64, release sdk.WorkflowNodeRunRelease) error
 	WorkflowAllHooksList() ([]sdk.WorkflowNodeHook, error)
+	WorkflowHookFreezeWindows(projectKey, workflowName string) ([]sdk.FreezeWindow, error)
+	WorkflowRestore(projectKey, workflowName string, auditID int64) (*sdk.Workflow, error)
+	WorkflowGroupAdd(projectKey, workflowName, groupName string, permission int) error
+	WorkflowGroupUpdate(projectKey, workflowName, groupName string, permission int) error
+	WorkflowGroupDelete(projectKey, workflowName, groupName string) error
+	WorkflowNotificationList(projectKey, workflowName string) ([]sdk.WorkflowNotification, error)
+	WorkflowNotificationAdd(projectKey, workflowName string, n *sdk.WorkflowNotification) error
+	WorkflowNotificationDelete(projectKey, workflowName string, id int64) error
+	WorkflowTestsTrends(projectKey, workflowName string, days int) ([]sdk.WorkflowTestSuiteTrend, error)
+	WorkflowTestsFlaky(projectKey, workflowName string, days int) ([]sdk.WorkflowFlakyTest, error)
+	WorkflowTestsQuarantineList(projectKey, workflowName string) ([]sdk.WorkflowTestQuarantine, error)
+	WorkflowTestsQuarantineAdd(projectKey, workflowName string, q *sdk.WorkflowTestQuarantine) error
+	WorkflowTestsQuarantineDelete(projectKey, workflowName string, id int64) error
 }
@@ -34,6 +34,34 @@ type EnvironmentVariableAudit struct {
 	Author         string    `json:"author" yaml:"-" db:"author"`
 }
 
+// EnvironmentDeployment is a deployment of an application on an environment by a workflow node run
+type EnvironmentDeployment struct {
+	ID                int64     `json:"id" yaml:"-" db:"id" cli:"id,key"`
+	EnvironmentID     int64     `json:"environment_id" yaml:"-" db:"environment_id" cli:"-"`
+	ApplicationID     int64     `json:"application_id" yaml:"-" db:"application_id" cli:"-"`
+	ApplicationName   string    `json:"application_name" yaml:"-" db:"application_name" cli:"application"`
+	Version           string    `json:"version" yaml:"-" db:"version" cli:"version"`
+	VCSHash           string    `json:"vcs_hash" yaml:"-" db:"vcs_hash" cli:"commit"`
+	VCSBranch         string    `json:"vcs_branch" yaml:"-" db:"vcs_branch" cli:"branch"`
+	WorkflowID        int64     `json:"workflow_id" yaml:"-" db:"workflow_id" cli:"-"`
+	WorkflowName      string    `json:"workflow_name" yaml:"-" db:"workflow_name" cli:"workflow"`
+	WorkflowRunID     int64     `json:"workflow_run_id" yaml:"-" db:"workflow_run_id" cli:"-"`
+	WorkflowRunNumber int64     `json:"workflow_run_number" yaml:"-" db:"workflow_run_number" cli:"run"`
+	WorkflowNodeRunID int64     `json:"workflow_node_run_id" yaml:"-" db:"workflow_node_run_id" cli:"-"`
+	NodeName          string    `json:"node_name" yaml:"-" db:"node_name" cli:"node"`
+	Status            string    `json:"status" yaml:"-" db:"status" cli:"status"`
+	Author            string    `json:"author" yaml:"-" db:"author" cli:"author"`
+	Created           time.Time `json:"created" yaml:"-" db:"created" cli:"created"`
+	Current           bool      `json:"current" yaml:"-" db:"-" cli:"current"`
+}
+
+// EnvironmentPromotion is the request to deploy on an environment what is deployed on another one
+type EnvironmentPromotion struct {
+	To              string `json:"to"`
+	ApplicationName string `json:"application_name,omitempty"`
+	DeploymentID    int64  `json:"deployment_id,omitempty"`
+}
+
 // NewEnvironment instanciate a new Environment
 func NewEnvironment(name string) *Environment {
 	e := &Environment{
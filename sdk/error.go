@@ -121,6 +121,18 @@ var (
 	ErrMethodNotAllowed                      = &Error{ID: 105, Status: http.StatusMethodNotAllowed}
 	ErrInvalidNodeNamePattern                = &Error{ID: 106, Status: http.StatusBadRequest}
 	ErrWorkflowNodeParentNotRun              = Error{ID: 107, Status: http.StatusForbidden}
+	ErrWorkflowNotificationNotFound          = &Error{ID: 108, Status: http.StatusNotFound}
+	ErrAuditNotFound                         = &Error{ID: 109, Status: http.StatusNotFound}
+	ErrInvalidSecretReference                = &Error{ID: 110, Status: http.StatusBadRequest}
+	ErrSecretBackendNotFound                 = &Error{ID: 111, Status: http.StatusNotFound}
+	ErrGroupQueueQuotaReached                = &Error{ID: 112, Status: http.StatusForbidden}
+	ErrNoWorkerModelVersion                  = &Error{ID: 113, Status: http.StatusNotFound}
+	ErrInvalidPassphrase                     = &Error{ID: 114, Status: http.StatusBadRequest}
+	ErrEnvironmentDeploymentNotFound         = &Error{ID: 115, Status: http.StatusNotFound}
+	ErrEnvironmentPromotionNodeNotFound      = &Error{ID: 116, Status: http.StatusNotFound}
+	ErrNoPreviousDeployment                  = &Error{ID: 117, Status: http.StatusNotFound}
+	ErrDeploymentFrozen                      = &Error{ID: 118, Status: http.StatusForbidden}
+	ErrInvalidFreezeWindow                   = &Error{ID: 119, Status: http.StatusBadRequest}
 )
 
 var errorsAmericanEnglish = map[int]string{
@@ -231,6 +243,18 @@ var errorsAmericanEnglish = map[int]string{
 	ErrMethodNotAllowed.ID:                      "Method not allowed",
 	ErrInvalidNodeNamePattern.ID:                "Node name must respect the following pattern: '^[a-zA-Z0-9.-_-]{1,}$'",
 	ErrWorkflowNodeParentNotRun.ID:              "Cannot run a node if their parents have never been launched",
+	ErrWorkflowNotificationNotFound.ID:          "Workflow notification not found",
+	ErrAuditNotFound.ID:                         "Audit not found",
+	ErrInvalidSecretReference.ID:                "Invalid secret reference, it must respect the pattern backend:path#field",
+	ErrSecretBackendNotFound.ID:                 "Secret backend not found",
+	ErrGroupQueueQuotaReached.ID:                "The group has reached its quota of building jobs",
+	ErrNoWorkerModelVersion.ID:                  "Worker model version does not exist",
+	ErrInvalidPassphrase.ID:                     "Invalid passphrase",
+	ErrEnvironmentDeploymentNotFound.ID:         "Deployment not found on this environment",
+	ErrEnvironmentPromotionNodeNotFound.ID:      "No node of the workflow deploys this application on the target environment",
+	ErrNoPreviousDeployment.ID:                  "No previous successful deployment to rollback to",
+	ErrDeploymentFrozen.ID:                      "Deployments are frozen",
+	ErrInvalidFreezeWindow.ID:                   "Invalid freeze window",
 }
 
 var errorsFrench = map[int]string{
@@ -341,6 +365,18 @@ var errorsFrench = map[int]string{
 	ErrMethodNotAllowed.ID:                      "La méthode n'est pas autorisée",
 	ErrInvalidNodeNamePattern.ID:                "Le nom du noeud du workflow doit respecter le pattern suivant; '^[a-zA-Z0-9.-_-]{1,}$'",
 	ErrWorkflowNodeParentNotRun.ID:              "Il est interdit de lancer un noeuds si ses parents n'ont jamais été lancés",
+	ErrWorkflowNotificationNotFound.ID:          "La notification du workflow n'existe pas",
+	ErrAuditNotFound.ID:                         "L'audit n'existe pas",
+	ErrInvalidSecretReference.ID:                "Référence de secret invalide, elle doit respecter le pattern backend:chemin#champ",
+	ErrSecretBackendNotFound.ID:                 "Le gestionnaire de secrets n'existe pas",
+	ErrGroupQueueQuotaReached.ID:                "Le groupe a atteint son quota de jobs en cours",
+	ErrNoWorkerModelVersion.ID:                  "La version du modèle de worker n'existe pas",
+	ErrInvalidPassphrase.ID:                     "Phrase secrète invalide",
+	ErrEnvironmentDeploymentNotFound.ID:         "Le déploiement n'existe pas sur cet environnement",
+	ErrEnvironmentPromotionNodeNotFound.ID:      "Aucun noeud du workflow ne déploie cette application sur l'environnement cible",
+	ErrNoPreviousDeployment.ID:                  "Aucun déploiement précédent réussi vers lequel revenir",
+	ErrDeploymentFrozen.ID:                      "Les déploiements sont gelés",
+	ErrInvalidFreezeWindow.ID:                   "Période de gel invalide",
 }
 
 var errorsLanguages = []map[int]string{
@@ -1,6 +1,7 @@
 package exportentities
 
 import (
+	"sort"
 	"text/template"
 
 	"github.com/ovh/cds/sdk"
@@ -13,6 +14,7 @@ type Application struct {
 	RepositoryName    string                         `json:"repo_name,omitempty" yaml:"repo_name,omitempty"`
 	Permissions       map[string]int                 `json:"permissions,omitempty" yaml:"permissions,omitempty"`
 	Variables         map[string]VariableValue       `json:"variables,omitempty" yaml:"variables,omitempty"`
+	Keys              map[string]Key                 `json:"keys,omitempty" yaml:"keys,omitempty"`
 	Pipelines         map[string]ApplicationPipeline `json:"pipelines,omitempty" yaml:"pipelines,omitempty"`
 }
 
@@ -78,6 +80,12 @@ func NewApplication(app *sdk.Application) (a *Application) {
 	for _, p := range app.ApplicationGroups {
 		a.Permissions[p.Group.Name] = p.Permission
 	}
+	if len(app.Keys) > 0 {
+		a.Keys = make(map[string]Key, len(app.Keys))
+		for _, k := range app.Keys {
+			a.Keys[k.Name] = newKey(k.Key)
+		}
+	}
 
 	a.Pipelines = make(map[string]ApplicationPipeline, len(app.Pipelines))
 	for _, ap := range app.Pipelines {
@@ -95,8 +103,7 @@ func NewApplication(app *sdk.Application) (a *Application) {
 		for _, t := range ap.Triggers {
 
 			c := make([]Condition, len(t.Prerequisites))
-			var i int
-			for _, pr := range t.Prerequisites {
+			for i, pr := range t.Prerequisites {
 				c[i] = Condition{
 					Variable: pr.Parameter,
 					Expected: pr.ExpectedValue,
@@ -191,10 +198,16 @@ func NewApplication(app *sdk.Application) (a *Application) {
 			}
 		}
 
-		//Compute all
+		//Compute all, sorted by environment to always export the same options
+		envs := make([]string, 0, len(mapEnvOpts))
+		for k := range mapEnvOpts {
+			envs = append(envs, k)
+		}
+		sort.Strings(envs)
 		pip.Options = make([]ApplicationPipelineOptions, len(mapEnvOpts))
 		var i int
-		for k, v := range mapEnvOpts {
+		for _, k := range envs {
+			v := mapEnvOpts[k]
 			if k != sdk.DefaultEnv.Name {
 				s := k
 				pip.Options[i].Environment = &s
@@ -217,6 +230,68 @@ func NewApplication(app *sdk.Application) (a *Application) {
 	return
 }
 
+// Application returns a sdk.Application. Groups, pipelines, environments and applications of triggers only
+// have their name. Options are not converted: hooks, pollers and schedulers are created by the API
+func (a *Application) Application() *sdk.Application {
+	app := &sdk.Application{
+		Name:               a.Name,
+		RepositoryFullname: a.RepositoryName,
+	}
+	if a.RepositoryManager != "" {
+		app.RepositoriesManager = &sdk.RepositoriesManager{Name: a.RepositoryManager}
+	}
+
+	for k, v := range a.Variables {
+		app.Variable = append(app.Variable, sdk.Variable{Name: k, Type: v.Type, Value: v.Value})
+	}
+	for k, v := range a.Permissions {
+		app.ApplicationGroups = append(app.ApplicationGroups, sdk.GroupPermission{Group: sdk.Group{Name: k}, Permission: v})
+	}
+	for k, v := range a.Keys {
+		app.Keys = append(app.Keys, sdk.ApplicationKey{Key: v.Key(k)})
+	}
+
+	// Pipelines are sorted to attach them always in the same order
+	names := make([]string, 0, len(a.Pipelines))
+	for name := range a.Pipelines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		p := a.Pipelines[name]
+		ap := sdk.ApplicationPipeline{Pipeline: sdk.Pipeline{Name: name}}
+		for k, v := range p.Parameters {
+			ap.Parameters = append(ap.Parameters, sdk.Parameter{Name: k, Type: v.Type, Value: v.Value})
+		}
+		for dest, t := range p.Triggers {
+			trigger := sdk.PipelineTrigger{
+				DestPipeline: sdk.Pipeline{Name: dest},
+				Manual:       t.Manual,
+			}
+			if t.ProjectKey != nil {
+				trigger.DestProject.Key = *t.ProjectKey
+			}
+			if t.ApplicationName != nil {
+				trigger.DestApplication.Name = *t.ApplicationName
+			}
+			if t.FromEnvironment != nil {
+				trigger.SrcEnvironment.Name = *t.FromEnvironment
+			}
+			if t.ToEnvironment != nil {
+				trigger.DestEnvironment.Name = *t.ToEnvironment
+			}
+			for _, c := range t.Conditions {
+				trigger.Prerequisites = append(trigger.Prerequisites, sdk.Prerequisite{Parameter: c.Variable, ExpectedValue: c.Expected})
+			}
+			ap.Triggers = append(ap.Triggers, trigger)
+		}
+		app.Pipelines = append(app.Pipelines, ap)
+	}
+
+	return app
+}
+
 //HCLTemplate returns text/template
 func (a *Application) HCLTemplate() (*template.Template, error) {
 	tmpl := `name = "{{.Name}}"
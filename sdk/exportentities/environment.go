@@ -11,6 +11,7 @@ type Environment struct {
 	Name        string                   `json:"name" yaml:"name"`
 	Values      map[string]VariableValue `json:"values" yaml:"values"`
 	Permissions map[string]int           `json:"permissions" yaml:"permissions"`
+	Keys        map[string]Key           `json:"keys,omitempty" yaml:"keys,omitempty"`
 }
 
 //NewEnvironment returns an Environment from an sdk.Environment pointer
@@ -31,6 +32,12 @@ func NewEnvironment(e *sdk.Environment) (env *Environment) {
 	for _, p := range e.EnvironmentGroups {
 		env.Permissions[p.Group.Name] = p.Permission
 	}
+	if len(e.Keys) > 0 {
+		env.Keys = make(map[string]Key, len(e.Keys))
+		for _, k := range e.Keys {
+			env.Keys[k.Name] = newKey(k.Key)
+		}
+	}
 	return
 }
 
@@ -86,6 +93,9 @@ func (e *Environment) Environment() (env *sdk.Environment) {
 		}
 		i++
 	}
+	for k, v := range e.Keys {
+		env.Keys = append(env.Keys, sdk.EnvironmentKey{Key: v.Key(k)})
+	}
 
 	return
 }
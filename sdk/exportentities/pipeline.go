@@ -6,6 +6,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/mitchellh/mapstructure"
 
@@ -21,6 +22,7 @@ type Pipeline struct {
 	Stages       map[string]Stage          `json:"stages,omitempty" yaml:"stages,omitempty"`
 	Jobs         map[string]Job            `json:"jobs,omitempty" yaml:"jobs,omitempty"`
 	Requirements []Requirement             `json:"requirements,omitempty" yaml:"requirements,omitempty" hcl:"requirement,omitempty"`
+	Services     map[string]Service        `json:"services,omitempty" yaml:"services,omitempty"`
 	Steps        []Step                    `json:"steps,omitempty" yaml:"steps,omitempty" hcl:"step,omitempty"`
 }
 
@@ -33,22 +35,34 @@ type Stage struct {
 
 // Job represents exported sdk.Job
 type Job struct {
-	Description    string        `json:"description,omitempty" yaml:"description,omitempty"`
-	Enabled        *bool         `json:"enabled,omitempty" yaml:"enabled,omitempty"`
-	Steps          []Step        `json:"steps,omitempty" yaml:"steps,omitempty" hcl:"step,omitempty"`
-	Requirements   []Requirement `json:"requirements,omitempty" yaml:"requirements,omitempty" hcl:"requirement,omitempty"`
-	Optional       *bool         `json:"optional,omitempty" yaml:"optional,omitempty" hcl:"optional,omitempty"`
-	AlwaysExecuted *bool         `json:"always_executed,omitempty" yaml:"always_executed,omitempty" hcl:"always_executed,omitempty"`
+	Description    string             `json:"description,omitempty" yaml:"description,omitempty"`
+	Enabled        *bool              `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	Steps          []Step             `json:"steps,omitempty" yaml:"steps,omitempty" hcl:"step,omitempty"`
+	Requirements   []Requirement      `json:"requirements,omitempty" yaml:"requirements,omitempty" hcl:"requirement,omitempty"`
+	Services       map[string]Service `json:"services,omitempty" yaml:"services,omitempty"`
+	Optional       *bool              `json:"optional,omitempty" yaml:"optional,omitempty" hcl:"optional,omitempty"`
+	AlwaysExecuted *bool              `json:"always_executed,omitempty" yaml:"always_executed,omitempty" hcl:"always_executed,omitempty"`
+	Timeout        string             `json:"timeout,omitempty" yaml:"timeout,omitempty" hcl:"timeout,omitempty"`
 }
 
 // Step represents exported step used in a job
 type Step map[string]interface{}
 
+// stepOptions are the keys of a step which are not the step itself
+var stepOptions = map[string]bool{
+	"enabled":         true,
+	"optional":        true,
+	"always_executed": true,
+	"timeout":         true,
+	"conditions":      true,
+	"image":           true,
+}
+
 // IsValid returns true is the step is valid
 func (s Step) IsValid() bool {
 	keys := []string{}
 	for k := range s {
-		if k != "enabled" && k != "optional" && k != "always_executed" {
+		if !stepOptions[k] {
 			keys = append(keys, k)
 		}
 	}
@@ -58,13 +72,125 @@ func (s Step) IsValid() bool {
 func (s Step) key() string {
 	keys := []string{}
 	for k := range s {
-		if k != "enabled" && k != "optional" && k != "always_executed" {
+		if !stepOptions[k] {
 			keys = append(keys, k)
 		}
 	}
 	return keys[0]
 }
 
+// Timeout returns the timeout of the step in seconds, 0 if not set
+func (s Step) Timeout() (int64, error) {
+	bI, ok := s["timeout"]
+	if !ok {
+		return 0, nil
+	}
+	bS, ok := bI.(string)
+	if !ok {
+		return 0, fmt.Errorf("Malformatted Step : timeout attribute must be a duration like 10m")
+	}
+	return parseTimeout(bS)
+}
+
+// Image returns the image of the container running the step, empty if not set
+func (s Step) Image() (string, error) {
+	bI, ok := s["image"]
+	if !ok {
+		return "", nil
+	}
+	bS, ok := bI.(string)
+	if !ok {
+		return "", fmt.Errorf("Malformatted Step : image attribute must be a string")
+	}
+	return bS, nil
+}
+
+// Conditions returns the conditions of the step, written like "cds.job.status eq Fail"
+func (s Step) Conditions() ([]sdk.WorkflowTriggerCondition, error) {
+	bI, ok := s["conditions"]
+	if !ok {
+		return nil, nil
+	}
+	var bS []interface{}
+	switch v := bI.(type) {
+	case []interface{}:
+		bS = v
+	case []string:
+		for _, c := range v {
+			bS = append(bS, c)
+		}
+	default:
+		return nil, fmt.Errorf("Malformatted Step : conditions attribute must be a list")
+	}
+	conditions := make([]sdk.WorkflowTriggerCondition, 0, len(bS))
+	for _, c := range bS {
+		cS, ok := c.(string)
+		if !ok {
+			return nil, fmt.Errorf("Malformatted Step : conditions must be strings like \"cds.job.status eq Fail\"")
+		}
+		cond, err := parseCondition(cS)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, cond)
+	}
+	return conditions, nil
+}
+
+// parseCondition parses a condition written "<variable> <operator> <value>", operators are the
+// workflow conditions operators or their symbols
+func parseCondition(s string) (sdk.WorkflowTriggerCondition, error) {
+	parts := strings.Fields(s)
+	if len(parts) < 3 {
+		return sdk.WorkflowTriggerCondition{}, fmt.Errorf("Invalid condition %s: must be like \"cds.job.status eq Fail\"", s)
+	}
+	operator := parts[1]
+	if _, ok := sdk.WorkflowConditionsOperators[operator]; !ok {
+		operator = ""
+		for op, symbol := range sdk.WorkflowConditionsOperators {
+			if symbol == parts[1] {
+				operator = op
+			}
+		}
+		if operator == "" {
+			return sdk.WorkflowTriggerCondition{}, fmt.Errorf("Invalid condition %s: unknown operator %s", s, parts[1])
+		}
+	}
+	// The value starts after the operator and may contain spaces
+	rest := s[strings.Index(s, parts[0])+len(parts[0]):]
+	value := strings.TrimSpace(rest[strings.Index(rest, parts[1])+len(parts[1]):])
+	return sdk.WorkflowTriggerCondition{Variable: parts[0], Operator: operator, Value: value}, nil
+}
+
+// formatConditions formats conditions as in steps
+func formatConditions(conditions []sdk.WorkflowTriggerCondition) []string {
+	res := make([]string, len(conditions))
+	for i, c := range conditions {
+		res[i] = fmt.Sprintf("%s %s %s", c.Variable, c.Operator, c.Value)
+	}
+	return res
+}
+
+// parseTimeout parses a duration like 1h30m into seconds
+func parseTimeout(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < time.Second {
+		return 0, fmt.Errorf("Invalid timeout %s: must be a duration like 10m", s)
+	}
+	return int64(d / time.Second), nil
+}
+
+// formatTimeout formats a timeout in seconds as a duration
+func formatTimeout(seconds int64) string {
+	if seconds <= 0 {
+		return ""
+	}
+	return (time.Duration(seconds) * time.Second).String()
+}
+
 //AsScript returns the step a sdk.Action
 func (s Step) AsScript() (*sdk.Action, bool, error) {
 	if !s.IsValid() {
@@ -314,12 +440,21 @@ type Requirement struct {
 	Memory   string             `json:"memory,omitempty" yaml:"memory,omitempty"`
 }
 
-// ServiceRequirement represents an exported sdk.Requirement of type ServiceRequirement
+// ServiceRequirement represents an exported sdk.Requirement of type ServiceRequirement.
+// Services are now exported in the services of a job
 type ServiceRequirement struct {
 	Name  string `json:"name,omitempty" yaml:"name,omitempty"`
 	Value string `json:"value,omitempty" yaml:"value,omitempty"`
 }
 
+// Service represents an exported sdk.JobService, its key in the job is its hostname
+type Service struct {
+	Image       string            `json:"image" yaml:"image"`
+	Env         map[string]string `json:"env,omitempty" yaml:"env,omitempty"`
+	Ports       []string          `json:"ports,omitempty" yaml:"ports,omitempty"`
+	HealthCheck string            `json:"healthcheck,omitempty" yaml:"healthcheck,omitempty"`
+}
+
 //NewPipeline creates an exportable pipeline from a sdk.Pipeline
 func NewPipeline(pip *sdk.Pipeline) (p *Pipeline) {
 	p = &Pipeline{}
@@ -360,9 +495,14 @@ func NewPipeline(pip *sdk.Pipeline) (p *Pipeline) {
 			case 0:
 				return
 			case 1:
-				p.Steps = newSteps(pip.Stages[0].Jobs[0].Action)
-				p.Requirements = newRequirements(pip.Stages[0].Jobs[0].Action.Requirements)
-				return
+				// The job timeout can only be exported with the job
+				if pip.Stages[0].Jobs[0].Action.Timeout == 0 {
+					p.Steps = newSteps(pip.Stages[0].Jobs[0].Action)
+					p.Requirements = newRequirements(pip.Stages[0].Jobs[0].Action.Requirements)
+					p.Services = newServices(pip.Stages[0].Jobs[0].Action.Requirements)
+					return
+				}
+				p.Jobs = newJobs(pip.Stages[0].Jobs)
 			default:
 				p.Jobs = newJobs(pip.Stages[0].Jobs)
 			}
@@ -419,7 +559,10 @@ func newRequirements(req []sdk.Requirement) []Requirement {
 		case sdk.PluginRequirement:
 			res = append(res, Requirement{Plugin: r.Value})
 		case sdk.ServiceRequirement:
-			res = append(res, Requirement{Service: ServiceRequirement{Name: r.Name, Value: r.Value}})
+			// Services are exported with newServices, unless they can't be read
+			if _, err := sdk.NewJobService(r); err != nil {
+				res = append(res, Requirement{Service: ServiceRequirement{Name: r.Name, Value: r.Value}})
+			}
 		case sdk.MemoryRequirement:
 			res = append(res, Requirement{Memory: r.Value})
 		}
@@ -427,6 +570,29 @@ func newRequirements(req []sdk.Requirement) []Requirement {
 	return res
 }
 
+func newServices(req []sdk.Requirement) map[string]Service {
+	var res map[string]Service
+	for _, r := range req {
+		if r.Type != sdk.ServiceRequirement {
+			continue
+		}
+		s, err := sdk.NewJobService(r)
+		if err != nil {
+			continue
+		}
+		if res == nil {
+			res = map[string]Service{}
+		}
+		res[s.Name] = Service{
+			Image:       s.Image,
+			Env:         s.Env,
+			Ports:       s.Ports,
+			HealthCheck: s.HealthCheck,
+		}
+	}
+	return res
+}
+
 func newJobs(jobs []sdk.Job) map[string]Job {
 	res := map[string]Job{}
 	for i := range jobs {
@@ -441,6 +607,8 @@ func newJobs(jobs []sdk.Job) map[string]Job {
 		jo.Steps = newSteps(j.Action)
 		jo.Description = j.Action.Description
 		jo.Requirements = newRequirements(j.Action.Requirements)
+		jo.Services = newServices(j.Action.Requirements)
+		jo.Timeout = formatTimeout(j.Action.Timeout)
 		res[j.Action.Name] = jo
 	}
 	return res
@@ -460,6 +628,15 @@ func newSteps(a sdk.Action) []Step {
 		if act.AlwaysExecuted {
 			s["always_executed"] = act.AlwaysExecuted
 		}
+		if act.Timeout > 0 {
+			s["timeout"] = formatTimeout(act.Timeout)
+		}
+		if len(act.Conditions) > 0 {
+			s["conditions"] = formatConditions(act.Conditions)
+		}
+		if act.Image != "" {
+			s["image"] = act.Image
+		}
 
 		switch act.Type {
 		case sdk.BuiltinAction:
@@ -605,7 +782,7 @@ func (p *Pipeline) Pipeline() (*sdk.Pipeline, error) {
 							Name:         p.Name,
 							Actions:      actions,
 							Type:         sdk.JoinedAction,
-							Requirements: computeJobRequirements(p.Requirements),
+							Requirements: append(computeJobRequirements(p.Requirements), computeServices(p.Services)...),
 						},
 					},
 				},
@@ -691,6 +868,21 @@ func computeSteps(steps []Step) ([]sdk.Action, error) {
 		if err != nil {
 			return nil, err
 		}
+		a.Timeout, err = s.Timeout()
+		if err != nil {
+			return nil, err
+		}
+		a.Conditions, err = s.Conditions()
+		if err != nil {
+			return nil, err
+		}
+		a.Image, err = s.Image()
+		if err != nil {
+			return nil, err
+		}
+		if a.Image != "" && s.key() != "script" {
+			return nil, fmt.Errorf("Malformatted Step : image attribute is only allowed on script steps")
+		}
 		res = append(res, *a)
 	}
 	return res, nil
@@ -778,6 +970,27 @@ func computeJobRequirements(req []Requirement) []sdk.Requirement {
 	return res
 }
 
+func computeServices(services map[string]Service) []sdk.Requirement {
+	names := make([]string, 0, len(services))
+	for n := range services {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	res := make([]sdk.Requirement, 0, len(services))
+	for _, n := range names {
+		s := services[n]
+		res = append(res, sdk.JobService{
+			Name:        n,
+			Image:       s.Image,
+			Env:         s.Env,
+			Ports:       s.Ports,
+			HealthCheck: s.HealthCheck,
+		}.Requirement())
+	}
+	return res
+}
+
 func computeJob(name string, j Job) (*sdk.Job, error) {
 	job := sdk.Job{
 		Action: sdk.Action{
@@ -792,7 +1005,13 @@ func computeJob(name string, j Job) (*sdk.Job, error) {
 		job.Enabled = true
 	}
 	job.Action.Enabled = job.Enabled
-	job.Action.Requirements = computeJobRequirements(j.Requirements)
+	job.Action.Requirements = append(computeJobRequirements(j.Requirements), computeServices(j.Services)...)
+
+	timeout, err := parseTimeout(j.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	job.Action.Timeout = timeout
 
 	//Compute steps for the jobs
 	children, err := computeSteps(j.Steps)
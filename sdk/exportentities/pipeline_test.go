@@ -3,6 +3,7 @@ package exportentities
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -541,6 +542,151 @@ steps:
 	assert.Len(t, p.Stages[0].Jobs[0].Action.Actions[0].Parameters, 7)
 }
 
+func Test_ImportPipelineWithTimeouts(t *testing.T) {
+	in := `name: build
+jobs:
+  compile:
+    timeout: 1h30m
+    steps:
+    - script: make
+      timeout: 10m
+`
+
+	payload := &Pipeline{}
+	test.NoError(t, yaml.Unmarshal([]byte(in), payload))
+
+	p, err := payload.Pipeline()
+	test.NoError(t, err)
+
+	assert.Equal(t, int64(5400), p.Stages[0].Jobs[0].Action.Timeout)
+	assert.Equal(t, int64(600), p.Stages[0].Jobs[0].Action.Actions[0].Timeout)
+
+	exported := NewPipeline(p)
+	assert.Equal(t, "1h30m0s", exported.Jobs["compile"].Timeout)
+	assert.Equal(t, "10m0s", exported.Jobs["compile"].Steps[0]["timeout"])
+
+	payload = &Pipeline{}
+	test.NoError(t, yaml.Unmarshal([]byte(strings.Replace(in, "10m", "10", 1)), payload))
+	_, err = payload.Pipeline()
+	assert.Error(t, err)
+}
+
+func Test_ImportPipelineWithConditions(t *testing.T) {
+	in := `name: build
+steps:
+- script: make
+- script: ./notify.sh
+  conditions:
+  - cds.job.status eq Fail
+  - git.branch = master
+`
+
+	payload := &Pipeline{}
+	test.NoError(t, yaml.Unmarshal([]byte(in), payload))
+
+	p, err := payload.Pipeline()
+	test.NoError(t, err)
+
+	steps := p.Stages[0].Jobs[0].Action.Actions
+	assert.Empty(t, steps[0].Conditions)
+	assert.Equal(t, []sdk.WorkflowTriggerCondition{
+		{Variable: sdk.JobStatusParameter, Operator: sdk.WorkflowConditionsOperatorEquals, Value: "Fail"},
+		{Variable: "git.branch", Operator: sdk.WorkflowConditionsOperatorEquals, Value: "master"},
+	}, steps[1].Conditions)
+
+	exported := NewPipeline(p)
+	assert.Equal(t, []string{"cds.job.status eq Fail", "git.branch eq master"}, exported.Steps[1]["conditions"])
+
+	payload = &Pipeline{}
+	test.NoError(t, yaml.Unmarshal([]byte(strings.Replace(in, "eq Fail", "is Fail", 1)), payload))
+	_, err = payload.Pipeline()
+	assert.Error(t, err)
+}
+
+func Test_ImportPipelineWithImages(t *testing.T) {
+	in := `name: build
+steps:
+- script: go build
+  image: golang:1.10
+- script: npm install
+  image: node:{{.cds.pip.node.version}}
+- script: make
+`
+
+	payload := &Pipeline{}
+	test.NoError(t, yaml.Unmarshal([]byte(in), payload))
+
+	p, err := payload.Pipeline()
+	test.NoError(t, err)
+
+	steps := p.Stages[0].Jobs[0].Action.Actions
+	assert.Equal(t, "golang:1.10", steps[0].Image)
+	assert.Equal(t, "node:{{.cds.pip.node.version}}", steps[1].Image)
+	assert.Equal(t, "", steps[2].Image)
+
+	exported := NewPipeline(p)
+	assert.Equal(t, "golang:1.10", exported.Steps[0]["image"])
+	_, ok := exported.Steps[2]["image"]
+	assert.False(t, ok)
+
+	// Only scripts run in the image of a step
+	in = `name: build
+steps:
+- jUnitReport: ./target/surefire-reports*.xml
+  image: golang:1.10
+`
+	payload = &Pipeline{}
+	test.NoError(t, yaml.Unmarshal([]byte(in), payload))
+	_, err = payload.Pipeline()
+	assert.Error(t, err)
+}
+
+func Test_ImportPipelineWithServices(t *testing.T) {
+	in := `name: build
+jobs:
+  test:
+    services:
+      postgres:
+        image: postgres:9.6
+        env:
+          POSTGRES_PASSWORD: secret
+        ports:
+        - "5432"
+        healthcheck: pg_isready -h postgres
+      redis:
+        image: redis:latest
+    requirements:
+    - service:
+        name: legacy
+        value: mysql:5.7 MYSQL_ROOT_PASSWORD=secret
+    steps:
+    - script: make test
+`
+
+	payload := &Pipeline{}
+	test.NoError(t, yaml.Unmarshal([]byte(in), payload))
+
+	p, err := payload.Pipeline()
+	test.NoError(t, err)
+
+	services, err := sdk.JobServices(p.Stages[0].Jobs[0].Action.Requirements)
+	test.NoError(t, err)
+	assert.Equal(t, []sdk.JobService{
+		{Name: "legacy", Image: "mysql:5.7", Env: map[string]string{"MYSQL_ROOT_PASSWORD": "secret"}},
+		{Name: "postgres", Image: "postgres:9.6", Env: map[string]string{"POSTGRES_PASSWORD": "secret"}, Ports: []string{"5432"}, HealthCheck: "pg_isready -h postgres"},
+		{Name: "redis", Image: "redis:latest"},
+	}, services)
+
+	// Services which can be written as legacy requirements keep this format
+	assert.Equal(t, "redis:latest", p.Stages[0].Jobs[0].Action.Requirements[2].Value)
+
+	// A single job is exported as the steps of the pipeline
+	exported := NewPipeline(p)
+	assert.Empty(t, exported.Requirements)
+	assert.Len(t, exported.Services, 3)
+	assert.Equal(t, "pg_isready -h postgres", exported.Services["postgres"].HealthCheck)
+}
+
 func Test_IsFlagged(t *testing.T) {
 	testc := []struct {
 		flag     string
@@ -0,0 +1,416 @@
+package exportentities
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/scrypt"
+	"gopkg.in/yaml.v2"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// ProjectArchiveVersion is the version of the project archives
+const ProjectArchiveVersion = "1"
+
+// Project is a struct to export a sdk.Project with its environments, applications, pipelines and workflows.
+// Secrets are encrypted with a passphrase. Legacy notifications of applications are not exported
+type Project struct {
+	Version      string                   `json:"version" yaml:"version"`
+	Key          string                   `json:"key" yaml:"key"`
+	Name         string                   `json:"name" yaml:"name"`
+	Salt         string                   `json:"salt,omitempty" yaml:"salt,omitempty"`
+	Permissions  map[string]int           `json:"permissions,omitempty" yaml:"permissions,omitempty"`
+	Variables    map[string]VariableValue `json:"variables,omitempty" yaml:"variables,omitempty"`
+	Keys         map[string]Key           `json:"keys,omitempty" yaml:"keys,omitempty"`
+	WorkerModels []string                 `json:"worker_models,omitempty" yaml:"worker_models,omitempty"`
+	Environments []Environment            `json:"environments,omitempty" yaml:"-"`
+	Applications []Application            `json:"applications,omitempty" yaml:"-"`
+	Pipelines    []Pipeline               `json:"pipelines,omitempty" yaml:"-"`
+	Workflows    []Workflow               `json:"workflows,omitempty" yaml:"-"`
+}
+
+// ProjectImport is the body of a project import: the project and the passphrase of its secrets
+type ProjectImport struct {
+	Passphrase string  `json:"passphrase"`
+	Project    Project `json:"project"`
+}
+
+// Key is a struct to export a sdk.Key
+type Key struct {
+	Type    string `json:"type" yaml:"type"`
+	Public  string `json:"public" yaml:"public"`
+	Private string `json:"private" yaml:"private"`
+	KeyID   string `json:"key_id,omitempty" yaml:"key_id,omitempty"`
+}
+
+func newKey(k sdk.Key) Key {
+	return Key{
+		Type:    k.Type,
+		Public:  k.Public,
+		Private: k.Private,
+		KeyID:   k.KeyID,
+	}
+}
+
+// Key returns the sdk.Key named name
+func (k Key) Key(name string) sdk.Key {
+	return sdk.Key{
+		Name:    name,
+		Type:    k.Type,
+		Public:  k.Public,
+		Private: k.Private,
+		KeyID:   k.KeyID,
+	}
+}
+
+// NewProject returns an exportable project from a sdk.Project, without its environments, applications,
+// pipelines and workflows
+func NewProject(proj *sdk.Project) *Project {
+	p := &Project{
+		Version: ProjectArchiveVersion,
+		Key:     proj.Key,
+		Name:    proj.Name,
+	}
+	if len(proj.ProjectGroups) > 0 {
+		p.Permissions = make(map[string]int, len(proj.ProjectGroups))
+		for _, g := range proj.ProjectGroups {
+			p.Permissions[g.Group.Name] = g.Permission
+		}
+	}
+	if len(proj.Variable) > 0 {
+		p.Variables = make(map[string]VariableValue, len(proj.Variable))
+		for _, v := range proj.Variable {
+			p.Variables[v.Name] = VariableValue{Type: v.Type, Value: v.Value}
+		}
+	}
+	if len(proj.Keys) > 0 {
+		p.Keys = make(map[string]Key, len(proj.Keys))
+		for _, k := range proj.Keys {
+			p.Keys[k.Name] = newKey(k.Key)
+		}
+	}
+	return p
+}
+
+// Project returns the sdk.Project, with its groups, variables and keys
+func (p *Project) Project() *sdk.Project {
+	proj := &sdk.Project{
+		Key:  p.Key,
+		Name: p.Name,
+	}
+	for k, v := range p.Permissions {
+		proj.ProjectGroups = append(proj.ProjectGroups, sdk.GroupPermission{Group: sdk.Group{Name: k}, Permission: v})
+	}
+	for k, v := range p.Variables {
+		proj.Variable = append(proj.Variable, sdk.Variable{Name: k, Type: v.Type, Value: v.Value})
+	}
+	for k, v := range p.Keys {
+		proj.Keys = append(proj.Keys, sdk.ProjectKey{Key: v.Key(k)})
+	}
+	return proj
+}
+
+// WriteArchive writes the project in a tar.gz archive: project.yml and a yaml file by environment,
+// application, pipeline and workflow
+func (p *Project) WriteArchive(w io.Writer) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	add := func(name string, i interface{}) error {
+		b, err := yaml.Marshal(i)
+		if err != nil {
+			return err
+		}
+		hdr := &tar.Header{
+			Name:    name,
+			Mode:    0644,
+			Size:    int64(len(b)),
+			ModTime: time.Now(),
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err = tw.Write(b)
+		return err
+	}
+
+	if err := add("project.yml", p); err != nil {
+		return err
+	}
+	for i := range p.Environments {
+		if err := add(path.Join("environments", p.Environments[i].Name+".yml"), &p.Environments[i]); err != nil {
+			return err
+		}
+	}
+	for i := range p.Applications {
+		if err := add(path.Join("applications", p.Applications[i].Name+".yml"), &p.Applications[i]); err != nil {
+			return err
+		}
+	}
+	for i := range p.Pipelines {
+		if err := add(path.Join("pipelines", p.Pipelines[i].Name+".yml"), &p.Pipelines[i]); err != nil {
+			return err
+		}
+	}
+	for i := range p.Workflows {
+		if err := add(path.Join("workflows", p.Workflows[i].Name+".yml"), &p.Workflows[i]); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// ReadProjectArchive reads a project archive written by WriteArchive
+func ReadProjectArchive(r io.Reader) (*Project, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gr.Close()
+
+	var p *Project
+	var envs []Environment
+	var apps []Application
+	var pips []Pipeline
+	var workflows []Workflow
+
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		b, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+
+		dir, file := path.Split(hdr.Name)
+		if !strings.HasSuffix(file, ".yml") {
+			continue
+		}
+		switch dir {
+		case "":
+			if file != "project.yml" {
+				continue
+			}
+			p = new(Project)
+			err = yaml.Unmarshal(b, p)
+		case "environments/":
+			var e Environment
+			err = yaml.Unmarshal(b, &e)
+			envs = append(envs, e)
+		case "applications/":
+			var a Application
+			err = yaml.Unmarshal(b, &a)
+			apps = append(apps, a)
+		case "pipelines/":
+			var pip Pipeline
+			err = yaml.Unmarshal(b, &pip)
+			pips = append(pips, pip)
+		case "workflows/":
+			var w Workflow
+			err = yaml.Unmarshal(b, &w)
+			workflows = append(workflows, w)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid file %s: %v", hdr.Name, err)
+		}
+	}
+
+	if p == nil {
+		return nil, fmt.Errorf("project.yml not found in archive")
+	}
+	if p.Version != ProjectArchiveVersion {
+		return nil, fmt.Errorf("unsupported archive version %s", p.Version)
+	}
+	p.Environments = envs
+	p.Applications = apps
+	p.Pipelines = pips
+	p.Workflows = workflows
+	return p, nil
+}
+
+// EncryptSecrets encrypts the secrets of the project, its environments, applications, pipelines and
+// workflows with a key derived from the passphrase: password and key variables, private keys and password
+// parameters
+func (p *Project) EncryptSecrets(passphrase string) error {
+	salt := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return err
+	}
+	p.Salt = base64.StdEncoding.EncodeToString(salt)
+
+	gcm, err := newSecretsCipher(passphrase, salt)
+	if err != nil {
+		return err
+	}
+	return p.walkSecrets(func(s string) (string, error) {
+		nonce := make([]byte, gcm.NonceSize())
+		if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+			return "", err
+		}
+		return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte(s), nil)), nil
+	})
+}
+
+// DecryptSecrets decrypts the secrets encrypted by EncryptSecrets. It returns sdk.ErrInvalidPassphrase if
+// the passphrase is not the one used to encrypt them
+func (p *Project) DecryptSecrets(passphrase string) error {
+	salt, err := base64.StdEncoding.DecodeString(p.Salt)
+	if err != nil || len(salt) == 0 {
+		return fmt.Errorf("invalid salt")
+	}
+
+	gcm, err := newSecretsCipher(passphrase, salt)
+	if err != nil {
+		return err
+	}
+	if err := p.walkSecrets(func(s string) (string, error) {
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil || len(b) < gcm.NonceSize() {
+			return "", sdk.ErrInvalidPassphrase
+		}
+		clear, err := gcm.Open(nil, b[:gcm.NonceSize()], b[gcm.NonceSize():], nil)
+		if err != nil {
+			return "", sdk.ErrInvalidPassphrase
+		}
+		return string(clear), nil
+	}); err != nil {
+		return err
+	}
+	p.Salt = ""
+	return nil
+}
+
+func newSecretsCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, 32768, 8, 1, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// walkSecrets replaces all the secrets which are not empty by f(secret)
+func (p *Project) walkSecrets(f func(string) (string, error)) error {
+	variables := func(m map[string]VariableValue) error {
+		for k, v := range m {
+			if !sdk.NeedPlaceholder(v.Type) || v.Value == "" {
+				continue
+			}
+			s, err := f(v.Value)
+			if err != nil {
+				return err
+			}
+			v.Value = s
+			m[k] = v
+		}
+		return nil
+	}
+	keys := func(m map[string]Key) error {
+		for k, v := range m {
+			if v.Private == "" {
+				continue
+			}
+			s, err := f(v.Private)
+			if err != nil {
+				return err
+			}
+			v.Private = s
+			m[k] = v
+		}
+		return nil
+	}
+
+	if err := variables(p.Variables); err != nil {
+		return err
+	}
+	if err := keys(p.Keys); err != nil {
+		return err
+	}
+	for _, e := range p.Environments {
+		if err := variables(e.Values); err != nil {
+			return err
+		}
+		if err := keys(e.Keys); err != nil {
+			return err
+		}
+	}
+	for _, a := range p.Applications {
+		if err := variables(a.Variables); err != nil {
+			return err
+		}
+		if err := keys(a.Keys); err != nil {
+			return err
+		}
+		for _, ap := range a.Pipelines {
+			if err := variables(ap.Parameters); err != nil {
+				return err
+			}
+			for _, o := range ap.Options {
+				for _, s := range o.Schedulers {
+					if err := variables(s.Parameters); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	for _, pip := range p.Pipelines {
+		for k, v := range pip.Parameters {
+			if v.Type != sdk.SecretVariable || v.DefaultValue == "" {
+				continue
+			}
+			s, err := f(v.DefaultValue)
+			if err != nil {
+				return err
+			}
+			v.DefaultValue = s
+			pip.Parameters[k] = v
+		}
+	}
+	for _, w := range p.Workflows {
+		for _, n := range w.Nodes {
+			if err := variables(n.Parameters); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Diff returns true if the two entities are different once exported in yaml
+func Diff(a, b interface{}) (bool, error) {
+	ba, err := yaml.Marshal(a)
+	if err != nil {
+		return false, err
+	}
+	bb, err := yaml.Marshal(b)
+	if err != nil {
+		return false, err
+	}
+	return !bytes.Equal(ba, bb), nil
+}
@@ -0,0 +1,127 @@
+package exportentities
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestProjectArchive(t *testing.T) {
+	w := &sdk.Workflow{
+		Name: "w",
+		Root: &sdk.WorkflowNode{
+			ID:       1,
+			Name:     "build",
+			Pipeline: sdk.Pipeline{Name: "build"},
+			Context: &sdk.WorkflowNodeContext{
+				Application:               &sdk.Application{Name: "app"},
+				DefaultPipelineParameters: []sdk.Parameter{{Name: "token", Type: sdk.SecretVariable, Value: "s3cr3t"}},
+			},
+			Hooks: []sdk.WorkflowNodeHook{{
+				WorkflowHookModel: sdk.WorkflowHookModel{Name: "Scheduler"},
+				Config:            sdk.WorkflowNodeHookConfig{"cron": "0 2 * * *", "project": "PROJ", "workflow": "w"},
+			}},
+			Triggers: []sdk.WorkflowNodeTrigger{
+				{
+					WorkflowDestNode: sdk.WorkflowNode{ID: 2, Name: "test", Pipeline: sdk.Pipeline{Name: "test"}},
+					Conditions:       []sdk.WorkflowTriggerCondition{{Variable: "git.branch", Operator: sdk.WorkflowConditionsOperatorEquals, Value: "master"}},
+				},
+				{
+					WorkflowDestNode: sdk.WorkflowNode{ID: 3, Name: "lint", Pipeline: sdk.Pipeline{Name: "test"}},
+					Manual:           true,
+				},
+			},
+		},
+		Joins: []sdk.WorkflowNodeJoin{{
+			SourceNodeIDs: []int64{3, 2},
+			Triggers: []sdk.WorkflowNodeJoinTrigger{{
+				WorkflowDestNode: sdk.WorkflowNode{ID: 4, Name: "deploy", Pipeline: sdk.Pipeline{Name: "deploy"}, Context: &sdk.WorkflowNodeContext{Environment: &sdk.Environment{ID: 10, Name: "prod"}}},
+			}},
+		}},
+		Groups: []sdk.GroupPermission{{Group: sdk.Group{Name: "devs"}, Permission: 7}},
+	}
+	ew, err := NewWorkflow(w)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"lint", "test"}, ew.Joins[0].Sources)
+	assert.Equal(t, map[string]string{"cron": "0 2 * * *"}, ew.Nodes["build"].Hooks[0].Config)
+
+	p := &Project{
+		Version:     ProjectArchiveVersion,
+		Key:         "PROJ",
+		Name:        "Project",
+		Permissions: map[string]int{"devs": 7},
+		Variables: map[string]VariableValue{
+			"password": {Type: sdk.SecretVariable, Value: "p4ssw0rd"},
+			"url":      {Type: sdk.StringVariable, Value: "https://example.com"},
+		},
+		Keys:         map[string]Key{"ssh": {Type: "ssh", Public: "public", Private: "private"}},
+		WorkerModels: []string{"golang"},
+		Environments: []Environment{{
+			Name:        "prod",
+			Values:      map[string]VariableValue{"token": {Type: sdk.SecretVariable, Value: "t0k3n"}},
+			Permissions: map[string]int{"devs": 7},
+		}},
+		Applications: []Application{{
+			Name:      "app",
+			Variables: map[string]VariableValue{"key": {Type: sdk.KeyVariable, Value: "private key"}},
+			Pipelines: map[string]ApplicationPipeline{"build": {Parameters: map[string]VariableValue{"secret": {Type: sdk.SecretVariable, Value: "s"}}}},
+		}},
+		Pipelines: []Pipeline{{
+			Name:       "build",
+			Parameters: map[string]ParameterValue{"pass": {Type: sdk.SecretVariable, DefaultValue: "default"}},
+			Steps:      []Step{{"script": "make"}},
+		}},
+		Workflows: []Workflow{*ew},
+	}
+
+	// Secrets are encrypted with the passphrase before being written
+	assert.NoError(t, p.EncryptSecrets("passphrase"))
+	assert.NotEqual(t, "p4ssw0rd", p.Variables["password"].Value)
+	assert.Equal(t, "https://example.com", p.Variables["url"].Value)
+	assert.NotEqual(t, "private", p.Keys["ssh"].Private)
+	assert.NotEqual(t, "default", p.Pipelines[0].Parameters["pass"].DefaultValue)
+	assert.NotEqual(t, "s3cr3t", p.Workflows[0].Nodes["build"].Parameters["token"].Value)
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, p.WriteArchive(buf))
+
+	read, err := ReadProjectArchive(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, p, read)
+
+	assert.Equal(t, sdk.ErrInvalidPassphrase, read.DecryptSecrets("wrong"))
+
+	read, err = ReadProjectArchive(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	assert.NoError(t, read.DecryptSecrets("passphrase"))
+	assert.Equal(t, "p4ssw0rd", read.Variables["password"].Value)
+	assert.Equal(t, "private", read.Keys["ssh"].Private)
+	assert.Equal(t, "t0k3n", read.Environments[0].Values["token"].Value)
+	assert.Equal(t, "private key", read.Applications[0].Variables["key"].Value)
+	assert.Equal(t, "s", read.Applications[0].Pipelines["build"].Parameters["secret"].Value)
+	assert.Equal(t, "default", read.Pipelines[0].Parameters["pass"].DefaultValue)
+
+	// The workflow is the same as the exported one
+	imported, err := read.Workflows[0].Workflow()
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cr3t", imported.Root.Context.DefaultPipelineParameters[0].Value)
+	assert.Equal(t, "app", imported.Root.Context.Application.Name)
+	assert.Len(t, imported.Root.Triggers, 2)
+	for _, tr := range imported.Root.Triggers {
+		switch tr.WorkflowDestNode.Name {
+		case "test":
+			assert.Equal(t, w.Root.Triggers[0].Conditions, tr.Conditions)
+		case "lint":
+			assert.True(t, tr.Manual)
+		default:
+			t.Errorf("unexpected node %s", tr.WorkflowDestNode.Name)
+		}
+	}
+	assert.Equal(t, []string{"lint", "test"}, imported.Joins[0].SourceNodeRefs)
+	assert.Equal(t, "deploy", imported.Joins[0].Triggers[0].WorkflowDestNode.Ref)
+	assert.Equal(t, "prod", imported.Joins[0].Triggers[0].WorkflowDestNode.Context.Environment.Name)
+	assert.Equal(t, "devs", imported.Groups[0].Group.Name)
+}
@@ -0,0 +1,128 @@
+package exportentities
+
+import (
+	"fmt"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// WorkerModel represents an exported sdk.Model
+type WorkerModel struct {
+	Name          string            `json:"name" yaml:"name"`
+	Type          string            `json:"type,omitempty" yaml:"type,omitempty"`
+	Image         string            `json:"image,omitempty" yaml:"image,omitempty"`
+	Dockerfile    string            `json:"dockerfile,omitempty" yaml:"dockerfile,omitempty"`
+	Communication string            `json:"communication,omitempty" yaml:"communication,omitempty"`
+	Group         string            `json:"group" yaml:"group"`
+	Capabilities  []Requirement     `json:"capabilities,omitempty" yaml:"capabilities,omitempty"`
+	Provision     int64             `json:"provision,omitempty" yaml:"provision,omitempty"`
+	Pool          *WorkerModelPool  `json:"pool,omitempty" yaml:"pool,omitempty"`
+	Template      map[string]string `json:"template,omitempty" yaml:"template,omitempty"`
+	RunScript     string            `json:"run_script,omitempty" yaml:"run_script,omitempty"`
+	Disabled      bool              `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+}
+
+// WorkerModelPool represents an exported sdk.ModelPoolPolicy
+type WorkerModelPool struct {
+	MinIdle int    `json:"min_idle,omitempty" yaml:"min_idle,omitempty"`
+	Max     int    `json:"max,omitempty" yaml:"max,omitempty"`
+	IdleTTL string `json:"idle_ttl,omitempty" yaml:"idle_ttl,omitempty"`
+}
+
+// NewWorkerModel creates an exportable worker model from a sdk.Model
+func NewWorkerModel(m *sdk.Model) *WorkerModel {
+	wm := &WorkerModel{
+		Name:          m.Name,
+		Communication: m.Communication,
+		Group:         m.Group.Name,
+		Capabilities:  newRequirements(m.Capabilities),
+		Provision:     m.Provision,
+		RunScript:     m.RunScript,
+		Disabled:      m.Disabled,
+	}
+
+	// We consider docker models are default
+	if m.Type != sdk.Docker {
+		wm.Type = m.Type
+	}
+	if m.Communication == sdk.HTTP {
+		wm.Communication = ""
+	}
+
+	// The image of a model built from a Dockerfile is computed
+	if m.Dockerfile != "" {
+		wm.Dockerfile = m.Dockerfile
+	} else {
+		wm.Image = m.Image
+	}
+
+	if m.PoolPolicy.Enabled() {
+		wm.Pool = &WorkerModelPool{
+			MinIdle: m.PoolPolicy.MinIdle,
+			Max:     m.PoolPolicy.Max,
+			IdleTTL: formatTimeout(m.PoolPolicy.IdleTTL),
+		}
+	}
+
+	if m.Template != nil && len(*m.Template) > 0 {
+		wm.Template = *m.Template
+	}
+	return wm
+}
+
+// Model returns a sdk.Model. The group of the model is only given by its name
+func (wm *WorkerModel) Model() (*sdk.Model, error) {
+	if wm.Name == "" {
+		return nil, fmt.Errorf("worker model name is mandatory")
+	}
+	if wm.Group == "" {
+		return nil, fmt.Errorf("worker model %s: group is mandatory", wm.Name)
+	}
+
+	m := &sdk.Model{
+		Name:          wm.Name,
+		Type:          wm.Type,
+		Image:         wm.Image,
+		Dockerfile:    wm.Dockerfile,
+		Communication: wm.Communication,
+		Group:         sdk.Group{Name: wm.Group},
+		Capabilities:  computeJobRequirements(wm.Capabilities),
+		Provision:     wm.Provision,
+		RunScript:     wm.RunScript,
+		Disabled:      wm.Disabled,
+	}
+	if m.Type == "" {
+		m.Type = sdk.Docker
+	}
+	if m.Communication == "" {
+		m.Communication = sdk.HTTP
+	}
+
+	if m.Dockerfile != "" && m.Type != sdk.Docker {
+		return nil, fmt.Errorf("worker model %s: only docker models can be built from a Dockerfile", wm.Name)
+	}
+	if m.Dockerfile != "" && m.Image != "" {
+		return nil, fmt.Errorf("worker model %s: image and dockerfile can't be both set", wm.Name)
+	}
+	if m.Dockerfile == "" && m.Image == "" {
+		return nil, fmt.Errorf("worker model %s: image or dockerfile is mandatory", wm.Name)
+	}
+
+	if wm.Pool != nil {
+		ttl, err := parseTimeout(wm.Pool.IdleTTL)
+		if err != nil {
+			return nil, err
+		}
+		m.PoolPolicy = sdk.ModelPoolPolicy{
+			MinIdle: wm.Pool.MinIdle,
+			Max:     wm.Pool.Max,
+			IdleTTL: ttl,
+		}
+	}
+
+	if len(wm.Template) > 0 {
+		template := wm.Template
+		m.Template = &template
+	}
+	return m, nil
+}
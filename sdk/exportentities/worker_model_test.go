@@ -0,0 +1,57 @@
+package exportentities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_ImportWorkerModel(t *testing.T) {
+	in := `name: golang
+group: shared.infra
+dockerfile: |
+  FROM golang:1.10
+  RUN apt-get update && apt-get install -y zip
+capabilities:
+- binary: go
+- binary: zip
+pool:
+  min_idle: 2
+  max: 10
+  idle_ttl: 15m0s
+`
+
+	payload := &WorkerModel{}
+	test.NoError(t, yaml.Unmarshal([]byte(in), payload))
+
+	m, err := payload.Model()
+	test.NoError(t, err)
+	assert.Equal(t, sdk.Docker, m.Type)
+	assert.Equal(t, sdk.HTTP, m.Communication)
+	assert.Equal(t, "shared.infra", m.Group.Name)
+	assert.Equal(t, "", m.Image)
+	assert.Contains(t, m.Dockerfile, "FROM golang:1.10")
+	assert.Len(t, m.Capabilities, 2)
+	assert.Equal(t, int64(900), m.PoolPolicy.IdleTTL)
+
+	exported := NewWorkerModel(m)
+	assert.Equal(t, payload, exported)
+}
+
+func Test_ImportWorkerModelInvalid(t *testing.T) {
+	for _, in := range []string{
+		"name: golang\ngroup: shared.infra\n",
+		"name: golang\nimage: golang:1.10\n",
+		"name: golang\ngroup: shared.infra\nimage: golang:1.10\ndockerfile: FROM golang:1.10\n",
+		"name: golang\ngroup: shared.infra\ntype: openstack\ndockerfile: FROM golang:1.10\n",
+	} {
+		payload := &WorkerModel{}
+		test.NoError(t, yaml.Unmarshal([]byte(in), payload))
+		_, err := payload.Model()
+		assert.Error(t, err, in)
+	}
+}
@@ -0,0 +1,307 @@
+package exportentities
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Workflow is a struct to export a sdk.Workflow. Nodes are referenced by their name, pipelines, applications
+// and environments too
+type Workflow struct {
+	Name        string                  `json:"name" yaml:"name"`
+	Description string                  `json:"description,omitempty" yaml:"description,omitempty"`
+	Root        string                  `json:"root" yaml:"root"`
+	Nodes       map[string]WorkflowNode `json:"nodes" yaml:"nodes"`
+	Joins       []WorkflowJoin          `json:"joins,omitempty" yaml:"joins,omitempty"`
+	Permissions map[string]int          `json:"permissions,omitempty" yaml:"permissions,omitempty"`
+}
+
+// WorkflowNode is a struct to export a sdk.WorkflowNode
+type WorkflowNode struct {
+	Pipeline    string                   `json:"pipeline" yaml:"pipeline"`
+	Application string                   `json:"application,omitempty" yaml:"application,omitempty"`
+	Environment string                   `json:"environment,omitempty" yaml:"environment,omitempty"`
+	Parameters  map[string]VariableValue `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Payload     map[string]string        `json:"payload,omitempty" yaml:"payload,omitempty"`
+	Hooks       []WorkflowHook           `json:"hooks,omitempty" yaml:"hooks,omitempty"`
+	Triggers    []WorkflowTrigger        `json:"triggers,omitempty" yaml:"triggers,omitempty"`
+}
+
+// WorkflowHook is a struct to export a sdk.WorkflowNodeHook, referenced by the name of its model
+type WorkflowHook struct {
+	Model  string            `json:"model" yaml:"model"`
+	Config map[string]string `json:"config,omitempty" yaml:"config,omitempty"`
+}
+
+// WorkflowTrigger is a struct to export a trigger to the node Node. Conditions are written like in steps
+type WorkflowTrigger struct {
+	Node            string   `json:"node" yaml:"node"`
+	Conditions      []string `json:"conditions,omitempty" yaml:"conditions,omitempty"`
+	Manual          bool     `json:"manual,omitempty" yaml:"manual,omitempty"`
+	ContinueOnError bool     `json:"continue_on_error,omitempty" yaml:"continue_on_error,omitempty"`
+}
+
+// WorkflowJoin is a struct to export a sdk.WorkflowNodeJoin
+type WorkflowJoin struct {
+	Sources  []string          `json:"sources" yaml:"sources"`
+	Triggers []WorkflowTrigger `json:"triggers" yaml:"triggers"`
+}
+
+// NewWorkflow returns an exportable workflow from a sdk.Workflow. The project and workflow keys of hooks
+// configuration are not exported, they are set when hooks are inserted
+func NewWorkflow(w *sdk.Workflow) (*Workflow, error) {
+	e := &Workflow{
+		Name:        w.Name,
+		Description: w.Description,
+		Nodes:       map[string]WorkflowNode{},
+	}
+	if w.Root == nil {
+		return nil, sdk.ErrWorkflowInvalidRoot
+	}
+	e.Root = w.Root.Name
+
+	names := map[int64]string{}
+	if err := e.addNode(w.Root, names); err != nil {
+		return nil, err
+	}
+	for _, j := range w.Joins {
+		for i := range j.Triggers {
+			if err := e.addNode(&j.Triggers[i].WorkflowDestNode, names); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, j := range w.Joins {
+		join := WorkflowJoin{}
+		for _, id := range j.SourceNodeIDs {
+			name, ok := names[id]
+			if !ok {
+				return nil, fmt.Errorf("unknown source node %d of join %d", id, j.ID)
+			}
+			join.Sources = append(join.Sources, name)
+		}
+		sort.Strings(join.Sources)
+		for _, t := range j.Triggers {
+			join.Triggers = append(join.Triggers, newWorkflowTrigger(t.WorkflowDestNode.Name, t.Conditions, t.Manual, t.ContinueOnError))
+		}
+		e.Joins = append(e.Joins, join)
+	}
+
+	if len(w.Groups) > 0 {
+		e.Permissions = make(map[string]int, len(w.Groups))
+		for _, g := range w.Groups {
+			e.Permissions[g.Group.Name] = g.Permission
+		}
+	}
+	return e, nil
+}
+
+func newWorkflowTrigger(node string, conditions []sdk.WorkflowTriggerCondition, manual, continueOnError bool) WorkflowTrigger {
+	t := WorkflowTrigger{
+		Node:            node,
+		Manual:          manual,
+		ContinueOnError: continueOnError,
+	}
+	if len(conditions) > 0 {
+		t.Conditions = formatConditions(conditions)
+	}
+	return t
+}
+
+// addNode adds a node and the nodes it triggers
+func (e *Workflow) addNode(n *sdk.WorkflowNode, names map[int64]string) error {
+	if _, ok := e.Nodes[n.Name]; ok {
+		return fmt.Errorf("duplicate node %s", n.Name)
+	}
+	names[n.ID] = n.Name
+
+	node := WorkflowNode{Pipeline: n.Pipeline.Name}
+	if n.Context != nil {
+		if n.Context.Application != nil {
+			node.Application = n.Context.Application.Name
+		}
+		if n.Context.Environment != nil && n.Context.Environment.ID != sdk.DefaultEnv.ID {
+			node.Environment = n.Context.Environment.Name
+		}
+		if len(n.Context.DefaultPipelineParameters) > 0 {
+			node.Parameters = make(map[string]VariableValue, len(n.Context.DefaultPipelineParameters))
+			for _, p := range n.Context.DefaultPipelineParameters {
+				node.Parameters[p.Name] = VariableValue{Type: p.Type, Value: p.Value}
+			}
+		}
+		if payload, ok := n.Context.DefaultPayload.(map[string]interface{}); ok && len(payload) > 0 {
+			node.Payload = make(map[string]string, len(payload))
+			for k, v := range payload {
+				node.Payload[k] = fmt.Sprintf("%v", v)
+			}
+		}
+	}
+
+	for _, h := range n.Hooks {
+		hook := WorkflowHook{Model: h.WorkflowHookModel.Name}
+		for k, v := range h.Config {
+			if k == "project" || k == "workflow" {
+				continue
+			}
+			if hook.Config == nil {
+				hook.Config = map[string]string{}
+			}
+			hook.Config[k] = v
+		}
+		node.Hooks = append(node.Hooks, hook)
+	}
+
+	for i := range n.Triggers {
+		t := &n.Triggers[i]
+		node.Triggers = append(node.Triggers, newWorkflowTrigger(t.WorkflowDestNode.Name, t.Conditions, t.Manual, t.ContinueOnError))
+		if err := e.addNode(&t.WorkflowDestNode, names); err != nil {
+			return err
+		}
+	}
+
+	e.Nodes[n.Name] = node
+	return nil
+}
+
+// Workflow returns the sdk.Workflow of an exported workflow. Pipelines, applications, environments, hook
+// models and groups only have their name. Nodes have their name as reference, for joins
+func (e *Workflow) Workflow() (*sdk.Workflow, error) {
+	w := &sdk.Workflow{
+		Name:        e.Name,
+		Description: e.Description,
+	}
+
+	// The nodes which are destination of a join are not in the tree of the root
+	joined := map[string]bool{}
+	for _, j := range e.Joins {
+		for _, t := range j.Triggers {
+			joined[t.Node] = true
+		}
+	}
+
+	done := map[string]bool{}
+	root, err := e.node(e.Root, done, joined)
+	if err != nil {
+		return nil, err
+	}
+	w.Root = root
+
+	for _, j := range e.Joins {
+		join := sdk.WorkflowNodeJoin{SourceNodeRefs: j.Sources}
+		for _, s := range j.Sources {
+			if _, ok := e.Nodes[s]; !ok {
+				return nil, fmt.Errorf("unknown source node %s of join", s)
+			}
+		}
+		for _, t := range j.Triggers {
+			conditions, err := parseConditions(t.Conditions)
+			if err != nil {
+				return nil, err
+			}
+			dest, err := e.node(t.Node, done, nil)
+			if err != nil {
+				return nil, err
+			}
+			join.Triggers = append(join.Triggers, sdk.WorkflowNodeJoinTrigger{
+				WorkflowDestNode: *dest,
+				Conditions:       conditions,
+				Manual:           t.Manual,
+				ContinueOnError:  t.ContinueOnError,
+			})
+		}
+		w.Joins = append(w.Joins, join)
+	}
+
+	for name := range e.Nodes {
+		if !done[name] {
+			return nil, fmt.Errorf("node %s is not triggered", name)
+		}
+	}
+
+	for g, p := range e.Permissions {
+		w.Groups = append(w.Groups, sdk.GroupPermission{Group: sdk.Group{Name: g}, Permission: p})
+	}
+	return w, nil
+}
+
+func parseConditions(conditions []string) ([]sdk.WorkflowTriggerCondition, error) {
+	var res []sdk.WorkflowTriggerCondition
+	for _, c := range conditions {
+		cond, err := parseCondition(c)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, cond)
+	}
+	return res, nil
+}
+
+// node returns a node and the nodes it triggers, except the destinations of joins
+func (e *Workflow) node(name string, done, joined map[string]bool) (*sdk.WorkflowNode, error) {
+	en, ok := e.Nodes[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown node %s", name)
+	}
+	if done[name] {
+		return nil, fmt.Errorf("node %s is triggered twice", name)
+	}
+	done[name] = true
+
+	n := &sdk.WorkflowNode{
+		Name:     name,
+		Ref:      name,
+		Pipeline: sdk.Pipeline{Name: en.Pipeline},
+		Context:  &sdk.WorkflowNodeContext{},
+	}
+	if en.Application != "" {
+		n.Context.Application = &sdk.Application{Name: en.Application}
+	}
+	if en.Environment != "" {
+		n.Context.Environment = &sdk.Environment{Name: en.Environment}
+	}
+	for k, v := range en.Parameters {
+		n.Context.DefaultPipelineParameters = append(n.Context.DefaultPipelineParameters, sdk.Parameter{Name: k, Type: v.Type, Value: v.Value})
+	}
+	if len(en.Payload) > 0 {
+		payload := make(map[string]interface{}, len(en.Payload))
+		for k, v := range en.Payload {
+			payload[k] = v
+		}
+		n.Context.DefaultPayload = payload
+	}
+
+	for _, h := range en.Hooks {
+		config := sdk.WorkflowNodeHookConfig{}
+		for k, v := range h.Config {
+			config[k] = v
+		}
+		n.Hooks = append(n.Hooks, sdk.WorkflowNodeHook{
+			WorkflowHookModel: sdk.WorkflowHookModel{Name: h.Model},
+			Config:            config,
+		})
+	}
+
+	for _, t := range en.Triggers {
+		if joined[t.Node] {
+			return nil, fmt.Errorf("node %s is triggered by a node and a join", t.Node)
+		}
+		conditions, err := parseConditions(t.Conditions)
+		if err != nil {
+			return nil, err
+		}
+		dest, err := e.node(t.Node, done, joined)
+		if err != nil {
+			return nil, err
+		}
+		n.Triggers = append(n.Triggers, sdk.WorkflowNodeTrigger{
+			WorkflowDestNode: *dest,
+			Conditions:       conditions,
+			Manual:           t.Manual,
+			ContinueOnError:  t.ContinueOnError,
+		})
+	}
+	return n, nil
+}
@@ -0,0 +1,33 @@
+package sdk
+
+import "time"
+
+// Actions of a freeze window on the deployments it covers
+const (
+	FreezeActionHold   = "hold"
+	FreezeActionRefuse = "refuse"
+)
+
+// FreezeWindow is a period during which deployments are frozen, on all projects, on a project or on an environment
+// of a project. A recurring window opens at each date of its cron expression between Start and End, for Duration
+// minutes. During a window, matching workflow nodes are held until its end or refused
+type FreezeWindow struct {
+	ID              int64     `json:"id" db:"id" cli:"id,key"`
+	Name            string    `json:"name" db:"name" cli:"name"`
+	Description     string    `json:"description,omitempty" db:"description" cli:"-"`
+	ProjectID       int64     `json:"project_id,omitempty" db:"project_id" cli:"-"`
+	ProjectKey      string    `json:"project_key,omitempty" db:"-" cli:"project"`
+	EnvironmentID   int64     `json:"environment_id,omitempty" db:"environment_id" cli:"-"`
+	EnvironmentName string    `json:"environment_name,omitempty" db:"-" cli:"environment"`
+	Start           time.Time `json:"start" db:"start_date" cli:"-"`
+	End             time.Time `json:"end,omitempty" db:"end_date" cli:"-"`
+	Recurrence      string    `json:"recurrence,omitempty" db:"recurrence" cli:"recurrence"`
+	Duration        int64     `json:"duration,omitempty" db:"duration" cli:"-"`
+	Timezone        string    `json:"timezone,omitempty" db:"timezone" cli:"-"`
+	Action          string    `json:"action" db:"action" cli:"action"`
+	Author          string    `json:"author" db:"author" cli:"author"`
+	Created         time.Time `json:"created" db:"created" cli:"-"`
+	Active          bool      `json:"active" db:"-" cli:"active"`
+	NextStart       time.Time `json:"next_start,omitempty" db:"-" cli:"start"`
+	NextEnd         time.Time `json:"next_end,omitempty" db:"-" cli:"end"`
+}
@@ -12,12 +12,16 @@ import (
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/cdsclient"
 	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/tracing"
 )
 
 // CommonConfiguration is the base configuration for all hatcheries
 type CommonConfiguration struct {
 	Name string `toml:"name" default:"" comment:"Name of Hatchery"`
-	API  struct {
+	HTTP struct {
+		Port int `toml:"port" default:"0" comment:"Listen port of the Prometheus metrics endpoint /mon/metrics, 0 to disable it"`
+	} `toml:"http"`
+	API struct {
 		HTTP struct {
 			URL      string `toml:"url" default:"http://localhost:8081" commented:"true" comment:"CDS API URL"`
 			Insecure bool   `toml:"insecure" default:"false" commented:"true" comment:"sslInsecureSkipVerify, set to true if you use a self-signed SSL on CDS API"`
@@ -57,6 +61,7 @@ type CommonConfiguration struct {
 			ThresholdWarning  int `toml:"thresholdWarning" default:"360" comment:"log warning if spawn take more than this value (in seconds)"`
 		} `toml:"spawnOptions"`
 	} `toml:"logOptions" comment:"Hatchery Log Configuration"`
+	Tracing tracing.Configuration `toml:"tracing" comment:"Hatchery and workers Distributed Tracing Configuration"`
 }
 
 // Interface describe an interface for each hatchery mode (mesos, local)
@@ -105,7 +110,7 @@ func CheckRequirement(r sdk.Requirement) (bool, error) {
 	}
 }
 
-func receiveJob(h Interface, isWorkflowJob bool, execGroups []sdk.Group, jobID int64, jobQueuedSeconds int64, jobBookedBy sdk.Hatchery, requirements []sdk.Requirement, models []sdk.Model, nRoutines *int64, spawnIDs *cache.Cache, hostname string) bool {
+func receiveJob(ctx context.Context, h Interface, isWorkflowJob bool, execGroups []sdk.Group, jobID int64, jobQueuedSeconds int64, jobBookedBy sdk.Hatchery, requirements []sdk.Requirement, models []sdk.Model, nRoutines *int64, spawnIDs *cache.Cache, hostname string) bool {
 	if jobID == 0 {
 		return false
 	}
@@ -138,7 +143,7 @@ func receiveJob(h Interface, isWorkflowJob bool, execGroups []sdk.Group, jobID i
 
 	atomic.AddInt64(nRoutines, 1)
 	defer atomic.AddInt64(nRoutines, -1)
-	isSpawned, errR := routine(h, isWorkflowJob, models, execGroups, jobID, requirements, hostname, time.Now().Unix())
+	isSpawned, errR := routine(ctx, h, isWorkflowJob, models, execGroups, jobID, requirements, hostname, time.Now().Unix())
 	if errR != nil {
 		log.Warning("Error on routine: %s", errR)
 		return false
@@ -146,7 +151,7 @@ func receiveJob(h Interface, isWorkflowJob bool, execGroups []sdk.Group, jobID i
 	return isSpawned
 }
 
-func routine(h Interface, isWorkflowJob bool, models []sdk.Model, execGroups []sdk.Group, jobID int64, requirements []sdk.Requirement, hostname string, timestamp int64) (bool, error) {
+func routine(ctx context.Context, h Interface, isWorkflowJob bool, models []sdk.Model, execGroups []sdk.Group, jobID int64, requirements []sdk.Requirement, hostname string, timestamp int64) (bool, error) {
 	defer logTime(h, fmt.Sprintf("routine> %d", timestamp), time.Now())
 	log.Debug("routine> %d enter", timestamp)
 
@@ -162,6 +167,13 @@ func routine(h Interface, isWorkflowJob bool, models []sdk.Model, execGroups []s
 
 	for _, model := range models {
 		if canRunJob(h, timestamp, execGroups, jobID, requirements, &model, hostname) {
+			pinned, errP := pinnedModel(h, &model, requirements)
+			if errP != nil {
+				log.Warning("routine> %d - cannot load pinned version of model %s for job %d: %s", timestamp, model.Name, jobID, errP)
+				continue
+			}
+			model = *pinned
+
 			if err := h.Client().QueueJobBook(isWorkflowJob, jobID); err != nil {
 				// perhaps already booked by another hatchery
 				log.Debug("routine> %d - cannot book job %d %s: %s", timestamp, jobID, model.Name, err)
@@ -176,7 +188,14 @@ func routine(h Interface, isWorkflowJob bool, models []sdk.Model, execGroups []s
 					Message:    sdk.SpawnMsg{ID: sdk.MsgSpawnInfoHatcheryStarts.ID, Args: []interface{}{fmt.Sprintf("%s", h.Hatchery().Name), fmt.Sprintf("%d", h.Hatchery().ID), model.Name}},
 				},
 			}
+			_, span := tracing.Start(ctx, "hatchery.spawn")
+			span.SetTag("cds.job.id", jobID)
+			span.SetTag("cds.model", model.Name)
+			span.SetTag("cds.hatchery", h.Hatchery().Name)
 			workerName, errSpawn := h.SpawnWorker(&model, jobID, requirements, false, "spawn for job")
+			observeSpawn(model.Name, "job", start, errSpawn)
+			span.SetError(errSpawn)
+			span.Finish()
 			if errSpawn != nil {
 				log.Warning("routine> %d - cannot spawn worker %s for job %d: %s", timestamp, model.Name, jobID, errSpawn)
 				infos = append(infos, sdk.SpawnInfo{
@@ -213,18 +232,23 @@ func routine(h Interface, isWorkflowJob bool, models []sdk.Model, execGroups []s
 	return false, nil
 }
 
-func provisioning(h Interface, provisionDisabled bool, models []sdk.Model) {
+func provisioning(h Interface, provisionDisabled bool, models []sdk.Model, pool *workerPool, hostname string) {
 	if provisionDisabled {
 		log.Debug("provisioning> disabled on this hatchery")
 		return
 	}
 
+	provisionPools(h, models, pool, hostname)
+
 	for k := range models {
-		if models[k].Type == h.ModelType() {
+		if models[k].Type == h.ModelType() && !models[k].PoolPolicy.Enabled() {
 			existing := h.WorkersStartedByModel(&models[k])
 			for i := existing; i < int(models[k].Provision); i++ {
 				go func(m sdk.Model) {
-					if name, errSpawn := h.SpawnWorker(&m, 0, nil, false, "spawn for provision"); errSpawn != nil {
+					start := time.Now()
+					name, errSpawn := h.SpawnWorker(&m, 0, nil, false, "spawn for provision")
+					observeSpawn(m.Name, "provision", start, errSpawn)
+					if errSpawn != nil {
 						log.Warning("provisioning> cannot spawn worker %s with model %s for provisioning: %s", name, m.Name, errSpawn)
 						if err := h.Client().WorkerModelSpawnError(m.ID, fmt.Sprintf("routine> cannot spawn worker %s for provisioning: %s", m.Name, errSpawn)); err != nil {
 							log.Error("provisioning> cannot client.WorkerModelSpawnError for worker %s with model %s for provisioning: %s", name, m.Name, errSpawn)
@@ -236,6 +260,74 @@ func provisioning(h Interface, provisionDisabled bool, models []sdk.Model) {
 	}
 }
 
+// provisionPools scales the warm pools of the models having a pool policy, according to the jobs they can run in queue
+func provisionPools(h Interface, models []sdk.Model, pool *workerPool, hostname string) {
+	var poolModels []sdk.Model
+	for _, m := range models {
+		if m.Type == h.ModelType() && m.PoolPolicy.Enabled() {
+			poolModels = append(poolModels, m)
+		}
+	}
+	if len(poolModels) == 0 {
+		return
+	}
+
+	workers, errW := h.Client().WorkerList()
+	if errW != nil {
+		log.Warning("provisionPools> cannot load workers: %s", errW)
+		return
+	}
+	pool.forget(workers)
+
+	wjobs, pbjobs, errQ := h.Client().Queue()
+	if errQ != nil {
+		log.Warning("provisionPools> cannot load queue: %s", errQ)
+		return
+	}
+
+	timestamp := time.Now().Unix()
+	for i := range poolModels {
+		m := &poolModels[i]
+		var queued int
+		for _, j := range wjobs {
+			if j.BookedBy.ID == 0 && canRunJob(h, timestamp, nil, j.ID, j.Job.Action.Requirements, m, hostname) {
+				queued++
+			}
+		}
+		for _, j := range pbjobs {
+			if j.Status == sdk.StatusWaiting.String() && j.BookedBy.ID == 0 && canRunJob(h, timestamp, j.ExecGroups, j.ID, j.Job.Action.Requirements, m, hostname) {
+				queued++
+			}
+		}
+		pool.scale(h, *m, workers, queued, hostname)
+	}
+}
+
+// pinnedModel returns the version of the model pinned by the model requirement of a job, the model itself
+// if the job does not pin a version
+func pinnedModel(h Interface, model *sdk.Model, requirements []sdk.Requirement) (*sdk.Model, error) {
+	for _, r := range requirements {
+		if r.Type != sdk.ModelRequirement {
+			continue
+		}
+		_, version := sdk.ParseModelRequirement(r.Value)
+		if version == 0 || version == model.Version {
+			return model, nil
+		}
+		v, err := h.Client().WorkerModelVersion(model.ID, version)
+		if err != nil {
+			return nil, err
+		}
+		pinned := v.Model
+		pinned.ID = model.ID
+		pinned.Name = model.Name
+		pinned.Version = version
+		pinned.NbSpawnErr = model.NbSpawnErr
+		return &pinned, nil
+	}
+	return model, nil
+}
+
 func canRunJob(h Interface, timestamp int64, execGroups []sdk.Group, jobID int64, requirements []sdk.Requirement, model *sdk.Model, hostname string) bool {
 	if model.Type != h.ModelType() {
 		return false
@@ -273,9 +365,11 @@ func canRunJob(h Interface, timestamp int64, execGroups []sdk.Group, jobID int64
 	// Common check
 	for _, r := range requirements {
 		// If requirement is a Model requirement, it's easy. It's either can or can't run
-		if r.Type == sdk.ModelRequirement && r.Value != model.Name {
-			log.Debug("canRunJob> %d - job %d - model requirement r.Value(%s) != model.Name(%s)", timestamp, jobID, r.Value, model.Name)
-			return false
+		if r.Type == sdk.ModelRequirement {
+			if name, _ := sdk.ParseModelRequirement(r.Value); name != model.Name {
+				log.Debug("canRunJob> %d - job %d - model requirement r.Value(%s) != model.Name(%s)", timestamp, jobID, r.Value, model.Name)
+				return false
+			}
 		}
 
 		// If requirement is an hostname requirement, it's for a specific worker
@@ -297,9 +391,11 @@ func canRunJob(h Interface, timestamp int64, execGroups []sdk.Group, jobID int64
 		}
 
 		// here, if there a model requirements, we don't need to check binaries
-		if r.Type == sdk.ModelRequirement && r.Value != model.Name {
-			log.Debug("canRunJob> %d - job %d - model requirement r.Value(%s) != model.Name(%s)", timestamp, jobID, r.Value, model.Name)
-			return false
+		if r.Type == sdk.ModelRequirement {
+			if name, _ := sdk.ParseModelRequirement(r.Value); name != model.Name {
+				log.Debug("canRunJob> %d - job %d - model requirement r.Value(%s) != model.Name(%s)", timestamp, jobID, r.Value, model.Name)
+				return false
+			}
 		}
 
 		if !containsModelRequirement && !containsHostnameRequirement {
@@ -0,0 +1,64 @@
+package hatchery
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/ovh/cds/sdk/log"
+)
+
+var (
+	registry = prometheus.NewRegistry()
+
+	jobsReceived        = prometheus.NewCounter(prometheus.CounterOpts{Name: "hatchery_jobs_received_total", Help: "metrics hatchery_jobs_received_total: jobs received from the queue"})
+	spawnDuration       = prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "hatchery_spawn_duration_seconds", Help: "metrics hatchery_spawn_duration_seconds: duration of the successful spawns of workers, by model and reason", Buckets: prometheus.ExponentialBuckets(1, 2, 10)}, []string{"model", "reason"})
+	spawnErrors         = prometheus.NewCounterVec(prometheus.CounterOpts{Name: "hatchery_spawn_errors_total", Help: "metrics hatchery_spawn_errors_total: failed spawns of workers, by model and reason"}, []string{"model", "reason"})
+	workersStartedGauge = prometheus.NewGauge(prometheus.GaugeOpts{Name: "hatchery_workers_started", Help: "metrics hatchery_workers_started: workers started by the hatchery"})
+)
+
+func init() {
+	registry.MustRegister(jobsReceived, spawnDuration, spawnErrors, workersStartedGauge)
+}
+
+// observeSpawn records the duration or the error of the spawn of a worker
+func observeSpawn(model, reason string, start time.Time, err error) {
+	if err != nil {
+		spawnErrors.WithLabelValues(model, reason).Inc()
+		return
+	}
+	spawnDuration.WithLabelValues(model, reason).Observe(time.Since(start).Seconds())
+}
+
+// serveMetrics exposes the metrics of the hatchery on /mon/metrics
+func serveMetrics(port int) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/mon/metrics", func(w http.ResponseWriter, r *http.Request) {
+		mfs, err := registry.Gather()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		contentType := expfmt.Negotiate(r.Header)
+		writer := &bytes.Buffer{}
+		enc := expfmt.NewEncoder(writer, contentType)
+		for _, mf := range mfs {
+			if err := enc.Encode(mf); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+		w.Header().Set("Content-Type", string(contentType))
+		w.Header().Set("Content-Length", fmt.Sprint(writer.Len()))
+		w.Write(writer.Bytes())
+	})
+
+	log.Info("Hatchery metrics listening on :%d", port)
+	if err := http.ListenAndServe(fmt.Sprintf(":%d", port), mux); err != nil {
+		log.Error("Hatchery metrics server stopped: %v", err)
+	}
+}
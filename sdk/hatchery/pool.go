@@ -0,0 +1,165 @@
+package hatchery
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// workerPool keeps the time since workers are idle. The spawn durations of models are computed by CDS API from
+// the spawn infos of all hatcheries
+type workerPool struct {
+	mutex     sync.Mutex
+	idleSince map[string]time.Time
+}
+
+func newWorkerPool() *workerPool {
+	return &workerPool{
+		idleSince: map[string]time.Time{},
+	}
+}
+
+// poolStatus is the state of the workers of a model started by the hatchery
+type poolStatus struct {
+	Idle          int
+	Building      int
+	Starting      int
+	Queued        int
+	SpawnDuration time.Duration
+}
+
+// poolScale computes the number of workers to spawn and the maximum number of idle workers
+// which can be released. Queued jobs are anticipated only when spawning a worker takes longer
+// than the time a job may wait in queue, otherwise workers spawned on demand are fast enough
+func poolScale(policy sdk.ModelPoolPolicy, s poolStatus, available int, graceTime time.Duration) (int, int) {
+	started := s.Idle + s.Building + s.Starting
+
+	want := policy.MinIdle
+	if s.SpawnDuration > graceTime {
+		want += s.Queued
+	}
+
+	spawn := want - s.Idle - s.Starting
+	if policy.Max > 0 && started+spawn > policy.Max {
+		spawn = policy.Max - started
+	}
+	if spawn > available {
+		spawn = available
+	}
+	if spawn > 0 {
+		return spawn, 0
+	}
+
+	var release int
+	if s.Queued == 0 {
+		release = s.Idle - policy.MinIdle
+	}
+	if policy.Max > 0 && started-policy.Max > release {
+		release = started - policy.Max
+	}
+	if release > s.Idle {
+		release = s.Idle
+	}
+	if release < 0 {
+		release = 0
+	}
+	return 0, release
+}
+
+// scale spawns workers and releases idle workers of a model according to its pool policy. Released workers
+// are disabled, then killed by the hatchery like any disabled worker
+func (p *workerPool) scale(h Interface, model sdk.Model, workers []sdk.Worker, queued int, hostname string) {
+	now := time.Now()
+	s := poolStatus{Queued: queued, SpawnDuration: model.SpawnDuration}
+	idle := []sdk.Worker{}
+
+	p.mutex.Lock()
+	for _, w := range workers {
+		if w.ModelID != model.ID || w.HatcheryID != h.Hatchery().ID {
+			continue
+		}
+		switch w.Status {
+		case sdk.StatusWaiting:
+			s.Idle++
+			if _, ok := p.idleSince[w.ID]; !ok {
+				p.idleSince[w.ID] = now
+			}
+			idle = append(idle, w)
+		case sdk.StatusBuilding, sdk.StatusChecking:
+			s.Building++
+			delete(p.idleSince, w.ID)
+		}
+	}
+	// oldest idle workers first
+	sort.Slice(idle, func(i, j int) bool { return p.idleSince[idle[i].ID].Before(p.idleSince[idle[j].ID]) })
+	p.mutex.Unlock()
+
+	if n := h.WorkersStartedByModel(&model) - s.Idle - s.Building; n > 0 {
+		s.Starting = n
+	}
+
+	available := h.Configuration().Provision.MaxWorker - h.WorkersStarted()
+	graceTime := time.Duration(h.Configuration().Provision.GraceTimeQueued) * time.Second
+	spawn, release := poolScale(model.PoolPolicy, s, available, graceTime)
+
+	log.Info("pool> model %s: %d idle, %d building, %d starting, %d queued, spawn duration %s, min idle %d, max %d -> spawn %d, release %d",
+		model.Name, s.Idle, s.Building, s.Starting, s.Queued, s.SpawnDuration, model.PoolPolicy.MinIdle, model.PoolPolicy.Max, spawn, release)
+
+	for i := 0; i < spawn; i++ {
+		go func(m sdk.Model) {
+			start := time.Now()
+			name, errSpawn := h.SpawnWorker(&m, 0, nil, false, "spawn for pool")
+			observeSpawn(m.Name, "pool", start, errSpawn)
+			if errSpawn != nil {
+				log.Warning("pool> cannot spawn worker %s with model %s: %s", name, m.Name, errSpawn)
+				if err := h.Client().WorkerModelSpawnError(m.ID, fmt.Sprintf("pool> cannot spawn worker %s: %s", m.Name, errSpawn)); err != nil {
+					log.Error("pool> cannot client.WorkerModelSpawnError for worker %s with model %s: %s", name, m.Name, errSpawn)
+				}
+			}
+		}(model)
+	}
+
+	if model.PoolPolicy.IdleTTL == 0 {
+		return
+	}
+	ttl := time.Duration(model.PoolPolicy.IdleTTL) * time.Second
+	for _, w := range idle {
+		if release == 0 {
+			break
+		}
+		p.mutex.Lock()
+		since := p.idleSince[w.ID]
+		p.mutex.Unlock()
+		if now.Sub(since) < ttl {
+			continue
+		}
+		if err := h.Client().WorkerDisable(w.ID); err != nil {
+			log.Warning("pool> cannot disable idle worker %s: %s", w.Name, err)
+			continue
+		}
+		log.Info("pool> worker %s of model %s idle since %s released", w.Name, model.Name, since)
+		p.mutex.Lock()
+		delete(p.idleSince, w.ID)
+		p.mutex.Unlock()
+		release--
+	}
+}
+
+// forget removes the workers which do not exist anymore
+func (p *workerPool) forget(workers []sdk.Worker) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	exist := make(map[string]bool, len(workers))
+	for _, w := range workers {
+		exist[w.ID] = true
+	}
+	for id := range p.idleSince {
+		if !exist[id] {
+			delete(p.idleSince, id)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package hatchery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestPoolScale(t *testing.T) {
+	policy := sdk.ModelPoolPolicy{MinIdle: 2, Max: 5, IdleTTL: 60}
+	grace := 4 * time.Second
+
+	// Fill the pool up to min idle
+	spawn, release := poolScale(policy, poolStatus{}, 10, grace)
+	assert.Equal(t, 2, spawn)
+	assert.Equal(t, 0, release)
+
+	// Workers starting count in the pool
+	spawn, release = poolScale(policy, poolStatus{Idle: 1, Starting: 1}, 10, grace)
+	assert.Equal(t, 0, spawn)
+	assert.Equal(t, 0, release)
+
+	// Fast spawns: queued jobs are not anticipated
+	spawn, _ = poolScale(policy, poolStatus{Idle: 2, Queued: 3, SpawnDuration: time.Second}, 10, grace)
+	assert.Equal(t, 0, spawn)
+
+	// Slow spawns: queued jobs are anticipated up to max
+	spawn, _ = poolScale(policy, poolStatus{Idle: 2, Building: 1, Queued: 4, SpawnDuration: time.Minute}, 10, grace)
+	assert.Equal(t, 2, spawn)
+
+	// Hatchery capacity
+	spawn, _ = poolScale(policy, poolStatus{}, 1, grace)
+	assert.Equal(t, 1, spawn)
+
+	// Idle workers beyond min idle are released when nothing is queued
+	spawn, release = poolScale(policy, poolStatus{Idle: 4, Building: 1}, 10, grace)
+	assert.Equal(t, 0, spawn)
+	assert.Equal(t, 2, release)
+
+	spawn, release = poolScale(policy, poolStatus{Idle: 4, Building: 1, Queued: 1}, 10, grace)
+	assert.Equal(t, 0, spawn)
+	assert.Equal(t, 0, release)
+}
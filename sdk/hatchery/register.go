@@ -14,6 +14,7 @@ import (
 
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/tracing"
 )
 
 // Create creates hatchery
@@ -39,6 +40,11 @@ func Create(h Interface) {
 		}
 	}()
 
+	if err := tracing.Init(h.Configuration().Tracing, "cds-hatchery"); err != nil {
+		log.Error("Create> Cannot initialize tracing: %s", err)
+		os.Exit(10)
+	}
+
 	if err := h.Init(); err != nil {
 		log.Error("Create> Init error: %s", err)
 		os.Exit(10)
@@ -52,6 +58,10 @@ func Create(h Interface) {
 
 	go hearbeat(h, h.Configuration().API.Token, h.Configuration().API.MaxHeartbeatFailures)
 
+	if port := h.Configuration().HTTP.Port; port > 0 {
+		go serveMetrics(port)
+	}
+
 	pbjobs := make(chan sdk.PipelineBuildJob, 1)
 	wjobs := make(chan sdk.WorkflowNodeJobRun, 1)
 	errs := make(chan error, 1)
@@ -66,6 +76,7 @@ func Create(h Interface) {
 	// Create a cache with a default expiration time of 3 second, and which
 	// purges expired items every minute
 	spawnIDs := cache.New(3*time.Second, 60*time.Second)
+	pool := newWorkerPool()
 
 	tickerProvision := time.NewTicker(time.Duration(h.Configuration().Provision.Frequency) * time.Second)
 	tickerRegister := time.NewTicker(time.Duration(h.Configuration().Provision.RegisterFrequency) * time.Second)
@@ -90,12 +101,14 @@ func Create(h Interface) {
 				log.Info("Exiting Hatchery")
 			}
 			tickerRegister.Stop()
+			tracing.Shutdown()
 			return
 		case <-tickerCountWorkersStarted.C:
 			workersStarted = int64(h.WorkersStarted())
 			if workersStarted > int64(h.Configuration().Provision.MaxWorker) {
 				log.Info("max workers reached. current:%d max:%d", workersStarted, int64(h.Configuration().Provision.MaxWorker))
 			}
+			workersStartedGauge.Set(float64(workersStarted))
 			log.Debug("workers already started:%d", workersStarted)
 		case <-tickerGetModels.C:
 			var errwm error
@@ -104,19 +117,21 @@ func Create(h Interface) {
 				log.Error("error on h.Client().WorkerModelsEnabled(): %v", errwm)
 			}
 		case j := <-pbjobs:
+			jobsReceived.Inc()
 			if workersStarted > int64(h.Configuration().Provision.MaxWorker) {
 				log.Debug("maxWorkersReached:%d", workersStarted)
 				continue
 			}
 			go func(job sdk.PipelineBuildJob) {
 				atomic.AddInt64(&workersStarted, 1)
-				if isRun := receiveJob(h, false, job.ExecGroups, job.ID, job.QueuedSeconds, job.BookedBy, job.Job.Action.Requirements, models, &nRoutines, spawnIDs, hostname); isRun {
+				if isRun := receiveJob(ctx, h, false, job.ExecGroups, job.ID, job.QueuedSeconds, job.BookedBy, job.Job.Action.Requirements, models, &nRoutines, spawnIDs, hostname); isRun {
 					spawnIDs.SetDefault(string(job.ID), job.ID)
 				} else {
 					atomic.AddInt64(&workersStarted, -1)
 				}
 			}(j)
 		case j := <-wjobs:
+			jobsReceived.Inc()
 			if workersStarted > int64(h.Configuration().Provision.MaxWorker) {
 				log.Debug("maxWorkersReached:%d", workersStarted)
 				continue
@@ -125,7 +140,7 @@ func Create(h Interface) {
 				// count + 1 here, and remove -1 if worker is not started
 				// this avoid to spawn to many workers compare
 				atomic.AddInt64(&workersStarted, 1)
-				if isRun := receiveJob(h, true, nil, job.ID, job.QueuedSeconds, job.BookedBy, job.Job.Action.Requirements, models, &nRoutines, spawnIDs, hostname); isRun {
+				if isRun := receiveJob(jobContext(ctx, job.Parameters), h, true, nil, job.ID, job.QueuedSeconds, job.BookedBy, job.Job.Action.Requirements, models, &nRoutines, spawnIDs, hostname); isRun {
 					atomic.AddInt64(&workersStarted, 1)
 					spawnIDs.SetDefault(string(job.ID), job.ID)
 				} else {
@@ -135,7 +150,7 @@ func Create(h Interface) {
 		case err := <-errs:
 			log.Error("%v", err)
 		case <-tickerProvision.C:
-			provisioning(h, h.Configuration().Provision.Disabled, models)
+			provisioning(h, h.Configuration().Provision.Disabled, models, pool, hostname)
 		case <-tickerRegister.C:
 			if err := workerRegister(h, models); err != nil {
 				log.Warning("Error on workerRegister: %s", err)
@@ -144,6 +159,12 @@ func Create(h Interface) {
 	}
 }
 
+// jobContext returns a context carrying the span given by the API in the parameters of a job
+func jobContext(ctx context.Context, params []sdk.Parameter) context.Context {
+	c, _ := tracing.ParseTraceParent(sdk.ParameterValue(params, "cds.traceparent"))
+	return tracing.ContextWithRemote(ctx, c)
+}
+
 // Register calls CDS API to register current hatchery
 func Register(h Interface) error {
 	newHatchery, uptodate, err := h.Client().HatcheryRegister(*h.Hatchery())
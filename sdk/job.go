@@ -1,5 +1,13 @@
 package sdk
 
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
 // Job is the element of a stage
 type Job struct {
 	PipelineActionID int64                  `json:"pipeline_action_id"`
@@ -9,3 +17,104 @@ type Job struct {
 	Action           Action                 `json:"action"`
 	Warnings         []PipelineBuildWarning `json:"warnings"`
 }
+
+// JobServiceParameterPrefix prefixes the parameters giving the hostnames of the services of a job
+const JobServiceParameterPrefix = "cds.service."
+
+const (
+	// JobServiceHealthInterval is the time between two runs of the health check of a service, in its container
+	JobServiceHealthInterval = 2 * time.Second
+	// JobServiceHealthTimeout is the maximum duration of a run of the health check of a service
+	JobServiceHealthTimeout = 30 * time.Second
+	// JobServiceReadyTimeout is the maximum time waited for the services of a job to be healthy
+	JobServiceReadyTimeout = 10 * time.Minute
+)
+
+// JobService is a container started next to the worker of a job, like a database used by tests.
+// It is stored as a requirement of type ServiceRequirement, the name of the requirement is the
+// hostname of the service. The health check is a shell command run in the container of the service.
+type JobService struct {
+	Name        string            `json:"-"`
+	Image       string            `json:"image"`
+	Env         map[string]string `json:"env,omitempty"`
+	Ports       []string          `json:"ports,omitempty"`
+	HealthCheck string            `json:"healthcheck,omitempty"`
+}
+
+// NewJobService reads a service from a requirement. The value of the requirement is either a json
+// document, or the legacy format "image ENV_1=value ENV_2=value"
+func NewJobService(r Requirement) (JobService, error) {
+	s := JobService{Name: r.Name}
+	value := strings.TrimSpace(r.Value)
+	if strings.HasPrefix(value, "{") {
+		if err := json.Unmarshal([]byte(value), &s); err != nil {
+			return s, fmt.Errorf("invalid service %s: %v", r.Name, err)
+		}
+	} else {
+		tuple := strings.Fields(value)
+		if len(tuple) == 0 {
+			return s, fmt.Errorf("invalid service %s: image is mandatory", r.Name)
+		}
+		s.Image = tuple[0]
+		for _, e := range tuple[1:] {
+			if s.Env == nil {
+				s.Env = map[string]string{}
+			}
+			kv := strings.SplitN(e, "=", 2)
+			if len(kv) == 2 {
+				s.Env[kv[0]] = kv[1]
+			} else {
+				s.Env[kv[0]] = ""
+			}
+		}
+	}
+	if s.Image == "" {
+		return s, fmt.Errorf("invalid service %s: image is mandatory", r.Name)
+	}
+	return s, nil
+}
+
+// Requirement returns the service as a requirement. The legacy format is kept for services
+// which can be written with it
+func (s JobService) Requirement() Requirement {
+	r := Requirement{Name: s.Name, Type: ServiceRequirement}
+	legacy := len(s.Ports) == 0 && s.HealthCheck == ""
+	for k, v := range s.Env {
+		if strings.ContainsAny(k+v, " \t\n") || v == "" {
+			legacy = false
+		}
+	}
+	if !legacy {
+		btes, _ := json.Marshal(s)
+		r.Value = string(btes)
+		return r
+	}
+	r.Value = strings.Join(append([]string{s.Image}, s.EnvList()...), " ")
+	return r
+}
+
+// EnvList returns the environment variables of the service as a sorted list of KEY=value
+func (s JobService) EnvList() []string {
+	env := make([]string, 0, len(s.Env))
+	for k, v := range s.Env {
+		env = append(env, k+"="+v)
+	}
+	sort.Strings(env)
+	return env
+}
+
+// JobServices returns the services declared in requirements
+func JobServices(requirements []Requirement) ([]JobService, error) {
+	var services []JobService
+	for _, r := range requirements {
+		if r.Type != ServiceRequirement {
+			continue
+		}
+		s, err := NewJobService(r)
+		if err != nil {
+			return nil, err
+		}
+		services = append(services, s)
+	}
+	return services, nil
+}
@@ -0,0 +1,22 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewJobService(t *testing.T) {
+	s, err := NewJobService(Requirement{Name: "mysql", Type: ServiceRequirement, Value: "mysql:5.7 MYSQL_ROOT_PASSWORD=secret"})
+	assert.NoError(t, err)
+	assert.Equal(t, JobService{Name: "mysql", Image: "mysql:5.7", Env: map[string]string{"MYSQL_ROOT_PASSWORD": "secret"}}, s)
+
+	s, err = NewJobService(Requirement{Name: "pg", Type: ServiceRequirement, Value: `{"image": "postgres:9.6", "ports": ["5432"]}`})
+	assert.NoError(t, err)
+	assert.Equal(t, JobService{Name: "pg", Image: "postgres:9.6", Ports: []string{"5432"}}, s)
+
+	for _, v := range []string{"", "   ", "\t\n", `{"ports": ["5432"]}`, "{invalid"} {
+		_, err := NewJobService(Requirement{Name: "bad", Type: ServiceRequirement, Value: v})
+		assert.Error(t, err, "value %q", v)
+	}
+}
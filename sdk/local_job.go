@@ -0,0 +1,34 @@
+package sdk
+
+import (
+	"time"
+
+	"github.com/ovh/venom"
+)
+
+// LocalJob is a job run by the command "worker exec" in the current directory of a user, without run on the API
+type LocalJob struct {
+	Name               string      `json:"name"`
+	Action             Action      `json:"action"`
+	Parameters         []Parameter `json:"parameters"`
+	ArtifactsDirectory string      `json:"artifacts_directory"`
+}
+
+// LocalJobReport is the report of a LocalJob
+type LocalJobReport struct {
+	Name      string            `json:"name"`
+	Status    string            `json:"status"`
+	Reason    string            `json:"reason,omitempty"`
+	Duration  time.Duration     `json:"duration"`
+	Steps     []LocalStepReport `json:"steps"`
+	Tests     *venom.Tests      `json:"tests,omitempty"`
+	Artifacts []string          `json:"artifacts,omitempty"`
+	Variables []Variable        `json:"variables,omitempty"`
+}
+
+// LocalStepReport is the report of a step of a LocalJob
+type LocalStepReport struct {
+	Name     string        `json:"name"`
+	Status   string        `json:"status"`
+	Duration time.Duration `json:"duration"`
+}
@@ -63,9 +63,16 @@ var (
 	MsgSpawnInfoWorkerForJob               = &Message{"MsgSpawnInfoWorkerForJob", trad{FR: "Ce worker %s a été créé pour lancer ce job", EN: "This worker %s was created to take this action"}, nil}
 	MsgSpawnInfoWorkerForJobError          = &Message{"MsgSpawnInfoWorkerForJobError", trad{FR: "Ce worker %s a été créé pour lancer ce job, mais ne possède pas tous les pré-requis. Vérifiez que les prérequis suivants:%s", EN: "This worker %s was created to take this action, but does not have all prerequisites. Please verify the following prerequisites:%s"}, nil}
 	MsgSpawnInfoJobError                   = &Message{"MsgSpawnInfoJobError", trad{FR: "Impossible de lancer ce job : %s", EN: "Unable to run this job: %s"}, nil}
+	MsgSpawnInfoJobTimeout                 = &Message{"MsgSpawnInfoJobTimeout", trad{FR: "Le job a été arrêté car il a dépassé son timeout de %s", EN: "Job has been stopped because it exceeded its timeout of %s"}, nil}
+	MsgSpawnInfoWorkerLost                 = &Message{"MsgSpawnInfoWorkerLost", trad{FR: "Le job a été arrêté car le worker %s ne répond plus", EN: "Job has been stopped because worker %s stopped heartbeating"}, nil}
 	MsgWorkflowStarting                    = &Message{"MsgWorkflowStarting", trad{FR: "Le workflow %s#%s a été démarré", EN: "Workflow %s#%s has been started"}, nil}
 	MsgWorkflowError                       = &Message{"MsgWorkflowError", trad{FR: "Une erreur est survenue: %v", EN: "An error has occured: %v"}, nil}
 	MsgWorkflowNodeStop                    = &Message{"MsgWorkflowNodeStop", trad{FR: "Le pipeline a été arrété par %s", EN: "The pipeline has been stopped by %s"}, nil}
+	MsgWorkflowRollback                    = &Message{"MsgWorkflowRollback", trad{FR: "Le noeud %s a été relancé par %s pour revenir sur l'environnement %s à cette version depuis le workflow #%s", EN: "Node %s has been run again by %s to rollback environment %s to this version from workflow #%s"}, nil}
+	MsgWorkflowRolledBack                  = &Message{"MsgWorkflowRolledBack", trad{FR: "Le déploiement du noeud %s sur l'environnement %s a été annulé par %s au profit du workflow #%s", EN: "Deployment of node %s on environment %s has been rolled back by %s to workflow #%s"}, nil}
+	MsgWorkflowNodeFreezeHold              = &Message{"MsgWorkflowNodeFreezeHold", trad{FR: "Le noeud %s est retenu jusqu'au %s par la période de gel %s", EN: "Node %s is held until %s by freeze window %s"}, nil}
+	MsgWorkflowNodeFreezeRefused           = &Message{"MsgWorkflowNodeFreezeRefused", trad{FR: "Le noeud %s n'a pas été lancé à cause de la période de gel %s", EN: "Node %s has not been run because of freeze window %s"}, nil}
+	MsgWorkflowNodeFreezeIgnored           = &Message{"MsgWorkflowNodeFreezeIgnored", trad{FR: "Le noeud %s a été lancé par %s malgré la période de gel %s", EN: "Node %s has been run by %s despite freeze window %s"}, nil}
 )
 
 // Messages contains all sdk Messages
@@ -114,9 +121,16 @@ var Messages = map[string]*Message{
 	MsgSpawnInfoWorkerForJob.ID:               MsgSpawnInfoWorkerForJob,
 	MsgSpawnInfoWorkerForJobError.ID:          MsgSpawnInfoWorkerForJobError,
 	MsgSpawnInfoJobError.ID:                   MsgSpawnInfoJobError,
+	MsgSpawnInfoJobTimeout.ID:                 MsgSpawnInfoJobTimeout,
+	MsgSpawnInfoWorkerLost.ID:                 MsgSpawnInfoWorkerLost,
 	MsgWorkflowStarting.ID:                    MsgWorkflowStarting,
 	MsgWorkflowError.ID:                       MsgWorkflowError,
 	MsgWorkflowNodeStop.ID:                    MsgWorkflowNodeStop,
+	MsgWorkflowRollback.ID:                    MsgWorkflowRollback,
+	MsgWorkflowRolledBack.ID:                  MsgWorkflowRolledBack,
+	MsgWorkflowNodeFreezeHold.ID:              MsgWorkflowNodeFreezeHold,
+	MsgWorkflowNodeFreezeRefused.ID:           MsgWorkflowNodeFreezeRefused,
+	MsgWorkflowNodeFreezeIgnored.ID:           MsgWorkflowNodeFreezeIgnored,
 }
 
 //Message represent a struc format translated messages
@@ -147,7 +147,7 @@ func ParametersFromPipelineParameters(pipParams []Parameter) map[string]string {
 func variablesToParameters(prefix string, variables []Variable) []Parameter {
 	res := []Parameter{}
 	for _, t := range variables {
-		if NeedPlaceholder(t.Type) {
+		if NeedPlaceholder(t.Type) || t.Type == SecretReferenceVariable {
 			continue
 		}
 		t.Name = prefix + "." + t.Name
@@ -40,6 +40,18 @@ type ProjectVariableAudit struct {
 	Author         string    `json:"author" yaml:"-" db:"author"`
 }
 
+// ProjectExportOptions is the body of a project export: the passphrase which encrypts the secrets
+type ProjectExportOptions struct {
+	Passphrase string `json:"passphrase"`
+}
+
+// ProjectImportReport lists what a project import created, and the existing entities which differ from
+// the archive and are left untouched
+type ProjectImportReport struct {
+	Created   []string `json:"created"`
+	Conflicts []string `json:"conflicts"`
+}
+
 // Metadata represents metadata
 type Metadata map[string]string
 
@@ -0,0 +1,28 @@
+package sdk
+
+// GroupQueueSettings are the fair-share settings of a group on the job queue. Weight is the share of the
+// group among groups with waiting jobs of the same priority, MaxBuilding is the maximum number of jobs of
+// the group building at the same time, 0 for no limit
+type GroupQueueSettings struct {
+	GroupID     int64 `json:"group_id" db:"group_id" cli:"-"`
+	Weight      int   `json:"weight" db:"weight" cli:"weight"`
+	MaxBuilding int   `json:"max_building" db:"max_building" cli:"max_building"`
+}
+
+// GroupQueueUsage is the usage of the job queue by a group versus its settings
+type GroupQueueUsage struct {
+	GroupQueueSettings
+	GroupName string `json:"group_name" cli:"group,key"`
+	Building  int    `json:"building" cli:"building"`
+	Waiting   int    `json:"waiting" cli:"waiting"`
+}
+
+// DefaultGroupQueueSettings returns the settings of groups without settings
+func DefaultGroupQueueSettings(groupID int64) GroupQueueSettings {
+	return GroupQueueSettings{GroupID: groupID, Weight: 1}
+}
+
+// QuotaReached returns true if the group cannot build more jobs
+func (u GroupQueueUsage) QuotaReached() bool {
+	return u.MaxBuilding > 0 && u.Building >= u.MaxBuilding
+}
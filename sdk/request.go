@@ -35,6 +35,8 @@ var (
 	RequestedWithValue = "X-CDS-SDK"
 	//SessionTokenHeader is user as HTTP header
 	SessionTokenHeader = "Session-Token"
+	// AccessTokenHeader is used as HTTP header to authenticate with a user access token
+	AccessTokenHeader = "Access-Token"
 	// HTTP client
 	client HTTPClient
 	// current agent calling
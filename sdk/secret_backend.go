@@ -0,0 +1,71 @@
+package sdk
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Types of secret backends
+const (
+	SecretBackendVaultKV1 = "vault-kv1"
+	SecretBackendVaultKV2 = "vault-kv2"
+	SecretBackendFile     = "file"
+)
+
+// SecretBackendTypes list all existing secret backend types
+var SecretBackendTypes = []string{SecretBackendVaultKV1, SecretBackendVaultKV2, SecretBackendFile}
+
+var secretBackendNamePattern = regexp.MustCompile(NamePattern)
+
+// ProjectSecretBackend is an external secret store configured on a project. Secret reference variables
+// are resolved against it when a job is taken. Address is the URL of vault, which must be allowed by the API configuration,
+// or the path of the file in the secret files directory of the API for file backends
+type ProjectSecretBackend struct {
+	ID        int64  `json:"id" db:"id"`
+	ProjectID int64  `json:"project_id" db:"project_id"`
+	Name      string `json:"name" db:"name" cli:"name,key"`
+	Type      string `json:"type" db:"type" cli:"type"`
+	Address   string `json:"address" db:"address" cli:"address"`
+	Token     string `json:"token,omitempty" db:"-" cli:"-"`
+}
+
+// IsValid checks the name and the type of the backend
+func (b ProjectSecretBackend) IsValid() error {
+	if !secretBackendNamePattern.MatchString(b.Name) {
+		return WrapError(ErrWrongRequest, "Invalid secret backend name %s", b.Name)
+	}
+	for _, t := range SecretBackendTypes {
+		if b.Type == t {
+			return nil
+		}
+	}
+	return WrapError(ErrWrongRequest, "Invalid secret backend type %s", b.Type)
+}
+
+// SecretReference is the value of a secret reference variable: backend:path#field
+type SecretReference struct {
+	Backend string
+	Path    string
+	Field   string
+}
+
+// ParseSecretReference parses a reference like vault:kv/team/db#password
+func ParseSecretReference(s string) (SecretReference, error) {
+	var ref SecretReference
+	i := strings.Index(s, ":")
+	j := strings.LastIndex(s, "#")
+	if i <= 0 || j < i {
+		return ref, ErrInvalidSecretReference
+	}
+	ref.Backend = s[:i]
+	ref.Path = strings.Trim(s[i+1:j], "/")
+	ref.Field = s[j+1:]
+	if ref.Path == "" || ref.Field == "" {
+		return ref, ErrInvalidSecretReference
+	}
+	return ref, nil
+}
+
+func (r SecretReference) String() string {
+	return r.Backend + ":" + r.Path + "#" + r.Field
+}
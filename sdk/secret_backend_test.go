@@ -0,0 +1,19 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSecretReference(t *testing.T) {
+	ref, err := ParseSecretReference("vault:kv/team/db#password")
+	assert.NoError(t, err)
+	assert.Equal(t, SecretReference{Backend: "vault", Path: "kv/team/db", Field: "password"}, ref)
+	assert.Equal(t, "vault:kv/team/db#password", ref.String())
+
+	for _, s := range []string{"", "kv/team/db#password", "vault:kv/team/db", "vault:#password", "vault:kv/team/db#"} {
+		_, err := ParseSecretReference(s)
+		assert.Equal(t, ErrInvalidSecretReference, err, s)
+	}
+}
@@ -41,3 +41,27 @@ type Week struct {
 		Deploy  int64 `json:"deploy"`
 	} `json:"runned_pipelines"`
 }
+
+// DeliveryMetrics are the delivery performance metrics of a project or a workflow on a period, computed from
+// the runs of its deployment nodes: nodes with a deployment pipeline and an environment
+type DeliveryMetrics struct {
+	ProjectKey   string    `json:"project_key"`
+	WorkflowName string    `json:"workflow_name,omitempty"`
+	Environment  string    `json:"environment,omitempty"`
+	From         time.Time `json:"from"`
+	To           time.Time `json:"to"`
+
+	Deployments       int64 `json:"deployments"`
+	FailedDeployments int64 `json:"failed_deployments"`
+	// DeploymentFrequency is the number of successful deployments by day
+	DeploymentFrequency float64 `json:"deployment_frequency"`
+	// LeadTime is the average duration in seconds from a commit to its successful deployment
+	LeadTime float64 `json:"lead_time"`
+	// MTTR is the average duration in seconds from a failed deployment to the next successful one on the same
+	// environment
+	MTTR float64 `json:"mttr"`
+	// ChangeFailureRate is the ratio of failed deployments
+	ChangeFailureRate float64 `json:"change_failure_rate"`
+
+	Workflows []DeliveryMetrics `json:"workflows,omitempty"`
+}
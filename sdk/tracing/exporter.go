@@ -0,0 +1,296 @@
+package tracing
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ovh/cds/sdk/log"
+)
+
+type exporter interface {
+	export(s *Span)
+	flush()
+}
+
+var (
+	exp      exporter
+	expMutex sync.RWMutex
+)
+
+func current() exporter {
+	expMutex.RLock()
+	defer expMutex.RUnlock()
+	return exp
+}
+
+// Enabled returns true if tracing has been initialized
+func Enabled() bool {
+	return current() != nil
+}
+
+// Flush sends the spans which have not been exported yet, and waits until they are sent
+func Flush() {
+	if e := current(); e != nil {
+		e.flush()
+	}
+}
+
+// Shutdown flushes the spans and stops exporting. It must be called before the service exits
+func Shutdown() {
+	expMutex.Lock()
+	e := exp
+	exp = nil
+	expMutex.Unlock()
+	if e != nil {
+		e.flush()
+	}
+}
+
+// Init initializes the exporter of the spans of a service
+func Init(cfg Configuration, service string) error {
+	if !cfg.Enable {
+		return nil
+	}
+
+	var e exporter
+	switch cfg.Exporter {
+	case "stdout":
+		e = &stdoutExporter{service: service, w: os.Stdout}
+	case "otlp", "":
+		if cfg.Endpoint == "" {
+			return fmt.Errorf("tracing> OTLP endpoint is mandatory")
+		}
+		o := &otlpExporter{service: service, endpoint: cfg.Endpoint, spans: make(chan *Span, 1000), flushes: make(chan chan struct{}), client: &http.Client{Timeout: 10 * time.Second}}
+		go o.run()
+		e = o
+	default:
+		return fmt.Errorf("tracing> unsupported exporter %s", cfg.Exporter)
+	}
+
+	expMutex.Lock()
+	exp = e
+	expMutex.Unlock()
+	log.Info("tracing> %s spans exported with %s", service, cfg.Exporter)
+	return nil
+}
+
+// stdoutExporter writes the spans as JSON lines, it is meant for tests and debugging
+type stdoutExporter struct {
+	service string
+	w       io.Writer
+	mutex   sync.Mutex
+}
+
+func (e *stdoutExporter) export(s *Span) {
+	s.mutex.Lock()
+	out := struct {
+		Service  string            `json:"service"`
+		TraceID  string            `json:"trace_id"`
+		SpanID   string            `json:"span_id"`
+		ParentID string            `json:"parent_id,omitempty"`
+		Name     string            `json:"name"`
+		Start    time.Time         `json:"start"`
+		Duration string            `json:"duration"`
+		Tags     map[string]string `json:"tags,omitempty"`
+		Error    string            `json:"error,omitempty"`
+	}{
+		Service:  e.service,
+		TraceID:  hex.EncodeToString(s.Context.TraceID[:]),
+		SpanID:   hex.EncodeToString(s.Context.SpanID[:]),
+		Name:     s.Name,
+		Start:    s.Start,
+		Duration: s.End.Sub(s.Start).String(),
+		Tags:     s.Tags,
+		Error:    s.Error,
+	}
+	if s.Parent.IsValid() {
+		out.ParentID = hex.EncodeToString(s.Parent.SpanID[:])
+	}
+	b, err := json.Marshal(out)
+	s.mutex.Unlock()
+	if err != nil {
+		return
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.w.Write(append(b, '\n'))
+}
+
+func (e *stdoutExporter) flush() {}
+
+// otlpExporter sends the spans by batch to an OpenTelemetry collector with the OTLP/HTTP JSON protocol
+type otlpExporter struct {
+	service  string
+	endpoint string
+	spans    chan *Span
+	flushes  chan chan struct{}
+	client   *http.Client
+}
+
+func (e *otlpExporter) export(s *Span) {
+	select {
+	case e.spans <- s:
+	default:
+		log.Debug("tracing> exporter queue is full, span %s dropped", s.Name)
+	}
+}
+
+// flush asks the run routine to send all the queued spans and waits for it
+func (e *otlpExporter) flush() {
+	done := make(chan struct{})
+	select {
+	case e.flushes <- done:
+	case <-time.After(otlpFlushTimeout):
+		log.Warning("tracing> unable to flush spans")
+		return
+	}
+	select {
+	case <-done:
+	case <-time.After(otlpFlushTimeout):
+		log.Warning("tracing> unable to flush spans")
+	}
+}
+
+// otlpFlushTimeout is the maximum time to wait for the spans to be sent by a flush
+const otlpFlushTimeout = 15 * time.Second
+
+func (e *otlpExporter) run() {
+	tick := time.NewTicker(5 * time.Second)
+	defer tick.Stop()
+	var batch []*Span
+	for {
+		var done chan struct{}
+		select {
+		case s := <-e.spans:
+			batch = append(batch, s)
+			if len(batch) < 100 {
+				continue
+			}
+		case <-tick.C:
+		case done = <-e.flushes:
+			batch = append(batch, e.queued()...)
+		}
+		if len(batch) > 0 {
+			if err := e.send(batch); err != nil {
+				log.Warning("tracing> unable to export %d spans: %v", len(batch), err)
+			}
+			batch = nil
+		}
+		if done != nil {
+			close(done)
+		}
+	}
+}
+
+// queued returns the spans waiting in the queue of the exporter
+func (e *otlpExporter) queued() []*Span {
+	var spans []*Span
+	for {
+		select {
+		case s := <-e.spans:
+			spans = append(spans, s)
+		default:
+			return spans
+		}
+	}
+}
+
+type otlpAttribute struct {
+	Key   string `json:"key"`
+	Value struct {
+		StringValue string `json:"stringValue"`
+	} `json:"value"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            struct {
+		Code    int    `json:"code,omitempty"`
+		Message string `json:"message,omitempty"`
+	} `json:"status"`
+}
+
+func newOTLPAttribute(key, value string) otlpAttribute {
+	a := otlpAttribute{Key: key}
+	a.Value.StringValue = value
+	return a
+}
+
+func newOTLPSpan(s *Span) otlpSpan {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	o := otlpSpan{
+		TraceID:           hex.EncodeToString(s.Context.TraceID[:]),
+		SpanID:            hex.EncodeToString(s.Context.SpanID[:]),
+		Name:              s.Name,
+		Kind:              1,
+		StartTimeUnixNano: strconv.FormatInt(s.Start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(s.End.UnixNano(), 10),
+	}
+	if s.Parent.IsValid() {
+		o.ParentSpanID = hex.EncodeToString(s.Parent.SpanID[:])
+	}
+	for k, v := range s.Tags {
+		o.Attributes = append(o.Attributes, newOTLPAttribute(k, v))
+	}
+	if s.Error != "" {
+		o.Status.Code = 2
+		o.Status.Message = s.Error
+	}
+	return o
+}
+
+func (e *otlpExporter) send(batch []*Span) error {
+	spans := make([]otlpSpan, len(batch))
+	for i := range batch {
+		spans[i] = newOTLPSpan(batch[i])
+	}
+
+	type scopeSpans struct {
+		Scope struct {
+			Name string `json:"name"`
+		} `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+	type resourceSpans struct {
+		Resource struct {
+			Attributes []otlpAttribute `json:"attributes"`
+		} `json:"resource"`
+		ScopeSpans []scopeSpans `json:"scopeSpans"`
+	}
+	var rs resourceSpans
+	rs.Resource.Attributes = []otlpAttribute{newOTLPAttribute("service.name", e.service)}
+	ss := scopeSpans{Spans: spans}
+	ss.Scope.Name = "github.com/ovh/cds"
+	rs.ScopeSpans = []scopeSpans{ss}
+
+	b, err := json.Marshal(map[string][]resourceSpans{"resourceSpans": {rs}})
+	if err != nil {
+		return err
+	}
+	resp, err := e.client.Post(e.endpoint, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("HTTP Code %d", resp.StatusCode)
+	}
+	return nil
+}
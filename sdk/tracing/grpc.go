@@ -0,0 +1,44 @@
+package tracing
+
+import (
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// UnaryClientInterceptor propagates the current span of the context in the metadata of the gRPC calls
+func UnaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return invoker(outgoingContext(ctx), method, req, reply, cc, opts...)
+}
+
+// StreamClientInterceptor propagates the current span of the context in the metadata of the gRPC streams
+func StreamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return streamer(outgoingContext(ctx), desc, cc, method, opts...)
+}
+
+func outgoingContext(ctx context.Context) context.Context {
+	c := FromContext(ctx)
+	if !c.IsValid() {
+		return ctx
+	}
+	md, ok := metadata.FromContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	md[TraceParentHeader] = []string{c.TraceParent()}
+	return metadata.NewContext(ctx, md)
+}
+
+// ExtractMetadata returns a context carrying the remote span of the metadata of a gRPC call, if any
+func ExtractMetadata(ctx context.Context) context.Context {
+	md, ok := metadata.FromContext(ctx)
+	if !ok || len(md[TraceParentHeader]) == 0 {
+		return ctx
+	}
+	if c, ok := ParseTraceParent(md[TraceParentHeader][0]); ok {
+		return ContextWithRemote(ctx, c)
+	}
+	return ctx
+}
@@ -0,0 +1,200 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TraceParentHeader is the W3C trace context header propagating spans between the CDS services
+const TraceParentHeader = "traceparent"
+
+// Configuration is the tracing configuration of the CDS services
+type Configuration struct {
+	Enable   bool   `toml:"enable" default:"false" comment:"Enable distributed tracing"`
+	Exporter string `toml:"exporter" default:"otlp" comment:"Exporter of the spans: otlp (OpenTelemetry collector, Jaeger) or stdout"`
+	Endpoint string `toml:"endpoint" default:"http://localhost:4318/v1/traces" comment:"URL of the OTLP/HTTP traces endpoint"`
+}
+
+// SpanContext identifies a span in a trace
+type SpanContext struct {
+	TraceID [16]byte
+	SpanID  [8]byte
+}
+
+// IsValid returns true if the span context identifies a span
+func (c SpanContext) IsValid() bool {
+	return c.TraceID != [16]byte{} && c.SpanID != [8]byte{}
+}
+
+// TraceParent returns the span context as the value of a W3C traceparent header
+func (c SpanContext) TraceParent() string {
+	if !c.IsValid() {
+		return ""
+	}
+	return fmt.Sprintf("00-%x-%x-01", c.TraceID, c.SpanID)
+}
+
+// ParseTraceParent parses the value of a W3C traceparent header
+func ParseTraceParent(s string) (SpanContext, bool) {
+	var c SpanContext
+	parts := strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) != 4 || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return c, false
+	}
+	if _, err := hex.Decode(c.TraceID[:], []byte(parts[1])); err != nil {
+		return c, false
+	}
+	if _, err := hex.Decode(c.SpanID[:], []byte(parts[2])); err != nil {
+		return c, false
+	}
+	return c, c.IsValid()
+}
+
+// WorkflowRunContext returns the span context of the root span of a workflow run. It is computed from the
+// run ID so every service processing the run can attach its spans to the trace of the run
+func WorkflowRunContext(runID int64) SpanContext {
+	var c SpanContext
+	sum := sha256.Sum256([]byte(fmt.Sprintf("cds-workflow-run-%d", runID)))
+	copy(c.TraceID[:], sum[:16])
+	copy(c.SpanID[:], sum[16:24])
+	return c
+}
+
+// Span is a timed operation of a trace
+type Span struct {
+	Context SpanContext
+	Parent  SpanContext
+	Name    string
+	Start   time.Time
+	End     time.Time
+	Tags    map[string]string
+	Error   string
+	mutex   sync.Mutex
+}
+
+// SetTag sets a tag on the span. It does nothing on a nil span, returned when tracing is disabled
+func (s *Span) SetTag(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	s.Tags[key] = fmt.Sprintf("%v", value)
+	s.mutex.Unlock()
+}
+
+// SetError marks the span as failed
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mutex.Lock()
+	s.Error = err.Error()
+	s.mutex.Unlock()
+}
+
+// Finish ends the span and sends it to the exporter
+func (s *Span) Finish() {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	s.End = time.Now()
+	s.mutex.Unlock()
+	if exp := current(); exp != nil {
+		exp.export(s)
+	}
+}
+
+// NewSpan returns a span with a given context, for the spans whose identifiers are known in advance such as
+// the root span of a workflow run. It returns nil when tracing is disabled
+func NewSpan(name string, c, parent SpanContext, start time.Time) *Span {
+	if !Enabled() {
+		return nil
+	}
+	return &Span{Context: c, Parent: parent, Name: name, Start: start, Tags: map[string]string{}}
+}
+
+type contextKey int
+
+const (
+	spanKey contextKey = iota
+	remoteKey
+)
+
+// Start starts a span, child of the span of the context or of the remote span extracted in the context.
+// The returned context carries the new span
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	if !Enabled() {
+		return ctx, nil
+	}
+	parent := FromContext(ctx)
+	s := &Span{Parent: parent, Name: name, Start: time.Now(), Tags: map[string]string{}}
+	if parent.IsValid() {
+		s.Context.TraceID = parent.TraceID
+	} else {
+		rand.Read(s.Context.TraceID[:])
+	}
+	rand.Read(s.Context.SpanID[:])
+	return context.WithValue(ctx, spanKey, s), s
+}
+
+// FromContext returns the context of the current span of a context
+func FromContext(ctx context.Context) SpanContext {
+	if s, ok := ctx.Value(spanKey).(*Span); ok && s != nil {
+		return s.Context
+	}
+	if c, ok := ctx.Value(remoteKey).(SpanContext); ok {
+		return c
+	}
+	return SpanContext{}
+}
+
+// ContextWithRemote returns a context whose spans are children of a span of another service
+func ContextWithRemote(ctx context.Context, c SpanContext) context.Context {
+	if !c.IsValid() {
+		return ctx
+	}
+	return context.WithValue(ctx, remoteKey, c)
+}
+
+// Inject sets the traceparent header of the current span of a context
+func Inject(ctx context.Context, h http.Header) {
+	if c := FromContext(ctx); c.IsValid() {
+		h.Set(TraceParentHeader, c.TraceParent())
+	}
+}
+
+// Extract returns a context carrying the remote span of the traceparent header, if any
+func Extract(ctx context.Context, h http.Header) context.Context {
+	if c, ok := ParseTraceParent(h.Get(TraceParentHeader)); ok {
+		return ContextWithRemote(ctx, c)
+	}
+	return ctx
+}
+
+var (
+	processSpan  SpanContext
+	processMutex sync.RWMutex
+)
+
+// SetProcessSpan sets the span propagated by the requests of the process which are not bound to a context,
+// such as the requests of a worker to the API during a job
+func SetProcessSpan(c SpanContext) {
+	processMutex.Lock()
+	processSpan = c
+	processMutex.Unlock()
+}
+
+// ProcessSpan returns the span propagated by the requests of the process
+func ProcessSpan() SpanContext {
+	processMutex.RLock()
+	defer processMutex.RUnlock()
+	return processSpan
+}
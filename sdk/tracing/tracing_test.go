@@ -0,0 +1,104 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTraceParent(t *testing.T) {
+	c := WorkflowRunContext(42)
+	assert.True(t, c.IsValid())
+	assert.Equal(t, c, WorkflowRunContext(42))
+	assert.NotEqual(t, c, WorkflowRunContext(43))
+
+	parsed, ok := ParseTraceParent(c.TraceParent())
+	assert.True(t, ok)
+	assert.Equal(t, c, parsed)
+
+	_, ok = ParseTraceParent("00-00000000000000000000000000000000-0000000000000000-01")
+	assert.False(t, ok)
+	_, ok = ParseTraceParent("garbage")
+	assert.False(t, ok)
+}
+
+func TestSpans(t *testing.T) {
+	// Nothing is recorded while tracing is disabled
+	ctx, s := Start(context.Background(), "disabled")
+	assert.Nil(t, s)
+	s.SetTag("key", "value")
+	s.Finish()
+	assert.False(t, FromContext(ctx).IsValid())
+
+	buf := new(bytes.Buffer)
+	exp = &stdoutExporter{service: "test", w: buf}
+	defer func() { exp = nil }()
+
+	// A span started from a remote parent belongs to the trace of the parent
+	h := http.Header{}
+	h.Set(TraceParentHeader, WorkflowRunContext(1).TraceParent())
+	ctx, parent := Start(Extract(context.Background(), h), "parent")
+	assert.Equal(t, WorkflowRunContext(1).TraceID, parent.Context.TraceID)
+	assert.Equal(t, WorkflowRunContext(1), parent.Parent)
+
+	_, child := Start(ctx, "child")
+	child.SetTag("status", 500)
+	child.SetError(errors.New("boom"))
+	child.Finish()
+	parent.Finish()
+	assert.Equal(t, parent.Context.TraceID, child.Context.TraceID)
+	assert.Equal(t, parent.Context, child.Parent)
+
+	out := http.Header{}
+	Inject(ctx, out)
+	assert.Equal(t, parent.Context.TraceParent(), out.Get(TraceParentHeader))
+
+	var spans []map[string]interface{}
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var s map[string]interface{}
+		assert.NoError(t, dec.Decode(&s))
+		spans = append(spans, s)
+	}
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "child", spans[0]["name"])
+	assert.Equal(t, "boom", spans[0]["error"])
+	assert.Equal(t, map[string]interface{}{"status": "500"}, spans[0]["tags"])
+	assert.Equal(t, spans[1]["span_id"], spans[0]["parent_id"])
+}
+
+func TestShutdownFlushesSpans(t *testing.T) {
+	received := make(chan int, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			ResourceSpans []struct {
+				ScopeSpans []struct {
+					Spans []otlpSpan `json:"spans"`
+				} `json:"scopeSpans"`
+			} `json:"resourceSpans"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		received <- len(body.ResourceSpans[0].ScopeSpans[0].Spans)
+	}))
+	defer srv.Close()
+
+	assert.NoError(t, Init(Configuration{Enable: true, Exporter: "otlp", Endpoint: srv.URL}, "test"))
+	_, s := Start(context.Background(), "job")
+	s.Finish()
+
+	// The span is sent before the next tick of the exporter
+	Shutdown()
+	assert.False(t, Enabled())
+	select {
+	case n := <-received:
+		assert.Equal(t, 1, n)
+	default:
+		t.Fatal("span has not been sent by Shutdown")
+	}
+}
@@ -27,6 +27,8 @@ const (
 	BooleanVariable    = "boolean"
 	NumberVariable     = "number"
 	RepositoryVariable = "repository"
+	// SecretReferenceVariable value is a reference to a secret stored in an external backend, ie. vault:kv/team/db#password
+	SecretReferenceVariable = "secret_reference"
 )
 
 var (
@@ -38,6 +40,7 @@ var (
 		KeyVariable,
 		BooleanVariable,
 		NumberVariable,
+		SecretReferenceVariable,
 	}
 )
 
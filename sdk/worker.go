@@ -3,6 +3,8 @@ package sdk
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -79,6 +81,71 @@ type Model struct {
 	NbSpawnErr       int64              `json:"nb_spawn_err" db:"nb_spawn_err" cli:"nb_spawn_err"`
 	LastSpawnErr     string             `json:"last_spawn_err" db:"last_spawn_err" cli:"-"`
 	DateLastSpawnErr *time.Time         `json:"date_last_spawn_err" db:"date_last_spawn_err" cli:"-"`
+	PoolPolicy       ModelPoolPolicy    `json:"pool_policy" db:"-" cli:"-"`
+	SpawnDuration    time.Duration      `json:"spawn_duration,omitempty" db:"-" cli:"-"` // average duration of the last spawns for jobs, sent to hatcheries
+	Dockerfile       string             `json:"dockerfile,omitempty" db:"dockerfile" cli:"-"`
+	Version          int64              `json:"version" db:"version" cli:"version"`
+}
+
+// ModelVersion is a version of the definition of a worker model, each change of the model creates a version
+type ModelVersion struct {
+	ModelID int64     `json:"model_id" cli:"-"`
+	Version int64     `json:"version" cli:"version"`
+	Model   Model     `json:"model" cli:"-"`
+	Author  string    `json:"author" cli:"author"`
+	Created time.Time `json:"created" cli:"created"`
+}
+
+// ModelVersionSeparator separates the name of a model and its version in a model requirement, ie. "go-official@3"
+const ModelVersionSeparator = "@"
+
+// ParseModelRequirement returns the name of the model and the version pinned by a model requirement,
+// 0 if the requirement does not pin a version
+func ParseModelRequirement(value string) (string, int64) {
+	i := strings.LastIndex(value, ModelVersionSeparator)
+	if i < 0 {
+		return value, 0
+	}
+	version, err := strconv.ParseInt(value[i+1:], 10, 64)
+	if err != nil || version <= 0 {
+		return value, 0
+	}
+	return value[:i], version
+}
+
+// BuildImageName returns the name of the image built by hatcheries from the Dockerfile of the model
+func (m Model) BuildImageName() string {
+	name := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' || r == '_' || r == '.' {
+			return r
+		}
+		return '-'
+	}, strings.ToLower(m.Name))
+	return fmt.Sprintf("cds-model-%s:%d", name, m.Version)
+}
+
+// ModelPoolPolicy describes the warm pool of workers hatcheries keep for a model. When it is not enabled,
+// hatcheries keep the Provision count of workers alive
+type ModelPoolPolicy struct {
+	MinIdle int   `json:"min_idle"`
+	Max     int   `json:"max"`
+	IdleTTL int64 `json:"idle_ttl"` // in seconds, 0 to never scale down
+}
+
+// Enabled returns true if the model is provisioned by a warm pool
+func (p ModelPoolPolicy) Enabled() bool {
+	return p.MinIdle > 0 || p.Max > 0
+}
+
+// IsValid checks the bounds of the pool
+func (p ModelPoolPolicy) IsValid() error {
+	if p.MinIdle < 0 || p.Max < 0 || p.IdleTTL < 0 {
+		return WrapError(ErrWrongRequest, "Invalid pool policy: negative values")
+	}
+	if p.Max > 0 && p.MinIdle > p.Max {
+		return WrapError(ErrWrongRequest, "Invalid pool policy: min idle %d is greater than max %d", p.MinIdle, p.Max)
+	}
+	return nil
 }
 
 // OpenstackModelData type details the "Image" field of Openstack type model
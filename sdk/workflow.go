@@ -8,15 +8,18 @@ import (
 
 //Workflow represents a pipeline based workflow
 type Workflow struct {
-	ID           int64              `json:"id" db:"id" cli:"-"`
-	Name         string             `json:"name" db:"name" cli:"name,key"`
-	Description  string             `json:"description,omitempty" db:"description" cli:"description"`
-	LastModified time.Time          `json:"last_modified" db:"last_modified"`
-	ProjectID    int64              `json:"project_id,omitempty" db:"project_id" cli:"-"`
-	ProjectKey   string             `json:"project_key" db:"-" cli:"-"`
-	RootID       int64              `json:"root_id,omitempty" db:"root_node_id" cli:"-"`
-	Root         *WorkflowNode      `json:"root" db:"-" cli:"-"`
-	Joins        []WorkflowNodeJoin `json:"joins,omitempty" db:"-" cli:"-"`
+	ID            int64                  `json:"id" db:"id" cli:"-"`
+	Name          string                 `json:"name" db:"name" cli:"name,key"`
+	Description   string                 `json:"description,omitempty" db:"description" cli:"description"`
+	Priority      int                    `json:"priority,omitempty" db:"priority" cli:"priority"`
+	LastModified  time.Time              `json:"last_modified" db:"last_modified"`
+	ProjectID     int64                  `json:"project_id,omitempty" db:"project_id" cli:"-"`
+	ProjectKey    string                 `json:"project_key" db:"-" cli:"-"`
+	RootID        int64                  `json:"root_id,omitempty" db:"root_node_id" cli:"-"`
+	Root          *WorkflowNode          `json:"root" db:"-" cli:"-"`
+	Joins         []WorkflowNodeJoin     `json:"joins,omitempty" db:"-" cli:"-"`
+	Groups        []GroupPermission      `json:"groups,omitempty" db:"-" cli:"-"`
+	Notifications []WorkflowNotification `json:"notifications,omitempty" db:"-" cli:"-"`
 }
 
 // FilterHooksConfig filter all hooks configuration and remove somme configuration key
@@ -218,6 +221,7 @@ type WorkflowNodeJoinTrigger struct {
 	Conditions         []WorkflowTriggerCondition `json:"conditions,omitempty" db:"-"`
 	Manual             bool                       `json:"manual" db:"manual"`
 	ContinueOnError    bool                       `json:"continue_on_error" db:"continue_on_error"`
+	Priority           int                        `json:"priority,omitempty" db:"priority"`
 }
 
 //WorkflowNode represents a node in w workflow tree
@@ -481,6 +485,7 @@ type WorkflowNodeTrigger struct {
 	Conditions         []WorkflowTriggerCondition `json:"conditions,omitempty" db:"-"`
 	Manual             bool                       `json:"manual" db:"manual"`
 	ContinueOnError    bool                       `json:"continue_on_error" db:"continue_on_error"`
+	Priority           int                        `json:"priority,omitempty" db:"priority"`
 }
 
 //WorkflowTriggerCondition represents a condition to trigger ot not a pipeline in a workflow. Operator can be =, !=, regex
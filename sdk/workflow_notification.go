@@ -0,0 +1,82 @@
+package sdk
+
+// WebhookUserNotification sends notifications to Slack or Mattermost compatible incoming webhooks.
+// Recipients of such notifications are webhook URLs
+const WebhookUserNotification UserNotificationSettingsType = "webhook"
+
+// WorkflowNotificationTypes lists all notification types available on workflows
+var WorkflowNotificationTypes = []UserNotificationSettingsType{EmailUserNotification, JabberUserNotification, WebhookUserNotification}
+
+// WorkflowNotification is a notification rule on a workflow. It applies on all nodes of the workflow
+// if no node name is given
+type WorkflowNotification struct {
+	ID         int64                               `json:"id,omitempty" db:"id"`
+	WorkflowID int64                               `json:"workflow_id,omitempty" db:"workflow_id"`
+	Type       UserNotificationSettingsType        `json:"type" db:"type"`
+	NodeNames  []string                            `json:"node_names,omitempty" db:"-"`
+	Settings   JabberEmailUserNotificationSettings `json:"settings" db:"-"`
+}
+
+// IsValid checks the type and the events of the notification
+func (n *WorkflowNotification) IsValid() error {
+	var validType bool
+	for _, t := range WorkflowNotificationTypes {
+		if n.Type == t {
+			validType = true
+			break
+		}
+	}
+	if !validType {
+		return WrapError(ErrNotSupportedUserNotification, "Invalid notification type %s", n.Type)
+	}
+
+	for _, e := range []UserNotificationEventType{n.Settings.OnSuccess, n.Settings.OnFailure} {
+		switch e {
+		case UserNotificationAlways, UserNotificationNever, UserNotificationChange:
+		default:
+			return WrapError(ErrParseUserNotification, "Invalid notification event %s", e)
+		}
+	}
+
+	if n.Type == WebhookUserNotification && len(n.Settings.Recipients) == 0 {
+		return WrapError(ErrParseUserNotification, "Webhook notification needs at least one URL")
+	}
+	return nil
+}
+
+// ShouldSend checks if the notification has to be sent for a node run. previousStatus is the status
+// of the previous run of the same node, if any
+func (n *WorkflowNotification) ShouldSend(nodeName, status, previousStatus string) bool {
+	if len(n.NodeNames) > 0 {
+		var found bool
+		for _, name := range n.NodeNames {
+			if name == nodeName {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	var check = func(e UserNotificationEventType) bool {
+		switch e {
+		case UserNotificationAlways:
+			return true
+		case UserNotificationChange:
+			return previousStatus == "" || previousStatus != status
+		}
+		return false
+	}
+
+	switch status {
+	case StatusSuccess.String():
+		return check(n.Settings.OnSuccess)
+	case StatusFail.String():
+		return check(n.Settings.OnFailure)
+	case StatusBuilding.String():
+		return n.Settings.OnStart
+	}
+	return false
+}
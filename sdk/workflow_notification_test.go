@@ -0,0 +1,41 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowNotificationShouldSend(t *testing.T) {
+	n := WorkflowNotification{
+		Type:      EmailUserNotification,
+		NodeNames: []string{"deploy"},
+		Settings: JabberEmailUserNotificationSettings{
+			OnSuccess: UserNotificationChange,
+			OnFailure: UserNotificationAlways,
+		},
+	}
+
+	tests := []struct {
+		name     string
+		node     string
+		status   string
+		previous string
+		expected bool
+	}{
+		{"other node", "build", StatusFail.String(), "", false},
+		{"failure always", "deploy", StatusFail.String(), StatusFail.String(), true},
+		{"success without previous run", "deploy", StatusSuccess.String(), "", true},
+		{"success after failure", "deploy", StatusSuccess.String(), StatusFail.String(), true},
+		{"success after success", "deploy", StatusSuccess.String(), StatusSuccess.String(), false},
+		{"start", "deploy", StatusBuilding.String(), "", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, n.ShouldSend(tt.node, tt.status, tt.previous), tt.name)
+	}
+
+	assert.NoError(t, n.IsValid())
+	n.Type = "sms"
+	assert.Error(t, n.IsValid())
+}
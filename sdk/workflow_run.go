@@ -42,6 +42,39 @@ type WorkflowRunPostHandlerOption struct {
 	FromNodeID *int64                    `json:"from_node,omitempty"`
 }
 
+// Sort orders of a search of workflow runs
+const (
+	WorkflowRunSortStart  = "start"
+	WorkflowRunSortNumber = "number"
+)
+
+// WorkflowRunSearch is a search of the runs of a workflow. Tags are matched on one of the values of the tag
+type WorkflowRunSearch struct {
+	Status      []string          `json:"status,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	From        time.Time         `json:"from,omitempty"`
+	To          time.Time         `json:"to,omitempty"`
+	TriggeredBy string            `json:"triggered_by,omitempty"`
+	Hook        string            `json:"hook,omitempty"`
+	Node        string            `json:"node,omitempty"`
+	Sort        string            `json:"sort,omitempty"`
+	Ascending   bool              `json:"ascending,omitempty"`
+	Limit       int               `json:"limit,omitempty"`
+	Cursor      string            `json:"cursor,omitempty"`
+}
+
+// WorkflowRunSearchResult is a page of workflow runs. The next page is searched with NextCursor as cursor
+type WorkflowRunSearchResult struct {
+	Runs       []WorkflowRun `json:"runs"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
+
+// WorkflowRollback is the request to deploy again on an environment the previous successful deployment of a node
+type WorkflowRollback struct {
+	NodeName    string `json:"node_name"`
+	Environment string `json:"environment"`
+}
+
 // Translate translates messages in WorkflowNodeRun
 func (r *WorkflowRun) Translate(lang string) {
 	for ki, info := range r.Infos {
@@ -59,8 +92,16 @@ func (r *WorkflowRun) Tag(tag, value string) {
 	for i := range r.Tags {
 		if r.Tags[i].Tag == tag {
 			found = true
-			if !strings.Contains(r.Tags[i].Value, value) {
-				r.Tags[i].Value = strings.Join([]string{r.Tags[i].Value, value}, ",")
+			values := strings.Split(r.Tags[i].Value, ",")
+			var exists bool
+			for _, v := range values {
+				if v == value {
+					exists = true
+					break
+				}
+			}
+			if !exists {
+				r.Tags[i].Value = strings.Join(append(values, value), ",")
 			}
 		}
 	}
@@ -145,6 +186,8 @@ type WorkflowNodeJobRun struct {
 	Model             string      `json:"model,omitempty" db:"model"`
 	BookedBy          Hatchery    `json:"bookedby" db:"-"`
 	SpawnInfos        []SpawnInfo `json:"spawninfos" db:"-"`
+	Priority          int         `json:"priority,omitempty" db:"priority"`
+	GroupID           int64       `json:"group_id,omitempty" db:"group_id"`
 }
 
 // Translate translates messages in WorkflowNodeJobRun
@@ -167,6 +210,9 @@ type WorkflowNodeRunManual struct {
 	Payload            interface{} `json:"payload" db:"-"`
 	PipelineParameters []Parameter `json:"pipeline_parameter" db:"-"`
 	User               User        `json:"user" db:"-"`
+	IgnoreFreeze       bool        `json:"ignore_freeze,omitempty" db:"-"`
+	// BuildParameters are the parameters of a previous node run, reused as is by rollbacks
+	BuildParameters []Parameter `json:"-" db:"-"`
 }
 
 //GetName returns the name the artifact
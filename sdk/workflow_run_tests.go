@@ -0,0 +1,92 @@
+package sdk
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/ovh/venom"
+)
+
+// Statuses of a test case in the tests history
+const (
+	TestStatusSuccess = "Success"
+	TestStatusFail    = "Fail"
+	TestStatusSkipped = "Skipped"
+)
+
+// WorkflowTestCase is the result of a test case in a workflow node run
+type WorkflowTestCase struct {
+	ID                int64     `json:"id" db:"id"`
+	WorkflowID        int64     `json:"workflow_id" db:"workflow_id"`
+	WorkflowRunID     int64     `json:"workflow_run_id" db:"workflow_run_id"`
+	WorkflowNodeRunID int64     `json:"workflow_node_run_id" db:"workflow_node_run_id"`
+	Suite             string    `json:"suite" db:"suite"`
+	Name              string    `json:"name" db:"name"`
+	Status            string    `json:"status" db:"status"`
+	Duration          float64   `json:"duration" db:"duration"`
+	VCSHash           string    `json:"vcs_hash" db:"vcs_hash"`
+	Created           time.Time `json:"created" db:"created"`
+}
+
+// NewWorkflowTestCases returns the test cases of venom test suites, with their status and duration in seconds
+func NewWorkflowTestCases(tests venom.Tests) []WorkflowTestCase {
+	var res []WorkflowTestCase
+	for _, ts := range tests.TestSuites {
+		for _, tc := range ts.TestCases {
+			c := WorkflowTestCase{
+				Suite:  ts.Name,
+				Name:   tc.Name,
+				Status: TestStatusSuccess,
+			}
+			switch {
+			case len(tc.Failures) > 0 || len(tc.Errors) > 0:
+				c.Status = TestStatusFail
+			case tc.Skipped > 0:
+				c.Status = TestStatusSkipped
+			}
+			c.Duration, _ = strconv.ParseFloat(tc.Time, 64)
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// WorkflowTestSuiteTrend is the failure rate and duration of the test cases of a suite, by day
+type WorkflowTestSuiteTrend struct {
+	Suite           string                   `json:"suite"`
+	Total           int64                    `json:"total"`
+	Failures        int64                    `json:"failures"`
+	FailureRate     float64                  `json:"failure_rate"`
+	AverageDuration float64                  `json:"average_duration"`
+	Days            []WorkflowTestTrendPoint `json:"days"`
+}
+
+// WorkflowTestTrendPoint is the trend of a test suite on a day
+type WorkflowTestTrendPoint struct {
+	Day             time.Time `json:"day" db:"day"`
+	Total           int64     `json:"total" db:"total"`
+	Failures        int64     `json:"failures" db:"failures"`
+	FailureRate     float64   `json:"failure_rate" db:"-"`
+	AverageDuration float64   `json:"average_duration" db:"average_duration"`
+}
+
+// WorkflowFlakyTest is a test case which both passed and failed on the same commit
+type WorkflowFlakyTest struct {
+	Suite       string    `json:"suite" db:"suite"`
+	Name        string    `json:"name" db:"name"`
+	Commits     int64     `json:"commits" db:"commits"`
+	Successes   int64     `json:"successes" db:"successes"`
+	Failures    int64     `json:"failures" db:"failures"`
+	LastSeen    time.Time `json:"last_seen" db:"last_seen"`
+	Quarantined bool      `json:"quarantined" db:"quarantined"`
+}
+
+// WorkflowTestQuarantine is a test case whose failures do not fail the JUnit action
+type WorkflowTestQuarantine struct {
+	ID         int64     `json:"id" db:"id"`
+	WorkflowID int64     `json:"workflow_id" db:"workflow_id"`
+	Suite      string    `json:"suite" db:"suite"`
+	Name       string    `json:"name" db:"name"`
+	Author     string    `json:"author" db:"author"`
+	Created    time.Time `json:"created" db:"created"`
+}
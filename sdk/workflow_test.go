@@ -148,3 +148,12 @@ func TestWorkflowNode_AncestorsAfterJoin(t *testing.T) {
 	assert.Equal(t, 1, len(ids))
 	assert.Equal(t, int64(4), ids[0])
 }
+
+func TestWorkflowRun_Tag(t *testing.T) {
+	r := WorkflowRun{}
+	r.Tag("environment", "preprod")
+	r.Tag("environment", "prod")
+	r.Tag("environment", "preprod")
+	r.Tag("environment", "")
+	assert.Equal(t, []WorkflowRunTag{{Tag: "environment", Value: "preprod,prod"}}, r.Tags)
+}